@@ -2,7 +2,6 @@ package main
 
 import (
 	"database/sql"
-	"encoding/json"
 	"log/slog"
 	"net/http"
 	"os"
@@ -15,7 +14,6 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
 	httpSwagger "github.com/swaggo/http-swagger"
-	"golang.org/x/crypto/bcrypt"
 
 	authHttp "lms_backend/internal/auth/delivery/http"
 	authMiddleware "lms_backend/internal/auth/delivery/middleware"
@@ -58,6 +56,10 @@ import (
 	attendanceRepo "lms_backend/internal/attendance/repository"
 	attendanceUseCase "lms_backend/internal/attendance/usecase"
 
+	extensionHttp "lms_backend/internal/extension/delivery/http"
+	extensionRepo "lms_backend/internal/extension/repository"
+	extensionUseCase "lms_backend/internal/extension/usecase"
+
 	freezeHttp "lms_backend/internal/freeze/delivery/http"
 	freezeRepo "lms_backend/internal/freeze/repository"
 	freezeUseCase "lms_backend/internal/freeze/usecase"
@@ -78,6 +80,16 @@ import (
 	bannerRepo "lms_backend/internal/banner/repository"
 	bannerUseCase "lms_backend/internal/banner/usecase"
 
+	permissionHttp "lms_backend/internal/permission/delivery/http"
+	permissionRepo "lms_backend/internal/permission/repository"
+	permissionUseCase "lms_backend/internal/permission/usecase"
+	scopeRepo "lms_backend/internal/scope/repository"
+	scopeUseCase "lms_backend/internal/scope/usecase"
+
+	parentHttp "lms_backend/internal/parent/delivery/http"
+	parentRepo "lms_backend/internal/parent/repository"
+	parentUseCase "lms_backend/internal/parent/usecase"
+
 	auditRepo "lms_backend/internal/audit/repository"
 	auditUseCase "lms_backend/internal/audit/usecase"
 
@@ -93,10 +105,11 @@ import (
 	reportsHttp "lms_backend/internal/reports/delivery/http"
 
 	"lms_backend/internal/domain"
-	"lms_backend/internal/httperror"
+	"lms_backend/pkg/broker"
 	dbPkg "lms_backend/pkg/database"
 	"lms_backend/pkg/logger"
 	storageService "lms_backend/pkg/storage"
+	"lms_backend/pkg/token"
 )
 
 // @title Cap Education LMS - API
@@ -156,9 +169,25 @@ func main() {
 		os.Exit(1)
 	}
 
-	authRepoImpl := repository.NewAuthRepository(db)
-	authUsecase := authUseCase.NewAuthUsecase(authRepoImpl)
-	authHandler := authHttp.NewAuthHandler(authUsecase)
+	tokenKeys, activeKID, err := token.ParseKeys(os.Getenv("AUTH_TOKEN_KEYS"))
+	if err != nil {
+		slog.Error("invalid AUTH_TOKEN_KEYS", logger.Err(err))
+		os.Exit(1)
+	}
+	if kid := os.Getenv("AUTH_TOKEN_ACTIVE_KID"); kid != "" {
+		activeKID = kid
+	}
+	tokenManager, err := token.NewManager(tokenKeys, activeKID, 15*time.Minute)
+	if err != nil {
+		slog.Error("failed to initialize token manager", logger.Err(err))
+		os.Exit(1)
+	}
+
+	trustedProxies, err := authMiddleware.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		slog.Error("invalid TRUSTED_PROXIES", logger.Err(err))
+		os.Exit(1)
+	}
 
 	redisAddr := os.Getenv("REDIS_ADDR")
 	if redisAddr == "" {
@@ -172,18 +201,38 @@ func main() {
 		MinIdleConns: 3,
 	})
 
-	dashboardRepository := dashboardRepo.NewCachedDashboardRepo(
-		dashboardRepo.NewDashboardRepository(db), rdb,
-	)
-	dashboardUsecase := dashboardUseCase.NewDashboardUseCase(dashboardRepository)
-	dashboardHandler := dashboardHttp.NewDashboardHandler(dashboardUsecase)
+	eventBroker := broker.NewEventBroker(redisAddr)
+	defer eventBroker.Close()
+
+	auditRepoImpl := auditRepo.NewAuditRepository(db)
+	auditUC := auditUseCase.NewAuditUseCase(auditRepoImpl)
+
+	authRepoImpl := repository.NewAuthRepository(db)
+	loginLimiter := repository.NewRedisLoginLimiter(rdb, authUseCase.LoginLimitPolicies)
+	authUsecase := authUseCase.NewAuthUsecase(authRepoImpl, tokenManager, eventBroker, loginLimiter, auditUC)
+	authHandler := authHttp.NewAuthHandler(authUsecase)
+
+	permissionRepoImpl := permissionRepo.NewPermissionRepository(db)
+	permissionUC := permissionUseCase.NewPermissionUseCase(permissionRepoImpl, auditUC)
+	permissionHandler := permissionHttp.NewPermissionHandler(permissionUC)
+	perm := func(permission string) func(http.Handler) http.Handler {
+		return authMiddleware.PermissionRequired(permissionUC, permission)
+	}
+
+	scopeRepoImpl := scopeRepo.NewScopeRepository(db)
+	scopeUC := scopeUseCase.NewScopeUseCase(scopeRepoImpl)
 
 	adminRepo := contentAdminRepo.NewContentAdminRepository(db)
-	adminUsecase := contentAdminUseCase.NewContentAdminUseCase(adminRepo, s3Client)
-	adminHandler := contentAdminHttp.NewContentAdminHandler(adminUsecase)
+	adminUsecase := contentAdminUseCase.NewContentAdminUseCase(adminRepo, s3Client, scopeUC)
+	adminHandler := contentAdminHttp.NewContentAdminHandler(adminUsecase, permissionUC)
 
 	learningRepoImpl := learningRepo.NewLearningRepository(db)
-	learningUC := learningUseCase.NewLearningUseCase(learningRepoImpl, s3Client)
+	certificateTemplate, err := learningUseCase.LoadCertificateTemplate(os.Getenv("CERTIFICATE_TEMPLATE_PATH"))
+	if err != nil {
+		slog.Error("Failed to load certificate template", logger.Err(err))
+		os.Exit(1)
+	}
+	learningUC := learningUseCase.NewLearningUseCase(learningRepoImpl, s3Client, certificateTemplate, auditUC)
 	learningHandler := learningHttp.NewLearningHandler(learningUC)
 
 	teacherDashboardRepoImpl := teacherDashboardRepo.NewTeacherDashboardRepository(db)
@@ -191,7 +240,7 @@ func main() {
 	teacherDashboardHandler := teacherDashboardHttp.NewTeacherDashboardHandler(teacherDashboardUC)
 
 	reviewRepoImpl := reviewRepo.NewReviewRepository(db)
-	reviewUC := reviewUseCase.NewReviewUseCase(reviewRepoImpl)
+	reviewUC := reviewUseCase.NewReviewUseCase(reviewRepoImpl, scopeUC, s3Client, learningUC)
 	reviewHandler := reviewHttp.NewReviewHandler(reviewUC)
 
 	profileRepoImpl := profileRepo.NewProfileRepository(db)
@@ -203,19 +252,23 @@ func main() {
 	scheduleHandler := scheduleHttp.NewScheduleHandler(scheduleUC)
 
 	chatRepoImpl := chatRepo.NewChatRepository(db)
-	chatUC := chatUseCase.NewChatUseCase(chatRepoImpl)
+	chatUC := chatUseCase.NewChatUseCase(chatRepoImpl, scopeUC)
 	chatHandler := chatHttp.NewChatHandler(chatUC)
 
 	attendanceRepoImpl := attendanceRepo.NewAttendanceRepository(db)
-	attendanceUC := attendanceUseCase.NewAttendanceUseCase(attendanceRepoImpl)
+	attendanceUC := attendanceUseCase.NewAttendanceUseCase(attendanceRepoImpl, scopeUC, learningUC)
 	attendanceHandler := attendanceHttp.NewAttendanceHandler(attendanceUC)
 
 	freezeRepoImpl := freezeRepo.NewFreezeRepository(db)
-	freezeUC := freezeUseCase.NewFreezeUseCase(freezeRepoImpl)
+	freezeUC := freezeUseCase.NewFreezeUseCase(freezeRepoImpl, scopeUC)
 	freezeHandler := freezeHttp.NewFreezeHandler(freezeUC)
 
+	extensionRepoImpl := extensionRepo.NewExtensionRepository(db)
+	extensionUC := extensionUseCase.NewExtensionUseCase(extensionRepoImpl, scopeUC)
+	extensionHandler := extensionHttp.NewExtensionHandler(extensionUC)
+
 	commentRepoImpl := commentRepo.NewCommentRepository(db)
-	commentUC := commentUseCase.NewCommentUseCase(commentRepoImpl)
+	commentUC := commentUseCase.NewCommentUseCase(commentRepoImpl, scopeUC)
 	commentHandler := commentHttp.NewCommentHandler(commentUC)
 
 	notificationRepoImpl := notificationRepo.NewNotificationRepository(db)
@@ -230,17 +283,24 @@ func main() {
 	bannerUC := bannerUseCase.NewBannerUseCase(bannerRepoImpl)
 	bannerHandler := bannerHttp.NewBannerHandler(bannerUC)
 
-	auditRepoImpl := auditRepo.NewAuditRepository(db)
-	_ = auditUseCase.NewAuditUseCase(auditRepoImpl)
-
 	statisticsRepoImpl := statisticsRepo.NewStatisticsRepository(db)
-	statisticsUC := statisticsUseCase.NewStatisticsUseCase(statisticsRepoImpl)
+	statisticsUC := statisticsUseCase.NewStatisticsUseCase(statisticsRepoImpl, scopeUC)
 	statisticsHandler := statisticsHttp.NewStatisticsHandler(statisticsUC)
 
 	groupsRepoImpl := groupsRepo.NewGroupRepository(db)
-	groupsUC := groupsUseCase.NewGroupUseCase(groupsRepoImpl)
+	groupsUC := groupsUseCase.NewGroupUseCase(groupsRepoImpl, scopeUC)
 	groupsHandler := groupsHttp.NewGroupHandler(groupsUC)
 
+	parentRepoImpl := parentRepo.NewParentRepository(db)
+	parentUC := parentUseCase.NewParentUseCase(parentRepoImpl, scopeUC, scheduleUC, attendanceUC, freezeUC, commentUC)
+	parentHandler := parentHttp.NewParentHandler(parentUC)
+
+	dashboardRepository := dashboardRepo.NewCachedDashboardRepo(
+		dashboardRepo.NewDashboardRepository(db), rdb,
+	)
+	dashboardUsecase := dashboardUseCase.NewDashboardUseCase(dashboardRepository, parentUC)
+	dashboardHandler := dashboardHttp.NewDashboardHandler(dashboardUsecase)
+
 	reportsServiceImpl := reportsService.NewReportsService(db)
 	reportsHandler := reportsHttp.NewReportsHandler(reportsServiceImpl)
 
@@ -277,6 +337,7 @@ func main() {
 		})
 	})
 
+	r.Use(authMiddleware.ClientIPMiddleware(trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
@@ -284,196 +345,223 @@ func main() {
 
 	r.Post("/auth/register", authHandler.Register)
 	r.Post("/auth/login", authHandler.Login)
+	r.Post("/auth/login/2fa", authHandler.LoginMFA)
 	r.Post("/auth/logout", authHandler.Logout)
 	r.Post("/auth/forgot-password", authHandler.ForgotPassword)
 	r.Post("/auth/reset-password", authHandler.ResetPassword)
 
 	r.Post("/api/auth/register", authHandler.Register)
 	r.Post("/api/auth/login", authHandler.Login)
+	r.Post("/api/auth/login/2fa", authHandler.LoginMFA)
 	r.Post("/api/auth/logout", authHandler.Logout)
+	r.Post("/api/auth/refresh", authHandler.Refresh)
 	r.Post("/api/auth/forgot-password", authHandler.ForgotPassword)
 	r.Post("/api/auth/reset-password", authHandler.ResetPassword)
 
-	r.Post("/system/reset-password", func(w http.ResponseWriter, r *http.Request) {
-		secret := os.Getenv("SYSTEM_SECRET")
-		if secret == "" || r.Header.Get("X-System-Secret") != secret {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
-
-		var body struct {
-			Email    string `json:"email"`
-			Password string `json:"password"`
-		}
-		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-			http.Error(w, "Bad Request", http.StatusBadRequest)
-			return
-		}
-
-		hash, err := bcrypt.GenerateFromPassword([]byte(body.Password), 12)
-		if err != nil {
-			http.Error(w, "Hash Error", http.StatusInternalServerError)
-			return
-		}
-
-		res, err := db.Exec("UPDATE users SET password_hash=$1 WHERE email=$2", string(hash), body.Email)
-		if err != nil {
-			httperror.Internal(w, err)
-			return
-		}
-
-		affected, _ := res.RowsAffected()
-		if affected == 0 {
-			_, err = db.Exec(`INSERT INTO users (id, first_name, last_name, email, password_hash, role)
-				VALUES (gen_random_uuid(), 'Root', 'Admin', $1, $2, 'admin')`, body.Email, string(hash))
-			if err != nil {
-				httperror.Internal(w, err)
-				return
-			}
-			w.WriteHeader(http.StatusCreated)
-			w.Write([]byte("User Created and Password Set"))
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("Password Updated Successfully"))
-	})
+	r.Get("/certificates/verify/{code}", learningHandler.VerifyCertificate)
+	r.Get("/api/certificates/verify/{code}", learningHandler.VerifyCertificate)
 
+	// Staff-маршруты: роль отсекает не-сотрудников, а доступ к конкретному маршруту решает право из role_permissions.
 	r.Group(func(r chi.Router) {
-		r.Use(authMiddleware.AuthMiddleware, authMiddleware.RoleRequiredMiddleware(domain.RoleAdmin, domain.RoleTeacher, domain.RoleModerator, domain.RoleCurator))
-
-		r.Get("/admin/dashboard/stats", dashboardHandler.GetAdminDashboard)
-		r.Get("/admin/curator/dashboard", dashboardHandler.GetCuratorDashboard)
-		r.Get("/admin/courses", adminHandler.GetAllCourses)
-		r.Post("/admin/courses", adminHandler.CreateCourse)
-		r.Put("/admin/courses/{id}/settings", adminHandler.UpdateCourseSettings)
-		r.Get("/admin/courses/{id}/structure", adminHandler.GetCourseStructure)
-		r.Get("/admin/courses/{id}/students", adminHandler.GetCourseStudents)
-		r.Get("/admin/courses/{id}/stats", adminHandler.GetCourseStats)
-		r.Post("/admin/modules", adminHandler.CreateModule)
-		r.Delete("/admin/modules/{id}", adminHandler.DeleteModule)
-		r.Get("/admin/tests/{id}", adminHandler.GetTest)
-		r.Get("/admin/projects/{id}", adminHandler.GetProject)
-		r.Post("/admin/lessons", adminHandler.CreateLesson)
-		r.Get("/admin/lessons/{id}", adminHandler.GetLesson)
-		r.Put("/admin/lessons/{id}", adminHandler.UpdateLesson)
-		r.Delete("/admin/lessons/{id}", adminHandler.DeleteLesson)
-		r.Post("/admin/lessons/{id}/cancel", adminHandler.CancelLesson)
-		r.Post("/admin/lessons/{id}/substitute", adminHandler.SubstituteTeacher)
-		r.Post("/admin/modules/bulk", adminHandler.CreateModulesBulk)
-		r.Post("/admin/lessons/bulk", adminHandler.CreateLessonsBulk)
-		r.Post("/admin/tests", adminHandler.CreateTest)
-		r.Delete("/admin/tests/{id}", adminHandler.DeleteTest)
-		r.Post("/admin/projects", adminHandler.CreateProject)
-		r.Delete("/admin/projects/{id}", adminHandler.DeleteProject)
-		r.Post("/admin/media/upload", adminHandler.UploadMedia)
-		r.Get("/admin/users", adminHandler.GetUsersList)
-		r.Get("/admin/users/{id}", adminHandler.GetUserInfo)
-		r.Post("/admin/users", adminHandler.CreateUser)
-		r.Put("/admin/users/{id}", adminHandler.UpdateUser)
-		r.Delete("/admin/users/{id}", adminHandler.DeleteUser)
-		r.Post("/admin/enroll", adminHandler.EnrollUser)
-		r.Delete("/admin/courses/{id}/enroll/{user_id}", adminHandler.UnenrollStudent)
-		r.Get("/admin/users/all", adminHandler.GetAllUsersTable)
-		r.Get("/admin/students/detailed", adminHandler.GetDetailedStudents)
-		r.Get("/admin/teachers/detailed", adminHandler.GetDetailedTeachers)
-		r.Get("/admin/teachers/{id}", adminHandler.GetUserInfo)
-		r.Get("/admin/curators/detailed", adminHandler.GetDetailedCurators)
-		r.Get("/admin/moderators/detailed", adminHandler.GetDetailedModerators)
-		r.Post("/admin/streams", adminHandler.CreateStream)
-		r.Get("/admin/streams", adminHandler.GetStreams)
-		r.Post("/admin/groups", adminHandler.CreateGroup)
-		r.Get("/admin/groups", adminHandler.GetGroups)
-		r.Post("/admin/courses/bulk", adminHandler.CreateFullCourse)
-
-		r.Get("/api/admin/dashboard/stats", dashboardHandler.GetAdminDashboard)
-		r.Get("/api/admin/curator/dashboard", dashboardHandler.GetCuratorDashboard)
-		r.Get("/api/admin/courses", adminHandler.GetAllCourses)
-		r.Post("/api/admin/courses", adminHandler.CreateCourse)
-		r.Put("/api/admin/courses/{id}/settings", adminHandler.UpdateCourseSettings)
-		r.Get("/api/admin/courses/{id}/structure", adminHandler.GetCourseStructure)
-		r.Get("/api/admin/courses/{id}/students", adminHandler.GetCourseStudents)
-		r.Get("/api/admin/courses/{id}/stats", adminHandler.GetCourseStats)
-		r.Post("/api/admin/modules", adminHandler.CreateModule)
-		r.Delete("/api/admin/modules/{id}", adminHandler.DeleteModule)
-		r.Get("/api/admin/tests/{id}", adminHandler.GetTest)
-		r.Get("/api/admin/projects/{id}", adminHandler.GetProject)
-		r.Post("/api/admin/lessons", adminHandler.CreateLesson)
-		r.Get("/api/admin/lessons/{id}", adminHandler.GetLesson)
-		r.Put("/api/admin/lessons/{id}", adminHandler.UpdateLesson)
-		r.Delete("/api/admin/lessons/{id}", adminHandler.DeleteLesson)
-		r.Post("/api/admin/lessons/{id}/cancel", adminHandler.CancelLesson)
-		r.Post("/api/admin/lessons/{id}/substitute", adminHandler.SubstituteTeacher)
-		r.Post("/api/admin/modules/bulk", adminHandler.CreateModulesBulk)
-		r.Post("/api/admin/lessons/bulk", adminHandler.CreateLessonsBulk)
-		r.Post("/api/admin/tests", adminHandler.CreateTest)
-		r.Delete("/api/admin/tests/{id}", adminHandler.DeleteTest)
-		r.Post("/api/admin/projects", adminHandler.CreateProject)
-		r.Delete("/api/admin/projects/{id}", adminHandler.DeleteProject)
-		r.Post("/api/admin/media/upload", adminHandler.UploadMedia)
-		r.Get("/api/admin/users", adminHandler.GetUsersList)
-		r.Get("/api/admin/users/{id}", adminHandler.GetUserInfo)
-		r.Post("/api/admin/users", adminHandler.CreateUser)
-		r.Put("/api/admin/users/{id}", adminHandler.UpdateUser)
-		r.Delete("/api/admin/users/{id}", adminHandler.DeleteUser)
-		r.Post("/api/admin/enroll", adminHandler.EnrollUser)
-		r.Delete("/api/admin/courses/{id}/enroll/{user_id}", adminHandler.UnenrollStudent)
-		r.Get("/api/admin/users/all", adminHandler.GetAllUsersTable)
-		r.Get("/api/admin/students/detailed", adminHandler.GetDetailedStudents)
-		r.Get("/api/admin/teachers/detailed", adminHandler.GetDetailedTeachers)
-		r.Get("/api/admin/teachers/{id}", adminHandler.GetUserInfo)
-		r.Get("/api/admin/curators/detailed", adminHandler.GetDetailedCurators)
-		r.Get("/api/admin/moderators/detailed", adminHandler.GetDetailedModerators)
-		r.Post("/api/admin/streams", adminHandler.CreateStream)
-		r.Get("/api/admin/streams", adminHandler.GetStreams)
-		r.Post("/api/admin/groups", adminHandler.CreateGroup)
-		r.Get("/api/admin/groups", adminHandler.GetGroups)
-		r.Post("/api/admin/courses/bulk", adminHandler.CreateFullCourse)
-
-		r.Patch("/api/groups/{groupId}", groupsHandler.UpdateGroup)
-		r.Post("/api/groups/{groupId}/students", groupsHandler.AddStudentToGroup)
-		r.Delete("/api/groups/{groupId}/students/{studentId}", groupsHandler.RemoveStudentFromGroup)
-		r.Patch("/api/students/{studentId}/group", groupsHandler.ChangeStudentGroup)
-		r.Patch("/api/teachers/{teacherId}/group", groupsHandler.ChangeTeacherGroup)
-
-		r.Get("/api/attendance/students/{studentId}/calendar", attendanceHandler.GetStudentCalendar)
-		r.Patch("/api/attendance/lessons/{lessonId}", attendanceHandler.MarkLessonAttendance)
-		r.Get("/api/attendance/students/{studentId}/stats", attendanceHandler.GetStudentStats)
-		r.Get("/api/attendance/lessons/{lessonId}", attendanceHandler.GetLessonAttendance)
-
-		r.Post("/api/freeze-requests", freezeHandler.CreateFreezeRequest)
-		r.Get("/api/freeze-requests", freezeHandler.GetPendingRequests)
-		r.Patch("/api/freeze-requests/{requestId}/approve", freezeHandler.ApproveRequest)
-		r.Patch("/api/freeze-requests/{requestId}/reject", freezeHandler.RejectRequest)
-		r.Get("/api/students/{studentId}/freeze-status", freezeHandler.GetStudentFreezeStatus)
-
-		r.Post("/api/comments", commentHandler.CreateComment)
-		r.Get("/api/comments", commentHandler.GetComments)
-		r.Patch("/api/comments/{commentId}/read", commentHandler.MarkCommentAsRead)
-
-		r.Post("/api/notifications", notificationHandler.CreateNotification)
-
-		r.Get("/api/access-requests", accessHandler.GetPendingRequests)
-		r.Patch("/api/access-requests/{requestId}/approve", accessHandler.ApproveRequest)
-		r.Patch("/api/access-requests/{requestId}/reject", accessHandler.RejectRequest)
-
-		r.Get("/api/statistics/students/{studentId}", statisticsHandler.GetStudentStatistics)
-		r.Post("/api/statistics/students/{studentId}/refresh", statisticsHandler.RefreshStudentStatistics)
-
-		r.Get("/api/reports/lessons.xlsx", reportsHandler.DownloadLessonsReport)
-
-		r.Post("/api/admin/banner", bannerHandler.CreateBanner)
-		r.Patch("/api/admin/banner/{bannerId}", bannerHandler.UpdateBanner)
-		r.Delete("/api/admin/banner/{bannerId}", bannerHandler.DeleteBanner)
+		r.Use(authMiddleware.AuthMiddleware(tokenManager, authUsecase), authMiddleware.RoleRequiredMiddleware(domain.RoleAdmin, domain.RoleTeacher, domain.RoleModerator, domain.RoleCurator))
+
+		r.With(perm(domain.PermDashboardView)).Get("/admin/dashboard/stats", dashboardHandler.GetAdminDashboard)
+		r.With(perm(domain.PermDashboardView)).Get("/admin/curator/dashboard", dashboardHandler.GetCuratorDashboard)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/courses", adminHandler.GetAllCourses)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/courses", adminHandler.CreateCourse)
+		r.With(perm(domain.PermCoursesEdit)).Put("/admin/courses/{id}/settings", adminHandler.UpdateCourseSettings)
+		r.With(perm(domain.PermCoursesEdit)).Put("/admin/courses/{id}/grading-scheme", adminHandler.UpdateGradeScheme)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/courses/{id}/structure", adminHandler.GetCourseStructure)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/courses/{id}/students", adminHandler.GetCourseStudents)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/courses/{id}/stats", adminHandler.GetCourseStats)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/modules", adminHandler.CreateModule)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/admin/modules/{id}", adminHandler.DeleteModule)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/tests/{id}", adminHandler.GetTest)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/projects/{id}", adminHandler.GetProject)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/projects/{id}/rubric", adminHandler.GetProjectRubric)
+		r.With(perm(domain.PermCoursesEdit)).Put("/admin/projects/{id}/rubric", adminHandler.SetProjectRubric)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/lessons/{id}/rubric", adminHandler.GetLessonRubric)
+		r.With(perm(domain.PermCoursesEdit)).Put("/admin/lessons/{id}/rubric", adminHandler.SetLessonRubric)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/lessons/{id}/deadline", adminHandler.GetLessonDeadline)
+		r.With(perm(domain.PermCoursesEdit)).Put("/admin/lessons/{id}/deadline", adminHandler.SetLessonDeadline)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/lessons/{id}/unlocks", adminHandler.UnlockLesson)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/admin/lessons/{id}/unlocks/{studentId}", adminHandler.RelockLesson)
+		r.With(perm(domain.PermCertificatesRevoke)).Post("/admin/certificates/{code}/revoke", learningHandler.RevokeCertificate)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/lessons", adminHandler.CreateLesson)
+		r.With(perm(domain.PermCoursesView)).Get("/admin/lessons/{id}", adminHandler.GetLesson)
+		r.With(perm(domain.PermCoursesEdit)).Put("/admin/lessons/{id}", adminHandler.UpdateLesson)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/admin/lessons/{id}", adminHandler.DeleteLesson)
+		r.With(perm(domain.PermScheduleManage)).Post("/admin/lessons/{id}/cancel", adminHandler.CancelLesson)
+		r.With(perm(domain.PermScheduleManage)).Post("/admin/lessons/{id}/substitute", adminHandler.SubstituteTeacher)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/modules/bulk", adminHandler.CreateModulesBulk)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/lessons/bulk", adminHandler.CreateLessonsBulk)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/tests", adminHandler.CreateTest)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/admin/tests/{id}", adminHandler.DeleteTest)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/projects", adminHandler.CreateProject)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/admin/projects/{id}", adminHandler.DeleteProject)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/media/upload", adminHandler.UploadMedia)
+		r.With(perm(domain.PermUsersView)).Get("/admin/users", adminHandler.GetUsersList)
+		r.With(perm(domain.PermUsersView)).Get("/admin/users/{id}", adminHandler.GetUserInfo)
+		r.With(perm(domain.PermUsersCreate)).Post("/admin/users", adminHandler.CreateUser)
+		r.With(perm(domain.PermUsersEdit)).Put("/admin/users/{id}", adminHandler.UpdateUser)
+		r.With(perm(domain.PermUsersDelete)).Delete("/admin/users/{id}", adminHandler.DeleteUser)
+		r.With(perm(domain.PermUsersSessions)).Delete("/admin/users/{id}/sessions", authHandler.RevokeUserSessions)
+		r.With(perm(domain.PermSecurityManage)).Get("/admin/security/2fa-roles", authHandler.GetMFAPolicies)
+		r.With(perm(domain.PermSecurityManage)).Put("/admin/security/2fa-roles/{role}", authHandler.SetMFAPolicy)
+		r.With(perm(domain.PermPermissionsManage)).Get("/admin/permissions", permissionHandler.GetPermissions)
+		r.With(perm(domain.PermPermissionsManage)).Put("/admin/permissions/roles/{role}", permissionHandler.UpdateRolePermissions)
+		r.With(perm(domain.PermEnrollmentManage)).Post("/admin/enroll", adminHandler.EnrollUser)
+		r.With(perm(domain.PermEnrollmentManage)).Delete("/admin/courses/{id}/enroll/{user_id}", adminHandler.UnenrollStudent)
+		r.With(perm(domain.PermUsersView)).Get("/admin/users/all", adminHandler.GetAllUsersTable)
+		r.With(perm(domain.PermUsersView)).Get("/admin/students/detailed", adminHandler.GetDetailedStudents)
+		r.With(perm(domain.PermUsersView)).Get("/admin/teachers/detailed", adminHandler.GetDetailedTeachers)
+		r.With(perm(domain.PermUsersView)).Get("/admin/teachers/{id}", adminHandler.GetUserInfo)
+		r.With(perm(domain.PermUsersView)).Get("/admin/curators/detailed", adminHandler.GetDetailedCurators)
+		r.With(perm(domain.PermUsersView)).Get("/admin/moderators/detailed", adminHandler.GetDetailedModerators)
+		r.With(perm(domain.PermGroupsManage)).Post("/admin/streams", adminHandler.CreateStream)
+		r.With(perm(domain.PermGroupsView)).Get("/admin/streams", adminHandler.GetStreams)
+		r.With(perm(domain.PermGroupsManage)).Post("/admin/groups", adminHandler.CreateGroup)
+		r.With(perm(domain.PermGroupsView)).Get("/admin/groups", adminHandler.GetGroups)
+		r.With(perm(domain.PermCoursesEdit)).Post("/admin/courses/bulk", adminHandler.CreateFullCourse)
+
+		r.With(perm(domain.PermDashboardView)).Get("/api/admin/dashboard/stats", dashboardHandler.GetAdminDashboard)
+		r.With(perm(domain.PermDashboardView)).Get("/api/admin/curator/dashboard", dashboardHandler.GetCuratorDashboard)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/courses", adminHandler.GetAllCourses)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/courses", adminHandler.CreateCourse)
+		r.With(perm(domain.PermCoursesEdit)).Put("/api/admin/courses/{id}/settings", adminHandler.UpdateCourseSettings)
+		r.With(perm(domain.PermCoursesEdit)).Put("/api/admin/courses/{id}/grading-scheme", adminHandler.UpdateGradeScheme)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/courses/{id}/structure", adminHandler.GetCourseStructure)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/courses/{id}/students", adminHandler.GetCourseStudents)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/courses/{id}/stats", adminHandler.GetCourseStats)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/modules", adminHandler.CreateModule)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/api/admin/modules/{id}", adminHandler.DeleteModule)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/tests/{id}", adminHandler.GetTest)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/projects/{id}", adminHandler.GetProject)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/projects/{id}/rubric", adminHandler.GetProjectRubric)
+		r.With(perm(domain.PermCoursesEdit)).Put("/api/admin/projects/{id}/rubric", adminHandler.SetProjectRubric)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/lessons/{id}/rubric", adminHandler.GetLessonRubric)
+		r.With(perm(domain.PermCoursesEdit)).Put("/api/admin/lessons/{id}/rubric", adminHandler.SetLessonRubric)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/lessons/{id}/deadline", adminHandler.GetLessonDeadline)
+		r.With(perm(domain.PermCoursesEdit)).Put("/api/admin/lessons/{id}/deadline", adminHandler.SetLessonDeadline)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/lessons/{id}/unlocks", adminHandler.UnlockLesson)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/api/admin/lessons/{id}/unlocks/{studentId}", adminHandler.RelockLesson)
+		r.With(perm(domain.PermCertificatesRevoke)).Post("/api/admin/certificates/{code}/revoke", learningHandler.RevokeCertificate)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/lessons", adminHandler.CreateLesson)
+		r.With(perm(domain.PermCoursesView)).Get("/api/admin/lessons/{id}", adminHandler.GetLesson)
+		r.With(perm(domain.PermCoursesEdit)).Put("/api/admin/lessons/{id}", adminHandler.UpdateLesson)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/api/admin/lessons/{id}", adminHandler.DeleteLesson)
+		r.With(perm(domain.PermScheduleManage)).Post("/api/admin/lessons/{id}/cancel", adminHandler.CancelLesson)
+		r.With(perm(domain.PermScheduleManage)).Post("/api/admin/lessons/{id}/substitute", adminHandler.SubstituteTeacher)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/modules/bulk", adminHandler.CreateModulesBulk)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/lessons/bulk", adminHandler.CreateLessonsBulk)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/tests", adminHandler.CreateTest)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/api/admin/tests/{id}", adminHandler.DeleteTest)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/projects", adminHandler.CreateProject)
+		r.With(perm(domain.PermCoursesEdit)).Delete("/api/admin/projects/{id}", adminHandler.DeleteProject)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/media/upload", adminHandler.UploadMedia)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/users", adminHandler.GetUsersList)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/users/{id}", adminHandler.GetUserInfo)
+		r.With(perm(domain.PermUsersCreate)).Post("/api/admin/users", adminHandler.CreateUser)
+		r.With(perm(domain.PermUsersEdit)).Put("/api/admin/users/{id}", adminHandler.UpdateUser)
+		r.With(perm(domain.PermUsersDelete)).Delete("/api/admin/users/{id}", adminHandler.DeleteUser)
+		r.With(perm(domain.PermUsersSessions)).Delete("/api/admin/users/{id}/sessions", authHandler.RevokeUserSessions)
+		r.With(perm(domain.PermSecurityManage)).Get("/api/admin/security/2fa-roles", authHandler.GetMFAPolicies)
+		r.With(perm(domain.PermSecurityManage)).Put("/api/admin/security/2fa-roles/{role}", authHandler.SetMFAPolicy)
+		r.With(perm(domain.PermPermissionsManage)).Get("/api/admin/permissions", permissionHandler.GetPermissions)
+		r.With(perm(domain.PermPermissionsManage)).Put("/api/admin/permissions/roles/{role}", permissionHandler.UpdateRolePermissions)
+		r.With(perm(domain.PermEnrollmentManage)).Post("/api/admin/enroll", adminHandler.EnrollUser)
+		r.With(perm(domain.PermEnrollmentManage)).Delete("/api/admin/courses/{id}/enroll/{user_id}", adminHandler.UnenrollStudent)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/users/all", adminHandler.GetAllUsersTable)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/students/detailed", adminHandler.GetDetailedStudents)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/teachers/detailed", adminHandler.GetDetailedTeachers)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/teachers/{id}", adminHandler.GetUserInfo)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/curators/detailed", adminHandler.GetDetailedCurators)
+		r.With(perm(domain.PermUsersView)).Get("/api/admin/moderators/detailed", adminHandler.GetDetailedModerators)
+		r.With(perm(domain.PermGroupsManage)).Post("/api/admin/streams", adminHandler.CreateStream)
+		r.With(perm(domain.PermGroupsView)).Get("/api/admin/streams", adminHandler.GetStreams)
+		r.With(perm(domain.PermGroupsManage)).Post("/api/admin/groups", adminHandler.CreateGroup)
+		r.With(perm(domain.PermGroupsView)).Get("/api/admin/groups", adminHandler.GetGroups)
+		r.With(perm(domain.PermCoursesEdit)).Post("/api/admin/courses/bulk", adminHandler.CreateFullCourse)
+
+		r.With(perm(domain.PermGroupsManage)).Patch("/api/groups/{groupId}", groupsHandler.UpdateGroup)
+		r.With(perm(domain.PermGroupsManage)).Post("/api/groups/{groupId}/students", groupsHandler.AddStudentToGroup)
+		r.With(perm(domain.PermGroupsManage)).Delete("/api/groups/{groupId}/students/{studentId}", groupsHandler.RemoveStudentFromGroup)
+		r.With(perm(domain.PermGroupsManage)).Patch("/api/students/{studentId}/group", groupsHandler.ChangeStudentGroup)
+		r.With(perm(domain.PermGroupsManage)).Patch("/api/teachers/{teacherId}/group", groupsHandler.ChangeTeacherGroup)
+
+		r.With(perm(domain.PermAttendanceView)).Get("/api/attendance/students/{studentId}/calendar", attendanceHandler.GetStudentCalendar)
+		r.With(perm(domain.PermAttendanceMark)).Patch("/api/attendance/lessons/{lessonId}", attendanceHandler.MarkLessonAttendance)
+		r.With(perm(domain.PermAttendanceView)).Get("/api/attendance/students/{studentId}/stats", attendanceHandler.GetStudentStats)
+		r.With(perm(domain.PermAttendanceView)).Get("/api/attendance/lessons/{lessonId}", attendanceHandler.GetLessonAttendance)
+
+		r.With(perm(domain.PermFreezeRequest)).Post("/api/freeze-requests", freezeHandler.CreateFreezeRequest)
+		r.With(perm(domain.PermFreezeApprove)).Get("/api/freeze-requests", freezeHandler.GetPendingRequests)
+		r.With(perm(domain.PermFreezeApprove)).Patch("/api/freeze-requests/{requestId}/approve", freezeHandler.ApproveRequest)
+		r.With(perm(domain.PermFreezeApprove)).Patch("/api/freeze-requests/{requestId}/reject", freezeHandler.RejectRequest)
+
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/extension-requests", extensionHandler.GetPendingRequests)
+		r.With(perm(domain.PermSubmissionsReview)).Patch("/api/extension-requests/{requestId}/approve", extensionHandler.ApproveRequest)
+		r.With(perm(domain.PermSubmissionsReview)).Patch("/api/extension-requests/{requestId}/reject", extensionHandler.RejectRequest)
+
+		r.With(perm(domain.PermCommentsManage)).Post("/api/comments", commentHandler.CreateComment)
+		r.With(perm(domain.PermCommentsManage)).Get("/api/comments", commentHandler.GetComments)
+		r.With(perm(domain.PermCommentsManage)).Patch("/api/comments/{commentId}/read", commentHandler.MarkCommentAsRead)
+
+		r.With(perm(domain.PermNotificationsSend)).Post("/api/notifications", notificationHandler.CreateNotification)
+
+		r.With(perm(domain.PermAccessApprove)).Get("/api/access-requests", accessHandler.GetPendingRequests)
+		r.With(perm(domain.PermAccessApprove)).Patch("/api/access-requests/{requestId}/approve", accessHandler.ApproveRequest)
+		r.With(perm(domain.PermAccessApprove)).Patch("/api/access-requests/{requestId}/reject", accessHandler.RejectRequest)
+
+		r.With(perm(domain.PermStatisticsView)).Post("/api/statistics/students/{studentId}/refresh", statisticsHandler.RefreshStudentStatistics)
+
+		r.With(perm(domain.PermReportsExport)).Get("/api/reports/lessons.xlsx", reportsHandler.DownloadLessonsReport)
+
+		r.With(perm(domain.PermBannersEdit)).Post("/api/admin/banner", bannerHandler.CreateBanner)
+		r.With(perm(domain.PermBannersEdit)).Patch("/api/admin/banner/{bannerId}", bannerHandler.UpdateBanner)
+		r.With(perm(domain.PermBannersEdit)).Delete("/api/admin/banner/{bannerId}", bannerHandler.DeleteBanner)
+
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/submissions", reviewHandler.GetPendingSubmissions)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/staff/submissions/{id}/evaluate", reviewHandler.EvaluateSubmission)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/submissions", reviewHandler.GetPendingSubmissions)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/api/staff/submissions/{id}/evaluate", reviewHandler.EvaluateSubmission)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/submissions/{id}/versions", reviewHandler.GetSubmissionHistory)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/submissions/{id}/diff", reviewHandler.DiffSubmission)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/submissions/{id}/similarity", reviewHandler.GetSimilarityReport)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/submissions/{id}/versions", reviewHandler.GetSubmissionHistory)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/submissions/{id}/diff", reviewHandler.DiffSubmission)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/submissions/{id}/similarity", reviewHandler.GetSimilarityReport)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/submissions/{id}/thread", reviewHandler.GetSubmissionThread)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/staff/submissions/{id}/thread", reviewHandler.AddSubmissionComment)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/staff/submissions/{id}/thread/read", reviewHandler.MarkSubmissionThreadRead)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/submissions/{id}/thread", reviewHandler.GetSubmissionThread)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/api/staff/submissions/{id}/thread", reviewHandler.AddSubmissionComment)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/api/staff/submissions/{id}/thread/read", reviewHandler.MarkSubmissionThreadRead)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/staff/project-submissions/{id}/evaluate", reviewHandler.EvaluateProjectSubmission)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/api/staff/project-submissions/{id}/evaluate", reviewHandler.EvaluateProjectSubmission)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/test-attempts", reviewHandler.GetPendingTestAttempts)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/staff/test-attempts/{id}", reviewHandler.GetTestAttempt)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/staff/test-attempts/{id}/grade", reviewHandler.GradeTestAttempt)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/test-attempts", reviewHandler.GetPendingTestAttempts)
+		r.With(perm(domain.PermSubmissionsReview)).Get("/api/staff/test-attempts/{id}", reviewHandler.GetTestAttempt)
+		r.With(perm(domain.PermSubmissionsReview)).Post("/api/staff/test-attempts/{id}/grade", reviewHandler.GradeTestAttempt)
+	})
 
-		r.Get("/staff/submissions", reviewHandler.GetPendingSubmissions)
-		r.Post("/staff/submissions/{id}/evaluate", reviewHandler.EvaluateSubmission)
-		r.Get("/api/staff/submissions", reviewHandler.GetPendingSubmissions)
-		r.Post("/api/staff/submissions/{id}/evaluate", reviewHandler.EvaluateSubmission)
+	// Кабинет родителя: доступ к ребёнку проверяется по активной связи в child_parent_link.
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.AuthMiddleware(tokenManager, authUsecase), authMiddleware.RoleRequiredMiddleware(domain.RoleParent))
+
+		r.Get("/api/parent/children", parentHandler.GetChildren)
+		r.Get("/api/parent/children/{childId}/schedule", parentHandler.GetChildSchedule)
+		r.Get("/api/parent/children/{childId}/attendance", parentHandler.GetChildAttendance)
+		r.Get("/api/parent/children/{childId}/grades", parentHandler.GetChildGrades)
+		r.Get("/api/parent/children/{childId}/freeze", parentHandler.GetChildFreeze)
+		r.Post("/api/parent/children/{childId}/freeze-requests", parentHandler.RequestFreeze)
+		r.Get("/api/parent/children/{childId}/comments", parentHandler.GetChildComments)
 	})
 
 	r.Group(func(r chi.Router) {
-		r.Use(authMiddleware.AuthMiddleware)
+		r.Use(authMiddleware.AuthMiddleware(tokenManager, authUsecase))
 
 		r.Get("/teachers", learningHandler.GetTeachers)
 		r.Get("/teachers/{id}", learningHandler.GetTeacherDetails)
@@ -485,12 +573,21 @@ func main() {
 		r.Get("/courses/{id}", learningHandler.GetCourseContent)
 		r.Get("/lessons/{id}", learningHandler.GetLessonDetail)
 		r.Post("/lessons/{id}/assignment", learningHandler.SubmitAssignment)
+		r.Post("/lessons/{id}/video-progress", learningHandler.RecordVideoProgress)
+		r.Get("/certificates", learningHandler.GetMyCertificates)
+		r.Get("/lessons/{id}/assignment/thread", reviewHandler.GetMySubmissionThread)
+		r.Post("/lessons/{id}/assignment/thread", reviewHandler.AddMySubmissionComment)
+		r.Post("/lessons/{id}/assignment/thread/read", reviewHandler.MarkMySubmissionThreadRead)
+		r.Get("/lessons/{id}/assignment/extension-requests", extensionHandler.GetMyExtensionRequests)
+		r.Post("/lessons/{id}/assignment/extension-requests", extensionHandler.CreateExtensionRequest)
 		r.Post("/lessons/{id}/attendance", learningHandler.SetLessonAttendance)
-		r.Post("/admin/courses/bulk", adminHandler.CreateFullCourse)
 		r.Get("/tests/{id}", learningHandler.GetTest)
+		r.Post("/tests/{id}/start", learningHandler.StartTest)
 		r.Post("/tests/{id}/submit", learningHandler.SubmitTest)
+		r.Get("/tests/{id}/attempts", learningHandler.GetTestAttempts)
 		r.Get("/projects/{id}", learningHandler.GetProject)
 		r.Post("/projects/{id}/submission", learningHandler.SubmitProject)
+		r.Get("/projects/{id}/submissions", learningHandler.GetProjectSubmissions)
 		r.Get("/profile", profileHandler.GetProfile)
 		r.Put("/profile", profileHandler.UpdateProfile)
 		r.Put("/profile/teacher/schedule", profileHandler.UpdateTeacherSchedule)
@@ -512,11 +609,21 @@ func main() {
 		r.Get("/api/courses/{id}", learningHandler.GetCourseContent)
 		r.Get("/api/lessons/{id}", learningHandler.GetLessonDetail)
 		r.Post("/api/lessons/{id}/assignment", learningHandler.SubmitAssignment)
+		r.Post("/api/lessons/{id}/video-progress", learningHandler.RecordVideoProgress)
+		r.Get("/api/certificates", learningHandler.GetMyCertificates)
+		r.Get("/api/lessons/{id}/assignment/thread", reviewHandler.GetMySubmissionThread)
+		r.Post("/api/lessons/{id}/assignment/thread", reviewHandler.AddMySubmissionComment)
+		r.Post("/api/lessons/{id}/assignment/thread/read", reviewHandler.MarkMySubmissionThreadRead)
+		r.Get("/api/lessons/{id}/assignment/extension-requests", extensionHandler.GetMyExtensionRequests)
+		r.Post("/api/lessons/{id}/assignment/extension-requests", extensionHandler.CreateExtensionRequest)
 		r.Post("/api/lessons/{id}/attendance", learningHandler.SetLessonAttendance)
 		r.Get("/api/tests/{id}", learningHandler.GetTest)
+		r.Post("/api/tests/{id}/start", learningHandler.StartTest)
 		r.Post("/api/tests/{id}/submit", learningHandler.SubmitTest)
+		r.Get("/api/tests/{id}/attempts", learningHandler.GetTestAttempts)
 		r.Get("/api/projects/{id}", learningHandler.GetProject)
 		r.Post("/api/projects/{id}/submission", learningHandler.SubmitProject)
+		r.Get("/api/projects/{id}/submissions", learningHandler.GetProjectSubmissions)
 		r.Get("/api/profile", profileHandler.GetProfile)
 		r.Put("/api/profile", profileHandler.UpdateProfile)
 		r.Put("/api/profile/teacher/schedule", profileHandler.UpdateTeacherSchedule)
@@ -525,6 +632,14 @@ func main() {
 		r.Get("/api/chat/ws", chatHandler.ConnectToChat)
 		r.Get("/api/chat/history", chatHandler.GetChatHistory)
 
+		r.Get("/api/auth/sessions", authHandler.GetSessions)
+		r.Delete("/api/auth/sessions", authHandler.RevokeAllSessions)
+		r.Delete("/api/auth/sessions/{id}", authHandler.RevokeSession)
+		r.Post("/api/auth/2fa/setup", authHandler.SetupMFA)
+		r.Post("/api/auth/2fa/confirm", authHandler.ConfirmMFA)
+		r.Post("/api/auth/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+		r.Delete("/api/auth/2fa", authHandler.DisableMFA)
+
 		r.Get("/api/notifications", notificationHandler.GetNotifications)
 		r.Patch("/api/notifications/{notificationId}/read", notificationHandler.MarkNotificationAsRead)
 
@@ -532,6 +647,7 @@ func main() {
 
 		r.Get("/api/banner/active", bannerHandler.GetActiveBanners)
 
+		// Доступны ученику (о себе), родителю (о ребёнке) и сотрудникам в пределах их групп — проверка в usecase.
 		r.Get("/api/students/{studentId}/freeze-status", freezeHandler.GetStudentFreezeStatus)
 		r.Get("/api/statistics/students/{studentId}", statisticsHandler.GetStudentStatistics)
 		r.Get("/api/courses", learningHandler.GetAllCourses)
@@ -22,10 +22,15 @@ func main() {
 		redisAddr = "localhost:6379"
 	}
 
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = "https://platform.capedu.kz/reset-password"
+	}
+
 	eventBroker := broker.NewEventBroker(redisAddr)
 	defer eventBroker.Close()
 
-	notificationWorker := worker.NewNotificationWorker(eventBroker, 5)
+	notificationWorker := worker.NewNotificationWorker(eventBroker, 5, resetURL)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,342 @@
+// Команда admin — аварийный доступ к учётным записям напрямую через БД.
+// Заменяет HTTP-эндпоинт /system/reset-password: запускается только там, где есть доступ
+// к переменным окружения БД (на сервере или в контейнере app), и пишет каждое действие в audit_logs
+// в той же транзакции, что и само изменение: без записи в журнале изменение не применяется.
+//
+//	go run ./cmd/tools/admin create-admin -operator ivan -email root@capedu.kz -first-name Root -last-name Admin
+//	go run ./cmd/tools/admin reset-password -operator ivan -email teacher@capedu.kz
+//	go run ./cmd/tools/admin disable-user -operator ivan -email leaked@capedu.kz -reason "account compromised"
+//	go run ./cmd/tools/admin list-admins -operator ivan
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+
+	auditRepo "lms_backend/internal/audit/repository"
+	auditUseCase "lms_backend/internal/audit/usecase"
+)
+
+const (
+	minPasswordLength = 8
+	// noEntityID — entity_id для действий без конкретной сущности (list-admins).
+	noEntityID = "00000000-0000-0000-0000-000000000000"
+)
+
+type tool struct {
+	db       *sql.DB
+	operator string
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: admin <command> -operator <name> [flags]
+
+Commands:
+  create-admin    -email -first-name -last-name [-password-stdin]
+  reset-password  -email [-password-stdin]
+  disable-user    -email -reason
+  list-admins
+
+Without -password-stdin a random temporary password is generated and printed once.`)
+	os.Exit(2)
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	operator := fs.String("operator", "", "who runs the command (goes to audit_logs)")
+	email := fs.String("email", "", "user email")
+	firstName := fs.String("first-name", "", "first name")
+	lastName := fs.String("last-name", "", "last name")
+	passwordStdin := fs.Bool("password-stdin", false, "read the new password from stdin")
+	reason := fs.String("reason", "", "why the user is disabled")
+	fs.Parse(args)
+
+	if *operator == "" {
+		fail(errors.New("-operator is required"))
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fail(err)
+	}
+	defer db.Close()
+
+	t := &tool{
+		db:       db,
+		operator: *operator,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	switch cmd {
+	case "create-admin":
+		err = t.createAdmin(ctx, *email, *firstName, *lastName, *passwordStdin)
+	case "reset-password":
+		err = t.resetPassword(ctx, *email, *passwordStdin)
+	case "disable-user":
+		err = t.disableUser(ctx, *email, *reason)
+	case "list-admins":
+		err = t.listAdmins(ctx)
+	default:
+		usage()
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
+
+func openDB() (*sql.DB, error) {
+	connStr := "host=" + os.Getenv("DB_HOST") + " port=" + os.Getenv("DB_PORT") +
+		" user=" + os.Getenv("DB_USER") + " password=" + os.Getenv("DB_PASSWORD") +
+		" dbname=" + os.Getenv("DB_NAME") + " sslmode=disable"
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database is unreachable: %w", err)
+	}
+	return db, nil
+}
+
+// obtainPassword читает пароль из stdin или генерирует временный.
+func obtainPassword(fromStdin bool) (password string, generated bool, err error) {
+	if !fromStdin {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", false, err
+		}
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", false, fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	password = strings.TrimRight(line, "\r\n")
+	if len(password) < minPasswordLength {
+		return "", false, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, false, nil
+}
+
+// logAction пишет действие в audit_logs через db — обычно транзакцию самого действия.
+// user_id пустой: CLI работает в обход учётных записей, поэтому исполнитель фиксируется
+// в new_values вместе с системным пользователем.
+func (t *tool) logAction(ctx context.Context, db auditRepo.DBTX, action, entityID string, details map[string]interface{}) error {
+	details["operator"] = t.operator
+	if u, err := user.Current(); err == nil {
+		details["os_user"] = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		details["host"] = host
+	}
+	ua := "cmd/tools/admin"
+	audit := auditUseCase.NewAuditUseCase(auditRepo.NewAuditRepository(db))
+	if err := audit.LogAction(ctx, nil, action, "USER", entityID, nil, details, nil, &ua); err != nil {
+		return fmt.Errorf("audit log failed, action rolled back: %w", err)
+	}
+	return nil
+}
+
+func (t *tool) findUser(ctx context.Context, email string) (id, role string, err error) {
+	if email == "" {
+		return "", "", errors.New("-email is required")
+	}
+	err = t.db.QueryRowContext(ctx, `SELECT id, role FROM users WHERE email = $1`, email).Scan(&id, &role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", fmt.Errorf("user %s not found", email)
+	}
+	return id, role, err
+}
+
+func revokeSessions(ctx context.Context, tx *sql.Tx, userID string) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE user_sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+func (t *tool) createAdmin(ctx context.Context, email, firstName, lastName string, passwordStdin bool) error {
+	if email == "" || firstName == "" || lastName == "" {
+		return errors.New("-email, -first-name and -last-name are required")
+	}
+	if _, _, err := t.findUser(ctx, email); err == nil {
+		return fmt.Errorf("user %s already exists; use reset-password", email)
+	}
+
+	password, generated, err := obtainPassword(passwordStdin)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	if err != nil {
+		return err
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO users (id, first_name, last_name, email, password_hash, role)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, 'admin')
+		RETURNING id
+	`, firstName, lastName, email, string(hash)).Scan(&id)
+	if err != nil {
+		return err
+	}
+	if err := t.logAction(ctx, tx, "CLI_CREATE_ADMIN", id, map[string]interface{}{"email": email}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Admin %s created (id %s)\n", email, id)
+	if generated {
+		fmt.Printf("Temporary password: %s\n", password)
+	}
+	return nil
+}
+
+func (t *tool) resetPassword(ctx context.Context, email string, passwordStdin bool) error {
+	id, _, err := t.findUser(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	password, generated, err := obtainPassword(passwordStdin)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	if err != nil {
+		return err
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET password_hash = $1 WHERE id = $2`, string(hash), id); err != nil {
+		return err
+	}
+	if err := revokeSessions(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := t.logAction(ctx, tx, "CLI_RESET_PASSWORD", id, map[string]interface{}{"email": email}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Password for %s reset, all sessions revoked\n", email)
+	if generated {
+		fmt.Printf("Temporary password: %s\n", password)
+	}
+	return nil
+}
+
+func (t *tool) disableUser(ctx context.Context, email, reason string) error {
+	if reason == "" {
+		return errors.New("-reason is required")
+	}
+	id, _, err := t.findUser(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE users SET disabled_at = NOW() WHERE id = $1 AND disabled_at IS NULL`, id,
+	); err != nil {
+		return err
+	}
+	if err := revokeSessions(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := t.logAction(ctx, tx, "CLI_DISABLE_USER", id, map[string]interface{}{"email": email, "reason": reason}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	fmt.Printf("User %s disabled, all sessions revoked\n", email)
+	return nil
+}
+
+func (t *tool) listAdmins(ctx context.Context) error {
+	rows, err := t.db.QueryContext(ctx, `
+		SELECT id, email, first_name, last_name, created_at, disabled_at
+		FROM users WHERE role = 'admin'
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tNAME\tCREATED\tSTATUS")
+	count := 0
+	for rows.Next() {
+		var id, email, first, last string
+		var created time.Time
+		var disabled *time.Time
+		if err := rows.Scan(&id, &email, &first, &last, &created, &disabled); err != nil {
+			return err
+		}
+		status := "active"
+		if disabled != nil {
+			status = "disabled " + disabled.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s %s\t%s\t%s\n", id, email, first, last, created.Format("2006-01-02"), status)
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	w.Flush()
+
+	return t.logAction(ctx, t.db, "CLI_LIST_ADMINS", noEntityID, map[string]interface{}{"count": count})
+}
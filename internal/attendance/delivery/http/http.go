@@ -42,6 +42,11 @@ type UpdateAttendanceRequest struct {
 // @Success 200 {array} domain.AttendanceRecord
 // @Router /api/attendance/students/{studentId}/calendar [get]
 func (h *AttendanceHandler) GetStudentCalendar(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := chi.URLParam(r, "studentId")
 
 	startDateStr := r.URL.Query().Get("start_date")
@@ -70,9 +75,9 @@ func (h *AttendanceHandler) GetStudentCalendar(w http.ResponseWriter, r *http.Re
 		endDate = time.Now()
 	}
 
-	records, err := h.uc.GetStudentCalendar(r.Context(), studentID, startDate, endDate)
+	records, err := h.uc.GetStudentCalendar(r.Context(), actor, studentID, startDate, endDate)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -96,16 +101,15 @@ func (h *AttendanceHandler) MarkLessonAttendance(w http.ResponseWriter, r *http.
 		return
 	}
 
-	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
-	if !ok || userCtxData == nil {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userID := userCtxData.UserID
 
-	err := h.uc.MarkAttendance(r.Context(), lessonID, req.StudentID, req.Status, req.Reason, req.Comment, userID)
+	err := h.uc.MarkAttendance(r.Context(), actor, lessonID, req.StudentID, req.Status, req.Reason, req.Comment)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -120,11 +124,16 @@ func (h *AttendanceHandler) MarkLessonAttendance(w http.ResponseWriter, r *http.
 // @Success 200 {object} map[string]int
 // @Router /api/attendance/students/{studentId}/stats [get]
 func (h *AttendanceHandler) GetStudentStats(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := chi.URLParam(r, "studentId")
 
-	stats, err := h.uc.GetStudentStats(r.Context(), studentID)
+	stats, err := h.uc.GetStudentStats(r.Context(), actor, studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -139,11 +148,16 @@ func (h *AttendanceHandler) GetStudentStats(w http.ResponseWriter, r *http.Reque
 // @Success 200 {array} domain.AttendanceRecord
 // @Router /api/attendance/lessons/{lessonId} [get]
 func (h *AttendanceHandler) GetLessonAttendance(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	lessonID := chi.URLParam(r, "lessonId")
 
-	records, err := h.uc.GetLessonAttendance(r.Context(), lessonID)
+	records, err := h.uc.GetLessonAttendance(r.Context(), actor, lessonID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -8,26 +8,49 @@ import (
 )
 
 type AttendanceUseCase interface {
-	GetStudentCalendar(ctx context.Context, studentID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error)
-	MarkAttendance(ctx context.Context, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string, markedBy string) error
-	UpdateAttendance(ctx context.Context, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string, updatedBy string) error
-	GetLessonAttendance(ctx context.Context, lessonID string) ([]*domain.AttendanceRecord, error)
-	GetStudentStats(ctx context.Context, studentID string) (map[string]int, error)
+	GetStudentCalendar(ctx context.Context, actor domain.Actor, studentID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error)
+	MarkAttendance(ctx context.Context, actor domain.Actor, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string) error
+	UpdateAttendance(ctx context.Context, actor domain.Actor, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string) error
+	GetLessonAttendance(ctx context.Context, actor domain.Actor, lessonID string) ([]*domain.AttendanceRecord, error)
+	GetStudentStats(ctx context.Context, actor domain.Actor, studentID string) (map[string]int, error)
+}
+
+// ScopeChecker — проверки доступа к ученику и уроку (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	CanAccessLesson(ctx context.Context, actor domain.Actor, lessonID string) error
+	CanAccessLessonStudent(ctx context.Context, actor domain.Actor, lessonID, studentID string) error
+}
+
+// CourseCompleter — проверка завершения курса (реализует learning.LearningUseCase).
+type CourseCompleter interface {
+	CheckLessonCourseCompletion(ctx context.Context, userID, lessonID string)
 }
 
 type attendanceUseCase struct {
-	repo repository.AttendanceRepository
+	repo    repository.AttendanceRepository
+	scope   ScopeChecker
+	courses CourseCompleter
 }
 
-func NewAttendanceUseCase(repo repository.AttendanceRepository) AttendanceUseCase {
-	return &attendanceUseCase{repo: repo}
+// NewAttendanceUseCase — courses == nil означает, что отметка посещения не проверяет завершение курса.
+func NewAttendanceUseCase(repo repository.AttendanceRepository, scope ScopeChecker, courses CourseCompleter) AttendanceUseCase {
+	return &attendanceUseCase{repo: repo, scope: scope, courses: courses}
 }
 
-func (uc *attendanceUseCase) GetStudentCalendar(ctx context.Context, studentID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error) {
+func (uc *attendanceUseCase) GetStudentCalendar(ctx context.Context, actor domain.Actor, studentID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetByStudent(ctx, studentID, startDate, endDate)
 }
 
-func (uc *attendanceUseCase) MarkAttendance(ctx context.Context, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string, markedBy string) error {
+func (uc *attendanceUseCase) MarkAttendance(ctx context.Context, actor domain.Actor, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string) error {
+	if err := uc.scope.CanAccessLessonStudent(ctx, actor, lessonID, studentID); err != nil {
+		return err
+	}
+	markedBy := actor.UserID
+
 	// Проверяем, существует ли уже запись
 	existing, err := uc.repo.GetByLessonAndStudent(ctx, lessonID, studentID)
 	if err != nil {
@@ -40,7 +63,11 @@ func (uc *attendanceUseCase) MarkAttendance(ctx context.Context, lessonID, stude
 		existing.Reason = reason
 		existing.Comment = comment
 		existing.UpdatedBy = &markedBy
-		return uc.repo.Update(ctx, existing)
+		if err := uc.repo.Update(ctx, existing); err != nil {
+			return err
+		}
+		uc.checkCourseCompletion(ctx, lessonID, studentID, status)
+		return nil
 	}
 
 	// Создаём новую запись
@@ -53,10 +80,18 @@ func (uc *attendanceUseCase) MarkAttendance(ctx context.Context, lessonID, stude
 		MarkedBy:  &markedBy,
 		UpdatedBy: &markedBy,
 	}
-	return uc.repo.Create(ctx, record)
+	if err := uc.repo.Create(ctx, record); err != nil {
+		return err
+	}
+	uc.checkCourseCompletion(ctx, lessonID, studentID, status)
+	return nil
 }
 
-func (uc *attendanceUseCase) UpdateAttendance(ctx context.Context, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string, updatedBy string) error {
+func (uc *attendanceUseCase) UpdateAttendance(ctx context.Context, actor domain.Actor, lessonID, studentID string, status domain.AttendanceStatus, reason, comment *string) error {
+	if err := uc.scope.CanAccessLessonStudent(ctx, actor, lessonID, studentID); err != nil {
+		return err
+	}
+	updatedBy := actor.UserID
 	record := &domain.AttendanceRecord{
 		LessonID:  lessonID,
 		StudentID: studentID,
@@ -65,13 +100,30 @@ func (uc *attendanceUseCase) UpdateAttendance(ctx context.Context, lessonID, stu
 		Comment:   comment,
 		UpdatedBy: &updatedBy,
 	}
-	return uc.repo.Update(ctx, record)
+	if err := uc.repo.Update(ctx, record); err != nil {
+		return err
+	}
+	uc.checkCourseCompletion(ctx, lessonID, studentID, status)
+	return nil
 }
 
-func (uc *attendanceUseCase) GetLessonAttendance(ctx context.Context, lessonID string) ([]*domain.AttendanceRecord, error) {
+// checkCourseCompletion — посещение, отмеченное сотрудником, засчитывает урок и может завершить курс.
+func (uc *attendanceUseCase) checkCourseCompletion(ctx context.Context, lessonID, studentID string, status domain.AttendanceStatus) {
+	if uc.courses != nil && status == domain.AttendanceStatusAttended {
+		uc.courses.CheckLessonCourseCompletion(ctx, studentID, lessonID)
+	}
+}
+
+func (uc *attendanceUseCase) GetLessonAttendance(ctx context.Context, actor domain.Actor, lessonID string) ([]*domain.AttendanceRecord, error) {
+	if err := uc.scope.CanAccessLesson(ctx, actor, lessonID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetByLesson(ctx, lessonID)
 }
 
-func (uc *attendanceUseCase) GetStudentStats(ctx context.Context, studentID string) (map[string]int, error) {
+func (uc *attendanceUseCase) GetStudentStats(ctx context.Context, actor domain.Actor, studentID string) (map[string]int, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetStudentStats(ctx, studentID)
 }
@@ -2,29 +2,44 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"lms_backend/internal/attendance/mocks"
 	"lms_backend/internal/attendance/usecase"
 	"lms_backend/internal/domain"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 )
 
 func ptr(s string) *string { return &s }
 
+var teacher = domain.Actor{UserID: "teacher-1", Role: domain.RoleTeacher}
+
+// newUseCase — teacher-1 ведёт lesson-1..3, на которые записаны student-1 и student-2.
+func newUseCase(repo *mocks.AttendanceRepositoryMock, courses usecase.CourseCompleter) usecase.AttendanceUseCase {
+	scopeRepo := scopeMocks.TwoGroups()
+	scopeRepo.TeacherStudents["teacher-1"] = []string{"student-1", "student-2"}
+	scopeRepo.TeacherLessons["teacher-1"] = []string{"lesson-1", "lesson-2", "lesson-3"}
+	scopeRepo.StudentLessons["student-1"] = []string{"lesson-1", "lesson-2", "lesson-3"}
+	scopeRepo.StudentLessons["student-2"] = []string{"lesson-1"}
+	return usecase.NewAttendanceUseCase(repo, scopeUseCase.NewScopeUseCase(scopeRepo), courses)
+}
+
 func TestAttendanceUseCase_MarkAttendance(t *testing.T) {
 	repoMock := mocks.NewAttendanceRepositoryMock()
-	uc := usecase.NewAttendanceUseCase(repoMock)
+	uc := newUseCase(repoMock, nil)
 	ctx := context.Background()
 
 	t.Run("CreateNew", func(t *testing.T) {
-		err := uc.MarkAttendance(ctx, "lesson-1", "student-1",
-			domain.AttendanceStatusAttended, nil, nil, "teacher-1")
+		err := uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1",
+			domain.AttendanceStatusAttended, nil, nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		records, err := uc.GetLessonAttendance(ctx, "lesson-1")
+		records, err := uc.GetLessonAttendance(ctx, teacher, "lesson-1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -38,13 +53,13 @@ func TestAttendanceUseCase_MarkAttendance(t *testing.T) {
 
 	t.Run("UpdateExisting", func(t *testing.T) {
 		reason := "sick"
-		err := uc.MarkAttendance(ctx, "lesson-1", "student-1",
-			domain.AttendanceStatusAbsentExcused, &reason, nil, "teacher-1")
+		err := uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1",
+			domain.AttendanceStatusAbsentExcused, &reason, nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		records, _ := uc.GetLessonAttendance(ctx, "lesson-1")
+		records, _ := uc.GetLessonAttendance(ctx, teacher, "lesson-1")
 		if len(records) != 1 {
 			t.Fatalf("expected 1 record, got %d", len(records))
 		}
@@ -57,15 +72,51 @@ func TestAttendanceUseCase_MarkAttendance(t *testing.T) {
 	})
 }
 
+// completionCalls запоминает, для каких учеников и уроков проверялось завершение курса.
+type completionCalls []string
+
+func (c *completionCalls) CheckLessonCourseCompletion(ctx context.Context, userID, lessonID string) {
+	*c = append(*c, userID+"/"+lessonID)
+}
+
+func TestAttendanceUseCase_CourseCompletion(t *testing.T) {
+	repoMock := mocks.NewAttendanceRepositoryMock()
+	calls := &completionCalls{}
+	uc := newUseCase(repoMock, calls)
+	ctx := context.Background()
+
+	if err := uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1", domain.AttendanceStatusAbsentExcused, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("absence must not complete the course, got %v", *calls)
+	}
+	if err := uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.MarkAttendance(ctx, teacher, "lesson-2", "student-1", domain.AttendanceStatusAttended, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*calls) != 2 || (*calls)[0] != "student-1/lesson-1" || (*calls)[1] != "student-1/lesson-2" {
+		t.Fatalf("expected completion checks for both attended lessons, got %v", *calls)
+	}
+	if err := uc.MarkAttendance(ctx, teacher, "lesson-b", "student-b", domain.AttendanceStatusAttended, nil, nil); !errors.Is(err, domain.ErrOutOfScope) {
+		t.Fatalf("expected ErrOutOfScope, got %v", err)
+	}
+	if len(*calls) != 2 {
+		t.Errorf("out-of-scope mark must not complete the course, got %v", *calls)
+	}
+}
+
 func TestAttendanceUseCase_GetLessonAttendance(t *testing.T) {
 	repoMock := mocks.NewAttendanceRepositoryMock()
-	uc := usecase.NewAttendanceUseCase(repoMock)
+	uc := newUseCase(repoMock, nil)
 	ctx := context.Background()
 
-	uc.MarkAttendance(ctx, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil, "teacher-1")
-	uc.MarkAttendance(ctx, "lesson-1", "student-2", domain.AttendanceStatusAbsentExcused, ptr("sick"), nil, "teacher-1")
+	uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil)
+	uc.MarkAttendance(ctx, teacher, "lesson-1", "student-2", domain.AttendanceStatusAbsentExcused, ptr("sick"), nil)
 
-	records, err := uc.GetLessonAttendance(ctx, "lesson-1")
+	records, err := uc.GetLessonAttendance(ctx, teacher, "lesson-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -76,14 +127,14 @@ func TestAttendanceUseCase_GetLessonAttendance(t *testing.T) {
 
 func TestAttendanceUseCase_GetStudentStats(t *testing.T) {
 	repoMock := mocks.NewAttendanceRepositoryMock()
-	uc := usecase.NewAttendanceUseCase(repoMock)
+	uc := newUseCase(repoMock, nil)
 	ctx := context.Background()
 
-	uc.MarkAttendance(ctx, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil, "teacher-1")
-	uc.MarkAttendance(ctx, "lesson-2", "student-1", domain.AttendanceStatusAttended, nil, nil, "teacher-1")
-	uc.MarkAttendance(ctx, "lesson-3", "student-1", domain.AttendanceStatusAbsentExcused, ptr("sick"), nil, "teacher-1")
+	uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil)
+	uc.MarkAttendance(ctx, teacher, "lesson-2", "student-1", domain.AttendanceStatusAttended, nil, nil)
+	uc.MarkAttendance(ctx, teacher, "lesson-3", "student-1", domain.AttendanceStatusAbsentExcused, ptr("sick"), nil)
 
-	stats, err := uc.GetStudentStats(ctx, "student-1")
+	stats, err := uc.GetStudentStats(ctx, teacher, "student-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -97,13 +148,13 @@ func TestAttendanceUseCase_GetStudentStats(t *testing.T) {
 
 func TestAttendanceUseCase_GetStudentCalendar(t *testing.T) {
 	repoMock := mocks.NewAttendanceRepositoryMock()
-	uc := usecase.NewAttendanceUseCase(repoMock)
+	uc := newUseCase(repoMock, nil)
 	ctx := context.Background()
 
 	now := time.Now()
-	uc.MarkAttendance(ctx, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil, "teacher-1")
+	uc.MarkAttendance(ctx, teacher, "lesson-1", "student-1", domain.AttendanceStatusAttended, nil, nil)
 
-	records, err := uc.GetStudentCalendar(ctx, "student-1", now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	records, err := uc.GetStudentCalendar(ctx, teacher, "student-1", now.Add(-24*time.Hour), now.Add(24*time.Hour))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -111,3 +162,50 @@ func TestAttendanceUseCase_GetStudentCalendar(t *testing.T) {
 		t.Errorf("expected 1 record, got %d", len(records))
 	}
 }
+
+func TestAttendanceUseCase_CrossGroupAccess(t *testing.T) {
+	repoMock := mocks.NewAttendanceRepositoryMock()
+	uc := newUseCase(repoMock, nil)
+	ctx := context.Background()
+	teacherA := domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+	curatorA := domain.Actor{UserID: "curator-a", Role: domain.RoleCurator}
+
+	t.Run("MarkForeignLesson", func(t *testing.T) {
+		err := uc.MarkAttendance(ctx, teacherA, "lesson-b", "student-b", domain.AttendanceStatusAttended, nil, nil)
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+		if len(repoMock.Records) != 0 {
+			t.Error("record must not be created")
+		}
+	})
+
+	t.Run("MarkForeignStudentOnOwnLesson", func(t *testing.T) {
+		err := uc.MarkAttendance(ctx, teacherA, "lesson-a", "student-b", domain.AttendanceStatusAttended, nil, nil)
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("ReadForeignStudent", func(t *testing.T) {
+		now := time.Now()
+		if _, err := uc.GetStudentCalendar(ctx, curatorA, "student-b", now.Add(-time.Hour), now); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("calendar: expected ErrOutOfScope, got %v", err)
+		}
+		if _, err := uc.GetStudentStats(ctx, teacherA, "student-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("stats: expected ErrOutOfScope, got %v", err)
+		}
+		if _, err := uc.GetLessonAttendance(ctx, curatorA, "lesson-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("lesson: expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("OwnGroup", func(t *testing.T) {
+		if err := uc.MarkAttendance(ctx, teacherA, "lesson-a", "student-a", domain.AttendanceStatusAttended, nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.GetStudentStats(ctx, curatorA, "student-a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
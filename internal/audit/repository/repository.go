@@ -13,11 +13,17 @@ type AuditRepository interface {
 	GetRecent(ctx context.Context, limit int) ([]*domain.AuditLog, error)
 }
 
+// DBTX — *sql.DB или *sql.Tx: запись в журнал можно включить в транзакцию самого действия.
+type DBTX interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 type auditRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewAuditRepository(db *sql.DB) AuditRepository {
+func NewAuditRepository(db DBTX) AuditRepository {
 	return &auditRepository{db: db}
 }
 
@@ -2,10 +2,16 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
+	"lms_backend/internal/auth/repository"
 	"lms_backend/internal/auth/usecase"
 	"lms_backend/internal/httperror"
 )
@@ -18,6 +24,69 @@ func NewAuthHandler(uc *usecase.AuthUsecase) *AuthHandler {
 	return &AuthHandler{uc: uc}
 }
 
+func setAuthCookie(w http.ResponseWriter, value string, expiresAt time.Time) {
+	maxAge := int(time.Until(expiresAt) / time.Second)
+	if value == "" {
+		maxAge = -1
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     "auth_token",
+		Value:    value,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+		Path:     "/",
+	})
+}
+
+// refreshCookiePath ограничивает отправку refresh-куки эндпоинтами /api/auth/*.
+const refreshCookiePath = "/api/auth"
+
+func setRefreshCookie(w http.ResponseWriter, value string, expiresAt time.Time) {
+	maxAge := int(time.Until(expiresAt) / time.Second)
+	if value == "" {
+		maxAge = -1
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    value,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+		Path:     refreshCookiePath,
+	})
+}
+
+func clearAuthCookies(w http.ResponseWriter) {
+	expired := time.Now().Add(-1 * time.Hour)
+	setAuthCookie(w, "", expired)
+	setRefreshCookie(w, "", expired)
+}
+
+func writeIssued(w http.ResponseWriter, issued *usecase.IssuedSession, extra map[string]interface{}) {
+	setAuthCookie(w, issued.Token, issued.ExpiresAt)
+	setRefreshCookie(w, issued.RefreshToken, issued.RefreshExpiresAt)
+
+	response := map[string]interface{}{
+		"token":              issued.Token,
+		"expires_at":         issued.ExpiresAt,
+		"refresh_token":      issued.RefreshToken,
+		"refresh_expires_at": issued.RefreshExpiresAt,
+		"session_id":         issued.Session.ID,
+	}
+	for k, v := range extra {
+		response[k] = v
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // Register godoc
 // @Summary Регистрация (ЗАБЛОКИРОВАНО)
 // @Description Данный функционал отключен, пользователи создаются только Администратором.
@@ -33,17 +102,19 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 type LoginRequest struct {
 	Email    string `json:"email" example:"admin@capedu.kz"`
 	Password string `json:"password" example:"capedu123"`
+	DeviceID string `json:"device_id,omitempty" example:"ios-3f2a9c"`
 }
 
 // Login godoc
 // @Summary Вход в систему
-// @Description Ввод email и пароля, возвращает HTTP-Only Cookie.
+// @Description Ввод email и пароля, возвращает HTTP-Only Cookie. Если у пользователя включена 2FA, вместо токенов возвращается mfa_required и mfa_token для POST /api/auth/login/2fa.
 // @Tags Аутентификация
 // @Accept  json
 // @Produce  json
 // @Param   request body LoginRequest true "Данные для входа"
 // @Success 200 {object} map[string]interface{} "message: Login successful, user: {...}"
-// @Failure 401 {object} map[string]string "error: Неправильный логин/пароль"
+// @Failure 401 {object} map[string]string "error: invalid email or password"
+// @Failure 429 {object} map[string]string "Слишком много неудачных попыток, см. заголовок Retry-After"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
@@ -53,55 +124,119 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.uc.Login(r.Context(), req.Email, req.Password)
+	meta := usecase.SessionMeta{
+		DeviceID:  req.DeviceID,
+		UserAgent: r.UserAgent(),
+		IPAddress: authMiddleware.ClientIP(r),
+	}
+
+	user, err := h.uc.Login(r.Context(), req.Email, req.Password, meta)
 	if err != nil {
-		httperror.Unauthorized(w, err)
+		var lockout *usecase.LockoutError
+		switch {
+		case errors.As(err, &lockout):
+			w.Header().Set("Retry-After", strconv.Itoa(int(lockout.RetryAfter.Seconds())+1))
+			http.Error(w, lockout.Error(), http.StatusTooManyRequests)
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			httperror.Unauthorized(w, err)
+		case errors.Is(err, usecase.ErrAccountDisabled):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			httperror.Internal(w, err)
+		}
 		return
 	}
 
-	token := user.ID + ":" + string(user.Role)
+	challenge, err := h.uc.MFAChallenge(r.Context(), user)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	if challenge != "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"mfa_required": true,
+			"mfa_token":    challenge,
+		})
+		return
+	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "auth_token",
-		Value:    token,
-		Expires:  time.Now().Add(24 * time.Hour * 7),
-		MaxAge:   int(24 * 7 * time.Hour / time.Second),
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteNoneMode,
-		Path:     "/",
-	})
-	// -------------------------------------------------------
+	issued, err := h.uc.StartSession(r.Context(), user, meta)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
 
-	response := map[string]interface{}{
+	writeIssued(w, issued, map[string]interface{}{
 		"message": "Login successful",
 		"user":    user,
-		"token":   token,
+	})
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"c2VjcmV0LXJlZnJlc2g"`
+}
+
+// Refresh godoc
+// @Summary Обновление токенов
+// @Description Обменивает refresh-токен (кука refresh_token или поле в теле) на новую пару access/refresh. Каждый refresh-токен одноразовый; повторное предъявление отзывает сессию.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest false "Refresh-токен (для мобильных клиентов)"
+// @Success 200 {object} map[string]interface{} "token, expires_at, refresh_token, refresh_expires_at, session_id"
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+	}
+	if req.RefreshToken == "" {
+		if c, err := r.Cookie("refresh_token"); err == nil {
+			req.RefreshToken = c.Value
+		}
+	}
+	if req.RefreshToken == "" {
+		http.Error(w, "Unauthorized: Refresh token required", http.StatusUnauthorized)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	issued, err := h.uc.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) {
+			clearAuthCookies(w)
+			httperror.Unauthorized(w, err)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
+
+	writeIssued(w, issued, nil)
 }
 
 // Logout godoc
 // @Summary Выход из системы
-// @Description Удаляет HTTP-Only Cookie.
+// @Description Отзывает текущую сессию и удаляет HTTP-Only Cookie.
 // @Tags Аутентификация
 // @Produce json
 // @Success 200 {object} map[string]string "message: Logged out successfully"
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     "auth_token",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteNoneMode,
-		Path:     "/",
-	})
+	var refreshToken string
+	if c, err := r.Cookie("refresh_token"); err == nil {
+		refreshToken = c.Value
+	}
+	if err := h.uc.EndSession(r.Context(), authMiddleware.TokenFromRequest(r), refreshToken); err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+
+	clearAuthCookies(w)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -114,7 +249,7 @@ type ForgotPasswordRequest struct {
 
 // ForgotPassword godoc
 // @Summary Запрос сброса пароля
-// @Description Отправляет email для сброса пароля (заглушка).
+// @Description Отправляет на email одноразовую ссылку для сброса пароля (действует 1 час). Ответ одинаковый для существующих и несуществующих адресов.
 // @Tags Аутентификация
 // @Accept json
 // @Produce json
@@ -133,8 +268,10 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Заглушка: в реальном проекте здесь отправка email
-	slog.Info("password reset requested", slog.String("email", req.Email))
+	if err := h.uc.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		httperror.Internal(w, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -149,12 +286,13 @@ type ResetPasswordRequest struct {
 
 // ResetPassword godoc
 // @Summary Сброс пароля
-// @Description Сбрасывает пароль по токену (заглушка).
+// @Description Устанавливает новый пароль по токену из письма. Токен одноразовый; после сброса все сессии пользователя завершаются.
 // @Tags Аутентификация
 // @Accept json
 // @Produce json
 // @Param request body ResetPasswordRequest true "Токен и новый пароль"
 // @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid or expired token / weak password"
 // @Router /auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	var req ResetPasswordRequest
@@ -168,11 +306,112 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Заглушка: в реальном проекте здесь проверка токена и смена пароля
-	slog.Info("password reset with token", slog.String("token", req.Token))
+	if err := h.uc.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, usecase.ErrInvalidResetToken) || errors.Is(err, usecase.ErrWeakPassword) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
 		"message": "Password has been reset successfully.",
 	})
 }
+
+// GetSessions godoc
+// @Summary Активные сессии
+// @Description Список активных сессий текущего пользователя по устройствам.
+// @Tags Аутентификация
+// @Produce json
+// @Success 200 {array} domain.Session
+// @Router /api/auth/sessions [get]
+func (h *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.uc.ListSessions(r.Context(), userCtx.UserID, userCtx.SessionID)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// RevokeSession godoc
+// @Summary Завершить сессию
+// @Description Отзывает одну сессию текущего пользователя.
+// @Tags Аутентификация
+// @Produce json
+// @Param id path string true "ID сессии"
+// @Success 200 {object} map[string]string
+// @Failure 404 {string} string "not found"
+// @Router /api/auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.uc.RevokeSession(r.Context(), userCtx.UserID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			httperror.NotFound(w, err)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Session revoked"})
+}
+
+// RevokeAllSessions godoc
+// @Summary Выйти на всех устройствах
+// @Description Отзывает все сессии текущего пользователя, включая текущую.
+// @Tags Аутентификация
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/auth/sessions [delete]
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	revoked, err := h.uc.RevokeAllSessions(r.Context(), userCtx.UserID)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+
+	clearAuthCookies(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "All sessions revoked", "revoked": revoked})
+}
+
+// RevokeUserSessions godoc
+// @Summary ADMIN: Завершить все сессии пользователя
+// @Description Принудительный выход пользователя на всех устройствах (например, при компрометации аккаунта).
+// @Tags Admin-Users
+// @Produce json
+// @Param id path string true "UserID"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/users/{id}/sessions [delete]
+func (h *AuthHandler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	revoked, err := h.uc.RevokeAllSessions(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "User sessions revoked", "revoked": revoked})
+}
@@ -0,0 +1,273 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
+	"lms_backend/internal/auth/usecase"
+	"lms_backend/internal/domain"
+	"lms_backend/internal/httperror"
+)
+
+func requestMeta(r *http.Request) usecase.SessionMeta {
+	return usecase.SessionMeta{UserAgent: r.UserAgent(), IPAddress: authMiddleware.ClientIP(r)}
+}
+
+func writeMFAError(w http.ResponseWriter, err error) {
+	var lockout *usecase.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		w.Header().Set("Retry-After", strconv.Itoa(int(lockout.RetryAfter.Seconds())+1))
+		http.Error(w, lockout.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, usecase.ErrInvalidMFAChallenge):
+		httperror.Unauthorized(w, err)
+	case errors.Is(err, usecase.ErrInvalidMFACode):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, usecase.ErrMFAAlreadyEnabled), errors.Is(err, usecase.ErrMFARequiredByRole):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, usecase.ErrMFANotEnabled), errors.Is(err, usecase.ErrMFASetupRequired):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrMFANotAvailable), errors.Is(err, usecase.ErrAccountDisabled):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		httperror.Internal(w, err)
+	}
+}
+
+type LoginMFARequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code,omitempty" example:"123456"`
+	RecoveryCode string `json:"recovery_code,omitempty" example:"abcde-fghij"`
+	DeviceID     string `json:"device_id,omitempty" example:"ios-3f2a9c"`
+}
+
+// LoginMFA godoc
+// @Summary Вход: второй шаг (2FA)
+// @Description Принимает mfa_token из ответа /auth/login и код из приложения-аутентификатора (или одноразовый код восстановления). Возвращает токены как обычный вход.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body LoginMFARequest true "Токен первого шага и код"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var req LoginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if req.MFAToken == "" || (req.Code == "" && req.RecoveryCode == "") {
+		http.Error(w, "mfa_token and code or recovery_code are required", http.StatusBadRequest)
+		return
+	}
+
+	meta := requestMeta(r)
+	meta.DeviceID = req.DeviceID
+	user, issued, err := h.uc.CompleteMFALogin(r.Context(), req.MFAToken, req.Code, req.RecoveryCode, meta)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	writeIssued(w, issued, map[string]interface{}{
+		"message": "Login successful",
+		"user":    user,
+	})
+}
+
+// SetupMFA godoc
+// @Summary Начать подключение 2FA
+// @Description Генерирует TOTP-секрет и otpauth-ссылку для QR-кода. Доступно сотрудникам.
+// @Tags Аутентификация
+// @Produce json
+// @Success 200 {object} domain.MFAEnrollment
+// @Failure 409 {object} map[string]string "2FA уже включена"
+// @Router /api/auth/2fa/setup [post]
+func (h *AuthHandler) SetupMFA(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.uc.BeginMFAEnrollment(r.Context(), userCtx.UserID)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+func decodeMFACode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return "", false
+	}
+	if req.Code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return "", false
+	}
+	return req.Code, true
+}
+
+// ConfirmMFA godoc
+// @Summary Подтвердить подключение 2FA
+// @Description Включает 2FA по первому коду из приложения и возвращает 10 кодов восстановления (показываются один раз). Текущая сессия считается прошедшей 2FA — обновите токен через /api/auth/refresh.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "Код из приложения"
+// @Success 200 {object} map[string]interface{} "recovery_codes"
+// @Router /api/auth/2fa/confirm [post]
+func (h *AuthHandler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	code, ok := decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.uc.ConfirmMFAEnrollment(r.Context(), userCtx.UserID, userCtx.SessionID, code, requestMeta(r))
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"recovery_codes": codes})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Новые коды восстановления 2FA
+// @Description Заменяет все коды восстановления новыми. Требует текущий код из приложения.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "Код из приложения"
+// @Success 200 {object} map[string]interface{} "recovery_codes"
+// @Router /api/auth/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	code, ok := decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.uc.RegenerateRecoveryCodes(r.Context(), userCtx.UserID, code, requestMeta(r))
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"recovery_codes": codes})
+}
+
+// DisableMFA godoc
+// @Summary Отключить 2FA
+// @Description Отключает 2FA, если она не обязательна для роли пользователя. Требует текущий код из приложения.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "Код из приложения"
+// @Success 200 {object} map[string]string
+// @Failure 409 {object} map[string]string "2FA обязательна для роли"
+// @Router /api/auth/2fa [delete]
+func (h *AuthHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	code, ok := decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DisableMFA(r.Context(), userCtx.UserID, code, requestMeta(r)); err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+// GetMFAPolicies godoc
+// @Summary ADMIN: Обязательность 2FA по ролям
+// @Tags Admin-Security
+// @Produce json
+// @Success 200 {array} domain.RoleMFAPolicy
+// @Router /admin/security/2fa-roles [get]
+func (h *AuthHandler) GetMFAPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.uc.GetMFAPolicies(r.Context())
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policies)
+}
+
+type SetMFAPolicyRequest struct {
+	Required bool `json:"required"`
+}
+
+// SetMFAPolicy godoc
+// @Summary ADMIN: Сделать 2FA обязательной для роли
+// @Description Применяется к токенам, выданным после изменения (не позже чем через 15 минут). Сотрудники без 2FA смогут войти, но до подключения 2FA получат 403 на staff-маршрутах.
+// @Tags Admin-Security
+// @Accept json
+// @Produce json
+// @Param role path string true "Роль (admin, moderator, curator, teacher)"
+// @Param request body SetMFAPolicyRequest true "Обязательна ли 2FA"
+// @Success 200 {object} map[string]string
+// @Router /admin/security/2fa-roles/{role} [put]
+func (h *AuthHandler) SetMFAPolicy(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req SetMFAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	role := domain.Role(chi.URLParam(r, "role"))
+	if err := h.uc.SetMFARequired(r.Context(), userCtx.UserID, role, req.Required, requestMeta(r)); err != nil {
+		if errors.Is(err, usecase.ErrMFANotAvailable) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "2FA policy updated"})
+}
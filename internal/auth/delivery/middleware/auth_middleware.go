@@ -2,63 +2,195 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 
+	"lms_backend/internal/auth/usecase"
 	"lms_backend/internal/domain"
+	"lms_backend/internal/httperror"
+	"lms_backend/pkg/token"
 )
 
 type UserContextData struct {
-	UserID string
-	Role   domain.Role
+	UserID     string
+	Role       domain.Role
+	SessionID  string
+	MFA        bool
+	MFAPending bool
+}
+
+// SessionValidator проверяет, что серверная сессия токена не отозвана.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, userID, sessionID string) error
 }
 
 type contextKey string
 
 const ContextUserDataKey contextKey = "userData"
 
-func extractUserDataFromToken(tokenValue string) *UserContextData {
-	parts := strings.Split(tokenValue, ":")
-	if len(parts) != 2 {
+func extractUserDataFromToken(tokens *token.Manager, tokenValue string) *UserContextData {
+	claims, err := tokens.Parse(tokenValue)
+	if err != nil {
 		return nil
 	}
 	return &UserContextData{
-		UserID: parts[0],
-		Role:   domain.Role(parts[1]),
+		UserID:     claims.UserID,
+		Role:       domain.Role(claims.Role),
+		SessionID:  claims.SessionID,
+		MFA:        claims.MFA,
+		MFAPending: claims.MFAPending,
 	}
 }
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var tokenValue string
+const clientIPKey contextKey = "clientIP"
 
-		cookie, err := r.Cookie("auth_token")
-		if err == nil {
-			tokenValue = cookie.Value
+// ParseTrustedProxies разбирает список доверенных прокси (TRUSTED_PROXIES): IP или CIDR через запятую.
+func ParseTrustedProxies(raw string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
 		}
-
-		if tokenValue == "" {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-				tokenValue = strings.TrimPrefix(authHeader, "Bearer ")
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
 			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
 		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
 
-		if tokenValue == "" {
-			http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
-			return
+// ClientIPMiddleware определяет адрес клиента. X-Forwarded-For учитывается, только если запрос
+// пришёл от доверенного прокси: берётся самый правый адрес цепочки, который не является доверенным прокси.
+func ClientIPMiddleware(trusted []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey, ip)))
+		})
+	}
+}
+
+func resolveClientIP(r *http.Request, trusted []*net.IPNet) string {
+	ip := remoteIP(r)
+	if !isTrustedProxy(ip, trusted) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(hop, trusted) {
+			break
 		}
+	}
+	return ip
+}
 
-		userData := extractUserDataFromToken(tokenValue)
-		if userData == nil {
-			http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
-			return
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(parsed) {
+			return true
 		}
+	}
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ClientIP — адрес клиента, определённый ClientIPMiddleware; без него — адрес соединения.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
 
-		ctx := context.WithValue(r.Context(), ContextUserDataKey, userData)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// TokenFromRequest достаёт токен из куки auth_token, а при её отсутствии — из Authorization: Bearer.
+func TokenFromRequest(r *http.Request) string {
+	if cookie, err := r.Cookie("auth_token"); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+	authHeader := r.Header.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimPrefix(authHeader, "Bearer ")
+	}
+	return ""
+}
+
+// mfaPendingAllowed — куда пускают сессию, не прошедшую обязательную для роли 2FA:
+// привязка 2FA и управление своими сессиями (выход — публичный маршрут).
+func mfaPendingAllowed(path string) bool {
+	for _, prefix := range []string{"/api/auth/2fa", "/api/auth/sessions"} {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthMiddleware проверяет токен и сессию. Сессия с mfa_pending получает 403 везде, кроме mfaPendingAllowed.
+func AuthMiddleware(tokens *token.Manager, sessions SessionValidator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenValue := TokenFromRequest(r)
+			if tokenValue == "" {
+				http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
+				return
+			}
+
+			userData := extractUserDataFromToken(tokens, tokenValue)
+			if userData == nil {
+				http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			if err := sessions.ValidateSession(r.Context(), userData.UserID, userData.SessionID); err != nil {
+				if errors.Is(err, usecase.ErrInvalidSession) {
+					http.Error(w, "Unauthorized: Session expired", http.StatusUnauthorized)
+					return
+				}
+				httperror.Internal(w, err)
+				return
+			}
+			if userData.MFAPending && !mfaPendingAllowed(r.URL.Path) {
+				http.Error(w, "Forbidden: two-factor authentication required", http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ContextUserDataKey, userData)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func RoleRequiredMiddleware(allowedRoles ...domain.Role) func(next http.Handler) http.Handler {
@@ -81,7 +213,55 @@ func RoleRequiredMiddleware(allowedRoles ...domain.Role) func(next http.Handler)
 				http.Error(w, msg, http.StatusForbidden)
 				return
 			}
+			// Роль требует 2FA, а сессия её не прошла. AuthMiddleware уже отсекает такие сессии;
+			// проверка повторяется на случай маршрута без него.
+			if userCtxData.MFAPending {
+				http.Error(w, "Forbidden: two-factor authentication required", http.StatusForbidden)
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// PermissionChecker отвечает, есть ли у роли право. Реализуется permission.PermissionUseCase.
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role domain.Role, permission string) (bool, error)
+}
+
+// PermissionRequired пропускает запрос, только если у роли пользователя есть право permission.
+// Ставится на конкретный маршрут поверх RoleRequiredMiddleware, который отсекает не-сотрудников и сессии без 2FA.
+func PermissionRequired(checker PermissionChecker, permission string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userCtxData, ok := r.Context().Value(ContextUserDataKey).(*UserContextData)
+			if !ok || userCtxData == nil {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			allowed, err := checker.HasPermission(r.Context(), userCtxData.Role, permission)
+			if err != nil {
+				httperror.Internal(w, err)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Forbidden: missing permission "+permission, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Actor — пользователь запроса в виде, который принимают usecase'ы с проверкой доступа к ресурсам.
+func (u *UserContextData) Actor() domain.Actor {
+	return domain.Actor{UserID: u.UserID, Role: u.Role}
+}
+
+func ActorFromContext(ctx context.Context) (domain.Actor, bool) {
+	userCtxData, ok := ctx.Value(ContextUserDataKey).(*UserContextData)
+	if !ok || userCtxData == nil {
+		return domain.Actor{}, false
+	}
+	return userCtxData.Actor(), true
+}
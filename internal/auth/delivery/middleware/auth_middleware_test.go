@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lms_backend/pkg/token"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"direct client ignores forwarded header", "203.0.113.7:5000", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:5000", []string{"198.51.100.9"}, "198.51.100.9"},
+		{"spoofed left-most hop is skipped", "10.0.0.2:5000", []string{"1.2.3.4, 198.51.100.9"}, "198.51.100.9"},
+		{"chain of trusted proxies", "10.0.0.2:5000", []string{"198.51.100.9, 192.168.1.5", "10.1.1.1"}, "198.51.100.9"},
+		{"garbage hop stops the walk", "10.0.0.2:5000", []string{"198.51.100.9, not-an-ip"}, "10.0.0.2"},
+		{"trusted proxy without header", "10.0.0.2:5000", nil, "10.0.0.2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.remote
+			for _, v := range tc.xff {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			var got string
+			ClientIPMiddleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			if got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := ParseTrustedProxies("10.0.0.0/33"); err == nil {
+		t.Fatalf("expected error for invalid CIDR")
+	}
+}
+
+type sessionsStub struct{}
+
+func (sessionsStub) ValidateSession(ctx context.Context, userID, sessionID string) error { return nil }
+
+func TestAuthMiddleware_MFAPending(t *testing.T) {
+	tokens, err := token.NewManager(map[string][]byte{"k1": []byte(strings.Repeat("a", 32))}, "k1", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pending, _, err := tokens.IssueWith(token.Claims{UserID: "teacher-1", Role: "teacher", SessionID: "s1", MFAPending: true}, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	full, _, err := tokens.Issue("teacher-1", "teacher", "s1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler := AuthMiddleware(tokens, sessionsStub{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		token, path string
+		want        int
+	}{
+		{pending, "/teacher/profile", http.StatusForbidden},
+		{pending, "/api/chat/history", http.StatusForbidden},
+		{pending, "/api/auth/2fa-bypass", http.StatusForbidden},
+		{pending, "/api/auth/2fa/setup", http.StatusOK},
+		{pending, "/api/auth/2fa", http.StatusOK},
+		{pending, "/api/auth/sessions/s2", http.StatusOK},
+		{full, "/teacher/profile", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s (pending=%v): expected %d, got %d", tt.path, tt.token == pending, tt.want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LoginLimiterMock блокирует ключ на LockFor после Threshold неудач.
+type LoginLimiterMock struct {
+	mu        sync.Mutex
+	Threshold int
+	LockFor   time.Duration
+	Failures  map[string]int
+	Locks     map[string]time.Time
+}
+
+func NewLoginLimiterMock(threshold int, lockFor time.Duration) *LoginLimiterMock {
+	return &LoginLimiterMock{
+		Threshold: threshold,
+		LockFor:   lockFor,
+		Failures:  make(map[string]int),
+		Locks:     make(map[string]time.Time),
+	}
+}
+
+func (l *LoginLimiterMock) LockedFor(ctx context.Context, scope, key string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until, ok := l.Locks[scope+":"+key]; ok && time.Now().Before(until) {
+		return time.Until(until), nil
+	}
+	return 0, nil
+}
+
+func (l *LoginLimiterMock) RegisterFailure(ctx context.Context, scope, key string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Failures[scope+":"+key]++
+	if l.Failures[scope+":"+key] >= l.Threshold {
+		l.Locks[scope+":"+key] = time.Now().Add(l.LockFor)
+		return l.LockFor, nil
+	}
+	return 0, nil
+}
+
+func (l *LoginLimiterMock) Reset(ctx context.Context, scope, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.Failures, scope+":"+key)
+	delete(l.Locks, scope+":"+key)
+	return nil
+}
+
+type AuditEntry struct {
+	Action   string
+	EntityID string
+	Details  interface{}
+}
+
+type AuditLoggerMock struct {
+	mu      sync.Mutex
+	Entries []AuditEntry
+}
+
+func (a *AuditLoggerMock) LogAction(ctx context.Context, userID *string, action, entityType, entityID string, oldValues, newValues interface{}, ipAddress, userAgent *string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Entries = append(a.Entries, AuditEntry{Action: action, EntityID: entityID, Details: newValues})
+	return nil
+}
+
+func (a *AuditLoggerMock) Count(action string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := 0
+	for _, e := range a.Entries {
+		if e.Action == action {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"lms_backend/pkg/broker"
+)
+
+type EventPublisherMock struct {
+	mu    sync.Mutex
+	Tasks []broker.Task
+}
+
+func (p *EventPublisherMock) Publish(ctx context.Context, event broker.EventType, task broker.Task) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Tasks = append(p.Tasks, task)
+	return nil
+}
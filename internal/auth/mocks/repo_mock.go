@@ -2,8 +2,10 @@ package mocks
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"lms_backend/internal/auth/repository"
 	"lms_backend/internal/domain"
@@ -11,8 +13,22 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+type ResetToken struct {
+	UserID    string
+	ExpiresAt time.Time
+	Used      bool
+}
+
 type AuthRepositoryMock struct {
-	Users map[string]*domain.User
+	mu          sync.Mutex
+	Users       map[string]*domain.User
+	Sessions    map[string]*domain.Session
+	ResetTokens map[string]*ResetToken
+	Refresh     map[string]*domain.RefreshToken
+	TOTP        map[string]*domain.UserTOTP
+	Recovery    map[string]map[string]bool
+	MFARequired map[domain.Role]bool
+	nextID      int
 }
 
 func hashPasswordForMock(password string) string {
@@ -39,6 +55,13 @@ func NewAuthRepositoryMock() *AuthRepositoryMock {
 				Role:      domain.RoleStudent,
 			},
 		},
+		Sessions:    make(map[string]*domain.Session),
+		ResetTokens: make(map[string]*ResetToken),
+		Refresh:     make(map[string]*domain.RefreshToken),
+		TOTP:        make(map[string]*domain.UserTOTP),
+		Recovery:    make(map[string]map[string]bool),
+		MFARequired: make(map[domain.Role]bool),
+		nextID:      1,
 	}
 }
 
@@ -57,5 +80,283 @@ func (r *AuthRepositoryMock) GetByEmail(ctx context.Context, email string) (*dom
 		copiedUser := *user
 		return &copiedUser, nil
 	}
-	return nil, sql.ErrNoRows
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *AuthRepositoryMock) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	for _, user := range r.Users {
+		if user.ID == id {
+			copiedUser := *user
+			return &copiedUser, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *AuthRepositoryMock) CreateSession(ctx context.Context, s *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = fmt.Sprintf("session-%d", r.nextID)
+	r.nextID++
+	s.CreatedAt = time.Now()
+	s.LastSeenAt = time.Now()
+	copied := *s
+	r.Sessions[s.ID] = &copied
+	return nil
+}
+
+func (r *AuthRepositoryMock) GetSession(ctx context.Context, id string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.Sessions[id]
+	if !ok {
+		return nil, repository.ErrSessionNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *AuthRepositoryMock) GetActiveSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*domain.Session
+	for _, s := range r.Sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.ExpiresAt.After(time.Now()) {
+			copied := *s
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *AuthRepositoryMock) TouchSession(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.Sessions[id]; ok {
+		s.LastSeenAt = time.Now()
+	}
+	return nil
+}
+
+func (r *AuthRepositoryMock) RevokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.Sessions[sessionID]
+	if !ok || s.UserID != userID || s.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	return true, nil
+}
+
+func (r *AuthRepositoryMock) RevokeDeviceSessions(ctx context.Context, userID, deviceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, s := range r.Sessions {
+		if s.UserID == userID && s.DeviceID == deviceID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *AuthRepositoryMock) RevokeAllSessions(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	now := time.Now()
+	for _, s := range r.Sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *AuthRepositoryMock) CreatePasswordResetToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ResetTokens[tokenHash] = &ResetToken{UserID: userID, ExpiresAt: expiresAt}
+	return nil
+}
+
+func (r *AuthRepositoryMock) ResetPasswordWithToken(ctx context.Context, tokenHash, passwordHash string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rt, ok := r.ResetTokens[tokenHash]
+	if !ok || rt.Used || !rt.ExpiresAt.After(time.Now()) {
+		return "", repository.ErrResetTokenInvalid
+	}
+
+	for _, u := range r.Users {
+		if u.ID == rt.UserID {
+			u.Password = passwordHash
+		}
+	}
+	for _, other := range r.ResetTokens {
+		if other.UserID == rt.UserID {
+			other.Used = true
+		}
+	}
+	now := time.Now()
+	for _, s := range r.Sessions {
+		if s.UserID == rt.UserID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+		}
+	}
+	return rt.UserID, nil
+}
+
+func (r *AuthRepositoryMock) CreateRefreshToken(ctx context.Context, sessionID, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Refresh[tokenHash] = &domain.RefreshToken{
+		ID:        fmt.Sprintf("refresh-%d", r.nextID),
+		SessionID: sessionID,
+		TokenHash: tokenHash,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	r.nextID++
+	return nil
+}
+
+func (r *AuthRepositoryMock) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.Refresh[tokenHash]
+	if !ok {
+		return nil, repository.ErrRefreshTokenNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *AuthRepositoryMock) RotateRefreshToken(ctx context.Context, oldID, sessionID, newHash string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	var old *domain.RefreshToken
+	for _, t := range r.Refresh {
+		if t.ID == oldID {
+			old = t
+		}
+	}
+	if old == nil || old.UsedAt != nil {
+		r.mu.Unlock()
+		return false, nil
+	}
+	now := time.Now()
+	old.UsedAt = &now
+	if s, ok := r.Sessions[sessionID]; ok {
+		s.ExpiresAt = expiresAt
+	}
+	r.mu.Unlock()
+
+	return true, r.CreateRefreshToken(ctx, sessionID, newHash, expiresAt)
+}
+
+func (r *AuthRepositoryMock) GetTOTP(ctx context.Context, userID string) (*domain.UserTOTP, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.TOTP[userID]
+	if !ok {
+		return nil, repository.ErrTOTPNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *AuthRepositoryMock) SaveTOTPSecret(ctx context.Context, userID, secret string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.TOTP[userID]; ok && t.EnabledAt != nil {
+		return nil
+	}
+	r.TOTP[userID] = &domain.UserTOTP{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (r *AuthRepositoryMock) EnableTOTP(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.TOTP[userID]; ok {
+		now := time.Now()
+		t.EnabledAt = &now
+	}
+	return nil
+}
+
+func (r *AuthRepositoryMock) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.TOTP[userID]
+	if !ok || t.LastUsedStep >= step {
+		return false, nil
+	}
+	t.LastUsedStep = step
+	return true, nil
+}
+
+func (r *AuthRepositoryMock) DeleteTOTP(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.TOTP, userID)
+	delete(r.Recovery, userID)
+	return nil
+}
+
+func (r *AuthRepositoryMock) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	codes := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		codes[h] = false
+	}
+	r.Recovery[userID] = codes
+	return nil
+}
+
+func (r *AuthRepositoryMock) UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.Recovery[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.Recovery[userID][hash] = true
+	return true, nil
+}
+
+func (r *AuthRepositoryMock) IsMFARequired(ctx context.Context, role domain.Role) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.MFARequired[role], nil
+}
+
+func (r *AuthRepositoryMock) GetMFAPolicies(ctx context.Context) ([]*domain.RoleMFAPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var policies []*domain.RoleMFAPolicy
+	for role, required := range r.MFARequired {
+		policies = append(policies, &domain.RoleMFAPolicy{Role: role, Required: required})
+	}
+	return policies, nil
+}
+
+func (r *AuthRepositoryMock) SetMFARequired(ctx context.Context, role domain.Role, required bool, updatedBy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.MFARequired[role] = required
+	return nil
+}
+
+func (r *AuthRepositoryMock) MarkSessionMFAVerified(ctx context.Context, sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.Sessions[sessionID]; ok {
+		s.MFAVerified = true
+	}
+	return nil
 }
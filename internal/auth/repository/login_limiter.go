@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginLimitPolicy — сколько неудачных попыток разрешено до блокировки и как растёт блокировка.
+// Каждая следующая неудача после порога удваивает блокировку, но не больше MaxLock.
+type LoginLimitPolicy struct {
+	Threshold int64
+	BaseLock  time.Duration
+	MaxLock   time.Duration
+	Window    time.Duration
+}
+
+// RedisLoginLimiter считает неудачные попытки входа по произвольным ключам (email, IP).
+type RedisLoginLimiter struct {
+	rdb      *redis.Client
+	policies map[string]LoginLimitPolicy
+}
+
+func NewRedisLoginLimiter(rdb *redis.Client, policies map[string]LoginLimitPolicy) *RedisLoginLimiter {
+	return &RedisLoginLimiter{rdb: rdb, policies: policies}
+}
+
+func failKey(scope, key string) string { return "login:fail:" + scope + ":" + key }
+func lockKey(scope, key string) string { return "login:lock:" + scope + ":" + key }
+
+// LockedFor возвращает оставшееся время блокировки ключа (0 — не заблокирован).
+func (l *RedisLoginLimiter) LockedFor(ctx context.Context, scope, key string) (time.Duration, error) {
+	ttl, err := l.rdb.PTTL(ctx, lockKey(scope, key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RegisterFailure учитывает неудачную попытку и, если порог превышен, ставит блокировку.
+// Возвращает длительность новой блокировки (0 — блокировки нет).
+func (l *RedisLoginLimiter) RegisterFailure(ctx context.Context, scope, key string) (time.Duration, error) {
+	policy, ok := l.policies[scope]
+	if !ok {
+		return 0, nil
+	}
+
+	pipe := l.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, failKey(scope, key))
+	pipe.Expire(ctx, failKey(scope, key), policy.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	failures := incr.Val()
+	if failures < policy.Threshold {
+		return 0, nil
+	}
+
+	lock := policy.BaseLock
+	for i := policy.Threshold; i < failures && lock < policy.MaxLock; i++ {
+		lock *= 2
+	}
+	if lock > policy.MaxLock {
+		lock = policy.MaxLock
+	}
+
+	if err := l.rdb.Set(ctx, lockKey(scope, key), failures, lock).Err(); err != nil {
+		return 0, err
+	}
+	return lock, nil
+}
+
+func (l *RedisLoginLimiter) Reset(ctx context.Context, scope, key string) error {
+	return l.rdb.Del(ctx, failKey(scope, key), lockKey(scope, key)).Err()
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"lms_backend/internal/domain"
+)
+
+var ErrTOTPNotFound = errors.New("totp is not configured")
+
+func (r *AuthRepositoryImpl) GetTOTP(ctx context.Context, userID string) (*domain.UserTOTP, error) {
+	t := &domain.UserTOTP{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id, secret, enabled_at, last_used_step FROM user_totp WHERE user_id = $1`, userID,
+	).Scan(&t.UserID, &t.Secret, &t.EnabledAt, &t.LastUsedStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTOTPNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SaveTOTPSecret начинает (или перезапускает) привязку. Уже включённую 2FA не трогает.
+func (r *AuthRepositoryImpl) SaveTOTPSecret(ctx context.Context, userID, secret string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_totp (user_id, secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_totp.enabled_at IS NULL
+	`, userID, secret)
+	return err
+}
+
+func (r *AuthRepositoryImpl) EnableTOTP(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE user_totp SET enabled_at = NOW() WHERE user_id = $1`, userID)
+	return err
+}
+
+// UseTOTPStep атомарно фиксирует использованный шаг. false — код этого или более позднего шага уже был принят.
+func (r *AuthRepositoryImpl) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`, userID, step,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *AuthRepositoryImpl) DeleteTOTP(ctx context.Context, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *AuthRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, h,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *AuthRepositoryImpl) UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_recovery_codes SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM user_recovery_codes
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)
+	`, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *AuthRepositoryImpl) IsMFARequired(ctx context.Context, role domain.Role) (bool, error) {
+	var required bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT required FROM role_mfa_requirements WHERE role = $1`, role,
+	).Scan(&required)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return required, err
+}
+
+func (r *AuthRepositoryImpl) GetMFAPolicies(ctx context.Context) ([]*domain.RoleMFAPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT role, required, updated_at FROM role_mfa_requirements ORDER BY role`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*domain.RoleMFAPolicy
+	for rows.Next() {
+		p := &domain.RoleMFAPolicy{}
+		if err := rows.Scan(&p.Role, &p.Required, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+func (r *AuthRepositoryImpl) SetMFARequired(ctx context.Context, role domain.Role, required bool, updatedBy string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO role_mfa_requirements (role, required, updated_by, updated_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (role) DO UPDATE SET required = EXCLUDED.required, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+	`, role, required, updatedBy)
+	return err
+}
+
+func (r *AuthRepositoryImpl) MarkSessionMFAVerified(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE user_sessions SET mfa_verified = TRUE WHERE id = $1`, sessionID)
+	return err
+}
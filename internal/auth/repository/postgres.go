@@ -9,6 +9,8 @@ import (
 	"lms_backend/internal/domain"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type AuthRepositoryImpl struct {
 	db *sql.DB
 }
@@ -24,18 +26,18 @@ func (r *AuthRepositoryImpl) GetByEmail(ctx context.Context, email string) (*dom
 
 	query := `
 		SELECT 
-			id, first_name, last_name, email, password_hash, role, created_at
+			id, first_name, last_name, email, password_hash, role, created_at, disabled_at
 		FROM users
 		WHERE email = $1;
 	`
 	var passwordHash string
 
 	err := r.db.QueryRowContext(ctx, query, email).
-		Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &passwordHash, &u.Role, &u.CreatedAt)
+		Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &passwordHash, &u.Role, &u.CreatedAt, &u.DisabledAt)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("database query failed: %w", err)
 	}
@@ -43,3 +45,22 @@ func (r *AuthRepositoryImpl) GetByEmail(ctx context.Context, email string) (*dom
 	u.Password = passwordHash
 	return u, nil
 }
+
+func (r *AuthRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	u := &domain.User{}
+
+	query := `
+		SELECT id, first_name, last_name, email, role, created_at, disabled_at
+		FROM users
+		WHERE id = $1;
+	`
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.Role, &u.CreatedAt, &u.DisabledAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+func (r *AuthRepositoryImpl) CreateRefreshToken(ctx context.Context, sessionID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		sessionID, tokenHash, expiresAt,
+	)
+	return err
+}
+
+func (r *AuthRepositoryImpl) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	t := &domain.RefreshToken{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, session_id, token_hash, created_at, expires_at, used_at
+		FROM refresh_tokens WHERE token_hash = $1
+	`, tokenHash).Scan(&t.ID, &t.SessionID, &t.TokenHash, &t.CreatedAt, &t.ExpiresAt, &t.UsedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RotateRefreshToken гасит старый токен и выдаёт новый в той же сессии, продлевая её.
+// Возвращает false, если старый токен уже был использован (гонка двух обменов).
+func (r *AuthRepositoryImpl) RotateRefreshToken(ctx context.Context, oldID, sessionID, newHash string, expiresAt time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL`, oldID,
+	)
+	if err != nil {
+		return false, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		sessionID, newHash, expiresAt,
+	); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE user_sessions SET expires_at = $2, last_seen_at = NOW() WHERE id = $1`, sessionID, expiresAt,
+	); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -2,9 +2,39 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"lms_backend/internal/domain"
 )
 
 type AuthRepository interface {
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetByID(ctx context.Context, id string) (*domain.User, error)
+
+	CreateSession(ctx context.Context, session *domain.Session) error
+	GetSession(ctx context.Context, id string) (*domain.Session, error)
+	GetActiveSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+	TouchSession(ctx context.Context, id string) error
+	RevokeSession(ctx context.Context, userID, sessionID string) (bool, error)
+	RevokeDeviceSessions(ctx context.Context, userID, deviceID string) error
+	RevokeAllSessions(ctx context.Context, userID string) (int64, error)
+
+	CreatePasswordResetToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	ResetPasswordWithToken(ctx context.Context, tokenHash, passwordHash string) (string, error)
+
+	CreateRefreshToken(ctx context.Context, sessionID, tokenHash string, expiresAt time.Time) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldID, sessionID, newHash string, expiresAt time.Time) (bool, error)
+
+	GetTOTP(ctx context.Context, userID string) (*domain.UserTOTP, error)
+	SaveTOTPSecret(ctx context.Context, userID, secret string) error
+	EnableTOTP(ctx context.Context, userID string) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	DeleteTOTP(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error)
+	IsMFARequired(ctx context.Context, role domain.Role) (bool, error)
+	GetMFAPolicies(ctx context.Context) ([]*domain.RoleMFAPolicy, error)
+	SetMFARequired(ctx context.Context, role domain.Role, required bool, updatedBy string) error
+	MarkSessionMFAVerified(ctx context.Context, sessionID string) error
 }
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"lms_backend/internal/domain"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+const sessionColumns = `id, user_id, device_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at, mfa_verified`
+
+func scanSession(row interface{ Scan(...any) error }) (*domain.Session, error) {
+	s := &domain.Session{}
+	err := row.Scan(&s.ID, &s.UserID, &s.DeviceID, &s.UserAgent, &s.IPAddress,
+		&s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt, &s.RevokedAt, &s.MFAVerified)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *AuthRepositoryImpl) CreateSession(ctx context.Context, s *domain.Session) error {
+	query := `
+		INSERT INTO user_sessions (user_id, device_id, user_agent, ip_address, expires_at, mfa_verified)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, last_seen_at
+	`
+	return r.db.QueryRowContext(ctx, query, s.UserID, s.DeviceID, s.UserAgent, s.IPAddress, s.ExpiresAt, s.MFAVerified).
+		Scan(&s.ID, &s.CreatedAt, &s.LastSeenAt)
+}
+
+func (r *AuthRepositoryImpl) GetSession(ctx context.Context, id string) (*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE id = $1`
+	s, err := scanSession(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	return s, err
+}
+
+func (r *AuthRepositoryImpl) GetActiveSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+		LIMIT 100
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *AuthRepositoryImpl) TouchSession(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE user_sessions SET last_seen_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func (r *AuthRepositoryImpl) RevokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		sessionID, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *AuthRepositoryImpl) RevokeDeviceSessions(ctx context.Context, userID, deviceID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE user_sessions SET revoked_at = NOW() WHERE user_id = $1 AND device_id = $2 AND revoked_at IS NULL`,
+		userID, deviceID,
+	)
+	return err
+}
+
+func (r *AuthRepositoryImpl) RevokeAllSessions(ctx context.Context, userID string) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
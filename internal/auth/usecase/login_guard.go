@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"lms_backend/internal/auth/repository"
+	"lms_backend/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	LimitScopeEmail = "email"
+	LimitScopeIP    = "ip"
+
+	// unknownUserEntityID — entity_id для аудита попыток входа на несуществующий email.
+	unknownUserEntityID = "00000000-0000-0000-0000-000000000000"
+)
+
+// LoginLimitPolicies — пороги блокировки. По IP порог выше: за одним NAT может сидеть целый класс.
+var LoginLimitPolicies = map[string]repository.LoginLimitPolicy{
+	LimitScopeEmail: {Threshold: 5, BaseLock: time.Minute, MaxLock: time.Hour, Window: 24 * time.Hour},
+	LimitScopeIP:    {Threshold: 30, BaseLock: 5 * time.Minute, MaxLock: time.Hour, Window: time.Hour},
+	LimitScopeMFA:   {Threshold: 5, BaseLock: time.Minute, MaxLock: time.Hour, Window: time.Hour},
+}
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrAccountDisabled    = errors.New("account is disabled")
+)
+
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+type LoginLimiter interface {
+	LockedFor(ctx context.Context, scope, key string) (time.Duration, error)
+	RegisterFailure(ctx context.Context, scope, key string) (time.Duration, error)
+	Reset(ctx context.Context, scope, key string) error
+}
+
+// AuditLogger — то, что нужно auth от audit.AuditUseCase.
+type AuditLogger interface {
+	LogAction(ctx context.Context, userID *string, action, entityType, entityID string, oldValues, newValues interface{}, ipAddress, userAgent *string) error
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// checkPasswordConstantTime проверяет пароль и для несуществующего пользователя,
+// чтобы время ответа не выдавало, зарегистрирован ли email.
+func checkPasswordConstantTime(user *domain.User, password string) bool {
+	if user == nil {
+		dummyHashOnce.Do(func() {
+			b, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), 12)
+			dummyHash = string(b)
+		})
+		_ = checkPassword(dummyHash, password)
+		return false
+	}
+	return checkPassword(user.Password, password) == nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (u *AuthUsecase) lockedFor(ctx context.Context, emailKey, ip string) time.Duration {
+	var longest time.Duration
+	for scope, key := range map[string]string{LimitScopeEmail: emailKey, LimitScopeIP: ip} {
+		if key == "" {
+			continue
+		}
+		ttl, err := u.limiter.LockedFor(ctx, scope, key)
+		if err != nil {
+			// Redis недоступен — не блокируем вход всем пользователям.
+			slog.Warn("login limiter unavailable", slog.String("error", err.Error()))
+			continue
+		}
+		if ttl > longest {
+			longest = ttl
+		}
+	}
+	return longest
+}
+
+func (u *AuthUsecase) recordLoginFailure(ctx context.Context, emailKey string, user *domain.User, meta SessionMeta) {
+	entityID := unknownUserEntityID
+	if user != nil {
+		entityID = user.ID
+	}
+	u.logAudit(ctx, nil, "LOGIN_FAILED", entityID, map[string]string{"email": emailKey}, meta)
+
+	for scope, key := range map[string]string{LimitScopeEmail: emailKey, LimitScopeIP: meta.IPAddress} {
+		if key == "" {
+			continue
+		}
+		lock, err := u.limiter.RegisterFailure(ctx, scope, key)
+		if err != nil {
+			slog.Warn("login limiter unavailable", slog.String("error", err.Error()))
+			continue
+		}
+		if lock > 0 {
+			u.logAudit(ctx, nil, "LOGIN_LOCKED", entityID, map[string]interface{}{
+				"scope":        scope,
+				"key":          key,
+				"lock_seconds": int(lock.Seconds()),
+			}, meta)
+		}
+	}
+}
+
+func (u *AuthUsecase) logAudit(ctx context.Context, actorID *string, action, userID string, details interface{}, meta SessionMeta) {
+	u.logAuditEntity(ctx, actorID, action, "USER", userID, details, meta)
+}
+
+func (u *AuthUsecase) logAuditEntity(ctx context.Context, actorID *string, action, entityType, entityID string, details interface{}, meta SessionMeta) {
+	var ip, ua *string
+	if meta.IPAddress != "" {
+		ip = &meta.IPAddress
+	}
+	if meta.UserAgent != "" {
+		ua = &meta.UserAgent
+	}
+	if err := u.audit.LogAction(ctx, actorID, action, entityType, entityID, nil, details, ip, ua); err != nil {
+		slog.Warn("failed to write audit log", slog.String("action", action), slog.String("error", err.Error()))
+	}
+}
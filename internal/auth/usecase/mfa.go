@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"lms_backend/internal/auth/repository"
+	"lms_backend/internal/domain"
+	"lms_backend/pkg/token"
+	"lms_backend/pkg/totp"
+)
+
+const (
+	LimitScopeMFA = "mfa"
+
+	mfaIssuer         = "Cap Education"
+	mfaChallengeTTL   = 5 * time.Minute
+	purposeMFA        = "mfa"
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrMFANotAvailable     = errors.New("two-factor authentication is available for staff roles only")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrMFASetupRequired    = errors.New("start two-factor setup first")
+	ErrMFARequiredByRole   = errors.New("two-factor authentication is required for your role")
+	ErrInvalidMFACode      = errors.New("invalid two-factor code")
+	ErrInvalidMFAChallenge = errors.New("login challenge is invalid or expired")
+)
+
+func IsStaffRole(role domain.Role) bool {
+	switch role {
+	case domain.RoleAdmin, domain.RoleModerator, domain.RoleCurator, domain.RoleTeacher:
+		return true
+	}
+	return false
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(enc.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashOpaqueToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+// checkTOTP проверяет код и запрещает повторное использование того же шага.
+func (u *AuthUsecase) checkTOTP(ctx context.Context, t *domain.UserTOTP, code string) (bool, error) {
+	step, ok := totp.Validate(t.Secret, code, time.Now(), 1)
+	if !ok {
+		return false, nil
+	}
+	return u.repo.UseTOTPStep(ctx, t.UserID, step)
+}
+
+func (u *AuthUsecase) enabledTOTP(ctx context.Context, userID string) (*domain.UserTOTP, error) {
+	t, err := u.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPNotFound) || (err == nil && t.EnabledAt == nil) {
+		return nil, ErrMFANotEnabled
+	}
+	return t, err
+}
+
+// MFAChallenge возвращает короткоживущий токен второго шага входа,
+// или пустую строку, если у пользователя 2FA не включена.
+func (u *AuthUsecase) MFAChallenge(ctx context.Context, user *domain.User) (string, error) {
+	if _, err := u.enabledTOTP(ctx, user.ID); err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return "", nil
+		}
+		return "", err
+	}
+	tok, _, err := u.tokens.IssueWith(token.Claims{
+		UserID:  user.ID,
+		Role:    string(user.Role),
+		Purpose: purposeMFA,
+	}, mfaChallengeTTL)
+	return tok, err
+}
+
+// CompleteMFALogin — второй шаг входа: TOTP-код или одноразовый код восстановления.
+func (u *AuthUsecase) CompleteMFALogin(ctx context.Context, challenge, code, recoveryCode string, meta SessionMeta) (*domain.User, *IssuedSession, error) {
+	claims, err := u.tokens.ParsePurpose(challenge, purposeMFA)
+	if err != nil {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+
+	if retry, err := u.limiter.LockedFor(ctx, LimitScopeMFA, claims.UserID); err == nil && retry > 0 {
+		return nil, nil, &LockoutError{RetryAfter: retry}
+	}
+
+	user, err := u.repo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil, ErrInvalidMFAChallenge
+		}
+		return nil, nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, nil, ErrAccountDisabled
+	}
+	t, err := u.enabledTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ok bool
+	if recoveryCode != "" {
+		ok, err = u.repo.UseRecoveryCode(ctx, user.ID, hashOpaqueToken(normalizeRecoveryCode(recoveryCode)))
+	} else {
+		ok, err = u.checkTOTP(ctx, t, code)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		u.logAudit(ctx, nil, "MFA_FAILED", user.ID, nil, meta)
+		if lock, err := u.limiter.RegisterFailure(ctx, LimitScopeMFA, user.ID); err == nil && lock > 0 {
+			u.logAudit(ctx, nil, "LOGIN_LOCKED", user.ID, map[string]interface{}{
+				"scope":        LimitScopeMFA,
+				"lock_seconds": int(lock.Seconds()),
+			}, meta)
+		}
+		return nil, nil, ErrInvalidMFACode
+	}
+
+	if recoveryCode != "" {
+		u.logAudit(ctx, &user.ID, "MFA_RECOVERY_CODE_USED", user.ID, nil, meta)
+	}
+	_ = u.limiter.Reset(ctx, LimitScopeMFA, user.ID)
+
+	issued, err := u.startSession(ctx, user, meta, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, issued, nil
+}
+
+// BeginMFAEnrollment генерирует новый секрет. 2FA включится только после ConfirmMFAEnrollment.
+func (u *AuthUsecase) BeginMFAEnrollment(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	user, err := u.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsStaffRole(user.Role) {
+		return nil, ErrMFANotAvailable
+	}
+	if _, err := u.enabledTOTP(ctx, userID); err == nil {
+		return nil, ErrMFAAlreadyEnabled
+	} else if !errors.Is(err, ErrMFANotEnabled) {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &domain.MFAEnrollment{Secret: secret, URI: totp.URI(mfaIssuer, user.Email, secret)}, nil
+}
+
+// ConfirmMFAEnrollment включает 2FA по первому коду из приложения и выдаёт коды восстановления.
+// Текущая сессия считается прошедшей 2FA.
+func (u *AuthUsecase) ConfirmMFAEnrollment(ctx context.Context, userID, sessionID, code string, meta SessionMeta) ([]string, error) {
+	t, err := u.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPNotFound) {
+		return nil, ErrMFASetupRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	ok, err := u.checkTOTP(ctx, t, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	if err := u.repo.EnableTOTP(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := u.repo.MarkSessionMFAVerified(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	u.logAudit(ctx, &userID, "MFA_ENABLED", userID, nil, meta)
+	return codes, nil
+}
+
+func (u *AuthUsecase) RegenerateRecoveryCodes(ctx context.Context, userID, code string, meta SessionMeta) ([]string, error) {
+	t, err := u.enabledTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := u.checkTOTP(ctx, t, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	u.logAudit(ctx, &userID, "MFA_RECOVERY_CODES_REGENERATED", userID, nil, meta)
+	return codes, nil
+}
+
+// DisableMFA отключает 2FA, если она не обязательна для роли пользователя.
+func (u *AuthUsecase) DisableMFA(ctx context.Context, userID, code string, meta SessionMeta) error {
+	user, err := u.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	required, err := u.repo.IsMFARequired(ctx, user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredByRole
+	}
+
+	t, err := u.enabledTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	ok, err := u.checkTOTP(ctx, t, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	if err := u.repo.DeleteTOTP(ctx, userID); err != nil {
+		return err
+	}
+	u.logAudit(ctx, &userID, "MFA_DISABLED", userID, nil, meta)
+	return nil
+}
+
+func (u *AuthUsecase) GetMFAPolicies(ctx context.Context) ([]*domain.RoleMFAPolicy, error) {
+	policies, err := u.repo.GetMFAPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if policies == nil {
+		policies = []*domain.RoleMFAPolicy{}
+	}
+	return policies, nil
+}
+
+// SetMFARequired включает или выключает обязательную 2FA для роли. Действует на токены,
+// выданные после изменения, то есть не позже чем через время жизни access-токена.
+func (u *AuthUsecase) SetMFARequired(ctx context.Context, actorID string, role domain.Role, required bool, meta SessionMeta) error {
+	if !IsStaffRole(role) {
+		return ErrMFANotAvailable
+	}
+	if err := u.repo.SetMFARequired(ctx, role, required, actorID); err != nil {
+		return err
+	}
+	u.logAuditEntity(ctx, &actorID, "MFA_POLICY_CHANGED", "MFA_POLICY", unknownUserEntityID,
+		map[string]interface{}{"role": role, "required": required}, meta)
+	return nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lms_backend/internal/auth/repository"
+	"lms_backend/pkg/broker"
+)
+
+const (
+	passwordResetTTL  = time.Hour
+	minPasswordLength = 8
+
+	TaskPasswordReset = "PASSWORD_RESET"
+)
+
+var (
+	ErrInvalidResetToken = errors.New("reset token is invalid or expired")
+	ErrWeakPassword      = errors.New("password must be at least 8 characters")
+)
+
+// RequestPasswordReset выпускает одноразовый токен и ставит письмо в очередь notifier.
+// Для неизвестного email ничего не делает и не возвращает ошибку, чтобы не раскрывать,
+// зарегистрирован ли адрес.
+func (u *AuthUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := u.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(passwordResetTTL)
+
+	if err := u.repo.CreatePasswordResetToken(ctx, user.ID, hash, expiresAt); err != nil {
+		return err
+	}
+
+	return u.events.Publish(ctx, broker.NotificationEvent, broker.Task{
+		Type: TaskPasswordReset,
+		Payload: map[string]string{
+			"email":      user.Email,
+			"first_name": user.FirstName,
+			"token":      raw,
+			"expires_at": expiresAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
+
+// ResetPassword меняет пароль по токену из письма и завершает все сессии пользователя.
+func (u *AuthUsecase) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	if len(newPassword) < minPasswordLength {
+		return ErrWeakPassword
+	}
+
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.repo.ResetPasswordWithToken(ctx, hashOpaqueToken(rawToken), hash)
+	if errors.Is(err, repository.ErrResetTokenInvalid) {
+		return ErrInvalidResetToken
+	}
+	return err
+}
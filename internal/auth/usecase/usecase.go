@@ -2,20 +2,50 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log/slog"
+	"time"
 
 	"lms_backend/internal/auth/repository"
 	"lms_backend/internal/domain"
+	"lms_backend/pkg/broker"
+	"lms_backend/pkg/token"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidSession      = errors.New("session is revoked or expired")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+)
+
+const (
+	// sessionTouchInterval — как часто обновлять last_seen_at, чтобы не писать в БД на каждый запрос.
+	sessionTouchInterval = 5 * time.Minute
+	// refreshTokenTTL — сколько живёт сессия без обращений к /auth/refresh.
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// EventPublisher — очередь задач для notifier (реализуется broker.EventBroker).
+type EventPublisher interface {
+	Publish(ctx context.Context, event broker.EventType, task broker.Task) error
+}
+
 type AuthUsecase struct {
-	repo repository.AuthRepository
+	repo    repository.AuthRepository
+	tokens  *token.Manager
+	events  EventPublisher
+	limiter LoginLimiter
+	audit   AuditLogger
 }
 
-func NewAuthUsecase(repo repository.AuthRepository) *AuthUsecase {
-	return &AuthUsecase{repo: repo}
+func NewAuthUsecase(repo repository.AuthRepository, tokens *token.Manager, events EventPublisher, limiter LoginLimiter, audit AuditLogger) *AuthUsecase {
+	return &AuthUsecase{repo: repo, tokens: tokens, events: events, limiter: limiter, audit: audit}
 }
 
 func hashPassword(password string) (string, error) {
@@ -27,19 +57,270 @@ func checkPassword(hash, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
+// newOpaqueToken возвращает случайный токен для клиента и его SHA-256 для хранения в БД.
+func newOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, hashOpaqueToken(raw), nil
+}
+
+func hashOpaqueToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
 func (u *AuthUsecase) Register(ctx context.Context, user *domain.User, password string) error {
 	return errors.New("Public registration is disabled.")
 }
 
-func (u *AuthUsecase) Login(ctx context.Context, email, password string) (*domain.User, error) {
+// Login проверяет пароль с учётом лимита попыток. На любую ошибку учётных данных
+// возвращается ErrInvalidCredentials, чтобы нельзя было перебором узнать существующие email.
+func (u *AuthUsecase) Login(ctx context.Context, email, password string, meta SessionMeta) (*domain.User, error) {
+	emailKey := normalizeEmail(email)
+	if retry := u.lockedFor(ctx, emailKey, meta.IPAddress); retry > 0 {
+		return nil, &LockoutError{RetryAfter: retry}
+	}
+
 	user, err := u.repo.GetByEmail(ctx, email)
-	if err != nil {
-		return nil, errors.New("user not found")
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
 	}
 
-	if err := checkPassword(user.Password, password); err != nil {
-		return nil, errors.New("invalid credentials")
+	if !checkPasswordConstantTime(user, password) {
+		u.recordLoginFailure(ctx, emailKey, user, meta)
+		return nil, ErrInvalidCredentials
 	}
 
+	if err := u.limiter.Reset(ctx, LimitScopeEmail, emailKey); err != nil {
+		slog.Warn("login limiter unavailable", slog.String("error", err.Error()))
+	}
+	// Сообщаем об отключении только после верного пароля, чтобы не раскрывать статус аккаунта.
+	if user.DisabledAt != nil {
+		return nil, ErrAccountDisabled
+	}
 	return user, nil
 }
+
+type SessionMeta struct {
+	DeviceID  string
+	UserAgent string
+	IPAddress string
+}
+
+type IssuedSession struct {
+	Token            string
+	ExpiresAt        time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	Session          *domain.Session
+}
+
+// StartSession создаёт серверную сессию и выдаёт короткий access-токен и refresh-токен к ней.
+// Повторный вход с того же устройства закрывает предыдущую сессию этого устройства.
+// Пользователям с включённой 2FA сессию открывает только CompleteMFALogin.
+func (u *AuthUsecase) StartSession(ctx context.Context, user *domain.User, meta SessionMeta) (*IssuedSession, error) {
+	return u.startSession(ctx, user, meta, false)
+}
+
+func (u *AuthUsecase) startSession(ctx context.Context, user *domain.User, meta SessionMeta, mfaVerified bool) (*IssuedSession, error) {
+	if meta.DeviceID != "" {
+		if err := u.repo.RevokeDeviceSessions(ctx, user.ID, meta.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &domain.Session{
+		UserID:      user.ID,
+		DeviceID:    meta.DeviceID,
+		UserAgent:   meta.UserAgent,
+		IPAddress:   meta.IPAddress,
+		ExpiresAt:   time.Now().Add(refreshTokenTTL),
+		MFAVerified: mfaVerified,
+	}
+	if err := u.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	refresh, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.CreateRefreshToken(ctx, session.ID, refreshHash, session.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return u.issueAccess(ctx, session, user.Role, refresh)
+}
+
+// issueAccess выпускает access-токен. Если роль требует 2FA, а сессия её не прошла,
+// токен помечается mfa_pending и RoleRequiredMiddleware не пустит его в staff-маршруты.
+func (u *AuthUsecase) issueAccess(ctx context.Context, session *domain.Session, role domain.Role, refresh string) (*IssuedSession, error) {
+	pending := false
+	if !session.MFAVerified && IsStaffRole(role) {
+		required, err := u.repo.IsMFARequired(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		pending = required
+	}
+
+	tok, claims, err := u.tokens.IssueWith(token.Claims{
+		UserID:     session.UserID,
+		Role:       string(role),
+		SessionID:  session.ID,
+		MFA:        session.MFAVerified,
+		MFAPending: pending,
+	}, u.tokens.TTL())
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssuedSession{
+		Token:            tok,
+		ExpiresAt:        time.Unix(claims.ExpiresAt, 0),
+		RefreshToken:     refresh,
+		RefreshExpiresAt: session.ExpiresAt,
+		Session:          session,
+	}, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару. Старый токен гасится; если его предъявят
+// повторно, значит он утёк — отзываем всю сессию, и вместе с ней все токены семейства.
+func (u *AuthUsecase) Refresh(ctx context.Context, rawRefresh string) (*IssuedSession, error) {
+	rt, err := u.repo.GetRefreshToken(ctx, hashOpaqueToken(rawRefresh))
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	session, err := u.repo.GetSession(ctx, rt.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if rt.UsedAt != nil {
+		return nil, u.revokeFamily(ctx, session)
+	}
+	now := time.Now()
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) || !now.Before(rt.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := u.repo.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	refresh, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	session.ExpiresAt = now.Add(refreshTokenTTL)
+	rotated, err := u.repo.RotateRefreshToken(ctx, rt.ID, session.ID, refreshHash, session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, u.revokeFamily(ctx, session)
+	}
+
+	return u.issueAccess(ctx, session, user.Role, refresh)
+}
+
+func (u *AuthUsecase) revokeFamily(ctx context.Context, session *domain.Session) error {
+	if _, err := u.repo.RevokeSession(ctx, session.UserID, session.ID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (u *AuthUsecase) ValidateSession(ctx context.Context, userID, sessionID string) error {
+	session, err := u.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ErrInvalidSession
+		}
+		return err
+	}
+
+	if session.UserID != userID || session.RevokedAt != nil || !time.Now().Before(session.ExpiresAt) {
+		return ErrInvalidSession
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		_ = u.repo.TouchSession(ctx, session.ID)
+	}
+	return nil
+}
+
+// EndSession отзывает сессию, к которой привязан access- или refresh-токен.
+// Невалидные токены игнорируются: выходить из системы можно и с протухшей кукой.
+func (u *AuthUsecase) EndSession(ctx context.Context, accessToken, refreshToken string) error {
+	if claims, err := u.tokens.Parse(accessToken); err == nil {
+		_, err = u.repo.RevokeSession(ctx, claims.UserID, claims.SessionID)
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	rt, err := u.repo.GetRefreshToken(ctx, hashOpaqueToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+	session, err := u.repo.GetSession(ctx, rt.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+	_, err = u.repo.RevokeSession(ctx, session.UserID, session.ID)
+	return err
+}
+
+func (u *AuthUsecase) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*domain.Session, error) {
+	sessions, err := u.repo.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sessions == nil {
+		sessions = []*domain.Session{}
+	}
+	for _, s := range sessions {
+		s.IsCurrent = s.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (u *AuthUsecase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	ok, err := u.repo.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return repository.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (u *AuthUsecase) RevokeAllSessions(ctx context.Context, userID string) (int64, error) {
+	return u.repo.RevokeAllSessions(ctx, userID)
+}
@@ -2,21 +2,40 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"lms_backend/internal/auth/mocks"
 	"lms_backend/internal/auth/usecase"
 	"lms_backend/internal/domain"
+	"lms_backend/pkg/token"
+	"lms_backend/pkg/totp"
 )
 
+func newTestUsecase(t *testing.T, repo *mocks.AuthRepositoryMock, tokens *token.Manager, events *mocks.EventPublisherMock) *usecase.AuthUsecase {
+	t.Helper()
+	return usecase.NewAuthUsecase(repo, tokens, events, mocks.NewLoginLimiterMock(5, time.Minute), &mocks.AuditLoggerMock{})
+}
+
+func newTestTokens(t *testing.T) *token.Manager {
+	t.Helper()
+	m, err := token.NewManager(map[string][]byte{"test": []byte(strings.Repeat("k", 32))}, "test", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create token manager: %v", err)
+	}
+	return m
+}
+
 func TestAuthUsecase_Login(t *testing.T) {
 	repoMock := mocks.NewAuthRepositoryMock()
-	uc := usecase.NewAuthUsecase(repoMock)
+	uc := newTestUsecase(t, repoMock, newTestTokens(t), &mocks.EventPublisherMock{})
 
 	ctx := context.Background()
 
 	t.Run("Success Login", func(t *testing.T) {
-		user, err := uc.Login(ctx, "test@lms.ru", "password")
+		user, err := uc.Login(ctx, "test@lms.ru", "password", usecase.SessionMeta{})
 		if err != nil {
 			t.Fatalf("Ожидалась успешная аутентификация, получена ошибка: %v", err)
 		}
@@ -26,14 +45,31 @@ func TestAuthUsecase_Login(t *testing.T) {
 	})
 
 	t.Run("Invalid Password", func(t *testing.T) {
-		_, err := uc.Login(ctx, "test@lms.ru", "wrong_password")
+		_, err := uc.Login(ctx, "test@lms.ru", "wrong_password", usecase.SessionMeta{})
 		if err == nil {
 			t.Error("Ожидалась ошибка 'invalid credentials', но ошибок нет")
 		}
 	})
 
+	t.Run("Disabled Account", func(t *testing.T) {
+		disabledAt := time.Now()
+		repoMock.Users["disabled@lms.ru"] = &domain.User{
+			ID:         "00000000-0000-0000-0000-000000000009",
+			Email:      "disabled@lms.ru",
+			Password:   repoMock.Users["test@lms.ru"].Password,
+			Role:       domain.RoleTeacher,
+			DisabledAt: &disabledAt,
+		}
+		if _, err := uc.Login(ctx, "disabled@lms.ru", "password", usecase.SessionMeta{}); !errors.Is(err, usecase.ErrAccountDisabled) {
+			t.Errorf("expected ErrAccountDisabled, got %v", err)
+		}
+		if _, err := uc.Login(ctx, "disabled@lms.ru", "wrong", usecase.SessionMeta{}); !errors.Is(err, usecase.ErrInvalidCredentials) {
+			t.Errorf("expected ErrInvalidCredentials for wrong password, got %v", err)
+		}
+	})
+
 	t.Run("User Not Found", func(t *testing.T) {
-		_, err := uc.Login(ctx, "unknown@lms.ru", "password")
+		_, err := uc.Login(ctx, "unknown@lms.ru", "password", usecase.SessionMeta{})
 		if err == nil {
 			t.Error("Ожидалась ошибка 'user not found', но ошибок нет")
 		}
@@ -42,7 +78,7 @@ func TestAuthUsecase_Login(t *testing.T) {
 
 func TestAuthUsecase_Register(t *testing.T) {
 	repoMock := mocks.NewAuthRepositoryMock()
-	uc := usecase.NewAuthUsecase(repoMock)
+	uc := newTestUsecase(t, repoMock, newTestTokens(t), &mocks.EventPublisherMock{})
 
 	ctx := context.Background()
 	newPassword := "newpassword"
@@ -60,3 +96,357 @@ func TestAuthUsecase_Register(t *testing.T) {
 		}
 	})
 }
+
+func TestAuthUsecase_Sessions(t *testing.T) {
+	repoMock := mocks.NewAuthRepositoryMock()
+	tokens := newTestTokens(t)
+	uc := newTestUsecase(t, repoMock, tokens, &mocks.EventPublisherMock{})
+
+	ctx := context.Background()
+	user, err := uc.Login(ctx, "test@lms.ru", "password", usecase.SessionMeta{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("TokenBoundToSession", func(t *testing.T) {
+		issued, err := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "laptop"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		claims, err := tokens.Parse(issued.Token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claims.SessionID != issued.Session.ID {
+			t.Errorf("expected sid %s, got %s", issued.Session.ID, claims.SessionID)
+		}
+		if err := uc.ValidateSession(ctx, user.ID, claims.SessionID); err != nil {
+			t.Errorf("expected active session, got %v", err)
+		}
+	})
+
+	t.Run("RevokeOne", func(t *testing.T) {
+		issued, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "phone"})
+		if err := uc.RevokeSession(ctx, user.ID, issued.Session.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := uc.ValidateSession(ctx, user.ID, issued.Session.ID)
+		if !errors.Is(err, usecase.ErrInvalidSession) {
+			t.Errorf("expected ErrInvalidSession, got %v", err)
+		}
+	})
+
+	t.Run("ForeignSessionCannotBeRevoked", func(t *testing.T) {
+		issued, _ := uc.StartSession(ctx, user, usecase.SessionMeta{})
+		if err := uc.RevokeSession(ctx, "another-user", issued.Session.ID); err == nil {
+			t.Error("expected error when revoking someone else's session")
+		}
+	})
+
+	t.Run("SameDeviceReplacesSession", func(t *testing.T) {
+		first, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "tablet"})
+		second, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "tablet"})
+		if err := uc.ValidateSession(ctx, user.ID, first.Session.ID); err == nil {
+			t.Error("expected previous session on the same device to be revoked")
+		}
+		if err := uc.ValidateSession(ctx, user.ID, second.Session.ID); err != nil {
+			t.Errorf("expected new session to be active, got %v", err)
+		}
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		issued, _ := uc.StartSession(ctx, user, usecase.SessionMeta{})
+		if err := uc.EndSession(ctx, issued.Token, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := uc.ValidateSession(ctx, user.ID, issued.Session.ID); err == nil {
+			t.Error("expected session to be revoked after logout")
+		}
+	})
+
+	t.Run("RevokeAll", func(t *testing.T) {
+		current, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "desktop"})
+		if _, err := uc.RevokeAllSessions(ctx, user.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sessions, err := uc.ListSessions(ctx, user.ID, current.Session.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sessions) != 0 {
+			t.Errorf("expected no active sessions, got %d", len(sessions))
+		}
+	})
+}
+
+func TestAuthUsecase_PasswordReset(t *testing.T) {
+	repoMock := mocks.NewAuthRepositoryMock()
+	events := &mocks.EventPublisherMock{}
+	uc := newTestUsecase(t, repoMock, newTestTokens(t), events)
+
+	ctx := context.Background()
+	user, _ := uc.Login(ctx, "test@lms.ru", "password", usecase.SessionMeta{})
+	session, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "laptop"})
+
+	t.Run("UnknownEmailIsSilent", func(t *testing.T) {
+		if err := uc.RequestPasswordReset(ctx, "unknown@lms.ru"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events.Tasks) != 0 {
+			t.Errorf("expected no email for unknown address, got %d", len(events.Tasks))
+		}
+	})
+
+	if err := uc.RequestPasswordReset(ctx, "test@lms.ru"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events.Tasks) != 1 || events.Tasks[0].Type != usecase.TaskPasswordReset {
+		t.Fatalf("expected one PASSWORD_RESET task, got %+v", events.Tasks)
+	}
+	rawToken := events.Tasks[0].Payload["token"]
+
+	t.Run("TokenStoredHashed", func(t *testing.T) {
+		if _, ok := repoMock.ResetTokens[rawToken]; ok {
+			t.Error("raw token must not be stored")
+		}
+	})
+
+	t.Run("WeakPassword", func(t *testing.T) {
+		if err := uc.ResetPassword(ctx, rawToken, "short"); !errors.Is(err, usecase.ErrWeakPassword) {
+			t.Errorf("expected ErrWeakPassword, got %v", err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		if err := uc.ResetPassword(ctx, rawToken, "brand-new-password"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.Login(ctx, "test@lms.ru", "brand-new-password", usecase.SessionMeta{}); err != nil {
+			t.Errorf("expected login with new password, got %v", err)
+		}
+		if err := uc.ValidateSession(ctx, user.ID, session.Session.ID); !errors.Is(err, usecase.ErrInvalidSession) {
+			t.Errorf("expected existing sessions to be revoked, got %v", err)
+		}
+	})
+
+	t.Run("TokenIsSingleUse", func(t *testing.T) {
+		if err := uc.ResetPassword(ctx, rawToken, "another-password"); !errors.Is(err, usecase.ErrInvalidResetToken) {
+			t.Errorf("expected ErrInvalidResetToken, got %v", err)
+		}
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		if err := uc.ResetPassword(ctx, "garbage", "another-password"); !errors.Is(err, usecase.ErrInvalidResetToken) {
+			t.Errorf("expected ErrInvalidResetToken, got %v", err)
+		}
+	})
+}
+
+func TestAuthUsecase_Refresh(t *testing.T) {
+	repoMock := mocks.NewAuthRepositoryMock()
+	tokens := newTestTokens(t)
+	uc := newTestUsecase(t, repoMock, tokens, &mocks.EventPublisherMock{})
+
+	ctx := context.Background()
+	user, _ := uc.Login(ctx, "test@lms.ru", "password", usecase.SessionMeta{})
+
+	t.Run("Rotation", func(t *testing.T) {
+		issued, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "phone"})
+		next, err := uc.Refresh(ctx, issued.RefreshToken)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if next.RefreshToken == issued.RefreshToken {
+			t.Error("expected a new refresh token")
+		}
+		if next.Session.ID != issued.Session.ID {
+			t.Errorf("expected the same session, got %s", next.Session.ID)
+		}
+		claims, err := tokens.Parse(next.Token)
+		if err != nil || claims.Role != string(domain.RoleStudent) {
+			t.Errorf("expected valid access token, got %+v, %v", claims, err)
+		}
+	})
+
+	t.Run("ReuseRevokesFamily", func(t *testing.T) {
+		issued, _ := uc.StartSession(ctx, user, usecase.SessionMeta{DeviceID: "tablet"})
+		next, _ := uc.Refresh(ctx, issued.RefreshToken)
+
+		if _, err := uc.Refresh(ctx, issued.RefreshToken); !errors.Is(err, usecase.ErrRefreshTokenReused) {
+			t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+		}
+		if _, err := uc.Refresh(ctx, next.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Errorf("expected the newest token to be revoked too, got %v", err)
+		}
+		if err := uc.ValidateSession(ctx, user.ID, issued.Session.ID); !errors.Is(err, usecase.ErrInvalidSession) {
+			t.Errorf("expected session to be revoked, got %v", err)
+		}
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		if _, err := uc.Refresh(ctx, "garbage"); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Errorf("expected ErrInvalidRefreshToken, got %v", err)
+		}
+	})
+
+	t.Run("LogoutWithExpiredAccessToken", func(t *testing.T) {
+		issued, _ := uc.StartSession(ctx, user, usecase.SessionMeta{})
+		if err := uc.EndSession(ctx, "expired-access", issued.RefreshToken); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.Refresh(ctx, issued.RefreshToken); !errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			t.Errorf("expected refresh after logout to fail, got %v", err)
+		}
+	})
+}
+
+func TestAuthUsecase_LoginLockout(t *testing.T) {
+	repoMock := mocks.NewAuthRepositoryMock()
+	limiter := mocks.NewLoginLimiterMock(3, time.Minute)
+	audit := &mocks.AuditLoggerMock{}
+	uc := usecase.NewAuthUsecase(repoMock, newTestTokens(t), &mocks.EventPublisherMock{}, limiter, audit)
+
+	ctx := context.Background()
+	meta := usecase.SessionMeta{IPAddress: "10.0.0.1"}
+
+	t.Run("UniformError", func(t *testing.T) {
+		_, errUnknown := uc.Login(ctx, "nobody@lms.ru", "password", meta)
+		_, errWrong := uc.Login(ctx, "test@lms.ru", "wrong", meta)
+		if !errors.Is(errUnknown, usecase.ErrInvalidCredentials) || !errors.Is(errWrong, usecase.ErrInvalidCredentials) {
+			t.Errorf("expected ErrInvalidCredentials for both, got %v / %v", errUnknown, errWrong)
+		}
+		if errUnknown.Error() != errWrong.Error() {
+			t.Errorf("error messages differ: %q vs %q", errUnknown, errWrong)
+		}
+	})
+
+	t.Run("SuccessResetsEmailCounter", func(t *testing.T) {
+		if _, err := uc.Login(ctx, "test@lms.ru", "password", meta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if limiter.Failures["email:test@lms.ru"] != 0 {
+			t.Errorf("expected email counter reset, got %d", limiter.Failures["email:test@lms.ru"])
+		}
+	})
+
+	t.Run("LockedAfterThreshold", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			uc.Login(ctx, "Test@lms.ru", "wrong", usecase.SessionMeta{IPAddress: "10.0.0.2"})
+		}
+		_, err := uc.Login(ctx, "test@lms.ru", "password", usecase.SessionMeta{IPAddress: "10.0.0.3"})
+		var lockout *usecase.LockoutError
+		if !errors.As(err, &lockout) || lockout.RetryAfter <= 0 {
+			t.Fatalf("expected LockoutError even with correct password, got %v", err)
+		}
+		if audit.Count("LOGIN_LOCKED") == 0 {
+			t.Error("expected LOGIN_LOCKED audit entry")
+		}
+		if audit.Count("LOGIN_FAILED") < 3 {
+			t.Errorf("expected LOGIN_FAILED audit entries, got %d", audit.Count("LOGIN_FAILED"))
+		}
+	})
+}
+
+func TestAuthUsecase_MFA(t *testing.T) {
+	repoMock := mocks.NewAuthRepositoryMock()
+	repoMock.Users["admin@lms.ru"] = &domain.User{ID: "00000000-0000-0000-0000-000000000002", Email: "admin@lms.ru", Role: domain.RoleAdmin}
+	repoMock.Users["curator@lms.ru"] = &domain.User{ID: "00000000-0000-0000-0000-000000000003", Email: "curator@lms.ru", Role: domain.RoleCurator}
+	tokens := newTestTokens(t)
+	uc := newTestUsecase(t, repoMock, tokens, &mocks.EventPublisherMock{})
+
+	ctx := context.Background()
+	admin, _ := repoMock.GetByEmail(ctx, "admin@lms.ru")
+	curator, _ := repoMock.GetByEmail(ctx, "curator@lms.ru")
+	student, _ := repoMock.GetByEmail(ctx, "test@lms.ru")
+	now := time.Now()
+
+	t.Run("StudentCannotEnroll", func(t *testing.T) {
+		if _, err := uc.BeginMFAEnrollment(ctx, student.ID); !errors.Is(err, usecase.ErrMFANotAvailable) {
+			t.Errorf("expected ErrMFANotAvailable, got %v", err)
+		}
+	})
+
+	session, _ := uc.StartSession(ctx, admin, usecase.SessionMeta{})
+	enrollment, err := uc.BeginMFAEnrollment(ctx, admin.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(enrollment.URI, "otpauth://totp/") {
+		t.Errorf("unexpected uri: %s", enrollment.URI)
+	}
+
+	t.Run("NoChallengeBeforeConfirm", func(t *testing.T) {
+		if challenge, _ := uc.MFAChallenge(ctx, admin); challenge != "" {
+			t.Error("2FA must not be active before confirmation")
+		}
+	})
+
+	code, _ := totp.Code(enrollment.Secret, totp.Step(now))
+	recoveryCodes, err := uc.ConfirmMFAEnrollment(ctx, admin.ID, session.Session.ID, code, usecase.SessionMeta{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recoveryCodes) != 10 {
+		t.Errorf("expected 10 recovery codes, got %d", len(recoveryCodes))
+	}
+
+	challenge, err := uc.MFAChallenge(ctx, admin)
+	if err != nil || challenge == "" {
+		t.Fatalf("expected challenge after enrollment, got %q, %v", challenge, err)
+	}
+
+	t.Run("ChallengeIsNotAccessToken", func(t *testing.T) {
+		if _, err := tokens.Parse(challenge); err == nil {
+			t.Error("challenge token must not pass as access token")
+		}
+	})
+
+	t.Run("ReplayedCodeRejected", func(t *testing.T) {
+		if _, _, err := uc.CompleteMFALogin(ctx, challenge, code, "", usecase.SessionMeta{}); !errors.Is(err, usecase.ErrInvalidMFACode) {
+			t.Errorf("expected ErrInvalidMFACode for reused code, got %v", err)
+		}
+	})
+
+	t.Run("SecondStep", func(t *testing.T) {
+		next, _ := totp.Code(enrollment.Secret, totp.Step(now)+1)
+		_, issued, err := uc.CompleteMFALogin(ctx, challenge, next, "", usecase.SessionMeta{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		claims, _ := tokens.Parse(issued.Token)
+		if !claims.MFA {
+			t.Error("expected mfa claim after second step")
+		}
+	})
+
+	t.Run("RecoveryCodeSingleUse", func(t *testing.T) {
+		if _, _, err := uc.CompleteMFALogin(ctx, challenge, "", strings.ToUpper(recoveryCodes[0]), usecase.SessionMeta{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := uc.CompleteMFALogin(ctx, challenge, "", recoveryCodes[0], usecase.SessionMeta{}); !errors.Is(err, usecase.ErrInvalidMFACode) {
+			t.Errorf("expected ErrInvalidMFACode for used recovery code, got %v", err)
+		}
+	})
+
+	t.Run("RolePolicyMarksPending", func(t *testing.T) {
+		if err := uc.SetMFARequired(ctx, admin.ID, domain.RoleCurator, true, usecase.SessionMeta{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		issued, _ := uc.StartSession(ctx, curator, usecase.SessionMeta{})
+		claims, _ := tokens.Parse(issued.Token)
+		if !claims.MFAPending {
+			t.Error("expected mfa_pending for curator without 2FA")
+		}
+		if err := uc.SetMFARequired(ctx, admin.ID, domain.RoleStudent, true, usecase.SessionMeta{}); !errors.Is(err, usecase.ErrMFANotAvailable) {
+			t.Errorf("expected ErrMFANotAvailable for student role, got %v", err)
+		}
+	})
+
+	t.Run("DisableBlockedWhenRequired", func(t *testing.T) {
+		uc.SetMFARequired(ctx, admin.ID, domain.RoleAdmin, true, usecase.SessionMeta{})
+		current, _ := totp.Code(enrollment.Secret, totp.Step(now)-1)
+		if err := uc.DisableMFA(ctx, admin.ID, current, usecase.SessionMeta{}); !errors.Is(err, usecase.ErrMFARequiredByRole) {
+			t.Errorf("expected ErrMFARequiredByRole, got %v", err)
+		}
+	})
+}
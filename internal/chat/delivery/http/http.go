@@ -52,7 +52,7 @@ func NewChatHandler(uc *usecase.ChatUseCase) *ChatHandler {
 // @Router /chat/ws [get]
 func (h *ChatHandler) ConnectToChat(w http.ResponseWriter, r *http.Request) {
 	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
-	if !ok {
+	if !ok || userCtxData == nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -69,6 +69,11 @@ func (h *ChatHandler) ConnectToChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.uc.CanJoinRoom(r.Context(), userCtxData.Actor(), studentID); err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		return
@@ -155,9 +160,9 @@ func (h *ChatHandler) GetChatHistory(w http.ResponseWriter, r *http.Request) {
 		limit = 50
 	}
 
-	history, err := h.uc.GetHistory(r.Context(), moduleID, studentID, limit, offset)
+	history, err := h.uc.GetHistory(r.Context(), userCtxData.Actor(), moduleID, studentID, limit, offset)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -7,19 +7,31 @@ import (
 	"sync"
 )
 
+// ScopeChecker — проверка доступа к ученику (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+}
+
 type ChatUseCase struct {
 	repo    repository.ChatRepository
+	scope   ScopeChecker
 	mu      sync.RWMutex
 	clients map[string]map[chan *domain.ChatMessage]bool
 }
 
-func NewChatUseCase(repo repository.ChatRepository) *ChatUseCase {
+func NewChatUseCase(repo repository.ChatRepository, scope ScopeChecker) *ChatUseCase {
 	return &ChatUseCase{
 		repo:    repo,
+		scope:   scope,
 		clients: make(map[string]map[chan *domain.ChatMessage]bool),
 	}
 }
 
+// CanJoinRoom — комната чата принадлежит ученику: войти может он сам и сотрудники, которые с ним работают.
+func (uc *ChatUseCase) CanJoinRoom(ctx context.Context, actor domain.Actor, studentID string) error {
+	return uc.scope.CanAccessStudent(ctx, actor, studentID)
+}
+
 func (uc *ChatUseCase) SendMessage(ctx context.Context, msg *domain.ChatMessage) error {
 	if err := uc.repo.SaveMessage(ctx, msg); err != nil {
 		return err
@@ -38,7 +50,10 @@ func (uc *ChatUseCase) SendMessage(ctx context.Context, msg *domain.ChatMessage)
 	return nil
 }
 
-func (uc *ChatUseCase) GetHistory(ctx context.Context, moduleID, studentID string, limit, offset int) ([]*domain.ChatMessage, error) {
+func (uc *ChatUseCase) GetHistory(ctx context.Context, actor domain.Actor, moduleID, studentID string, limit, offset int) ([]*domain.ChatMessage, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetHistory(ctx, moduleID, studentID, limit, offset)
 }
 
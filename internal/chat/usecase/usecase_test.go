@@ -2,16 +2,23 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"lms_backend/internal/chat/mocks"
 	"lms_backend/internal/chat/usecase"
 	"lms_backend/internal/domain"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 )
 
+func newUseCase(repo *mocks.ChatRepoMock) *usecase.ChatUseCase {
+	return usecase.NewChatUseCase(repo, scopeUseCase.NewScopeUseCase(scopeMocks.TwoGroups()))
+}
+
 func TestSendMessage(t *testing.T) {
 	repoMock := mocks.NewChatRepoMock()
-	uc := usecase.NewChatUseCase(repoMock)
+	uc := newUseCase(repoMock)
 
 	ctx := context.Background()
 
@@ -35,3 +42,29 @@ func TestSendMessage(t *testing.T) {
 		t.Error("Message text mismatch")
 	}
 }
+
+func TestChatScope(t *testing.T) {
+	uc := newUseCase(mocks.NewChatRepoMock())
+	ctx := context.Background()
+
+	t.Run("TeacherOtherGroupHistory", func(t *testing.T) {
+		teacher := domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+		if _, err := uc.GetHistory(ctx, teacher, "mod-1", "student-b", 50, 0); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("CuratorOtherGroupRoom", func(t *testing.T) {
+		curator := domain.Actor{UserID: "curator-b", Role: domain.RoleCurator}
+		if err := uc.CanJoinRoom(ctx, curator, "student-a"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("OwnStudent", func(t *testing.T) {
+		teacher := domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+		if _, err := uc.GetHistory(ctx, teacher, "mod-1", "student-a", 50, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
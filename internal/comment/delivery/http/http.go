@@ -39,16 +39,15 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
-	if !ok || userCtxData == nil {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userID := userCtxData.UserID
 
-	err := h.uc.CreateComment(r.Context(), req.StudentID, req.LessonID, userID, req.RecipientID, req.Content, req.ParentCommentID)
+	err := h.uc.CreateComment(r.Context(), actor, req.StudentID, req.LessonID, req.RecipientID, req.Content, req.ParentCommentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -63,15 +62,20 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {array} domain.Comment
 // @Router /api/comments [get]
 func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := r.URL.Query().Get("studentId")
 	if studentID == "" {
 		http.Error(w, "studentId is required", http.StatusBadRequest)
 		return
 	}
 
-	comments, err := h.uc.GetStudentComments(r.Context(), studentID)
+	comments, err := h.uc.GetStudentComments(r.Context(), actor, studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -86,11 +90,16 @@ func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]string
 // @Router /api/comments/{commentId}/read [patch]
 func (h *CommentHandler) MarkCommentAsRead(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	commentID := chi.URLParam(r, "commentId")
 
-	err := h.uc.MarkAsRead(r.Context(), commentID)
+	err := h.uc.MarkAsRead(r.Context(), actor, commentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
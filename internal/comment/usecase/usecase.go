@@ -7,25 +7,34 @@ import (
 )
 
 type CommentUseCase interface {
-	CreateComment(ctx context.Context, studentID string, lessonID *string, authorID string, recipientID *string, content string, parentCommentID *string) error
-	GetStudentComments(ctx context.Context, studentID string) ([]*domain.Comment, error)
-	MarkAsRead(ctx context.Context, commentID string) error
+	CreateComment(ctx context.Context, actor domain.Actor, studentID string, lessonID *string, recipientID *string, content string, parentCommentID *string) error
+	GetStudentComments(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.Comment, error)
+	MarkAsRead(ctx context.Context, actor domain.Actor, commentID string) error
 	GetUnreadComments(ctx context.Context, recipientID string) ([]*domain.Comment, error)
 }
 
+// ScopeChecker — проверка доступа к ученику (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+}
+
 type commentUseCase struct {
-	repo repository.CommentRepository
+	repo  repository.CommentRepository
+	scope ScopeChecker
 }
 
-func NewCommentUseCase(repo repository.CommentRepository) CommentUseCase {
-	return &commentUseCase{repo: repo}
+func NewCommentUseCase(repo repository.CommentRepository, scope ScopeChecker) CommentUseCase {
+	return &commentUseCase{repo: repo, scope: scope}
 }
 
-func (uc *commentUseCase) CreateComment(ctx context.Context, studentID string, lessonID *string, authorID string, recipientID *string, content string, parentCommentID *string) error {
+func (uc *commentUseCase) CreateComment(ctx context.Context, actor domain.Actor, studentID string, lessonID *string, recipientID *string, content string, parentCommentID *string) error {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return err
+	}
 	comment := &domain.Comment{
 		StudentID:       studentID,
 		LessonID:        lessonID,
-		AuthorID:        authorID,
+		AuthorID:        actor.UserID,
 		RecipientID:     recipientID,
 		Content:         content,
 		ParentCommentID: parentCommentID,
@@ -33,11 +42,21 @@ func (uc *commentUseCase) CreateComment(ctx context.Context, studentID string, l
 	return uc.repo.Create(ctx, comment)
 }
 
-func (uc *commentUseCase) GetStudentComments(ctx context.Context, studentID string) ([]*domain.Comment, error) {
+func (uc *commentUseCase) GetStudentComments(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.Comment, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetByStudent(ctx, studentID)
 }
 
-func (uc *commentUseCase) MarkAsRead(ctx context.Context, commentID string) error {
+func (uc *commentUseCase) MarkAsRead(ctx context.Context, actor domain.Actor, commentID string) error {
+	comment, err := uc.repo.GetByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, comment.StudentID); err != nil {
+		return err
+	}
 	return uc.repo.MarkAsRead(ctx, commentID)
 }
 
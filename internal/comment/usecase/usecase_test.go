@@ -2,24 +2,37 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"lms_backend/internal/comment/mocks"
 	"lms_backend/internal/comment/usecase"
+	"lms_backend/internal/domain"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 )
 
+var teacher = domain.Actor{UserID: "teacher-1", Role: domain.RoleTeacher}
+
+// newUseCase — teacher-1 ведёт student-1.
+func newUseCase(repo *mocks.CommentRepositoryMock) usecase.CommentUseCase {
+	scopeRepo := scopeMocks.TwoGroups()
+	scopeRepo.TeacherStudents["teacher-1"] = []string{"student-1"}
+	return usecase.NewCommentUseCase(repo, scopeUseCase.NewScopeUseCase(scopeRepo))
+}
+
 func TestCommentUseCase_CreateAndGet(t *testing.T) {
 	repoMock := mocks.NewCommentRepositoryMock()
-	uc := usecase.NewCommentUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 
 	t.Run("CreateAndGetByStudent", func(t *testing.T) {
-		err := uc.CreateComment(ctx, "student-1", nil, "author-1", nil, "Great job!", nil)
+		err := uc.CreateComment(ctx, teacher, "student-1", nil, nil, "Great job!", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		comments, err := uc.GetStudentComments(ctx, "student-1")
+		comments, err := uc.GetStudentComments(ctx, teacher, "student-1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -33,7 +46,7 @@ func TestCommentUseCase_CreateAndGet(t *testing.T) {
 
 	t.Run("CreateWithRecipient", func(t *testing.T) {
 		recipientID := "teacher-1"
-		err := uc.CreateComment(ctx, "student-1", nil, "author-1", &recipientID, "Please check", nil)
+		err := uc.CreateComment(ctx, teacher, "student-1", nil, &recipientID, "Please check", nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -42,11 +55,12 @@ func TestCommentUseCase_CreateAndGet(t *testing.T) {
 
 func TestCommentUseCase_MarkAsRead(t *testing.T) {
 	repoMock := mocks.NewCommentRepositoryMock()
-	uc := usecase.NewCommentUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 
 	recipientID := "teacher-1"
-	err := uc.CreateComment(ctx, "student-1", nil, "student-1", &recipientID, "Please check", nil)
+	student := domain.Actor{UserID: "student-1", Role: domain.RoleStudent}
+	err := uc.CreateComment(ctx, student, "student-1", nil, &recipientID, "Please check", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -63,7 +77,7 @@ func TestCommentUseCase_MarkAsRead(t *testing.T) {
 
 	t.Run("MarkAsRead", func(t *testing.T) {
 		comments, _ := uc.GetUnreadComments(ctx, "teacher-1")
-		err := uc.MarkAsRead(ctx, comments[0].ID)
+		err := uc.MarkAsRead(ctx, teacher, comments[0].ID)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -74,3 +88,37 @@ func TestCommentUseCase_MarkAsRead(t *testing.T) {
 		}
 	})
 }
+
+func TestCommentUseCase_CrossGroupAccess(t *testing.T) {
+	repoMock := mocks.NewCommentRepositoryMock()
+	uc := newUseCase(repoMock)
+	ctx := context.Background()
+	teacherA := domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+	teacherB := domain.Actor{UserID: "teacher-b", Role: domain.RoleTeacher}
+
+	if err := uc.CreateComment(ctx, teacherA, "student-a", nil, nil, "Well done", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("CreateForForeignStudent", func(t *testing.T) {
+		err := uc.CreateComment(ctx, teacherB, "student-a", nil, nil, "Hi", nil)
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("ReadForeignComments", func(t *testing.T) {
+		_, err := uc.GetStudentComments(ctx, teacherB, "student-a")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("MarkForeignComment", func(t *testing.T) {
+		comments, _ := uc.GetStudentComments(ctx, teacherA, "student-a")
+		err := uc.MarkAsRead(ctx, teacherB, comments[0].ID)
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
@@ -2,7 +2,9 @@ package http
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
 	"lms_backend/internal/content_admin/usecase"
 	"lms_backend/internal/domain"
 	"lms_backend/internal/httperror"
@@ -22,6 +25,7 @@ type ContentAdminService interface {
 	CreateCourse(ctx context.Context, input usecase.CreateCourseInput) (string, error)
 	UploadMedia(ctx context.Context, fileHeader *multipart.FileHeader) (string, error)
 	UpdateCourseSettings(ctx context.Context, input usecase.UpdateCourseSettingsInput) error
+	UpdateGradeScheme(ctx context.Context, courseID string, scheme domain.GradeScheme) error
 	GetAllCourses(ctx context.Context) ([]*domain.Course, error)
 	GetCourseStructure(ctx context.Context, courseID string) (*domain.CourseStructure, error)
 	CreateModule(ctx context.Context, input usecase.CreateModuleInput) (string, error)
@@ -36,17 +40,17 @@ type ContentAdminService interface {
 	CreateLessonsBulk(ctx context.Context, input []usecase.CreateLessonInput) ([]string, error)
 	CreateFullCourse(ctx context.Context, input usecase.CreateBulkCourseInput) (string, error)
 	CreateFullUser(ctx context.Context, input usecase.ExtendedCreateUserInput) (map[string]string, error)
-	GetUserInfo(ctx context.Context, userID string) (map[string]interface{}, error)
-	UpdateUser(ctx context.Context, userID string, input usecase.ExtendedCreateUserInput) error
+	GetUserInfo(ctx context.Context, actor domain.Actor, userID string) (map[string]interface{}, error)
+	UpdateUser(ctx context.Context, actor domain.Actor, userID string, input usecase.ExtendedCreateUserInput) error
 	DeleteUser(ctx context.Context, userID string) error
-	GetUsersList(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error)
-	GetDetailedStudents(ctx context.Context, courseID string) ([]*domain.StudentTableItem, error)
+	GetUsersList(ctx context.Context, actor domain.Actor, filter domain.UserFilter) ([]*domain.User, error)
+	GetDetailedStudents(ctx context.Context, actor domain.Actor, courseID string) ([]*domain.StudentTableItem, error)
 	GetDetailedTeachers(ctx context.Context) ([]*domain.TeacherTableItem, error)
 	GetDetailedCurators(ctx context.Context) ([]*domain.CuratorTableItem, error)
 	GetDetailedModerators(ctx context.Context) ([]*domain.ModeratorTableItem, error)
-	GetAllUsersTable(ctx context.Context) ([]*domain.AllUsersTableItem, error)
+	GetAllUsersTable(ctx context.Context, actor domain.Actor) ([]*domain.AllUsersTableItem, error)
 	EnrollStudent(ctx context.Context, userID, courseID string) error
-	GetCourseStudents(ctx context.Context, courseID string) ([]*domain.AdminStudentProgress, error)
+	GetCourseStudents(ctx context.Context, actor domain.Actor, courseID string) ([]*domain.AdminStudentProgress, error)
 	GetCourseStats(ctx context.Context, courseID string) (*domain.AdminCourseStats, error)
 	CreateStream(ctx context.Context, input usecase.CreateStreamInput) (string, error)
 	GetStreamsByCourse(ctx context.Context, courseID string) ([]*domain.Stream, error)
@@ -57,17 +61,39 @@ type ContentAdminService interface {
 	GetLesson(ctx context.Context, lessonID string) (*domain.Lesson, error)
 	GetTest(ctx context.Context, id string) (*domain.Test, error)
 	GetProject(ctx context.Context, id string) (*domain.Project, error)
+	GetLessonRubric(ctx context.Context, lessonID string) (*domain.Rubric, error)
+	SetLessonRubric(ctx context.Context, lessonID string, criteria []domain.RubricCriterion) (*domain.Rubric, error)
+	GetProjectRubric(ctx context.Context, projectID string) (*domain.Rubric, error)
+	SetProjectRubric(ctx context.Context, projectID string, criteria []domain.RubricCriterion) (*domain.Rubric, error)
+	GetLessonDeadline(ctx context.Context, lessonID string) (*domain.AssignmentDeadline, error)
+	SetLessonDeadline(ctx context.Context, lessonID string, deadline domain.AssignmentDeadline) (*domain.AssignmentDeadline, error)
+	UnlockLesson(ctx context.Context, lessonID, studentID, adminID string) error
+	RelockLesson(ctx context.Context, lessonID, studentID string) error
 	LinkTeachersToCourse(ctx context.Context, courseID string, teacherIDs []string) error
 	CancelLesson(ctx context.Context, lessonID, reason string) error
 	SubstituteTeacher(ctx context.Context, lessonID, teacherID string) error
 }
 
 type ContentAdminHandler struct {
-	uc ContentAdminService
+	uc    ContentAdminService
+	perms authMiddleware.PermissionChecker
 }
 
-func NewContentAdminHandler(uc ContentAdminService) *ContentAdminHandler {
-	return &ContentAdminHandler{uc: uc}
+func NewContentAdminHandler(uc ContentAdminService, perms authMiddleware.PermissionChecker) *ContentAdminHandler {
+	return &ContentAdminHandler{uc: uc, perms: perms}
+}
+
+// canEditBalance — есть ли у текущего сотрудника право finance.balance.
+func (h *ContentAdminHandler) canEditBalance(r *http.Request) (bool, error) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		return false, nil
+	}
+	return h.perms.HasPermission(r.Context(), userCtx.Role, domain.PermFinanceBalance)
+}
+
+type UnlockLessonRequest struct {
+	StudentID string `json:"student_id"`
 }
 
 type CreateLessonRequest struct {
@@ -111,7 +137,7 @@ type CreateFullUserRequest struct {
 	Parents                []usecase.ParentInfo `json:"parents"`
 	IntroBroadcastURL      string               `json:"intro_broadcast_url"`
 	GraduationBroadcastURL string               `json:"graduation_broadcast_url"`
-	Balance                float64              `json:"balance"`
+	Balance                *float64             `json:"balance,omitempty"`
 }
 
 type EnrollRequest struct {
@@ -126,6 +152,33 @@ type CreateTestRequest struct {
 	Title        string `json:"title"`
 	Description  string `json:"description"`
 	PassingScore int    `json:"passing_score"`
+	// never | after_submit (по умолчанию) | after_pass
+	AnswersReveal string `json:"answers_reveal"`
+	// 0 — без ограничения
+	TimeLimitMin int `json:"time_limit_min" example:"30"`
+	MaxAttempts  int `json:"max_attempts" example:"3"`
+	// best (по умолчанию) | last
+	Scoring          string `json:"scoring" example:"best"`
+	ShuffleQuestions bool   `json:"shuffle_questions"`
+	ShuffleOptions   bool   `json:"shuffle_options"`
+	// сколько вопросов случайно выбрать в попытку; 0 — все
+	PoolSize  int                   `json:"pool_size"`
+	Questions []TestQuestionRequest `json:"questions"`
+}
+
+// TestQuestionRequest — вопрос теста. Ключ ответа зависит от type:
+// single/numeric/text — correct_answer, multiple/ordering/matching — correct_answers.
+type TestQuestionRequest struct {
+	Type           string   `json:"type" example:"multiple"`
+	Question       string   `json:"question"`
+	Options        []string `json:"options"`
+	MatchOptions   []string `json:"match_options"`
+	CorrectAnswer  string   `json:"correct_answer"`
+	CorrectAnswers []string `json:"correct_answers"`
+	Tolerance      float64  `json:"tolerance"`
+	// по умолчанию true
+	PartialCredit *bool `json:"partial_credit"`
+	Points        int   `json:"points"`
 }
 
 type CreateProjectRequest struct {
@@ -307,6 +360,38 @@ func (h *ContentAdminHandler) UpdateCourseSettings(w http.ResponseWriter, r *htt
 	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
 }
 
+// UpdateGradeScheme godoc
+// @Summary ADMIN: Схема оценивания ДЗ курса
+// @Description numeric (min, max, step), letter (letters), pass_fail или rubric (max).
+// @Tags Admin-Content
+// @Accept json
+// @Produce json
+// @Param id path string true "ID курса"
+// @Param request body domain.GradeScheme true "Схема оценивания"
+// @Success 200 {object} map[string]string "status: updated"
+// @Router /admin/courses/{id}/grading-scheme [put]
+func (h *ContentAdminHandler) UpdateGradeScheme(w http.ResponseWriter, r *http.Request) {
+	var scheme domain.GradeScheme
+	if err := json.NewDecoder(r.Body).Decode(&scheme); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if err := h.uc.UpdateGradeScheme(r.Context(), chi.URLParam(r, "id"), scheme); err != nil {
+		if errors.Is(err, domain.ErrInvalidGradeScheme) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			httperror.NotFound(w, err)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+}
+
 // GetAllCourses godoc
 // @Summary ADMIN: Список всех курсов
 // @Tags Admin-Content
@@ -513,15 +598,46 @@ func (h *ContentAdminHandler) CreateTest(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	input := usecase.CreateTestInput{
-		CourseID:     req.CourseID,
-		LessonNumber: req.LessonNumber,
-		LessonID:     req.LessonID,
-		Title:        req.Title,
-		Description:  req.Description,
-		PassingScore: req.PassingScore,
+		CourseID:      req.CourseID,
+		LessonNumber:  req.LessonNumber,
+		LessonID:      req.LessonID,
+		Title:         req.Title,
+		Description:   req.Description,
+		PassingScore:  req.PassingScore,
+		AnswersReveal: domain.AnswersReveal(req.AnswersReveal),
+		Policy: domain.TestPolicy{
+			TimeLimitMin:     req.TimeLimitMin,
+			MaxAttempts:      req.MaxAttempts,
+			Scoring:          domain.TestScoring(req.Scoring),
+			ShuffleQuestions: req.ShuffleQuestions,
+			ShuffleOptions:   req.ShuffleOptions,
+			PoolSize:         req.PoolSize,
+		},
+	}
+	for _, q := range req.Questions {
+		partial := true
+		if q.PartialCredit != nil {
+			partial = *q.PartialCredit
+		}
+		input.Questions = append(input.Questions, domain.TestQuestion{
+			Type:           domain.QuestionType(q.Type),
+			Question:       q.Question,
+			Options:        q.Options,
+			MatchOptions:   q.MatchOptions,
+			CorrectAnswer:  q.CorrectAnswer,
+			CorrectAnswers: q.CorrectAnswers,
+			Tolerance:      q.Tolerance,
+			PartialCredit:  partial,
+			Points:         q.Points,
+		})
 	}
 	id, err := h.uc.CreateTest(r.Context(), input)
 	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAnswersReveal) || errors.Is(err, usecase.ErrInvalidQuestion) ||
+			errors.Is(err, usecase.ErrInvalidTestPolicy) {
+			httperror.BadRequest(w, err)
+			return
+		}
 		httperror.Internal(w, err)
 		return
 	}
@@ -692,9 +808,19 @@ func (h *ContentAdminHandler) CreateUser(w http.ResponseWriter, r *http.Request)
 		GraduationBroadcastURL: req.GraduationBroadcastURL,
 		Balance:                req.Balance,
 	}
+	canEdit, err := h.canEditBalance(r)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	input.CanEditBalance = canEdit
 
 	result, err := h.uc.CreateFullUser(r.Context(), input)
 	if err != nil {
+		if errors.Is(err, usecase.ErrBalanceForbidden) {
+			http.Error(w, "Forbidden: missing permission "+domain.PermFinanceBalance, http.StatusForbidden)
+			return
+		}
 		slog.Error("creating user", logger.Err(err))
 		httperror.Internal(w, err)
 		return
@@ -711,8 +837,17 @@ func (h *ContentAdminHandler) CreateUser(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} map[string]interface{}
 // @Router /admin/users/{id} [get]
 func (h *ContentAdminHandler) GetUserInfo(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	userID := chi.URLParam(r, "id")
-	res, err := h.uc.GetUserInfo(r.Context(), userID)
+	res, err := h.uc.GetUserInfo(r.Context(), actor, userID)
+	if errors.Is(err, domain.ErrOutOfScope) {
+		httperror.Respond(w, err)
+		return
+	}
 	if err != nil {
 		httperror.NotFound(w, err)
 		return
@@ -729,6 +864,11 @@ func (h *ContentAdminHandler) GetUserInfo(w http.ResponseWriter, r *http.Request
 // @Param id path string true "UserID"
 // @Router /admin/users/{id} [put]
 func (h *ContentAdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	userID := chi.URLParam(r, "id")
 	var req CreateFullUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -769,12 +909,24 @@ func (h *ContentAdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request)
 		GraduationBroadcastURL: req.GraduationBroadcastURL,
 		Balance:                req.Balance,
 	}
-
-	if err := h.uc.UpdateUser(r.Context(), userID, input); err != nil {
-		slog.Error("updating user", logger.Err(err))
+	canEdit, err := h.canEditBalance(r)
+	if err != nil {
 		httperror.Internal(w, err)
 		return
 	}
+	input.CanEditBalance = canEdit
+
+	if err := h.uc.UpdateUser(r.Context(), actor, userID, input); err != nil {
+		if errors.Is(err, usecase.ErrBalanceForbidden) {
+			http.Error(w, "Forbidden: missing permission "+domain.PermFinanceBalance, http.StatusForbidden)
+			return
+		}
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			slog.Error("updating user", logger.Err(err))
+		}
+		httperror.Respond(w, err)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
 }
@@ -803,6 +955,11 @@ func (h *ContentAdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request)
 // @Success 200 {array} domain.User
 // @Router /admin/users [get]
 func (h *ContentAdminHandler) GetUsersList(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 
@@ -811,7 +968,7 @@ func (h *ContentAdminHandler) GetUsersList(w http.ResponseWriter, r *http.Reques
 		Limit:  limit,
 		Offset: offset,
 	}
-	users, err := h.uc.GetUsersList(r.Context(), filter)
+	users, err := h.uc.GetUsersList(r.Context(), actor, filter)
 	if err != nil {
 		httperror.Internal(w, err)
 		return
@@ -828,8 +985,13 @@ func (h *ContentAdminHandler) GetUsersList(w http.ResponseWriter, r *http.Reques
 // @Success 200 {array} domain.StudentTableItem
 // @Router /admin/students/detailed [get]
 func (h *ContentAdminHandler) GetDetailedStudents(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	courseID := r.URL.Query().Get("course_id")
-	list, err := h.uc.GetDetailedStudents(r.Context(), courseID)
+	list, err := h.uc.GetDetailedStudents(r.Context(), actor, courseID)
 	if err != nil {
 		httperror.Internal(w, err)
 		return
@@ -893,7 +1055,12 @@ func (h *ContentAdminHandler) GetDetailedModerators(w http.ResponseWriter, r *ht
 // @Success 200 {array} domain.AllUsersTableItem
 // @Router /admin/users/all [get]
 func (h *ContentAdminHandler) GetAllUsersTable(w http.ResponseWriter, r *http.Request) {
-	list, err := h.uc.GetAllUsersTable(r.Context())
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := h.uc.GetAllUsersTable(r.Context(), actor)
 	if err != nil {
 		httperror.Internal(w, err)
 		return
@@ -934,7 +1101,12 @@ func (h *ContentAdminHandler) EnrollUser(w http.ResponseWriter, r *http.Request)
 // @Success 200 {array} domain.AdminStudentProgress
 // @Router /admin/courses/{id}/students [get]
 func (h *ContentAdminHandler) GetCourseStudents(w http.ResponseWriter, r *http.Request) {
-	students, err := h.uc.GetCourseStudents(r.Context(), chi.URLParam(r, "id"))
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	students, err := h.uc.GetCourseStudents(r.Context(), actor, chi.URLParam(r, "id"))
 	if err != nil {
 		httperror.Internal(w, err)
 		return
@@ -1200,3 +1372,209 @@ func (h *ContentAdminHandler) GetProject(w http.ResponseWriter, r *http.Request)
 	}
 	json.NewEncoder(w).Encode(proj)
 }
+
+type RubricRequest struct {
+	Criteria []domain.RubricCriterion `json:"criteria"`
+}
+
+func rubricError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRubric):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		httperror.NotFound(w, err)
+	default:
+		httperror.Internal(w, err)
+	}
+}
+
+func writeRubric(w http.ResponseWriter, rubric *domain.Rubric) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rubric)
+}
+
+// GetLessonRubric godoc
+// @Summary ADMIN: Рубрика ДЗ урока
+// @Tags Admin-Content
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {object} domain.Rubric
+// @Router /admin/lessons/{id}/rubric [get]
+func (h *ContentAdminHandler) GetLessonRubric(w http.ResponseWriter, r *http.Request) {
+	rubric, err := h.uc.GetLessonRubric(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		rubricError(w, err)
+		return
+	}
+	writeRubric(w, rubric)
+}
+
+// SetLessonRubric godoc
+// @Summary ADMIN: Задать рубрику ДЗ урока
+// @Description Критерии заменяются целиком; пустой список убирает рубрику.
+// @Tags Admin-Content
+// @Accept json
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param request body RubricRequest true "Критерии"
+// @Success 200 {object} domain.Rubric
+// @Router /admin/lessons/{id}/rubric [put]
+func (h *ContentAdminHandler) SetLessonRubric(w http.ResponseWriter, r *http.Request) {
+	var req RubricRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	rubric, err := h.uc.SetLessonRubric(r.Context(), chi.URLParam(r, "id"), req.Criteria)
+	if err != nil {
+		rubricError(w, err)
+		return
+	}
+	writeRubric(w, rubric)
+}
+
+// GetProjectRubric godoc
+// @Summary ADMIN: Рубрика проекта
+// @Tags Admin-Content
+// @Produce json
+// @Param id path string true "ID проекта"
+// @Success 200 {object} domain.Rubric
+// @Router /admin/projects/{id}/rubric [get]
+func (h *ContentAdminHandler) GetProjectRubric(w http.ResponseWriter, r *http.Request) {
+	rubric, err := h.uc.GetProjectRubric(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		rubricError(w, err)
+		return
+	}
+	writeRubric(w, rubric)
+}
+
+// SetProjectRubric godoc
+// @Summary ADMIN: Задать рубрику проекта
+// @Description Критерии заменяются целиком; сумма баллов не может превышать max_score проекта.
+// @Tags Admin-Content
+// @Accept json
+// @Produce json
+// @Param id path string true "ID проекта"
+// @Param request body RubricRequest true "Критерии"
+// @Success 200 {object} domain.Rubric
+// @Router /admin/projects/{id}/rubric [put]
+func (h *ContentAdminHandler) SetProjectRubric(w http.ResponseWriter, r *http.Request) {
+	var req RubricRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	rubric, err := h.uc.SetProjectRubric(r.Context(), chi.URLParam(r, "id"), req.Criteria)
+	if err != nil {
+		rubricError(w, err)
+		return
+	}
+	writeRubric(w, rubric)
+}
+
+func deadlineError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidDeadline):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sql.ErrNoRows):
+		httperror.NotFound(w, err)
+	default:
+		httperror.Internal(w, err)
+	}
+}
+
+// GetLessonDeadline godoc
+// @Summary ADMIN: Срок сдачи ДЗ урока
+// @Tags Admin-Content
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {object} domain.AssignmentDeadline
+// @Router /admin/lessons/{id}/deadline [get]
+func (h *ContentAdminHandler) GetLessonDeadline(w http.ResponseWriter, r *http.Request) {
+	deadline, err := h.uc.GetLessonDeadline(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		deadlineError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deadline)
+}
+
+// SetLessonDeadline godoc
+// @Summary ADMIN: Задать срок сдачи ДЗ урока
+// @Description due_at (абсолютный срок) или due_offset_hours (часы от начала урока); без них срок снимается.
+// @Description late_policy: accept, penalize (с late_penalty_percent) или reject.
+// @Tags Admin-Content
+// @Accept json
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param request body domain.AssignmentDeadline true "Срок и политика опоздания"
+// @Success 200 {object} domain.AssignmentDeadline
+// @Router /admin/lessons/{id}/deadline [put]
+func (h *ContentAdminHandler) SetLessonDeadline(w http.ResponseWriter, r *http.Request) {
+	var req domain.AssignmentDeadline
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	deadline, err := h.uc.SetLessonDeadline(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		deadlineError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deadline)
+}
+
+func unlockError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrStudentRequired):
+		httperror.BadRequest(w, err)
+	case errors.Is(err, sql.ErrNoRows):
+		httperror.NotFound(w, err)
+	default:
+		httperror.Internal(w, err)
+	}
+}
+
+// UnlockLesson godoc
+// @Summary ADMIN: Открыть урок ученику
+// @Description Открывает урок в обход правил последовательного прохождения курса.
+// @Tags Admin-Content
+// @Accept json
+// @Param id path string true "ID урока"
+// @Param request body UnlockLessonRequest true "Ученик"
+// @Success 204
+// @Router /admin/lessons/{id}/unlocks [post]
+func (h *ContentAdminHandler) UnlockLesson(w http.ResponseWriter, r *http.Request) {
+	var req UnlockLessonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	var adminID string
+	if userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData); ok && userCtx != nil {
+		adminID = userCtx.UserID
+	}
+	if err := h.uc.UnlockLesson(r.Context(), chi.URLParam(r, "id"), req.StudentID, adminID); err != nil {
+		unlockError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RelockLesson godoc
+// @Summary ADMIN: Отменить открытие урока ученику
+// @Tags Admin-Content
+// @Param id path string true "ID урока"
+// @Param studentId path string true "ID ученика"
+// @Success 204
+// @Router /admin/lessons/{id}/unlocks/{studentId} [delete]
+func (h *ContentAdminHandler) RelockLesson(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.RelockLesson(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "studentId")); err != nil {
+		unlockError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
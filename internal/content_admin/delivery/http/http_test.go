@@ -33,6 +33,42 @@ func (m *MockContentAdminUseCase) UpdateCourseSettings(ctx context.Context, inpu
 	args := m.Called(ctx, input)
 	return args.Error(0)
 }
+func (m *MockContentAdminUseCase) UpdateGradeScheme(ctx context.Context, courseID string, scheme domain.GradeScheme) error {
+	args := m.Called(ctx, courseID, scheme)
+	return args.Error(0)
+}
+func (m *MockContentAdminUseCase) GetLessonRubric(ctx context.Context, lessonID string) (*domain.Rubric, error) {
+	args := m.Called(ctx, lessonID)
+	return args.Get(0).(*domain.Rubric), args.Error(1)
+}
+func (m *MockContentAdminUseCase) SetLessonRubric(ctx context.Context, lessonID string, criteria []domain.RubricCriterion) (*domain.Rubric, error) {
+	args := m.Called(ctx, lessonID, criteria)
+	return args.Get(0).(*domain.Rubric), args.Error(1)
+}
+func (m *MockContentAdminUseCase) GetProjectRubric(ctx context.Context, projectID string) (*domain.Rubric, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(*domain.Rubric), args.Error(1)
+}
+func (m *MockContentAdminUseCase) SetProjectRubric(ctx context.Context, projectID string, criteria []domain.RubricCriterion) (*domain.Rubric, error) {
+	args := m.Called(ctx, projectID, criteria)
+	return args.Get(0).(*domain.Rubric), args.Error(1)
+}
+func (m *MockContentAdminUseCase) GetLessonDeadline(ctx context.Context, lessonID string) (*domain.AssignmentDeadline, error) {
+	args := m.Called(ctx, lessonID)
+	return args.Get(0).(*domain.AssignmentDeadline), args.Error(1)
+}
+func (m *MockContentAdminUseCase) SetLessonDeadline(ctx context.Context, lessonID string, deadline domain.AssignmentDeadline) (*domain.AssignmentDeadline, error) {
+	args := m.Called(ctx, lessonID, deadline)
+	return args.Get(0).(*domain.AssignmentDeadline), args.Error(1)
+}
+func (m *MockContentAdminUseCase) UnlockLesson(ctx context.Context, lessonID, studentID, adminID string) error {
+	args := m.Called(ctx, lessonID, studentID, adminID)
+	return args.Error(0)
+}
+func (m *MockContentAdminUseCase) RelockLesson(ctx context.Context, lessonID, studentID string) error {
+	args := m.Called(ctx, lessonID, studentID)
+	return args.Error(0)
+}
 func (m *MockContentAdminUseCase) GetAllCourses(ctx context.Context) ([]*domain.Course, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*domain.Course), args.Error(1)
@@ -89,24 +125,24 @@ func (m *MockContentAdminUseCase) CreateFullUser(ctx context.Context, input usec
 	args := m.Called(ctx, input)
 	return args.Get(0).(map[string]string), args.Error(1)
 }
-func (m *MockContentAdminUseCase) GetUserInfo(ctx context.Context, userID string) (map[string]interface{}, error) {
-	args := m.Called(ctx, userID)
+func (m *MockContentAdminUseCase) GetUserInfo(ctx context.Context, actor domain.Actor, userID string) (map[string]interface{}, error) {
+	args := m.Called(ctx, actor, userID)
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
-func (m *MockContentAdminUseCase) UpdateUser(ctx context.Context, userID string, input usecase.ExtendedCreateUserInput) error {
-	args := m.Called(ctx, userID, input)
+func (m *MockContentAdminUseCase) UpdateUser(ctx context.Context, actor domain.Actor, userID string, input usecase.ExtendedCreateUserInput) error {
+	args := m.Called(ctx, actor, userID, input)
 	return args.Error(0)
 }
 func (m *MockContentAdminUseCase) DeleteUser(ctx context.Context, userID string) error {
 	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
-func (m *MockContentAdminUseCase) GetUsersList(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
-	args := m.Called(ctx, filter)
+func (m *MockContentAdminUseCase) GetUsersList(ctx context.Context, actor domain.Actor, filter domain.UserFilter) ([]*domain.User, error) {
+	args := m.Called(ctx, actor, filter)
 	return args.Get(0).([]*domain.User), args.Error(1)
 }
-func (m *MockContentAdminUseCase) GetDetailedStudents(ctx context.Context, courseID string) ([]*domain.StudentTableItem, error) {
-	args := m.Called(ctx, courseID)
+func (m *MockContentAdminUseCase) GetDetailedStudents(ctx context.Context, actor domain.Actor, courseID string) ([]*domain.StudentTableItem, error) {
+	args := m.Called(ctx, actor, courseID)
 	return args.Get(0).([]*domain.StudentTableItem), args.Error(1)
 }
 func (m *MockContentAdminUseCase) GetDetailedTeachers(ctx context.Context) ([]*domain.TeacherTableItem, error) {
@@ -121,16 +157,16 @@ func (m *MockContentAdminUseCase) GetDetailedModerators(ctx context.Context) ([]
 	args := m.Called(ctx)
 	return args.Get(0).([]*domain.ModeratorTableItem), args.Error(1)
 }
-func (m *MockContentAdminUseCase) GetAllUsersTable(ctx context.Context) ([]*domain.AllUsersTableItem, error) {
-	args := m.Called(ctx)
+func (m *MockContentAdminUseCase) GetAllUsersTable(ctx context.Context, actor domain.Actor) ([]*domain.AllUsersTableItem, error) {
+	args := m.Called(ctx, actor)
 	return args.Get(0).([]*domain.AllUsersTableItem), args.Error(1)
 }
 func (m *MockContentAdminUseCase) EnrollStudent(ctx context.Context, userID, courseID string) error {
 	args := m.Called(ctx, userID, courseID)
 	return args.Error(0)
 }
-func (m *MockContentAdminUseCase) GetCourseStudents(ctx context.Context, courseID string) ([]*domain.AdminStudentProgress, error) {
-	args := m.Called(ctx, courseID)
+func (m *MockContentAdminUseCase) GetCourseStudents(ctx context.Context, actor domain.Actor, courseID string) ([]*domain.AdminStudentProgress, error) {
+	args := m.Called(ctx, actor, courseID)
 	return args.Get(0).([]*domain.AdminStudentProgress), args.Error(1)
 }
 func (m *MockContentAdminUseCase) GetCourseStats(ctx context.Context, courseID string) (*domain.AdminCourseStats, error) {
@@ -2,6 +2,9 @@ package mocks
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"lms_backend/internal/content_admin/repository"
 	"lms_backend/internal/domain"
 )
@@ -10,6 +13,14 @@ type ContentAdminRepoMock struct {
 	CreatedUsers   map[string]*domain.User
 	CreatedCourses map[string]*domain.Course
 	LinkedParents  map[string]string
+	CreatedTests   []*domain.Test
+	// Projects — проекты по ID для GetProjectByID; Rubrics — рубрики по ID задания или проекта.
+	Projects map[string]*domain.Project
+	Rubrics  map[string]*domain.Rubric
+	// Deadlines — сроки сдачи по ID задания.
+	Deadlines map[string]*domain.AssignmentDeadline
+	// Unlocks — кто открыл урок ученику, по ключу "lessonID/studentID".
+	Unlocks map[string]string
 }
 
 func NewContentAdminRepoMock() *ContentAdminRepoMock {
@@ -17,6 +28,10 @@ func NewContentAdminRepoMock() *ContentAdminRepoMock {
 		CreatedUsers:   make(map[string]*domain.User),
 		CreatedCourses: make(map[string]*domain.Course),
 		LinkedParents:  make(map[string]string),
+		Projects:       make(map[string]*domain.Project),
+		Rubrics:        make(map[string]*domain.Rubric),
+		Deadlines:      make(map[string]*domain.AssignmentDeadline),
+		Unlocks:        make(map[string]string),
 	}
 }
 
@@ -30,6 +45,12 @@ func (m *ContentAdminRepoMock) CreateCourse(ctx context.Context, course *domain.
 func (m *ContentAdminRepoMock) UpdateCourseSettings(ctx context.Context, course *domain.Course) error {
 	return nil
 }
+func (m *ContentAdminRepoMock) UpdateCourseGradeScheme(ctx context.Context, courseID string, scheme domain.GradeScheme) error {
+	if c, ok := m.CreatedCourses[courseID]; ok {
+		c.GradeScheme = scheme
+	}
+	return nil
+}
 func (m *ContentAdminRepoMock) CreateModule(ctx context.Context, module *domain.Module) (string, error) {
 	return "id", nil
 }
@@ -60,13 +81,19 @@ func (m *ContentAdminRepoMock) GetLessonsByCourseID(ctx context.Context, courseI
 	return nil, nil
 }
 func (m *ContentAdminRepoMock) CreateUser(ctx context.Context, user *domain.User) (string, error) {
-	return "id", nil
+	id := "id"
+	user.ID = id
+	m.CreatedUsers[id] = user
+	return id, nil
 }
 func (m *ContentAdminRepoMock) GetUsers(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
 	return nil, nil
 }
 func (m *ContentAdminRepoMock) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	return nil, nil
+	if u, ok := m.CreatedUsers[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("user not found")
 }
 func (m *ContentAdminRepoMock) GetParentsByStudentID(ctx context.Context, studentID string) ([]domain.User, error) {
 	return nil, nil
@@ -87,17 +114,27 @@ func (m *ContentAdminRepoMock) GetCourseStats(ctx context.Context, courseID stri
 	return nil, nil
 }
 func (m *ContentAdminRepoMock) CreateTest(ctx context.Context, test *domain.Test) (string, error) {
+	m.CreatedTests = append(m.CreatedTests, test)
 	return "id", nil
 }
 func (m *ContentAdminRepoMock) DeleteTest(ctx context.Context, id string) error { return nil }
 func (m *ContentAdminRepoMock) CreateProject(ctx context.Context, project *domain.Project) (string, error) {
 	return "id", nil
 }
-func (m *ContentAdminRepoMock) DeleteProject(ctx context.Context, id string) error      { return nil }
-func (m *ContentAdminRepoMock) UpdateUser(ctx context.Context, user *domain.User) error { return nil }
-func (m *ContentAdminRepoMock) DeleteUser(ctx context.Context, userID string) error     { return nil }
+func (m *ContentAdminRepoMock) DeleteProject(ctx context.Context, id string) error { return nil }
+func (m *ContentAdminRepoMock) UpdateUser(ctx context.Context, user *domain.User) error {
+	m.CreatedUsers[user.ID] = user
+	return nil
+}
+func (m *ContentAdminRepoMock) DeleteUser(ctx context.Context, userID string) error { return nil }
 func (m *ContentAdminRepoMock) GetDetailedStudentList(ctx context.Context, filter domain.UserFilter) ([]*domain.StudentTableItem, error) {
-	return nil, nil
+	var list []*domain.StudentTableItem
+	for _, u := range m.CreatedUsers {
+		if u.Role == domain.RoleStudent {
+			list = append(list, &domain.StudentTableItem{ID: u.ID})
+		}
+	}
+	return list, nil
 }
 func (m *ContentAdminRepoMock) GetDetailedTeacherList(ctx context.Context) ([]*domain.TeacherTableItem, error) {
 	return nil, nil
@@ -151,13 +188,54 @@ func (m *ContentAdminRepoMock) LinkTeachersToCourse(ctx context.Context, courseI
 	return nil
 }
 func (m *ContentAdminRepoMock) GetLessonByID(ctx context.Context, id string) (*domain.Lesson, error) {
-	return nil, nil
+	return &domain.Lesson{ID: id}, nil
 }
 func (m *ContentAdminRepoMock) GetTestByID(ctx context.Context, id string) (*domain.Test, error) {
 	return nil, nil
 }
 func (m *ContentAdminRepoMock) GetProjectByID(ctx context.Context, id string) (*domain.Project, error) {
-	return nil, nil
+	if p, ok := m.Projects[id]; ok {
+		return p, nil
+	}
+	return nil, errors.New("project not found")
+}
+func (m *ContentAdminRepoMock) GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
+	return "asg-" + lessonID, nil
+}
+func (m *ContentAdminRepoMock) GetRubric(ctx context.Context, assignmentID, projectID string) (*domain.Rubric, error) {
+	if r, ok := m.Rubrics[assignmentID+projectID]; ok {
+		return r, nil
+	}
+	return &domain.Rubric{AssignmentID: assignmentID, ProjectID: projectID, Criteria: []domain.RubricCriterion{}}, nil
+}
+func (m *ContentAdminRepoMock) SaveRubric(ctx context.Context, rubric *domain.Rubric) error {
+	for i := range rubric.Criteria {
+		rubric.Criteria[i].ID = fmt.Sprintf("crit-%d", i+1)
+	}
+	m.Rubrics[rubric.AssignmentID+rubric.ProjectID] = rubric
+	return nil
+}
+func (m *ContentAdminRepoMock) GetAssignmentDeadline(ctx context.Context, assignmentID string) (*domain.AssignmentDeadline, error) {
+	if d, ok := m.Deadlines[assignmentID]; ok {
+		return d, nil
+	}
+	return &domain.AssignmentDeadline{LatePolicy: domain.LatePolicyAccept}, nil
+}
+func (m *ContentAdminRepoMock) SaveAssignmentDeadline(ctx context.Context, assignmentID string, deadline *domain.AssignmentDeadline) error {
+	m.Deadlines[assignmentID] = deadline
+	return nil
+}
+func (m *ContentAdminRepoMock) UnlockLesson(ctx context.Context, lessonID, studentID, unlockedBy string) error {
+	m.Unlocks[lessonID+"/"+studentID] = unlockedBy
+	return nil
+}
+func (m *ContentAdminRepoMock) RelockLesson(ctx context.Context, lessonID, studentID string) error {
+	key := lessonID + "/" + studentID
+	if _, ok := m.Unlocks[key]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.Unlocks, key)
+	return nil
 }
 func (m *ContentAdminRepoMock) CancelLesson(ctx context.Context, lessonID, reason string) error {
 	return nil
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -22,6 +23,21 @@ func (r *ContentAdminRepoImpl) UpdateCourseSettings(ctx context.Context, course
 	return err
 }
 
+func (r *ContentAdminRepoImpl) UpdateCourseGradeScheme(ctx context.Context, courseID string, scheme domain.GradeScheme) error {
+	raw, err := json.Marshal(scheme)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, "UPDATE courses SET grading_scheme = $1 WHERE id = $2", raw, courseID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (r *ContentAdminRepoImpl) CreateModule(ctx context.Context, module *domain.Module) (string, error) {
 	var newID string
 	query := `INSERT INTO modules (course_id, title, description, order_num) VALUES ($1, $2, $3, $4) RETURNING id`
@@ -53,8 +69,12 @@ func (r *ContentAdminRepoImpl) GetAllCourses(ctx context.Context) ([]*domain.Cou
 
 func (r *ContentAdminRepoImpl) GetCourseByID(ctx context.Context, id string) (*domain.Course, error) {
 	c := &domain.Course{}
-	err := r.db.QueryRowContext(ctx, "SELECT id, title, description, is_main, image_url, status, created_at, has_homework, is_homework_mandatory, is_test_mandatory, is_project_mandatory, is_discord_mandatory, is_anti_copy_enabled FROM courses WHERE id = $1", id).Scan(&c.ID, &c.Title, &c.Description, &c.IsMain, &c.ImageURL, &c.Status, &c.CreatedAt, &c.HasHomework, &c.IsHomeworkMandatory, &c.IsTestMandatory, &c.IsProjectMandatory, &c.IsDiscordMandatory, &c.IsAntiCopyEnabled)
-	return c, err
+	var schemeRaw []byte
+	err := r.db.QueryRowContext(ctx, "SELECT id, title, description, is_main, image_url, status, created_at, has_homework, is_homework_mandatory, is_test_mandatory, is_project_mandatory, is_discord_mandatory, is_anti_copy_enabled, grading_scheme FROM courses WHERE id = $1", id).Scan(&c.ID, &c.Title, &c.Description, &c.IsMain, &c.ImageURL, &c.Status, &c.CreatedAt, &c.HasHomework, &c.IsHomeworkMandatory, &c.IsTestMandatory, &c.IsProjectMandatory, &c.IsDiscordMandatory, &c.IsAntiCopyEnabled, &schemeRaw)
+	if err != nil {
+		return c, err
+	}
+	return c, json.Unmarshal(schemeRaw, &c.GradeScheme)
 }
 
 func (r *ContentAdminRepoImpl) GetModulesByCourseID(ctx context.Context, courseID string) ([]*domain.Module, error) {
@@ -122,6 +142,24 @@ func (r *ContentAdminRepoImpl) GetCourseStudents(ctx context.Context, courseID s
 func (r *ContentAdminRepoImpl) GetCourseStats(ctx context.Context, courseID string) (*domain.AdminCourseStats, error) {
 	stats := &domain.AdminCourseStats{}
 	err := r.db.QueryRowContext(ctx, "SELECT COUNT(user_id), COALESCE(AVG(progress_percent), 0) FROM user_courses WHERE course_id = $1", courseID).Scan(&stats.TotalStudents, &stats.AverageScore)
+	if err != nil {
+		return stats, err
+	}
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE((SELECT AVG(w.watched) FROM (
+				SELECT COALESCE(SUM(vp.watched_sec), 0) AS watched
+				FROM user_courses uc
+				LEFT JOIN lesson_video_progress vp ON vp.user_id = uc.user_id
+					AND vp.lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)
+				WHERE uc.course_id = $1
+				GROUP BY uc.user_id
+			) w), 0) / 60.0,
+			COALESCE(100.0 * COUNT(vp.completed_at) / NULLIF(COUNT(*), 0), 0)
+		FROM lesson_video_progress vp
+		JOIN lessons l ON vp.lesson_id = l.id
+		WHERE l.course_id = $1
+	`, courseID).Scan(&stats.AverageWatchTimeMin, &stats.VideoCompletionRate)
 	return stats, err
 }
 
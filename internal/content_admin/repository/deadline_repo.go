@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"lms_backend/internal/domain"
+)
+
+func (r *ContentAdminRepoImpl) GetAssignmentDeadline(ctx context.Context, assignmentID string) (*domain.AssignmentDeadline, error) {
+	d := &domain.AssignmentDeadline{}
+	var dueAt sql.NullTime
+	var offset sql.NullInt32
+	err := r.db.QueryRowContext(ctx, `
+		SELECT due_at, due_offset_hours, late_policy, late_penalty_percent
+		FROM assignments WHERE id = $1
+	`, assignmentID).Scan(&dueAt, &offset, &d.LatePolicy, &d.LatePenaltyPercent)
+	if err != nil {
+		return nil, err
+	}
+	if dueAt.Valid {
+		d.DueAt = &dueAt.Time
+	}
+	if offset.Valid {
+		hours := int(offset.Int32)
+		d.DueOffsetHours = &hours
+	}
+	return d, nil
+}
+
+func (r *ContentAdminRepoImpl) SaveAssignmentDeadline(ctx context.Context, assignmentID string, d *domain.AssignmentDeadline) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE assignments
+		SET due_at = $1, due_offset_hours = $2, late_policy = $3, late_penalty_percent = $4
+		WHERE id = $5
+	`, d.DueAt, d.DueOffsetHours, d.LatePolicy, d.LatePenaltyPercent, assignmentID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
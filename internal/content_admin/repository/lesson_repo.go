@@ -210,10 +210,49 @@ func (r *ContentAdminRepoImpl) SubstituteTeacher(ctx context.Context, lessonID,
 	return tx.Commit()
 }
 
+// CreateTest сохраняет тест вместе с вопросами в одной транзакции; порядок вопросов задаёт position.
 func (r *ContentAdminRepoImpl) CreateTest(ctx context.Context, test *domain.Test) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
 	var newID string
-	err := r.db.QueryRowContext(ctx, "INSERT INTO tests (lesson_id, title, description, passing_score) VALUES ($1, $2, $3, $4) RETURNING id", test.LessonID, test.Title, test.Description, test.PassingScore).Scan(&newID)
-	return newID, err
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO tests (lesson_id, title, description, passing_score, answers_reveal,
+			time_limit_min, max_attempts, scoring, shuffle_questions, shuffle_options, pool_size)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		test.LessonID, test.Title, test.Description, test.PassingScore, test.AnswersReveal,
+		test.TimeLimitMin, test.MaxAttempts, test.Scoring, test.ShuffleQuestions, test.ShuffleOptions, test.PoolSize,
+	).Scan(&newID)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	for i, q := range test.Questions {
+		optionsJSON, _ := json.Marshal(nonNilStrings(q.Options))
+		matchJSON, _ := json.Marshal(nonNilStrings(q.MatchOptions))
+		correctJSON, _ := json.Marshal(nonNilStrings(q.CorrectAnswers))
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO test_questions (test_id, type, question, options, match_options, correct_answer, correct_answers, tolerance, partial_credit, points, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			newID, q.Type, q.Question, optionsJSON, matchJSON, q.CorrectAnswer, correctJSON, q.Tolerance, q.PartialCredit, q.Points, i,
+		)
+		if err != nil {
+			tx.Rollback()
+			return "", err
+		}
+	}
+
+	return newID, tx.Commit()
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
 }
 
 func (r *ContentAdminRepoImpl) DeleteTest(ctx context.Context, id string) error {
@@ -223,8 +262,12 @@ func (r *ContentAdminRepoImpl) DeleteTest(ctx context.Context, id string) error
 
 func (r *ContentAdminRepoImpl) GetTestByID(ctx context.Context, id string) (*domain.Test, error) {
 	t := &domain.Test{}
-	err := r.db.QueryRowContext(ctx, "SELECT id, lesson_id, title, description, passing_score, created_at FROM tests WHERE id = $1", id).
-		Scan(&t.ID, &t.LessonID, &t.Title, &t.Description, &t.PassingScore, &t.CreatedAt)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, lesson_id, title, description, passing_score, answers_reveal,
+		       time_limit_min, max_attempts, scoring, shuffle_questions, shuffle_options, pool_size, created_at
+		FROM tests WHERE id = $1`, id).
+		Scan(&t.ID, &t.LessonID, &t.Title, &t.Description, &t.PassingScore, &t.AnswersReveal,
+			&t.TimeLimitMin, &t.MaxAttempts, &t.Scoring, &t.ShuffleQuestions, &t.ShuffleOptions, &t.PoolSize, &t.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +276,11 @@ func (r *ContentAdminRepoImpl) GetTestByID(ctx context.Context, id string) (*dom
 }
 
 func (r *ContentAdminRepoImpl) getTestQuestions(ctx context.Context, testID string) []domain.TestQuestion {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, question, options, correct_answer, points FROM test_questions WHERE test_id = $1 ORDER BY created_at ASC", testID)
+	query := `
+		SELECT id, type, question, options, match_options, correct_answer, correct_answers, tolerance, partial_credit, points
+		FROM test_questions WHERE test_id = $1 ORDER BY position ASC, created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, testID)
 	if err != nil {
 		return []domain.TestQuestion{}
 	}
@@ -241,8 +288,8 @@ func (r *ContentAdminRepoImpl) getTestQuestions(ctx context.Context, testID stri
 	var questions []domain.TestQuestion
 	for rows.Next() {
 		var q domain.TestQuestion
-		var optionsRaw []byte
-		if err := rows.Scan(&q.ID, &q.Question, &optionsRaw, &q.CorrectAnswer, &q.Points); err != nil {
+		var optionsRaw, matchRaw, correctRaw []byte
+		if err := rows.Scan(&q.ID, &q.Type, &q.Question, &optionsRaw, &matchRaw, &q.CorrectAnswer, &correctRaw, &q.Tolerance, &q.PartialCredit, &q.Points); err != nil {
 			continue
 		}
 		if len(optionsRaw) > 0 {
@@ -251,6 +298,8 @@ func (r *ContentAdminRepoImpl) getTestQuestions(ctx context.Context, testID stri
 		if q.Options == nil {
 			q.Options = []string{}
 		}
+		json.Unmarshal(matchRaw, &q.MatchOptions)
+		json.Unmarshal(correctRaw, &q.CorrectAnswers)
 		questions = append(questions, q)
 	}
 	return questions
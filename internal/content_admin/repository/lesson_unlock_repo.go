@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// UnlockLesson открывает урок ученику в обход правил прохождения. Не ученик — sql.ErrNoRows.
+func (r *ContentAdminRepoImpl) UnlockLesson(ctx context.Context, lessonID, studentID, unlockedBy string) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO lesson_unlocks (lesson_id, student_id, unlocked_by)
+		SELECT $1, u.id, NULLIF($3, '')::uuid FROM users u WHERE u.id = $2 AND u.role = 'student'
+		ON CONFLICT (lesson_id, student_id) DO UPDATE SET unlocked_by = EXCLUDED.unlocked_by, created_at = NOW()
+	`, lessonID, studentID, unlockedBy)
+	return affectedOne(res, err)
+}
+
+// RelockLesson снимает ручное открытие урока; если его не было — sql.ErrNoRows.
+func (r *ContentAdminRepoImpl) RelockLesson(ctx context.Context, lessonID, studentID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM lesson_unlocks WHERE lesson_id = $1 AND student_id = $2`, lessonID, studentID)
+	return affectedOne(res, err)
+}
+
+func affectedOne(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 type ContentAdminRepository interface {
 	CreateCourse(ctx context.Context, course *domain.Course) (string, error)
 	UpdateCourseSettings(ctx context.Context, course *domain.Course) error
+	UpdateCourseGradeScheme(ctx context.Context, courseID string, scheme domain.GradeScheme) error
 	CreateModule(ctx context.Context, module *domain.Module) (string, error)
 	DeleteModule(ctx context.Context, id string) error
 	CreateLesson(ctx context.Context, lesson *domain.Lesson) (string, error)
@@ -26,6 +27,13 @@ type ContentAdminRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 	GetTestByID(ctx context.Context, id string) (*domain.Test, error)
 	GetProjectByID(ctx context.Context, id string) (*domain.Project, error)
+	GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error)
+	GetRubric(ctx context.Context, assignmentID, projectID string) (*domain.Rubric, error)
+	SaveRubric(ctx context.Context, rubric *domain.Rubric) error
+	GetAssignmentDeadline(ctx context.Context, assignmentID string) (*domain.AssignmentDeadline, error)
+	SaveAssignmentDeadline(ctx context.Context, assignmentID string, deadline *domain.AssignmentDeadline) error
+	UnlockLesson(ctx context.Context, lessonID, studentID, unlockedBy string) error
+	RelockLesson(ctx context.Context, lessonID, studentID string) error
 	GetParentsByStudentID(ctx context.Context, studentID string) ([]domain.User, error)
 	LinkParentToStudent(ctx context.Context, studentID, parentID string) error
 	EnrollStudentExtended(ctx context.Context, userID, courseID, streamID, groupID string) error
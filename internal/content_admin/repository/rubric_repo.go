@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"lms_backend/internal/domain"
+)
+
+func (r *ContentAdminRepoImpl) GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM assignments WHERE lesson_id = $1 LIMIT 1", lessonID).Scan(&id)
+	return id, err
+}
+
+func (r *ContentAdminRepoImpl) GetRubric(ctx context.Context, assignmentID, projectID string) (*domain.Rubric, error) {
+	rubric := &domain.Rubric{AssignmentID: assignmentID, ProjectID: projectID, Criteria: []domain.RubricCriterion{}}
+	column, owner := "assignment_id", assignmentID
+	if projectID != "" {
+		column, owner = "project_id", projectID
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, title, description, max_points FROM rubric_criteria WHERE %s = $1 ORDER BY position ASC", column,
+	), owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c domain.RubricCriterion
+		if err := rows.Scan(&c.ID, &c.Title, &c.Description, &c.MaxPoints); err != nil {
+			return nil, err
+		}
+		rubric.Criteria = append(rubric.Criteria, c)
+	}
+	return rubric, rows.Err()
+}
+
+// SaveRubric заменяет критерии рубрики целиком и проставляет им новые ID.
+func (r *ContentAdminRepoImpl) SaveRubric(ctx context.Context, rubric *domain.Rubric) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var assignmentID, projectID interface{}
+	if rubric.AssignmentID != "" {
+		assignmentID = rubric.AssignmentID
+		_, err = tx.ExecContext(ctx, "DELETE FROM rubric_criteria WHERE assignment_id = $1", rubric.AssignmentID)
+	} else {
+		projectID = rubric.ProjectID
+		_, err = tx.ExecContext(ctx, "DELETE FROM rubric_criteria WHERE project_id = $1", rubric.ProjectID)
+	}
+	if err != nil {
+		return err
+	}
+	for i := range rubric.Criteria {
+		c := &rubric.Criteria[i]
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO rubric_criteria (assignment_id, project_id, title, description, max_points, position)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+		`, assignmentID, projectID, c.Title, c.Description, c.MaxPoints, i).Scan(&c.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
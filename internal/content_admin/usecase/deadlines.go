@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+)
+
+// GetLessonDeadline — срок сдачи и политика опоздания ДЗ урока.
+func (uc *ContentAdminUseCase) GetLessonDeadline(ctx context.Context, lessonID string) (*domain.AssignmentDeadline, error) {
+	assignmentID, err := uc.repo.GetAssignmentIDByLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetAssignmentDeadline(ctx, assignmentID)
+}
+
+// SetLessonDeadline задаёт срок ДЗ урока; задание создаётся, если его ещё нет.
+// Без due_at и due_offset_hours срок снимается. Пустая политика означает accept.
+func (uc *ContentAdminUseCase) SetLessonDeadline(ctx context.Context, lessonID string, deadline domain.AssignmentDeadline) (*domain.AssignmentDeadline, error) {
+	if deadline.LatePolicy == "" {
+		deadline.LatePolicy = domain.LatePolicyAccept
+	}
+	if err := deadline.Validate(); err != nil {
+		return nil, err
+	}
+	lesson, err := uc.repo.GetLessonByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.EnsureAssignment(ctx, lessonID, lesson.Title); err != nil {
+		return nil, err
+	}
+	assignmentID, err := uc.repo.GetAssignmentIDByLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SaveAssignmentDeadline(ctx, assignmentID, &deadline); err != nil {
+		return nil, err
+	}
+	return &deadline, nil
+}
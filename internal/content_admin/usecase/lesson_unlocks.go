@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrStudentRequired = errors.New("student_id is required")
+
+// UnlockLesson открывает урок ученику, даже если предыдущий урок не выполнен по правилам курса.
+// Следующие уроки по-прежнему открываются по правилам.
+func (uc *ContentAdminUseCase) UnlockLesson(ctx context.Context, lessonID, studentID, adminID string) error {
+	if studentID == "" {
+		return ErrStudentRequired
+	}
+	if _, err := uc.repo.GetLessonByID(ctx, lessonID); err != nil {
+		return err
+	}
+	return uc.repo.UnlockLesson(ctx, lessonID, studentID, adminID)
+}
+
+// RelockLesson отменяет ручное открытие урока ученику.
+func (uc *ContentAdminUseCase) RelockLesson(ctx context.Context, lessonID, studentID string) error {
+	if studentID == "" {
+		return ErrStudentRequired
+	}
+	return uc.repo.RelockLesson(ctx, lessonID, studentID)
+}
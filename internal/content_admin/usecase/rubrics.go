@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"lms_backend/internal/domain"
+)
+
+// GetLessonRubric — рубрика ДЗ урока. Если у урока нет задания, возвращается ошибка репозитория.
+func (uc *ContentAdminUseCase) GetLessonRubric(ctx context.Context, lessonID string) (*domain.Rubric, error) {
+	assignmentID, err := uc.repo.GetAssignmentIDByLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetRubric(ctx, assignmentID, "")
+}
+
+// SetLessonRubric заменяет рубрику ДЗ урока; задание создаётся, если его ещё нет.
+// Пустой список критериев убирает рубрику.
+func (uc *ContentAdminUseCase) SetLessonRubric(ctx context.Context, lessonID string, criteria []domain.RubricCriterion) (*domain.Rubric, error) {
+	rubric := &domain.Rubric{Criteria: nonNilCriteria(criteria)}
+	if err := rubric.Validate(); err != nil {
+		return nil, err
+	}
+	lesson, err := uc.repo.GetLessonByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.EnsureAssignment(ctx, lessonID, lesson.Title); err != nil {
+		return nil, err
+	}
+	if rubric.AssignmentID, err = uc.repo.GetAssignmentIDByLesson(ctx, lessonID); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SaveRubric(ctx, rubric); err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+func (uc *ContentAdminUseCase) GetProjectRubric(ctx context.Context, projectID string) (*domain.Rubric, error) {
+	if _, err := uc.repo.GetProjectByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetRubric(ctx, "", projectID)
+}
+
+// SetProjectRubric заменяет рубрику проекта. Сумма баллов критериев не может превышать max_score проекта.
+func (uc *ContentAdminUseCase) SetProjectRubric(ctx context.Context, projectID string, criteria []domain.RubricCriterion) (*domain.Rubric, error) {
+	rubric := &domain.Rubric{ProjectID: projectID, Criteria: nonNilCriteria(criteria)}
+	if err := rubric.Validate(); err != nil {
+		return nil, err
+	}
+	project, err := uc.repo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if rubric.MaxPoints() > project.MaxScore {
+		return nil, fmt.Errorf("%w: criteria add up to %d points, project max_score is %d", domain.ErrInvalidRubric, rubric.MaxPoints(), project.MaxScore)
+	}
+	if err := uc.repo.SaveRubric(ctx, rubric); err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+func nonNilCriteria(criteria []domain.RubricCriterion) []domain.RubricCriterion {
+	if criteria == nil {
+		return []domain.RubricCriterion{}
+	}
+	return criteria
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lms_backend/internal/domain"
+)
+
+var (
+	ErrInvalidQuestion   = errors.New("invalid question")
+	ErrInvalidTestPolicy = errors.New("invalid test policy")
+)
+
+// normalizePolicy проверяет правила прохождения теста; scoring по умолчанию best.
+func normalizePolicy(p *domain.TestPolicy, questions int) error {
+	if p.Scoring == "" {
+		p.Scoring = domain.TestScoringBest
+	}
+	if p.Scoring != domain.TestScoringBest && p.Scoring != domain.TestScoringLast {
+		return fmt.Errorf("%w: scoring must be best or last", ErrInvalidTestPolicy)
+	}
+	if p.TimeLimitMin < 0 || p.MaxAttempts < 0 || p.PoolSize < 0 {
+		return fmt.Errorf("%w: time_limit_min, max_attempts and pool_size must not be negative", ErrInvalidTestPolicy)
+	}
+	if p.PoolSize > questions {
+		return fmt.Errorf("%w: pool_size exceeds the number of questions", ErrInvalidTestPolicy)
+	}
+	return nil
+}
+
+func invalidQuestion(i int, reason string) error {
+	return fmt.Errorf("%w #%d: %s", ErrInvalidQuestion, i+1, reason)
+}
+
+// normalizeQuestion проверяет ключ ответа под тип вопроса и проставляет значения по умолчанию:
+// тип single и 1 балл.
+func normalizeQuestion(i int, q *domain.TestQuestion) error {
+	if q.Type == "" {
+		q.Type = domain.QuestionSingle
+	}
+	if !q.Type.IsValid() {
+		return invalidQuestion(i, fmt.Sprintf("unknown type %q", q.Type))
+	}
+	if strings.TrimSpace(q.Question) == "" {
+		return invalidQuestion(i, "question text is required")
+	}
+	if q.Points < 0 {
+		return invalidQuestion(i, "points must not be negative")
+	}
+	if q.Points == 0 {
+		q.Points = 1
+	}
+
+	switch q.Type {
+	case domain.QuestionSingle:
+		if strings.TrimSpace(q.CorrectAnswer) == "" {
+			return invalidQuestion(i, "correct_answer is required")
+		}
+		if len(q.Options) > 0 && !containsFold(q.Options, q.CorrectAnswer) {
+			return invalidQuestion(i, "correct_answer must be one of options")
+		}
+	case domain.QuestionMultiple:
+		if len(q.Options) < 2 || len(q.CorrectAnswers) == 0 {
+			return invalidQuestion(i, "multiple needs at least two options and one correct answer")
+		}
+		for _, c := range q.CorrectAnswers {
+			if !containsFold(q.Options, c) {
+				return invalidQuestion(i, fmt.Sprintf("correct answer %q is not among options", c))
+			}
+		}
+	case domain.QuestionNumeric:
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(q.CorrectAnswer), ",", "."), 64); err != nil {
+			return invalidQuestion(i, "numeric correct_answer must be a number")
+		}
+		if q.Tolerance < 0 {
+			return invalidQuestion(i, "tolerance must not be negative")
+		}
+	case domain.QuestionOrdering:
+		if len(q.Options) < 2 || !isPermutation(q.Options, q.CorrectAnswers) {
+			return invalidQuestion(i, "ordering correct_answers must list every option exactly once")
+		}
+	case domain.QuestionMatching:
+		if len(q.Options) == 0 || len(q.MatchOptions) == 0 || len(q.CorrectAnswers) != len(q.Options) {
+			return invalidQuestion(i, "matching needs a correct_answers entry for every option")
+		}
+		for _, c := range q.CorrectAnswers {
+			if !containsFold(q.MatchOptions, c) {
+				return invalidQuestion(i, fmt.Sprintf("match %q is not among match_options", c))
+			}
+		}
+	}
+	return nil
+}
+
+func containsFold(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPermutation(options, order []string) bool {
+	if len(options) != len(order) {
+		return false
+	}
+	left := make(map[string]int, len(options))
+	for _, o := range options {
+		left[strings.ToLower(strings.TrimSpace(o))]++
+	}
+	for _, o := range order {
+		key := strings.ToLower(strings.TrimSpace(o))
+		if left[key] == 0 {
+			return false
+		}
+		left[key]--
+	}
+	return true
+}
@@ -27,13 +27,51 @@ func s3Context(ctx context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, s3UploadTimeout)
 }
 
+// ErrBalanceForbidden — баланс пытается изменить сотрудник без права finance.balance.
+var ErrBalanceForbidden = errors.New("changing balance requires finance.balance permission")
+
+var ErrInvalidAnswersReveal = errors.New("answers_reveal must be one of: never, after_submit, after_pass")
+
+// ScopeChecker — учитель и куратор работают с карточками только своих учеников (реализует scope ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	VisibleStudents(ctx context.Context, actor domain.Actor) (*domain.StudentScope, error)
+}
+
 type ContentAdminUseCase struct {
 	repo      repository.ContentAdminRepository
 	s3Storage storageService.ObjectStorage
+	scope     ScopeChecker
 }
 
-func NewContentAdminUseCase(repo repository.ContentAdminRepository, s3Storage storageService.ObjectStorage) *ContentAdminUseCase {
-	return &ContentAdminUseCase{repo: repo, s3Storage: s3Storage}
+func NewContentAdminUseCase(repo repository.ContentAdminRepository, s3Storage storageService.ObjectStorage, scope ScopeChecker) *ContentAdminUseCase {
+	return &ContentAdminUseCase{repo: repo, s3Storage: s3Storage, scope: scope}
+}
+
+// canViewUser — карточки сотрудников видны всем с users.view, ученики — только в области доступа,
+// родители — только админу и модератору.
+func (uc *ContentAdminUseCase) canViewUser(ctx context.Context, actor domain.Actor, user *domain.User) error {
+	if actor.HasGlobalScope() {
+		return nil
+	}
+	switch user.Role {
+	case domain.RoleStudent:
+		return uc.scope.CanAccessStudent(ctx, actor, user.ID)
+	case domain.RoleParent:
+		return domain.ErrOutOfScope
+	}
+	return nil
+}
+
+// userVisible — фильтр списков по тем же правилам, что canViewUser.
+func userVisible(scope *domain.StudentScope, userID string, role domain.Role) bool {
+	switch role {
+	case domain.RoleStudent:
+		return scope.Contains(userID)
+	case domain.RoleParent:
+		return scope.All
+	}
+	return true
 }
 
 func splitName(fullName string) (string, string) {
@@ -123,7 +161,10 @@ type ExtendedCreateUserInput struct {
 	Parents                []ParentInfo
 	IntroBroadcastURL      string
 	GraduationBroadcastURL string
-	Balance                float64
+	// Balance == nil — баланс не передан: при создании будет 0, при изменении останется прежним.
+	Balance *float64
+	// CanEditBalance — у вызывающего есть право finance.balance.
+	CanEditBalance bool
 }
 
 type ParentInfo struct {
@@ -139,6 +180,10 @@ type CreateTestInput struct {
 	Title        string
 	Description  string
 	PassingScore int
+	// AnswersReveal по умолчанию after_submit.
+	AnswersReveal domain.AnswersReveal
+	Policy        domain.TestPolicy
+	Questions     []domain.TestQuestion
 }
 
 type CreateProjectInput struct {
@@ -328,6 +373,15 @@ func (uc *ContentAdminUseCase) UpdateCourseSettings(ctx context.Context, input U
 
 	return nil
 }
+
+// UpdateGradeScheme меняет схему оценивания ДЗ курса. Уже выставленные оценки не пересчитываются.
+func (uc *ContentAdminUseCase) UpdateGradeScheme(ctx context.Context, courseID string, scheme domain.GradeScheme) error {
+	if err := scheme.Validate(); err != nil {
+		return err
+	}
+	return uc.repo.UpdateCourseGradeScheme(ctx, courseID, scheme)
+}
+
 func (uc *ContentAdminUseCase) GetAllCourses(ctx context.Context) ([]*domain.Course, error) {
 	return uc.repo.GetAllCourses(ctx)
 }
@@ -533,6 +587,14 @@ func (uc *ContentAdminUseCase) CreateLessonsBulk(ctx context.Context, input []Cr
 }
 
 func (uc *ContentAdminUseCase) CreateFullUser(ctx context.Context, input ExtendedCreateUserInput) (map[string]string, error) {
+	var balance float64
+	if input.Balance != nil {
+		balance = *input.Balance
+	}
+	if balance != 0 && !input.CanEditBalance {
+		return nil, ErrBalanceForbidden
+	}
+
 	firstName, lastName := resolveNames(input)
 	hashedPass, err := bcrypt.GenerateFromPassword([]byte(input.Password), 12)
 	if err != nil {
@@ -556,7 +618,7 @@ func (uc *ContentAdminUseCase) CreateFullUser(ctx context.Context, input Extende
 		Telegram:               input.Telegram,
 		IntroBroadcastURL:      input.IntroBroadcastURL,
 		GraduationBroadcastURL: input.GraduationBroadcastURL,
-		Balance:                input.Balance,
+		Balance:                balance,
 	}
 
 	userID, err := uc.repo.CreateUser(ctx, user)
@@ -610,21 +672,63 @@ func (uc *ContentAdminUseCase) EnrollStudent(ctx context.Context, userID, course
 	return uc.repo.EnrollStudentExtended(ctx, userID, courseID, "", "")
 }
 
-func (uc *ContentAdminUseCase) GetCourseStudents(ctx context.Context, courseID string) ([]*domain.AdminStudentProgress, error) {
-	return uc.repo.GetCourseStudents(ctx, courseID)
+func (uc *ContentAdminUseCase) GetCourseStudents(ctx context.Context, actor domain.Actor, courseID string) ([]*domain.AdminStudentProgress, error) {
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	list, err := uc.repo.GetCourseStudents(ctx, courseID)
+	if err != nil || scope.All {
+		return list, err
+	}
+	visible := make([]*domain.AdminStudentProgress, 0, len(list))
+	for _, s := range list {
+		if scope.Contains(s.UserID) {
+			visible = append(visible, s)
+		}
+	}
+	return visible, nil
 }
 
 func (uc *ContentAdminUseCase) GetCourseStats(ctx context.Context, courseID string) (*domain.AdminCourseStats, error) {
 	return uc.repo.GetCourseStats(ctx, courseID)
 }
 
-func (uc *ContentAdminUseCase) GetUsersList(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
-	return uc.repo.GetUsers(ctx, filter)
+func (uc *ContentAdminUseCase) GetUsersList(ctx context.Context, actor domain.Actor, filter domain.UserFilter) ([]*domain.User, error) {
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	users, err := uc.repo.GetUsers(ctx, filter)
+	if err != nil || scope.All {
+		return users, err
+	}
+	visible := make([]*domain.User, 0, len(users))
+	for _, u := range users {
+		if userVisible(scope, u.ID, u.Role) {
+			visible = append(visible, u)
+		}
+	}
+	return visible, nil
 }
 
-func (uc *ContentAdminUseCase) GetDetailedStudents(ctx context.Context, courseID string) ([]*domain.StudentTableItem, error) {
+func (uc *ContentAdminUseCase) GetDetailedStudents(ctx context.Context, actor domain.Actor, courseID string) ([]*domain.StudentTableItem, error) {
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
 	filter := domain.UserFilter{CourseID: courseID}
-	return uc.repo.GetDetailedStudentList(ctx, filter)
+	list, err := uc.repo.GetDetailedStudentList(ctx, filter)
+	if err != nil || scope.All {
+		return list, err
+	}
+	visible := make([]*domain.StudentTableItem, 0, len(list))
+	for _, s := range list {
+		if scope.Contains(s.ID) {
+			visible = append(visible, s)
+		}
+	}
+	return visible, nil
 }
 
 func (uc *ContentAdminUseCase) GetDetailedTeachers(ctx context.Context) ([]*domain.TeacherTableItem, error) {
@@ -639,15 +743,32 @@ func (uc *ContentAdminUseCase) GetDetailedModerators(ctx context.Context) ([]*do
 	return uc.repo.GetDetailedModeratorList(ctx)
 }
 
-func (uc *ContentAdminUseCase) GetAllUsersTable(ctx context.Context) ([]*domain.AllUsersTableItem, error) {
-	return uc.repo.GetAllUsersList(ctx)
+func (uc *ContentAdminUseCase) GetAllUsersTable(ctx context.Context, actor domain.Actor) ([]*domain.AllUsersTableItem, error) {
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	list, err := uc.repo.GetAllUsersList(ctx)
+	if err != nil || scope.All {
+		return list, err
+	}
+	visible := make([]*domain.AllUsersTableItem, 0, len(list))
+	for _, u := range list {
+		if userVisible(scope, u.ID, u.Role) {
+			visible = append(visible, u)
+		}
+	}
+	return visible, nil
 }
 
-func (uc *ContentAdminUseCase) GetUserInfo(ctx context.Context, userID string) (map[string]interface{}, error) {
+func (uc *ContentAdminUseCase) GetUserInfo(ctx context.Context, actor domain.Actor, userID string) (map[string]interface{}, error) {
 	user, err := uc.repo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("usecase: user not found: %w", err)
 	}
+	if err := uc.canViewUser(ctx, actor, user); err != nil {
+		return nil, err
+	}
 
 	res := map[string]interface{}{
 		"user":      user,
@@ -678,11 +799,20 @@ func (uc *ContentAdminUseCase) GetUserInfo(ctx context.Context, userID string) (
 	return res, nil
 }
 
-func (uc *ContentAdminUseCase) UpdateUser(ctx context.Context, userID string, input ExtendedCreateUserInput) error {
+// UpdateUser — учитель и куратор меняют только своих учеников и не меняют им роль.
+func (uc *ContentAdminUseCase) UpdateUser(ctx context.Context, actor domain.Actor, userID string, input ExtendedCreateUserInput) error {
 	existing, err := uc.repo.GetByID(ctx, userID)
 	if err != nil {
 		return err
 	}
+	if !actor.HasGlobalScope() {
+		if existing.Role != domain.RoleStudent || input.Role != existing.Role {
+			return domain.ErrOutOfScope
+		}
+		if err := uc.scope.CanAccessStudent(ctx, actor, userID); err != nil {
+			return err
+		}
+	}
 
 	firstName, lastName := resolveNames(input)
 	finalBD := input.BirthDate
@@ -690,6 +820,14 @@ func (uc *ContentAdminUseCase) UpdateUser(ctx context.Context, userID string, in
 		finalBD = existing.BirthDate
 	}
 
+	balance := existing.Balance
+	if input.Balance != nil && *input.Balance != existing.Balance {
+		if !input.CanEditBalance {
+			return ErrBalanceForbidden
+		}
+		balance = *input.Balance
+	}
+
 	user := &domain.User{
 		ID: userID, FirstName: firstName, LastName: lastName, Email: input.Email, Role: input.Role,
 		Phone: input.Phone, City: input.City, SchoolName: input.SchoolName, Language: input.Language,
@@ -697,7 +835,7 @@ func (uc *ContentAdminUseCase) UpdateUser(ctx context.Context, userID string, in
 		Whatsapp: input.Whatsapp, Telegram: input.Telegram,
 		IntroBroadcastURL:      input.IntroBroadcastURL,
 		GraduationBroadcastURL: input.GraduationBroadcastURL,
-		Balance:                input.Balance,
+		Balance:                balance,
 	}
 
 	if err := uc.repo.UpdateUser(ctx, user); err != nil {
@@ -738,6 +876,21 @@ func (uc *ContentAdminUseCase) DeleteUser(ctx context.Context, userID string) er
 }
 
 func (uc *ContentAdminUseCase) CreateTest(ctx context.Context, input CreateTestInput) (string, error) {
+	if input.AnswersReveal == "" {
+		input.AnswersReveal = domain.AnswersRevealNever
+	}
+	if !input.AnswersReveal.IsValid() {
+		return "", ErrInvalidAnswersReveal
+	}
+	for i := range input.Questions {
+		if err := normalizeQuestion(i, &input.Questions[i]); err != nil {
+			return "", err
+		}
+	}
+	if err := normalizePolicy(&input.Policy, len(input.Questions)); err != nil {
+		return "", err
+	}
+
 	var lid *string
 	if input.LessonID != "" {
 		lid = &input.LessonID
@@ -749,10 +902,13 @@ func (uc *ContentAdminUseCase) CreateTest(ctx context.Context, input CreateTestI
 	}
 
 	test := &domain.Test{
-		LessonID:     lid,
-		Title:        input.Title,
-		Description:  input.Description,
-		PassingScore: input.PassingScore,
+		LessonID:      lid,
+		Title:         input.Title,
+		Description:   input.Description,
+		PassingScore:  input.PassingScore,
+		AnswersReveal: input.AnswersReveal,
+		TestPolicy:    input.Policy,
+		Questions:     input.Questions,
 	}
 	return uc.repo.CreateTest(ctx, test)
 }
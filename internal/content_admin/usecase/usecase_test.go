@@ -2,17 +2,28 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"lms_backend/internal/content_admin/mocks"
 	"lms_backend/internal/content_admin/usecase"
+	"lms_backend/internal/domain"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 	s3Mocks "lms_backend/pkg/storage/mocks"
 )
 
+var admin = domain.Actor{UserID: "admin-1", Role: domain.RoleAdmin}
+
+func newScope() usecase.ScopeChecker {
+	return scopeUseCase.NewScopeUseCase(scopeMocks.TwoGroups())
+}
+
 func TestCreateCourse(t *testing.T) {
 	repoMock := mocks.NewContentAdminRepoMock()
 	s3Mock := s3Mocks.NewS3StorageMock()
-	uc := usecase.NewContentAdminUseCase(repoMock, s3Mock)
+	uc := usecase.NewContentAdminUseCase(repoMock, s3Mock, newScope())
 
 	ctx := context.Background()
 
@@ -36,10 +47,190 @@ func TestCreateCourse(t *testing.T) {
 	}
 }
 
+func TestUpdateGradeScheme(t *testing.T) {
+	repoMock := mocks.NewContentAdminRepoMock()
+	repoMock.CreatedCourses["course-1"] = &domain.Course{ID: "course-1", GradeScheme: domain.DefaultGradeScheme()}
+	uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+	ctx := context.Background()
+
+	invalid := domain.GradeScheme{Type: domain.GradeSchemeLetter}
+	if err := uc.UpdateGradeScheme(ctx, "course-1", invalid); !errors.Is(err, domain.ErrInvalidGradeScheme) {
+		t.Fatalf("expected ErrInvalidGradeScheme, got %v", err)
+	}
+	if repoMock.CreatedCourses["course-1"].GradeScheme.Type != domain.GradeSchemeNumeric {
+		t.Fatalf("invalid scheme must not be saved")
+	}
+
+	passFail := domain.GradeScheme{Type: domain.GradeSchemePassFail}
+	if err := uc.UpdateGradeScheme(ctx, "course-1", passFail); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoMock.CreatedCourses["course-1"].GradeScheme.Type != domain.GradeSchemePassFail {
+		t.Errorf("scheme was not saved")
+	}
+}
+
+func TestRubrics(t *testing.T) {
+	ctx := context.Background()
+	criteria := func() []domain.RubricCriterion {
+		return []domain.RubricCriterion{{Title: "Code quality", MaxPoints: 10}, {Title: "Completeness", MaxPoints: 20}}
+	}
+
+	t.Run("LessonRubric", func(t *testing.T) {
+		repoMock := mocks.NewContentAdminRepoMock()
+		uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+		rubric, err := uc.SetLessonRubric(ctx, "lesson-1", criteria())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rubric.AssignmentID != "asg-lesson-1" || rubric.Criteria[0].ID == "" || rubric.MaxPoints() != 30 {
+			t.Errorf("unexpected rubric: %+v", rubric)
+		}
+		got, _ := uc.GetLessonRubric(ctx, "lesson-1")
+		if len(got.Criteria) != 2 {
+			t.Errorf("rubric was not saved: %+v", got)
+		}
+	})
+
+	t.Run("InvalidCriteria", func(t *testing.T) {
+		uc := usecase.NewContentAdminUseCase(mocks.NewContentAdminRepoMock(), s3Mocks.NewS3StorageMock(), newScope())
+		bad := [][]domain.RubricCriterion{
+			{{Title: "", MaxPoints: 5}},
+			{{Title: "Style", MaxPoints: 0}},
+			{{Title: "Style", MaxPoints: 5}, {Title: "style", MaxPoints: 5}},
+		}
+		for i, c := range bad {
+			if _, err := uc.SetLessonRubric(ctx, "lesson-1", c); !errors.Is(err, domain.ErrInvalidRubric) {
+				t.Errorf("case %d: expected ErrInvalidRubric, got %v", i, err)
+			}
+		}
+	})
+
+	t.Run("ProjectMaxScore", func(t *testing.T) {
+		repoMock := mocks.NewContentAdminRepoMock()
+		repoMock.Projects["p1"] = &domain.Project{ID: "p1", MaxScore: 25}
+		uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+		if _, err := uc.SetProjectRubric(ctx, "p1", criteria()); !errors.Is(err, domain.ErrInvalidRubric) {
+			t.Fatalf("expected ErrInvalidRubric above max_score, got %v", err)
+		}
+		repoMock.Projects["p1"].MaxScore = 30
+		if _, err := uc.SetProjectRubric(ctx, "p1", criteria()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestLessonDeadline(t *testing.T) {
+	ctx := context.Background()
+	hours := 48
+
+	t.Run("RelativeDeadline", func(t *testing.T) {
+		repoMock := mocks.NewContentAdminRepoMock()
+		uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+		saved, err := uc.SetLessonDeadline(ctx, "lesson-1", domain.AssignmentDeadline{DueOffsetHours: &hours})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved.LatePolicy != domain.LatePolicyAccept {
+			t.Errorf("expected default accept policy, got %q", saved.LatePolicy)
+		}
+		got, _ := uc.GetLessonDeadline(ctx, "lesson-1")
+		if got.DueOffsetHours == nil || *got.DueOffsetHours != 48 {
+			t.Errorf("deadline was not saved: %+v", got)
+		}
+	})
+
+	t.Run("InvalidDeadline", func(t *testing.T) {
+		uc := usecase.NewContentAdminUseCase(mocks.NewContentAdminRepoMock(), s3Mocks.NewS3StorageMock(), newScope())
+		now := time.Now()
+		bad := []domain.AssignmentDeadline{
+			{DueAt: &now, DueOffsetHours: &hours},
+			{DueAt: &now, LatePolicy: domain.LatePolicyPenalize},
+			{DueAt: &now, LatePolicy: domain.LatePolicyReject, LatePenaltyPercent: 10},
+			{DueAt: &now, LatePolicy: "ignore"},
+		}
+		for i, d := range bad {
+			if _, err := uc.SetLessonDeadline(ctx, "lesson-1", d); !errors.Is(err, domain.ErrInvalidDeadline) {
+				t.Errorf("case %d: expected ErrInvalidDeadline, got %v", i, err)
+			}
+		}
+	})
+}
+
+func TestLessonUnlocks(t *testing.T) {
+	ctx := context.Background()
+	repoMock := mocks.NewContentAdminRepoMock()
+	uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+
+	if err := uc.UnlockLesson(ctx, "lesson-1", "", "admin-1"); !errors.Is(err, usecase.ErrStudentRequired) {
+		t.Fatalf("expected ErrStudentRequired, got %v", err)
+	}
+	if err := uc.UnlockLesson(ctx, "lesson-1", "student-1", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoMock.Unlocks["lesson-1/student-1"] != "admin-1" {
+		t.Fatalf("expected unlock by admin-1, got %v", repoMock.Unlocks)
+	}
+	if err := uc.RelockLesson(ctx, "lesson-1", "student-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.RelockLesson(ctx, "lesson-1", "student-1"); err == nil {
+		t.Fatal("expected error when the lesson was not unlocked")
+	}
+}
+
+func TestUserBalancePermission(t *testing.T) {
+	ctx := context.Background()
+	balance := func(v float64) *float64 { return &v }
+
+	t.Run("Create With Balance Without Permission", func(t *testing.T) {
+		uc := usecase.NewContentAdminUseCase(mocks.NewContentAdminRepoMock(), s3Mocks.NewS3StorageMock(), newScope())
+		_, err := uc.CreateFullUser(ctx, usecase.ExtendedCreateUserInput{
+			FullName: "Иван Иванов", Email: "a@test.kz", Password: "secret123", Role: domain.RoleStudent,
+			Balance: balance(5000),
+		})
+		if !errors.Is(err, usecase.ErrBalanceForbidden) {
+			t.Fatalf("expected ErrBalanceForbidden, got %v", err)
+		}
+	})
+
+	t.Run("Update Keeps Balance", func(t *testing.T) {
+		repoMock := mocks.NewContentAdminRepoMock()
+		uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+		repoMock.CreatedUsers["u1"] = &domain.User{ID: "u1", Role: domain.RoleStudent, Balance: 1200}
+
+		// Баланс не передан или не изменился — право не нужно.
+		input := usecase.ExtendedCreateUserInput{FullName: "Иван Иванов", Email: "a@test.kz", Role: domain.RoleStudent}
+		if err := uc.UpdateUser(ctx, admin, "u1", input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		input.Balance = balance(1200)
+		if err := uc.UpdateUser(ctx, admin, "u1", input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repoMock.CreatedUsers["u1"].Balance != 1200 {
+			t.Errorf("balance must stay 1200, got %v", repoMock.CreatedUsers["u1"].Balance)
+		}
+
+		input.Balance = balance(0)
+		if err := uc.UpdateUser(ctx, admin, "u1", input); !errors.Is(err, usecase.ErrBalanceForbidden) {
+			t.Fatalf("expected ErrBalanceForbidden, got %v", err)
+		}
+
+		input.CanEditBalance = true
+		if err := uc.UpdateUser(ctx, admin, "u1", input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repoMock.CreatedUsers["u1"].Balance != 0 {
+			t.Errorf("balance must be changed to 0, got %v", repoMock.CreatedUsers["u1"].Balance)
+		}
+	})
+}
+
 // func TestCreateFullUser_StudentWithParent(t *testing.T) {
 // 	repoMock := mocks.NewContentAdminRepoMock()
 // 	s3Mock := s3Mocks.NewS3StorageMock()
-// 	uc := usecase.NewContentAdminUseCase(repoMock, s3Mock)
+// 	uc := usecase.NewContentAdminUseCase(repoMock, s3Mock, newScope())
 
 // 	ctx := context.Background()
 
@@ -84,3 +275,131 @@ func TestCreateCourse(t *testing.T) {
 // 		t.Error("Linked wrong parent ID")
 // 	}
 // }
+
+func TestCreateTest_Questions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid Questions Of Every Type", func(t *testing.T) {
+		repoMock := mocks.NewContentAdminRepoMock()
+		uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+		_, err := uc.CreateTest(ctx, usecase.CreateTestInput{
+			Title: "Quiz",
+			Questions: []domain.TestQuestion{
+				{Question: "2+2?", Options: []string{"3", "4"}, CorrectAnswer: "4"},
+				{Type: domain.QuestionMultiple, Question: "Even?", Options: []string{"1", "2", "4"}, CorrectAnswers: []string{"2", "4"}, Points: 2},
+				{Type: domain.QuestionNumeric, Question: "Pi?", CorrectAnswer: "3,14", Tolerance: 0.01},
+				{Type: domain.QuestionOrdering, Question: "Sort", Options: []string{"b", "a"}, CorrectAnswers: []string{"a", "b"}},
+				{Type: domain.QuestionMatching, Question: "Capitals", Options: []string{"France", "Italy"}, MatchOptions: []string{"Rome", "Paris"}, CorrectAnswers: []string{"Paris", "Rome"}},
+				{Type: domain.QuestionText, Question: "Explain goroutines", Points: 5},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		saved := repoMock.CreatedTests[0]
+		if saved.AnswersReveal != domain.AnswersRevealNever {
+			t.Errorf("expected default reveal policy, got %q", saved.AnswersReveal)
+		}
+		if saved.Questions[0].Type != domain.QuestionSingle || saved.Questions[0].Points != 1 {
+			t.Errorf("expected defaults for first question, got %+v", saved.Questions[0])
+		}
+	})
+
+	t.Run("Policy Defaults And Validation", func(t *testing.T) {
+		repoMock := mocks.NewContentAdminRepoMock()
+		uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+		questions := []domain.TestQuestion{{Question: "2+2?", CorrectAnswer: "4"}}
+		if _, err := uc.CreateTest(ctx, usecase.CreateTestInput{Title: "Quiz", Questions: questions, Policy: domain.TestPolicy{TimeLimitMin: 20, PoolSize: 1}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if saved := repoMock.CreatedTests[0]; saved.Scoring != domain.TestScoringBest || saved.TimeLimitMin != 20 {
+			t.Errorf("unexpected policy: %+v", saved.TestPolicy)
+		}
+
+		for _, p := range []domain.TestPolicy{{Scoring: "average"}, {MaxAttempts: -1}, {PoolSize: 2}} {
+			_, err := uc.CreateTest(ctx, usecase.CreateTestInput{Title: "Quiz", Questions: questions, Policy: p})
+			if !errors.Is(err, usecase.ErrInvalidTestPolicy) {
+				t.Errorf("policy %+v: expected ErrInvalidTestPolicy, got %v", p, err)
+			}
+		}
+	})
+
+	invalid := map[string]domain.TestQuestion{
+		"Unknown Type":             {Type: "essay", Question: "?"},
+		"Single Answer Not Option": {Question: "2+2?", Options: []string{"3", "5"}, CorrectAnswer: "4"},
+		"Multiple Without Key":     {Type: domain.QuestionMultiple, Question: "?", Options: []string{"a", "b"}},
+		"Numeric Not A Number":     {Type: domain.QuestionNumeric, Question: "?", CorrectAnswer: "many"},
+		"Ordering Not Permutation": {Type: domain.QuestionOrdering, Question: "?", Options: []string{"a", "b"}, CorrectAnswers: []string{"a", "a"}},
+		"Matching Missing Pair":    {Type: domain.QuestionMatching, Question: "?", Options: []string{"x", "y"}, MatchOptions: []string{"1"}, CorrectAnswers: []string{"1"}},
+		"Matching Unknown Target":  {Type: domain.QuestionMatching, Question: "?", Options: []string{"x"}, MatchOptions: []string{"1"}, CorrectAnswers: []string{"2"}},
+		"Negative Points":          {Type: domain.QuestionText, Question: "?", Points: -1},
+	}
+	for name, q := range invalid {
+		t.Run(name, func(t *testing.T) {
+			uc := usecase.NewContentAdminUseCase(mocks.NewContentAdminRepoMock(), s3Mocks.NewS3StorageMock(), newScope())
+			_, err := uc.CreateTest(ctx, usecase.CreateTestInput{Title: "Quiz", Questions: []domain.TestQuestion{q}})
+			if !errors.Is(err, usecase.ErrInvalidQuestion) {
+				t.Fatalf("expected ErrInvalidQuestion, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUserScope(t *testing.T) {
+	ctx := context.Background()
+	repoMock := mocks.NewContentAdminRepoMock()
+	repoMock.CreatedUsers["student-a"] = &domain.User{ID: "student-a", Role: domain.RoleStudent}
+	repoMock.CreatedUsers["student-b"] = &domain.User{ID: "student-b", Role: domain.RoleStudent}
+	repoMock.CreatedUsers["teacher-b"] = &domain.User{ID: "teacher-b", Role: domain.RoleTeacher}
+	uc := usecase.NewContentAdminUseCase(repoMock, s3Mocks.NewS3StorageMock(), newScope())
+	teacher := domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+	curator := domain.Actor{UserID: "curator-a", Role: domain.RoleCurator}
+
+	t.Run("Card", func(t *testing.T) {
+		if _, err := uc.GetUserInfo(ctx, teacher, "student-a"); err != nil {
+			t.Fatalf("own student: unexpected error: %v", err)
+		}
+		if _, err := uc.GetUserInfo(ctx, teacher, "student-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("foreign student: expected ErrOutOfScope, got %v", err)
+		}
+		if _, err := uc.GetUserInfo(ctx, admin, "student-b"); err != nil {
+			t.Fatalf("admin: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		input := usecase.ExtendedCreateUserInput{FullName: "Иван Иванов", Role: domain.RoleStudent, CanEditBalance: true}
+		if err := uc.UpdateUser(ctx, curator, "student-b", input); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("foreign student: expected ErrOutOfScope, got %v", err)
+		}
+		if err := uc.UpdateUser(ctx, curator, "teacher-b", usecase.ExtendedCreateUserInput{Role: domain.RoleTeacher}); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("staff account: expected ErrOutOfScope, got %v", err)
+		}
+		input.Role = domain.RoleAdmin
+		if err := uc.UpdateUser(ctx, curator, "student-a", input); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("role change: expected ErrOutOfScope, got %v", err)
+		}
+		input.Role = domain.RoleStudent
+		input.Balance = func(v float64) *float64 { return &v }(500)
+		if err := uc.UpdateUser(ctx, curator, "student-a", input); err != nil {
+			t.Fatalf("own student: unexpected error: %v", err)
+		}
+		if repoMock.CreatedUsers["student-a"].Balance != 500 {
+			t.Errorf("balance must be 500, got %v", repoMock.CreatedUsers["student-a"].Balance)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := uc.GetDetailedStudents(ctx, teacher, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].ID != "student-a" {
+			t.Fatalf("expected only student-a, got %+v", list)
+		}
+		list, _ = uc.GetDetailedStudents(ctx, admin, "")
+		if len(list) != 2 {
+			t.Fatalf("admin must see all students, got %d", len(list))
+		}
+	})
+}
@@ -45,6 +45,10 @@ func (m *mockDashboardRepo) GetUpcomingLessons(ctx context.Context, userID strin
 	args := m.Called(ctx, userID)
 	return args.Get(0).([]domain.UpcomingLesson), args.Error(1)
 }
+func (m *mockDashboardRepo) GetOverdueAssignments(ctx context.Context, userID string) ([]domain.OverdueHomework, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.OverdueHomework), args.Error(1)
+}
 func (m *mockDashboardRepo) GetAdminCounters(ctx context.Context) (totalStudents, newStudents int, studentsDelta float64, totalTeachers, activeCourses int, err error) {
 	args := m.Called(ctx)
 	return args.Int(0), args.Int(1), args.Get(2).(float64), args.Int(3), args.Int(4), args.Error(5)
@@ -101,7 +105,7 @@ func (m *mockDashboardRepo) GetCuratorPerformanceZones(ctx context.Context, cura
 func TestGetCuratorDashboard(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 		handler := NewDashboardHandler(uc)
 
 		expectedGroups := []domain.Group{{ID: "g1", Title: "Group A"}}
@@ -144,7 +148,7 @@ func TestGetCuratorDashboard(t *testing.T) {
 
 	t.Run("usecase error", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 		handler := NewDashboardHandler(uc)
 
 		mockRepo.On("GetCuratorGroups", mock.Anything, "curator-2").Return(nil, assert.AnError).Once()
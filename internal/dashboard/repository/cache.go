@@ -118,6 +118,20 @@ func (c *CachedDashboardRepo) GetUpcomingLessons(ctx context.Context, userID str
 	return v, err
 }
 
+func (c *CachedDashboardRepo) GetOverdueAssignments(ctx context.Context, userID string) ([]domain.OverdueHomework, error) {
+	key := cacheKey("user:%s:overdue", userID)
+	var v []domain.OverdueHomework
+	ok, err := cacheGet(ctx, c.rdb, key, &v)
+	if err == nil && ok {
+		return v, nil
+	}
+	v, err = c.next.GetOverdueAssignments(ctx, userID)
+	if err == nil {
+		cacheSet(ctx, c.rdb, key, v)
+	}
+	return v, err
+}
+
 func (c *CachedDashboardRepo) GetAdminCounters(ctx context.Context) (totalStudents, newStudents int, studentsDelta float64, totalTeachers, activeCourses int, err error) {
 	type counters struct {
 		TotalStudents int
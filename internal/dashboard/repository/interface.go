@@ -11,6 +11,7 @@ type UserDataRepository interface {
 	GetAttendancePercentage(ctx context.Context, userID string) (*domain.StatisticSummary, error)
 	GetAssignmentsCompletionPercentage(ctx context.Context, userID string) (*domain.StatisticSummary, error)
 	GetUpcomingLessons(ctx context.Context, userID string) ([]domain.UpcomingLesson, error)
+	GetOverdueAssignments(ctx context.Context, userID string) ([]domain.OverdueHomework, error)
 	GetAdminCounters(ctx context.Context) (totalStudents, newStudents int, studentsDelta float64, totalTeachers, activeCourses int, err error)
 	GetAllPerformanceStats(ctx context.Context) (*domain.AllPerformanceStats, error)
 	GetPerformanceStats(ctx context.Context) (domain.PerformanceZones, error)
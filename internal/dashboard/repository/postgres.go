@@ -78,7 +78,12 @@ func (r *DashboardRepositoryImpl) GetUpcomingLessons(ctx context.Context, userID
 		SELECT 
 			l.lesson_time, 
 			COALESCE(u.first_name || ' ' || u.last_name, '') as teacher_name,
-			c.title as course_title
+			c.title as course_title,
+			EXISTS (
+				SELECT 1 FROM assignments a
+				WHERE a.lesson_id = l.id AND (a.due_at IS NOT NULL OR a.due_offset_hours IS NOT NULL)
+				  AND NOT EXISTS (SELECT 1 FROM user_assignments_submission uas WHERE uas.assignment_id = a.id AND uas.user_id = $1)
+			) as is_homework_due
 		FROM user_courses uc
 		JOIN courses c ON uc.course_id = c.id
 		JOIN modules m ON m.course_id = c.id
@@ -97,7 +102,7 @@ func (r *DashboardRepositoryImpl) GetUpcomingLessons(ctx context.Context, userID
 	var lessons []domain.UpcomingLesson
 	for rows.Next() {
 		var l domain.UpcomingLesson
-		if err := rows.Scan(&l.Date, &l.TeacherName, &l.CourseTitle); err != nil {
+		if err := rows.Scan(&l.Date, &l.TeacherName, &l.CourseTitle, &l.IsHomeworkDue); err != nil {
 			return nil, err
 		}
 		lessons = append(lessons, l)
@@ -105,6 +110,45 @@ func (r *DashboardRepositoryImpl) GetUpcomingLessons(ctx context.Context, userID
 	return lessons, nil
 }
 
+// GetOverdueAssignments — ДЗ курсов ученика, срок которых (с учётом одобренных продлений) истёк,
+// а работа так и не отправлена.
+func (r *DashboardRepositoryImpl) GetOverdueAssignments(ctx context.Context, userID string) ([]domain.OverdueHomework, error) {
+	query := `
+		WITH deadlines AS (
+			SELECT a.id as assignment_id, l.id as lesson_id, l.title as lesson_title, c.title as course_title,
+				COALESCE(a.due_at, l.lesson_time + a.due_offset_hours * INTERVAL '1 hour') as due_at,
+				(SELECT MAX(e.requested_due_at) FROM assignment_extension_requests e
+				 WHERE e.assignment_id = a.id AND e.student_id = $1 AND e.status = 'APPROVED') as extended_until
+			FROM user_courses uc
+			JOIN courses c ON uc.course_id = c.id
+			JOIN modules m ON m.course_id = c.id
+			JOIN lessons l ON l.module_id = m.id
+			JOIN assignments a ON a.lesson_id = l.id
+			WHERE uc.user_id = $1 AND l.is_published = true
+		)
+		SELECT d.assignment_id, d.lesson_id, d.lesson_title, d.course_title, GREATEST(d.due_at, d.extended_until) as effective_due
+		FROM deadlines d
+		WHERE d.due_at IS NOT NULL AND GREATEST(d.due_at, d.extended_until) < NOW()
+		  AND NOT EXISTS (SELECT 1 FROM user_assignments_submission uas WHERE uas.assignment_id = d.assignment_id AND uas.user_id = $1)
+		ORDER BY effective_due ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overdue := []domain.OverdueHomework{}
+	for rows.Next() {
+		var o domain.OverdueHomework
+		if err := rows.Scan(&o.AssignmentID, &o.LessonID, &o.LessonTitle, &o.CourseTitle, &o.DueAt); err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, o)
+	}
+	return overdue, rows.Err()
+}
+
 func (r *DashboardRepositoryImpl) GetAdminCounters(ctx context.Context) (totalStudents, newStudents int, studentsDelta float64, totalTeachers, activeCourses int, err error) {
 	query := `
 		WITH stats AS (
@@ -298,14 +342,32 @@ func (r *DashboardRepositoryImpl) GetCuratorHomeworkStats(ctx context.Context, c
 			JOIN user_courses uc ON uc.group_id = g.id
 			JOIN user_assignments_submission uas ON uas.user_id = uc.user_id
 			WHERE g.curator_id = $1
+		),
+		group_overdue AS (
+			SELECT g.id as group_id, COUNT(*) as overdue
+			FROM groups g
+			JOIN user_courses uc ON uc.group_id = g.id
+			JOIN modules m ON m.course_id = uc.course_id
+			JOIN lessons l ON l.module_id = m.id
+			JOIN assignments a ON a.lesson_id = l.id
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(a.due_at, l.lesson_time + a.due_offset_hours * INTERVAL '1 hour') as due_at
+			) d
+			WHERE g.curator_id = $1 AND d.due_at IS NOT NULL
+			  AND GREATEST(d.due_at, (SELECT MAX(e.requested_due_at) FROM assignment_extension_requests e
+			                          WHERE e.assignment_id = a.id AND e.student_id = uc.user_id AND e.status = 'APPROVED')) < NOW()
+			  AND NOT EXISTS (SELECT 1 FROM user_assignments_submission s WHERE s.assignment_id = a.id AND s.user_id = uc.user_id)
+			GROUP BY g.id
 		)
 		SELECT
 			gh.group_id,
 			gh.group_title,
 			COUNT(*) as total_submitted,
 			COUNT(CASE WHEN gh.status = 'accepted' THEN 1 END) as total_accepted,
-			COALESCE(ROUND(COUNT(CASE WHEN gh.status = 'accepted' THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0), 2), 0) as avg_completion
+			COALESCE(ROUND(COUNT(CASE WHEN gh.status = 'accepted' THEN 1 END) * 100.0 / NULLIF(COUNT(*), 0), 2), 0) as avg_completion,
+			COALESCE(MAX(od.overdue), 0) as total_overdue
 		FROM group_homework gh
+		LEFT JOIN group_overdue od ON od.group_id = gh.group_id
 		GROUP BY gh.group_id, gh.group_title
 		ORDER BY gh.group_title ASC
 	`
@@ -317,7 +379,7 @@ func (r *DashboardRepositoryImpl) GetCuratorHomeworkStats(ctx context.Context, c
 	var stats []domain.CuratorHomeworkStats
 	for rows.Next() {
 		var s domain.CuratorHomeworkStats
-		if err := rows.Scan(&s.GroupID, &s.GroupTitle, &s.TotalSubmitted, &s.TotalAccepted, &s.AvgCompletion); err != nil {
+		if err := rows.Scan(&s.GroupID, &s.GroupTitle, &s.TotalSubmitted, &s.TotalAccepted, &s.AvgCompletion, &s.TotalOverdue); err != nil {
 			return nil, err
 		}
 		stats = append(stats, s)
@@ -11,12 +11,18 @@ import (
 	"lms_backend/internal/domain"
 )
 
+// ChildrenLister — дети родителя для главной (реализует parent.ParentUseCase).
+type ChildrenLister interface {
+	GetChildren(ctx context.Context, actor domain.Actor) ([]*domain.ParentChild, error)
+}
+
 type DashboardUseCase struct {
-	repo repository.UserDataRepository
+	repo     repository.UserDataRepository
+	children ChildrenLister
 }
 
-func NewDashboardUseCase(repo repository.UserDataRepository) *DashboardUseCase {
-	return &DashboardUseCase{repo: repo}
+func NewDashboardUseCase(repo repository.UserDataRepository, children ChildrenLister) *DashboardUseCase {
+	return &DashboardUseCase{repo: repo, children: children}
 }
 
 type homeData struct {
@@ -25,9 +31,25 @@ type homeData struct {
 	attendance   *domain.StatisticSummary
 	assignments  *domain.StatisticSummary
 	upcoming     []domain.UpcomingLesson
+	overdue      []domain.OverdueHomework
 }
 
 func (uc *DashboardUseCase) GetUserHomeData(ctx context.Context, user *domain.User) (*domain.HomeDashboard, error) {
+	// У родителя нет своих курсов — главная показывает привязанных детей.
+	if user.Role == domain.RoleParent {
+		children, err := uc.children.GetChildren(ctx, domain.Actor{UserID: user.ID, Role: user.Role})
+		if err != nil {
+			return nil, err
+		}
+		return &domain.HomeDashboard{
+			UserRole:        user.Role,
+			User:            user,
+			UpcomingLessons: []domain.UpcomingLesson{},
+			OverdueHomework: []domain.OverdueHomework{},
+			Children:        children,
+		}, nil
+	}
+
 	eg, egCtx := errgroup.WithContext(ctx)
 
 	var d homeData
@@ -51,6 +73,10 @@ func (uc *DashboardUseCase) GetUserHomeData(ctx context.Context, user *domain.Us
 		d.upcoming, err = uc.repo.GetUpcomingLessons(egCtx, user.ID)
 		return err
 	})
+	eg.Go(func() (err error) {
+		d.overdue, err = uc.repo.GetOverdueAssignments(egCtx, user.ID)
+		return err
+	})
 
 	if err := eg.Wait(); err != nil {
 		return nil, err
@@ -64,6 +90,7 @@ func (uc *DashboardUseCase) GetUserHomeData(ctx context.Context, user *domain.Us
 		AttendanceStats:    d.attendance,
 		AssignmentStats:    d.assignments,
 		UpcomingLessons:    d.upcoming,
+		OverdueHomework:    d.overdue,
 	}, nil
 }
 
@@ -57,6 +57,14 @@ func (m *mockDashboardRepo) GetUpcomingLessons(ctx context.Context, userID strin
 	return args.Get(0).([]domain.UpcomingLesson), args.Error(1)
 }
 
+func (m *mockDashboardRepo) GetOverdueAssignments(ctx context.Context, userID string) ([]domain.OverdueHomework, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OverdueHomework), args.Error(1)
+}
+
 func (m *mockDashboardRepo) GetAdminCounters(ctx context.Context) (totalStudents, newStudents int, studentsDelta float64, totalTeachers, activeCourses int, err error) {
 	args := m.Called(ctx)
 	return args.Int(0), args.Int(1), args.Get(2).(float64), args.Int(3), args.Int(4), args.Error(5)
@@ -132,7 +140,7 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		lastLesson := &domain.LastLesson{
 			CourseTitle:      "Go Basics",
@@ -145,7 +153,10 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 		attendance := &domain.StatisticSummary{Percentage: 75.0, Delta: 5.0}
 		assignment := &domain.StatisticSummary{Percentage: 60.0, Delta: -10.0}
 		upcoming := []domain.UpcomingLesson{
-			{Date: now, CourseTitle: "Go Basics", TeacherName: "John Doe"},
+			{Date: now, CourseTitle: "Go Basics", TeacherName: "John Doe", IsHomeworkDue: true},
+		}
+		overdue := []domain.OverdueHomework{
+			{AssignmentID: "asg-1", LessonID: "lesson-0", LessonTitle: "Intro", CourseTitle: "Go Basics", DueAt: now.Add(-time.Hour)},
 		}
 
 		mockRepo.On("GetLastLessonData", mock.Anything, "user-1").Return(lastLesson, nil).Once()
@@ -153,6 +164,7 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 		mockRepo.On("GetAttendancePercentage", mock.Anything, "user-1").Return(attendance, nil).Once()
 		mockRepo.On("GetAssignmentsCompletionPercentage", mock.Anything, "user-1").Return(assignment, nil).Once()
 		mockRepo.On("GetUpcomingLessons", mock.Anything, "user-1").Return(upcoming, nil).Once()
+		mockRepo.On("GetOverdueAssignments", mock.Anything, "user-1").Return(overdue, nil).Once()
 
 		result, err := uc.GetUserHomeData(context.Background(), user)
 
@@ -163,13 +175,15 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 		assert.Equal(t, 75.0, result.AttendanceStats.Percentage)
 		assert.Equal(t, 60.0, result.AssignmentStats.Percentage)
 		assert.Len(t, result.UpcomingLessons, 1)
+		assert.True(t, result.UpcomingLessons[0].IsHomeworkDue)
+		assert.Len(t, result.OverdueHomework, 1)
 		assert.Equal(t, "Go Basics", result.LastLessonData.CourseTitle)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("repo error propagated", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		expectedErr := errors.New("db connection failed")
 		mockRepo.On("GetLastLessonData", mock.Anything, "user-1").Return(nil, expectedErr).Once()
@@ -177,6 +191,7 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 		mockRepo.On("GetAttendancePercentage", mock.Anything, "user-1").Return(nil, nil).Maybe()
 		mockRepo.On("GetAssignmentsCompletionPercentage", mock.Anything, "user-1").Return(nil, nil).Maybe()
 		mockRepo.On("GetUpcomingLessons", mock.Anything, "user-1").Return([]domain.UpcomingLesson{}, nil).Maybe()
+		mockRepo.On("GetOverdueAssignments", mock.Anything, "user-1").Return([]domain.OverdueHomework{}, nil).Maybe()
 
 		result, err := uc.GetUserHomeData(context.Background(), user)
 		assert.Error(t, err)
@@ -185,13 +200,14 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 
 	t.Run("partial data with nil last lesson", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		mockRepo.On("GetLastLessonData", mock.Anything, "user-1").Return(nil, nil).Once()
 		mockRepo.On("GetActiveCoursesCount", mock.Anything, "user-1").Return(0, nil).Once()
 		mockRepo.On("GetAttendancePercentage", mock.Anything, "user-1").Return(&domain.StatisticSummary{Percentage: 0}, nil).Once()
 		mockRepo.On("GetAssignmentsCompletionPercentage", mock.Anything, "user-1").Return(&domain.StatisticSummary{Percentage: 0}, nil).Once()
 		mockRepo.On("GetUpcomingLessons", mock.Anything, "user-1").Return([]domain.UpcomingLesson{}, nil).Once()
+		mockRepo.On("GetOverdueAssignments", mock.Anything, "user-1").Return([]domain.OverdueHomework{}, nil).Once()
 
 		result, err := uc.GetUserHomeData(context.Background(), user)
 		assert.NoError(t, err)
@@ -203,25 +219,45 @@ func TestDashboardUseCase_GetUserHomeData(t *testing.T) {
 	t.Run("admin role", func(t *testing.T) {
 		admin := &domain.User{ID: "admin-1", Role: domain.RoleAdmin}
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		mockRepo.On("GetLastLessonData", mock.Anything, "admin-1").Return(nil, nil).Once()
 		mockRepo.On("GetActiveCoursesCount", mock.Anything, "admin-1").Return(0, nil).Once()
 		mockRepo.On("GetAttendancePercentage", mock.Anything, "admin-1").Return(&domain.StatisticSummary{Percentage: 0}, nil).Once()
 		mockRepo.On("GetAssignmentsCompletionPercentage", mock.Anything, "admin-1").Return(&domain.StatisticSummary{Percentage: 0}, nil).Once()
 		mockRepo.On("GetUpcomingLessons", mock.Anything, "admin-1").Return([]domain.UpcomingLesson{}, nil).Once()
+		mockRepo.On("GetOverdueAssignments", mock.Anything, "admin-1").Return([]domain.OverdueHomework{}, nil).Once()
 
 		result, err := uc.GetUserHomeData(context.Background(), admin)
 		assert.NoError(t, err)
 		assert.Equal(t, domain.RoleAdmin, result.UserRole)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("parent role shows children", func(t *testing.T) {
+		parent := &domain.User{ID: "parent-1", Role: domain.RoleParent}
+		mockRepo := new(mockDashboardRepo)
+		children := childrenStub{"parent-1": {{ID: "child-1", FirstName: "Alice"}}}
+		uc := usecase.NewDashboardUseCase(mockRepo, children)
+
+		result, err := uc.GetUserHomeData(context.Background(), parent)
+		assert.NoError(t, err)
+		assert.Len(t, result.Children, 1)
+		assert.Equal(t, "child-1", result.Children[0].ID)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+type childrenStub map[string][]*domain.ParentChild
+
+func (s childrenStub) GetChildren(ctx context.Context, actor domain.Actor) ([]*domain.ParentChild, error) {
+	return s[actor.UserID], nil
 }
 
 func TestDashboardUseCase_GetCuratorDashboard(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		groups := []domain.Group{
 			{ID: "group-1", Title: "Group A"},
@@ -253,7 +289,7 @@ func TestDashboardUseCase_GetCuratorDashboard(t *testing.T) {
 
 	t.Run("repo error", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		mockRepo.On("GetCuratorGroups", mock.Anything, "curator-2").Return(nil, errors.New("not found")).Once()
 		mockRepo.On("GetCuratorAttendanceStats", mock.Anything, "curator-2").Return([]domain.CuratorGroupAttendance{}, nil).Maybe()
@@ -269,7 +305,7 @@ func TestDashboardUseCase_GetCuratorDashboard(t *testing.T) {
 func TestDashboardUseCase_GetAdminDashboard(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		stats := &domain.AllPerformanceStats{
 			CourseZones:     domain.PerformanceZones{Green: 10, Yellow: 5, Red: 3},
@@ -299,7 +335,7 @@ func TestDashboardUseCase_GetAdminDashboard(t *testing.T) {
 
 	t.Run("repo error", func(t *testing.T) {
 		mockRepo := new(mockDashboardRepo)
-		uc := usecase.NewDashboardUseCase(mockRepo)
+		uc := usecase.NewDashboardUseCase(mockRepo, nil)
 
 		mockRepo.On("GetAdminCounters", mock.Anything).Return(0, 0, 0.0, 0, 0, errors.New("db error")).Once()
 		mockRepo.On("GetAllPerformanceStats", mock.Anything).Return(nil, nil).Maybe()
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCertificateNotFound  = errors.New("certificate not found")
+	ErrCertificateRevoked   = errors.New("certificate is already revoked")
+	ErrRevokeReasonRequired = errors.New("revoke reason is required")
+)
+
+// CertificateMinProgress — прогресс по заданиям курса (user_courses.progress_percent),
+// начиная с которого курс может считаться завершённым.
+const CertificateMinProgress = 60
+
+// certificateCodeAlphabet — без похожих друг на друга символов (0/O, 1/I), чтобы код было удобно вводить вручную.
+const certificateCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Certificate — сертификат об окончании курса. Имя ученика и название курса фиксируются при выдаче.
+// Отозванный сертификат (RevokedAt != nil) недействителен и повторно не выдаётся.
+type Certificate struct {
+	ID           string     `json:"id"`
+	Code         string     `json:"code"`
+	UserID       string     `json:"user_id"`
+	CourseID     string     `json:"course_id"`
+	StudentName  string     `json:"student_name"`
+	CourseTitle  string     `json:"course_title"`
+	PDFURL       string     `json:"pdf_url"`
+	IssuedAt     time.Time  `json:"issued_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
+
+func (c *Certificate) Valid() bool {
+	return c.RevokedAt == nil
+}
+
+// CertificateVerification — публичный ответ на проверку сертификата по коду.
+// Причина отзыва не раскрывается.
+type CertificateVerification struct {
+	Code        string     `json:"code"`
+	StudentName string     `json:"student_name"`
+	CourseTitle string     `json:"course_title"`
+	IssuedAt    time.Time  `json:"issued_at"`
+	Valid       bool       `json:"valid"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NewCertificateCode — случайный код проверки вида XXXX-XXXX-XXXX.
+func NewCertificateCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i, v := range buf {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(certificateCodeAlphabet[int(v)%len(certificateCodeAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// NormalizeCertificateCode приводит введённый код к виду, в котором он хранится.
+func NormalizeCertificateCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CourseCompletion — выполнение курса учеником. Lessons — опубликованные уроки в порядке прохождения,
+// LessonsDone — сколько из них отмечено пройденными (посещение, принятое ДЗ или просмотр видео).
+type CourseCompletion struct {
+	Lessons         []LessonGate
+	LessonsDone     int
+	Assignments     int
+	ProgressPercent int
+}
+
+// Completed — курс завершён: все уроки пройдены, обязательные по правилам курса ДЗ, тесты и проекты
+// выполнены, а прогресс по заданиям (если они есть) не ниже CertificateMinProgress.
+func (cc CourseCompletion) Completed(c *Course) bool {
+	if len(cc.Lessons) == 0 || cc.LessonsDone < len(cc.Lessons) {
+		return false
+	}
+	for _, g := range cc.Lessons {
+		if !g.Completed(c) {
+			return false
+		}
+	}
+	return cc.Assignments == 0 || cc.ProgressPercent >= CertificateMinProgress
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"math"
+	"time"
+)
 
 type CourseStatus string
 
@@ -24,6 +28,7 @@ type Course struct {
 	IsProjectMandatory  bool         `json:"is_project_mandatory" db:"is_project_mandatory"`
 	IsDiscordMandatory  bool         `json:"is_discord_mandatory" db:"is_discord_mandatory"`
 	IsAntiCopyEnabled   bool         `json:"is_anti_copy_enabled" db:"is_anti_copy_enabled"`
+	GradeScheme         GradeScheme  `json:"grading_scheme" db:"grading_scheme"`
 	TeacherIDs          []string     `json:"teacher_ids" db:"-"`
 }
 
@@ -81,34 +86,244 @@ type Group struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
+// AnswersReveal — когда ученику показываются правильные ответы теста.
+type AnswersReveal string
+
+const (
+	AnswersRevealNever       AnswersReveal = "never"
+	AnswersRevealAfterSubmit AnswersReveal = "after_submit"
+	AnswersRevealAfterPass   AnswersReveal = "after_pass"
+)
+
+func (a AnswersReveal) IsValid() bool {
+	switch a {
+	case AnswersRevealNever, AnswersRevealAfterSubmit, AnswersRevealAfterPass:
+		return true
+	}
+	return false
+}
+
+// QuestionType — тип вопроса теста. Вопросы text проверяет преподаватель вручную.
+type QuestionType string
+
+const (
+	QuestionSingle   QuestionType = "single"
+	QuestionMultiple QuestionType = "multiple"
+	QuestionNumeric  QuestionType = "numeric"
+	QuestionOrdering QuestionType = "ordering"
+	QuestionMatching QuestionType = "matching"
+	QuestionText     QuestionType = "text"
+)
+
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionSingle, QuestionMultiple, QuestionNumeric, QuestionOrdering, QuestionMatching, QuestionText:
+		return true
+	}
+	return false
+}
+
+// TestQuestion — вопрос с ключом ответа. Где лежит ключ, зависит от типа:
+//   - single, numeric, text — CorrectAnswer (для text это образец ответа для преподавателя);
+//   - multiple — CorrectAnswers, набор верных вариантов из Options;
+//   - ordering — CorrectAnswers, Options в правильном порядке;
+//   - matching — CorrectAnswers[i] из MatchOptions в пару к Options[i].
 type TestQuestion struct {
-	ID            string   `json:"id"`
-	Question      string   `json:"question"`
-	Options       []string `json:"options"`
-	CorrectAnswer string   `json:"correct_answer,omitempty"`
-	Points        int      `json:"points"`
+	ID             string       `json:"id"`
+	Type           QuestionType `json:"type"`
+	Question       string       `json:"question"`
+	Options        []string     `json:"options"`
+	MatchOptions   []string     `json:"match_options,omitempty"`
+	CorrectAnswer  string       `json:"correct_answer,omitempty"`
+	CorrectAnswers []string     `json:"correct_answers,omitempty"`
+	Tolerance      float64      `json:"tolerance,omitempty"`
+	PartialCredit  bool         `json:"partial_credit"`
+	Points         int          `json:"points"`
+}
+
+// TestScoring — какая попытка идёт в зачёт: лучшая или последняя.
+type TestScoring string
+
+const (
+	TestScoringBest TestScoring = "best"
+	TestScoringLast TestScoring = "last"
+)
+
+// TestPolicy — правила прохождения теста. Нулевые значения снимают ограничение:
+// TimeLimitMin = 0 — без лимита времени, MaxAttempts = 0 — без лимита попыток,
+// PoolSize = 0 — в попытку попадают все вопросы.
+type TestPolicy struct {
+	TimeLimitMin     int         `json:"time_limit_min" db:"time_limit_min"`
+	MaxAttempts      int         `json:"max_attempts" db:"max_attempts"`
+	Scoring          TestScoring `json:"scoring" db:"scoring"`
+	ShuffleQuestions bool        `json:"shuffle_questions" db:"shuffle_questions"`
+	ShuffleOptions   bool        `json:"shuffle_options" db:"shuffle_options"`
+	PoolSize         int         `json:"pool_size" db:"pool_size"`
+}
+
+// RequiresStart — вопросы такого теста выдаются только внутри начатой попытки.
+func (p TestPolicy) RequiresStart() bool {
+	return p.TimeLimitMin > 0 || p.PoolSize > 0 || p.ShuffleQuestions || p.ShuffleOptions
 }
 
 type Test struct {
-	ID           string         `json:"id" db:"id"`
-	LessonID     *string        `json:"lesson_id" db:"lesson_id"`
-	Title        string         `json:"title" db:"title"`
-	Description  string         `json:"description" db:"description"`
-	PassingScore int            `json:"passing_score" db:"passing_score"`
-	Questions    []TestQuestion `json:"questions,omitempty"`
-	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	ID            string        `json:"id" db:"id"`
+	LessonID      *string       `json:"lesson_id" db:"lesson_id"`
+	Title         string        `json:"title" db:"title"`
+	Description   string        `json:"description" db:"description"`
+	PassingScore  int           `json:"passing_score" db:"passing_score"`
+	AnswersReveal AnswersReveal `json:"answers_reveal" db:"answers_reveal"`
+	TestPolicy
+	Questions []TestQuestion `json:"questions,omitempty"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	Result    *TestResult    `json:"result,omitempty"`
+}
+
+// StudentTestQuestion — вопрос в том виде, в каком его видит ученик.
+// CorrectAnswer и CorrectAnswers заполняются, только когда политика теста разрешает показать ответы.
+type StudentTestQuestion struct {
+	ID             string       `json:"id"`
+	Type           QuestionType `json:"type"`
+	Question       string       `json:"question"`
+	Options        []string     `json:"options"`
+	MatchOptions   []string     `json:"match_options,omitempty"`
+	Points         int          `json:"points"`
+	CorrectAnswer  *string      `json:"correct_answer,omitempty"`
+	CorrectAnswers []string     `json:"correct_answers,omitempty"`
+}
+
+// StudentTest — проекция Test для учеников: без ответов, пока они не открыты.
+//
+// Вопросы отдаются внутри начатой попытки (в её порядке), после открытия ответов
+// или для тестов без ограничений (см. TestPolicy.RequiresStart); иначе список пуст.
+type StudentTest struct {
+	ID              string        `json:"id"`
+	LessonID        *string       `json:"lesson_id"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	PassingScore    int           `json:"passing_score"`
+	AnswersReveal   AnswersReveal `json:"answers_reveal"`
+	AnswersRevealed bool          `json:"answers_revealed"`
+	TestPolicy
+	AttemptsUsed int                   `json:"attempts_used"`
+	Attempt      *ActiveTestAttempt    `json:"attempt,omitempty"`
+	Questions    []StudentTestQuestion `json:"questions"`
+	Result       *TestResult           `json:"result,omitempty"`
+}
+
+// ActiveTestAttempt — начатая и ещё не отправленная попытка.
+type ActiveTestAttempt struct {
+	ID         string     `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	DeadlineAt *time.Time `json:"deadline_at,omitempty"`
+}
+
+// TestAnswer — ответ на вопрос. Answer используется для single, numeric и text,
+// Answers — для multiple, ordering и matching (в порядке Options).
+type TestAnswer struct {
+	QuestionID string   `json:"question_id"`
+	Answer     string   `json:"answer,omitempty"`
+	Answers    []string `json:"answers,omitempty"`
+}
+
+// ErrTestAttemptActive — у ученика уже есть незавершённая попытка теста (параллельный старт).
+var ErrTestAttemptActive = errors.New("test attempt is already in progress")
+
+type TestAttemptStatus string
+
+const (
+	TestAttemptInProgress    TestAttemptStatus = "in_progress"
+	TestAttemptGraded        TestAttemptStatus = "graded"
+	TestAttemptPendingReview TestAttemptStatus = "pending_review"
+	TestAttemptExpired       TestAttemptStatus = "expired"
+)
+
+// QuestionResult — баллы за один вопрос попытки. Score может быть дробным при частичном зачёте.
+type QuestionResult struct {
+	QuestionID  string  `json:"question_id"`
+	Score       float64 `json:"score"`
+	MaxScore    int     `json:"max_score"`
+	NeedsReview bool    `json:"needs_review,omitempty"`
+	Comment     string  `json:"comment,omitempty"`
+}
+
+// TestAttempt — одна сдача теста. PassingScore теста задаётся в процентах.
+// Пока есть непроверенные вопросы, попытка в статусе pending_review и не считается сданной.
+//
+// QuestionIDs — вопросы, выданные в попытку, в порядке показа; Seed задаёт перемешивание вариантов.
+type TestAttempt struct {
+	ID          string            `json:"id"`
+	TestID      string            `json:"test_id"`
+	UserID      string            `json:"user_id"`
+	QuestionIDs []string          `json:"question_ids,omitempty"`
+	Seed        int64             `json:"-"`
+	Answers     []TestAnswer      `json:"answers"`
+	Results     []QuestionResult  `json:"results,omitempty"`
+	Score       float64           `json:"score"`
+	MaxScore    int               `json:"max_score"`
+	Percent     int               `json:"percent"`
+	Passed      bool              `json:"passed"`
+	Status      TestAttemptStatus `json:"status"`
+	StartedAt   time.Time         `json:"started_at"`
+	DeadlineAt  *time.Time        `json:"deadline_at,omitempty"`
+	SubmittedAt *time.Time        `json:"submitted_at,omitempty"`
+	GradedAt    *time.Time        `json:"graded_at,omitempty"`
+}
+
+// Finalize пересчитывает итог попытки по Results.
+func (a *TestAttempt) Finalize(passingScore int) {
+	a.Score, a.MaxScore = 0, 0
+	a.Status = TestAttemptGraded
+	for _, r := range a.Results {
+		a.Score += r.Score
+		a.MaxScore += r.MaxScore
+		if r.NeedsReview {
+			a.Status = TestAttemptPendingReview
+		}
+	}
+	a.Percent = 0
+	if a.MaxScore > 0 {
+		// эпсилон защищает от 28.999… при дробных баллах
+		a.Percent = int(math.Floor(a.Score*100/float64(a.MaxScore) + 1e-9))
+	}
+	a.Passed = a.Status == TestAttemptGraded && a.Percent >= passingScore
+}
+
+// TestAttemptReview — попытка с вопросами ручной проверки для очереди преподавателя.
+type TestAttemptReview struct {
+	TestAttempt
+	StudentName  string         `json:"student_name"`
+	TestTitle    string         `json:"test_title"`
+	PassingScore int            `json:"passing_score"`
+	Questions    []TestQuestion `json:"questions"`
+}
+
+// TestResult — сводка отправленных попыток ученика по тесту для уроков и дашбордов.
+// Percent и Passed считаются по Scoring теста: лучшая или последняя проверенная попытка.
+type TestResult struct {
+	TestID        string     `json:"test_id"`
+	Title         string     `json:"title,omitempty"`
+	Attempts      int        `json:"attempts"`
+	BestPercent   int        `json:"best_percent"`
+	LastPercent   int        `json:"last_percent"`
+	Percent       int        `json:"percent"`
+	Passed        bool       `json:"passed"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
 }
 
 type Project struct {
-	ID               string    `json:"id" db:"id"`
-	LessonID         *string   `json:"lesson_id" db:"lesson_id"`
-	Title            string    `json:"title" db:"title"`
-	Description      string    `json:"description" db:"description"`
-	MaxScore         int       `json:"max_score" db:"max_score"`
-	SubmissionStatus string    `json:"submission_status,omitempty"`
-	Grade            int       `json:"grade,omitempty"`
-	TeacherComment   string    `json:"teacher_comment,omitempty"`
-	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	ID          string  `json:"id" db:"id"`
+	LessonID    *string `json:"lesson_id" db:"lesson_id"`
+	Title       string  `json:"title" db:"title"`
+	Description string  `json:"description" db:"description"`
+	MaxScore    int     `json:"max_score" db:"max_score"`
+	// Состояние последней версии сдачи текущего ученика.
+	SubmissionStatus  string     `json:"submission_status,omitempty"`
+	SubmissionVersion int        `json:"submission_version,omitempty"`
+	SubmittedAt       *time.Time `json:"submitted_at,omitempty"`
+	Grade             int        `json:"grade,omitempty"`
+	TeacherComment    string     `json:"teacher_comment,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
 }
 
 type CourseStructure struct {
@@ -17,6 +17,7 @@ type StatisticSummary struct {
 	Breakdown  map[string]int `json:"breakdown"`
 }
 
+// UpcomingLesson — IsHomeworkDue: у ДЗ урока есть срок, а работа ещё не отправлена.
 type UpcomingLesson struct {
 	Date          time.Time `json:"date"`
 	TimeRange     string    `json:"time_range"`
@@ -32,7 +33,9 @@ type HomeDashboard struct {
 	AttendanceStats    *StatisticSummary `json:"attendance_stats"`
 	AssignmentStats    *StatisticSummary `json:"assignment_stats"`
 	UpcomingLessons    []UpcomingLesson  `json:"upcoming_lessons"`
+	OverdueHomework    []OverdueHomework `json:"overdue_homework"`
 	User               *User             `json:"user_data"`
+	Children           []*ParentChild    `json:"children,omitempty"`
 }
 
 type PerformanceZones struct {
@@ -72,6 +75,9 @@ type AdminHomeDashboard struct {
 	UpdatePeriodMonth     string                `json:"update_period_month"`
 }
 
+// AdminCourseStats — сводка по курсу. AverageWatchTimeMin — среднее на ученика курса время
+// просмотренного видео (без повторов), VideoCompletionRate — доля начатых видео, просмотренных
+// не меньше чем на VideoCompletionPercent процентов.
 type AdminCourseStats struct {
 	TotalStudents        int            `json:"total_students"`
 	NewStudentsMonth     int            `json:"new_students_month"`
@@ -80,6 +86,7 @@ type AdminCourseStats struct {
 	AverageScore         float64        `json:"average_score"`
 	AverageLagLessons    float64        `json:"average_lag_lessons"`
 	AverageWatchTimeMin  float64        `json:"average_watch_time_minutes"`
+	VideoCompletionRate  float64        `json:"video_completion_percent"`
 	SuccessRateBreakdown map[string]int `json:"success_rate_breakdown"`
 }
 
@@ -109,6 +116,7 @@ type CuratorHomeworkStats struct {
 	AvgCompletion  float64 `json:"avg_completion_percent"`
 	TotalSubmitted int     `json:"total_submitted"`
 	TotalAccepted  int     `json:"total_accepted"`
+	TotalOverdue   int     `json:"total_overdue"`
 }
 
 type CuratorDashboardData struct {
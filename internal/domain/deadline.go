@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidDeadline  = errors.New("invalid assignment deadline")
+	ErrDeadlinePassed   = errors.New("assignment deadline has passed")
+	ErrInvalidExtension = errors.New("invalid extension request")
+)
+
+// LatePolicy — что происходит с работой, отправленной после срока.
+type LatePolicy string
+
+const (
+	LatePolicyAccept   LatePolicy = "accept"
+	LatePolicyPenalize LatePolicy = "penalize"
+	LatePolicyReject   LatePolicy = "reject"
+)
+
+// AssignmentDeadline — срок сдачи ДЗ: абсолютный DueAt или DueOffsetHours от начала урока.
+// Если не задано ни то, ни другое, срока нет и работа никогда не опаздывает.
+type AssignmentDeadline struct {
+	DueAt              *time.Time `json:"due_at,omitempty"`
+	DueOffsetHours     *int       `json:"due_offset_hours,omitempty"`
+	LatePolicy         LatePolicy `json:"late_policy"`
+	LatePenaltyPercent int        `json:"late_penalty_percent"`
+}
+
+func (d AssignmentDeadline) Validate() error {
+	if d.DueAt != nil && d.DueOffsetHours != nil {
+		return fmt.Errorf("%w: set either due_at or due_offset_hours", ErrInvalidDeadline)
+	}
+	if d.DueOffsetHours != nil && *d.DueOffsetHours < 0 {
+		return fmt.Errorf("%w: due_offset_hours must be >= 0", ErrInvalidDeadline)
+	}
+	switch d.LatePolicy {
+	case LatePolicyPenalize:
+		if d.LatePenaltyPercent <= 0 || d.LatePenaltyPercent > 100 {
+			return fmt.Errorf("%w: late_penalty_percent must be 1..100", ErrInvalidDeadline)
+		}
+	case LatePolicyAccept, LatePolicyReject:
+		if d.LatePenaltyPercent != 0 {
+			return fmt.Errorf("%w: late_penalty_percent is only used with the penalize policy", ErrInvalidDeadline)
+		}
+	default:
+		return fmt.Errorf("%w: unknown late_policy %q", ErrInvalidDeadline, d.LatePolicy)
+	}
+	return nil
+}
+
+// Due — момент окончания срока; nil, если срока нет или он считается от урока без даты.
+func (d AssignmentDeadline) Due(lessonTime *time.Time) *time.Time {
+	switch {
+	case d.DueAt != nil:
+		due := *d.DueAt
+		return &due
+	case d.DueOffsetHours != nil && lessonTime != nil:
+		due := lessonTime.Add(time.Duration(*d.DueOffsetHours) * time.Hour)
+		return &due
+	}
+	return nil
+}
+
+// StudentDeadline — срок ДЗ для конкретного ученика. DueAt учитывает одобренное продление
+// (ExtendedUntil), если оно позже общего срока.
+type StudentDeadline struct {
+	AssignmentID       string     `json:"assignment_id"`
+	DueAt              *time.Time `json:"due_at,omitempty"`
+	ExtendedUntil      *time.Time `json:"extended_until,omitempty"`
+	LatePolicy         LatePolicy `json:"late_policy"`
+	LatePenaltyPercent int        `json:"late_penalty_percent"`
+}
+
+func NewStudentDeadline(assignmentID string, d AssignmentDeadline, lessonTime, extendedUntil *time.Time) *StudentDeadline {
+	sd := &StudentDeadline{
+		AssignmentID:       assignmentID,
+		DueAt:              d.Due(lessonTime),
+		LatePolicy:         d.LatePolicy,
+		LatePenaltyPercent: d.LatePenaltyPercent,
+	}
+	if sd.LatePolicy == "" {
+		sd.LatePolicy = LatePolicyAccept
+	}
+	if sd.DueAt != nil && extendedUntil != nil && extendedUntil.After(*sd.DueAt) {
+		sd.DueAt = extendedUntil
+		sd.ExtendedUntil = extendedUntil
+	}
+	return sd
+}
+
+func (d *StudentDeadline) IsLate(at time.Time) bool {
+	return d != nil && d.DueAt != nil && at.After(*d.DueAt)
+}
+
+// CheckSubmission сообщает, опаздывает ли отправка в момент at; при политике reject опоздание — ошибка.
+func (d *StudentDeadline) CheckSubmission(at time.Time) (bool, error) {
+	if !d.IsLate(at) {
+		return false, nil
+	}
+	if d.LatePolicy == LatePolicyReject {
+		return true, ErrDeadlinePassed
+	}
+	return true, nil
+}
+
+// ApplyLatePenalty снижает оценку опоздавшей работы на percent процентов.
+func ApplyLatePenalty(grade, percent int) int {
+	if percent <= 0 {
+		return grade
+	}
+	return grade * (100 - clampPercent(percent)) / 100
+}
+
+type ExtensionStatus string
+
+const (
+	ExtensionStatusPending  ExtensionStatus = "PENDING"
+	ExtensionStatusApproved ExtensionStatus = "APPROVED"
+	ExtensionStatusRejected ExtensionStatus = "REJECTED"
+)
+
+// ExtensionRequest — запрос ученика на продление срока ДЗ до RequestedDueAt.
+type ExtensionRequest struct {
+	ID             string          `json:"id"`
+	AssignmentID   string          `json:"assignment_id"`
+	LessonID       string          `json:"lesson_id"`
+	LessonTitle    string          `json:"lesson_title"`
+	StudentID      string          `json:"student_id"`
+	StudentName    string          `json:"student_name"`
+	RequestedDueAt time.Time       `json:"requested_due_at"`
+	Reason         string          `json:"reason"`
+	Status         ExtensionStatus `json:"status"`
+	ReviewedBy     *string         `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewComment  *string         `json:"review_comment,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// OverdueHomework — ДЗ с истёкшим сроком, которое ученик так и не отправил.
+type OverdueHomework struct {
+	AssignmentID string    `json:"assignment_id"`
+	LessonID     string    `json:"lesson_id"`
+	LessonTitle  string    `json:"lesson_title"`
+	CourseTitle  string    `json:"course_title"`
+	DueAt        time.Time `json:"due_at"`
+}
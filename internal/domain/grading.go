@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidGradeScheme = errors.New("invalid grading scheme")
+	ErrInvalidGrade       = errors.New("grade is not allowed by the course grading scheme")
+)
+
+type GradeSchemeType string
+
+const (
+	GradeSchemeNumeric  GradeSchemeType = "numeric"
+	GradeSchemeLetter   GradeSchemeType = "letter"
+	GradeSchemePassFail GradeSchemeType = "pass_fail"
+	GradeSchemeRubric   GradeSchemeType = "rubric"
+)
+
+// passGrade — оценка, которую получает принятая работа в схеме pass_fail.
+const passGrade = 100
+
+// LetterGrade — буквенная оценка и её вес в процентах; в базе хранится Value.
+type LetterGrade struct {
+	Letter string `json:"letter"`
+	Value  int    `json:"value"`
+}
+
+// GradeScheme — схема оценивания ДЗ курса.
+//   - numeric: целая оценка от Min до Max, при Step > 0 — только с этим шагом от Min;
+//   - letter: одна из Letters;
+//   - pass_fail: принятая работа получает 100, оценка не выставляется;
+//   - rubric: сумма баллов по критериям от 0 до Max.
+//
+// Отклонённая работа во всех схемах получает 0.
+type GradeScheme struct {
+	Type    GradeSchemeType `json:"type"`
+	Min     int             `json:"min,omitempty"`
+	Max     int             `json:"max,omitempty"`
+	Step    int             `json:"step,omitempty"`
+	Letters []LetterGrade   `json:"letters,omitempty"`
+}
+
+// DefaultGradeScheme — прежняя сетка 20/40/60/80/100, схема курсов по умолчанию.
+func DefaultGradeScheme() GradeScheme {
+	return GradeScheme{Type: GradeSchemeNumeric, Min: 20, Max: 100, Step: 20}
+}
+
+func (s GradeScheme) Validate() error {
+	switch s.Type {
+	case GradeSchemeNumeric:
+		if s.Min < 0 || s.Max <= 0 || s.Min > s.Max || s.Step < 0 {
+			return fmt.Errorf("%w: numeric scheme requires 0 <= min <= max, max > 0, step >= 0", ErrInvalidGradeScheme)
+		}
+		if s.Step > 0 && (s.Max-s.Min)%s.Step != 0 {
+			return fmt.Errorf("%w: max must be reachable from min with the given step", ErrInvalidGradeScheme)
+		}
+	case GradeSchemeLetter:
+		if len(s.Letters) == 0 {
+			return fmt.Errorf("%w: letter scheme requires letters", ErrInvalidGradeScheme)
+		}
+		seen := map[string]bool{}
+		for _, l := range s.Letters {
+			key := strings.ToUpper(strings.TrimSpace(l.Letter))
+			if key == "" || seen[key] || l.Value < 0 || l.Value > 100 {
+				return fmt.Errorf("%w: letters must be unique and worth 0..100", ErrInvalidGradeScheme)
+			}
+			seen[key] = true
+		}
+	case GradeSchemePassFail:
+	case GradeSchemeRubric:
+		if s.Max <= 0 {
+			return fmt.Errorf("%w: rubric scheme requires max > 0", ErrInvalidGradeScheme)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidGradeScheme, s.Type)
+	}
+	return nil
+}
+
+// AcceptedGrade проверяет оценку принятой работы и возвращает значение для хранения.
+// В буквенной схеме letter имеет приоритет над grade; grade должен совпадать со значением одной из букв.
+func (s GradeScheme) AcceptedGrade(grade int, letter string) (int, error) {
+	switch s.Type {
+	case GradeSchemeNumeric:
+		if grade < s.Min || grade > s.Max || s.Step > 0 && (grade-s.Min)%s.Step != 0 {
+			return 0, fmt.Errorf("%w: expected %d..%d with step %d", ErrInvalidGrade, s.Min, s.Max, s.Step)
+		}
+	case GradeSchemeLetter:
+		for _, l := range s.Letters {
+			if letter != "" && strings.EqualFold(strings.TrimSpace(letter), l.Letter) || letter == "" && grade == l.Value {
+				return l.Value, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: unknown letter grade", ErrInvalidGrade)
+	case GradeSchemePassFail:
+		if grade != 0 && grade != passGrade {
+			return 0, fmt.Errorf("%w: pass/fail scheme takes no grade", ErrInvalidGrade)
+		}
+		return passGrade, nil
+	case GradeSchemeRubric:
+		if grade < 0 || grade > s.Max {
+			return 0, fmt.Errorf("%w: expected 0..%d", ErrInvalidGrade, s.Max)
+		}
+	}
+	return grade, nil
+}
+
+// SnapGrade приводит рассчитанную оценку (например, после штрафа за опоздание) к ближайшей
+// допустимой в схеме не выше неё; если такой нет — к наименьшей допустимой. В pass_fail принятая
+// работа остаётся зачтённой.
+func (s GradeScheme) SnapGrade(grade int) int {
+	switch s.Type {
+	case GradeSchemeNumeric:
+		if grade <= s.Min {
+			return s.Min
+		}
+		if grade > s.Max {
+			grade = s.Max
+		}
+		if s.Step > 0 {
+			grade -= (grade - s.Min) % s.Step
+		}
+		return grade
+	case GradeSchemeLetter:
+		best, lowest := -1, -1
+		for _, l := range s.Letters {
+			if l.Value <= grade && l.Value > best {
+				best = l.Value
+			}
+			if lowest < 0 || l.Value < lowest {
+				lowest = l.Value
+			}
+		}
+		if best < 0 {
+			return lowest
+		}
+		return best
+	case GradeSchemePassFail:
+		return passGrade
+	case GradeSchemeRubric:
+		if grade < 0 {
+			return 0
+		}
+		if grade > s.Max {
+			return s.Max
+		}
+	}
+	return grade
+}
+
+// Percent — вклад принятой работы с оценкой grade в прогресс курса, 0..100.
+func (s GradeScheme) Percent(grade int) int {
+	switch s.Type {
+	case GradeSchemeLetter, GradeSchemePassFail:
+		return clampPercent(grade)
+	}
+	if s.Max <= 0 {
+		return 0
+	}
+	return clampPercent(grade * 100 / s.Max)
+}
+
+func clampPercent(p int) int {
+	if p < 0 {
+		return 0
+	}
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+// AssignmentResult — итог ученика по заданию курса; Status пуст, если работа не сдавалась.
+// MaxPoints — сумма баллов рубрики задания, 0 — рубрики нет.
+type AssignmentResult struct {
+	AssignmentID string
+	Status       string
+	Grade        int
+	MaxPoints    int
+}
+
+// CourseProgress — средний вклад заданий курса: несданные и не принятые дают 0, оценённые
+// по рубрике — долю набранных баллов, остальные — вклад по схеме курса.
+func CourseProgress(scheme GradeScheme, results []AssignmentResult) int {
+	if len(results) == 0 {
+		return 0
+	}
+	total := 0
+	for _, r := range results {
+		switch {
+		case r.Status != "accepted":
+		case r.MaxPoints > 0:
+			total += clampPercent(r.Grade * 100 / r.MaxPoints)
+		default:
+			total += scheme.Percent(r.Grade)
+		}
+	}
+	return total / len(results)
+}
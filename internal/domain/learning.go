@@ -49,6 +49,13 @@ type StudentLessonDetail struct {
 	AssignmentStatus string            `json:"assignment_status,omitempty"`
 	TeacherComment   string            `json:"teacher_comment,omitempty"`
 	Grade            int               `json:"grade,omitempty"`
+	UnreadComments   int               `json:"unread_comments"`
+	Rubric           *Rubric           `json:"rubric,omitempty"`
+	RubricScores     []CriterionScore  `json:"rubric_scores,omitempty"`
+	Deadline         *StudentDeadline  `json:"deadline,omitempty"`
+	IsLate           bool              `json:"is_late,omitempty"`
+	VideoProgress    *VideoProgress    `json:"video_progress,omitempty"`
+	TestResults      []TestResult      `json:"test_results"`
 }
 
 type LessonMaterial struct {
@@ -59,22 +66,100 @@ type LessonMaterial struct {
 	PublicURL string `json:"public_url"`
 }
 
+const (
+	SubmissionKindHomework = "homework"
+	SubmissionKindProject  = "project"
+)
+
+// SubmissionRecord — строка очереди проверки. Для ДЗ ID — это ID задания,
+// для проекта — ID версии (project_submissions.id). UnreadComments — ответы ученика
+// в обсуждении работы, которые проверяющий ещё не прочитал. Rubric — критерии, по которым
+// выставляется оценка, если они заданы. Similarity — наибольшая схожесть ДЗ с работой другого
+// ученика, если она не ниже SimilarityThreshold.
 type SubmissionRecord struct {
 	ID             string    `json:"id"`
+	Kind           string    `json:"kind"`
 	UserID         string    `json:"user_id"`
 	StudentName    string    `json:"student_name"`
 	CourseTitle    string    `json:"course_title"`
 	ModuleOrder    int       `json:"module_order"`
 	LessonOrder    int       `json:"lesson_order"`
 	LessonTitle    string    `json:"lesson_title"`
+	ProjectID      string    `json:"project_id,omitempty"`
+	ProjectTitle   string    `json:"project_title,omitempty"`
+	MaxScore       int       `json:"max_score,omitempty"`
+	Version        int       `json:"version,omitempty"`
 	Text           string    `json:"submission_text"`
 	Files          []string  `json:"submission_files"`
 	Status         string    `json:"status"`
 	Grade          int       `json:"grade"`
 	TeacherComment string    `json:"teacher_comment"`
+	UnreadComments int       `json:"unread_comments"`
+	Rubric         *Rubric   `json:"rubric,omitempty"`
+	IsLate         bool      `json:"is_late"`
+	Similarity     float64   `json:"similarity_score,omitempty"`
 	SubmittedAt    time.Time `json:"submitted_at"`
 }
 
+// SubmissionVersion — одна неизменяемая версия сдачи ДЗ со своей оценкой и комментарием.
+// Последняя версия совпадает с текущим состоянием работы.
+type SubmissionVersion struct {
+	ID             string     `json:"id"`
+	AssignmentID   string     `json:"assignment_id"`
+	UserID         string     `json:"user_id"`
+	Version        int        `json:"version"`
+	Text           string     `json:"submission_text"`
+	Files          []string   `json:"submission_files"`
+	Status         string     `json:"status"`
+	Grade          int        `json:"grade"`
+	TeacherComment string     `json:"teacher_comment"`
+	ReviewedBy     *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	SubmittedAt    time.Time  `json:"submitted_at"`
+}
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine — строка построчного сравнения текста ответа.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// SubmissionDiff — что изменилось между двумя версиями сдачи.
+type SubmissionDiff struct {
+	AssignmentID string     `json:"assignment_id"`
+	UserID       string     `json:"user_id"`
+	From         int        `json:"from"`
+	To           int        `json:"to"`
+	Text         []DiffLine `json:"text"`
+	FilesAdded   []string   `json:"files_added"`
+	FilesRemoved []string   `json:"files_removed"`
+}
+
+// ProjectSubmission — одна версия сдачи проекта. Версии не перезаписываются:
+// повторная отправка создаёт следующую.
+type ProjectSubmission struct {
+	ID             string           `json:"id"`
+	ProjectID      string           `json:"project_id"`
+	UserID         string           `json:"user_id"`
+	Version        int              `json:"version"`
+	Text           string           `json:"submission_text"`
+	Files          []string         `json:"submission_files"`
+	Status         string           `json:"status"`
+	Grade          int              `json:"grade"`
+	TeacherComment string           `json:"teacher_comment"`
+	MaxScore       int              `json:"max_score"`
+	RubricScores   []CriterionScore `json:"rubric_scores"`
+	ReviewedBy     *string          `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time       `json:"reviewed_at,omitempty"`
+	SubmittedAt    time.Time        `json:"submitted_at"`
+}
+
 type StudentSubmissionInput struct {
 	LessonID   string
 	UserID     string
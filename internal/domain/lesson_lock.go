@@ -0,0 +1,50 @@
+package domain
+
+import "errors"
+
+var ErrLessonLocked = errors.New("lesson is locked until the previous lesson is completed")
+
+// LessonGate — что ученик выполнил по уроку из того, что может требоваться для открытия следующего.
+// Unlocked — урок открыт ученику администратором независимо от правил.
+type LessonGate struct {
+	LessonID         string
+	HasHomework      bool
+	HomeworkAccepted bool
+	Tests            int
+	TestsPassed      int
+	Projects         int
+	ProjectsAccepted int
+	Unlocked         bool
+}
+
+// Completed — выполнены ли обязательные по правилам курса части урока.
+func (g LessonGate) Completed(c *Course) bool {
+	if c.IsHomeworkMandatory && g.HasHomework && !g.HomeworkAccepted {
+		return false
+	}
+	if c.IsTestMandatory && g.TestsPassed < g.Tests {
+		return false
+	}
+	if c.IsProjectMandatory && g.ProjectsAccepted < g.Projects {
+		return false
+	}
+	return true
+}
+
+// LockedLessons — закрытые уроки курса. gates идут в порядке прохождения; первый урок всегда открыт,
+// следующий открывается, когда предыдущий открыт и выполнен. Открытый администратором урок
+// доступен всегда, но следующий за ним всё равно ждёт его выполнения.
+func LockedLessons(c *Course, gates []LessonGate) map[string]bool {
+	locked := make(map[string]bool)
+	accessible := true
+	for i, g := range gates {
+		if i > 0 {
+			accessible = accessible && gates[i-1].Completed(c)
+		}
+		accessible = accessible || g.Unlocked
+		if !accessible {
+			locked[g.LessonID] = true
+		}
+	}
+	return locked
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type UserTOTP struct {
+	UserID       string     `json:"user_id" db:"user_id"`
+	Secret       string     `json:"-" db:"secret"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty" db:"enabled_at"`
+	LastUsedStep int64      `json:"-" db:"last_used_step"`
+}
+
+type RoleMFAPolicy struct {
+	Role      Role      `json:"role" db:"role"`
+	Required  bool      `json:"required" db:"required"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
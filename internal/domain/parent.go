@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// ParentChild — ребёнок в кабинете родителя.
+type ParentChild struct {
+	ID        string        `json:"id"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	AvatarURL string        `json:"avatar_url"`
+	Courses   []ChildCourse `json:"courses"`
+}
+
+type ChildCourse struct {
+	CourseID        string `json:"course_id"`
+	Title           string `json:"title"`
+	GroupTitle      string `json:"group_title,omitempty"`
+	ProgressPercent int    `json:"progress_percent"`
+}
+
+// ChildGrade — проверенное или ожидающее проверки ДЗ ребёнка.
+type ChildGrade struct {
+	CourseID       string    `json:"course_id"`
+	CourseTitle    string    `json:"course_title"`
+	LessonID       string    `json:"lesson_id"`
+	LessonTitle    string    `json:"lesson_title"`
+	ModuleOrder    int       `json:"module_order"`
+	LessonOrder    int       `json:"lesson_order"`
+	Status         string    `json:"status"`
+	Grade          int       `json:"grade"`
+	TeacherComment string    `json:"teacher_comment"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+}
+
+type ChildFreezeInfo struct {
+	Status   *FreezePeriod    `json:"status"`
+	Requests []*FreezeRequest `json:"requests"`
+}
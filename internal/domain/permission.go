@@ -0,0 +1,43 @@
+package domain
+
+// Именованные права. Маршруты и обработчики проверяют права, а не роли;
+// какие права есть у роли — хранится в role_permissions и редактируется админом.
+const (
+	PermDashboardView      = "dashboard.view"
+	PermCoursesView        = "courses.view"
+	PermCoursesEdit        = "courses.edit"
+	PermScheduleManage     = "schedule.manage"
+	PermUsersView          = "users.view"
+	PermUsersCreate        = "users.create"
+	PermUsersEdit          = "users.edit"
+	PermUsersDelete        = "users.delete"
+	PermUsersSessions      = "users.sessions"
+	PermFinanceBalance     = "finance.balance"
+	PermEnrollmentManage   = "enrollment.manage"
+	PermGroupsView         = "groups.view"
+	PermGroupsManage       = "groups.manage"
+	PermAttendanceView     = "attendance.view"
+	PermAttendanceMark     = "attendance.mark"
+	PermFreezeRequest      = "freeze.request"
+	PermFreezeApprove      = "freeze.approve"
+	PermAccessApprove      = "access.approve"
+	PermCommentsManage     = "comments.manage"
+	PermNotificationsSend  = "notifications.send"
+	PermStatisticsView     = "statistics.view"
+	PermReportsExport      = "reports.export"
+	PermBannersEdit        = "banners.edit"
+	PermSubmissionsReview  = "submissions.review"
+	PermSecurityManage     = "security.manage"
+	PermPermissionsManage  = "permissions.manage"
+	PermCertificatesRevoke = "certificates.revoke"
+)
+
+type Permission struct {
+	Code        string `json:"code" db:"code"`
+	Description string `json:"description" db:"description"`
+}
+
+type RolePermissions struct {
+	Role        Role     `json:"role" db:"role"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidRubric       = errors.New("invalid rubric")
+	ErrInvalidRubricScores = errors.New("invalid rubric scores")
+)
+
+// RubricCriterion — критерий оценки с диапазоном баллов 0..MaxPoints.
+type RubricCriterion struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	MaxPoints   int    `json:"max_points"`
+}
+
+// Rubric — критерии ДЗ (AssignmentID) или проекта (ProjectID). Без критериев оценка выставляется целиком.
+type Rubric struct {
+	AssignmentID string            `json:"assignment_id,omitempty"`
+	ProjectID    string            `json:"project_id,omitempty"`
+	Criteria     []RubricCriterion `json:"criteria"`
+}
+
+// CriterionScore — балл по критерию. Title и MaxPoints копируются из рубрики при проверке,
+// чтобы разбивка не менялась, если рубрику потом отредактируют.
+type CriterionScore struct {
+	CriterionID string `json:"criterion_id"`
+	Title       string `json:"title"`
+	MaxPoints   int    `json:"max_points"`
+	Score       int    `json:"score"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+func (r *Rubric) Empty() bool {
+	return r == nil || len(r.Criteria) == 0
+}
+
+func (r *Rubric) MaxPoints() int {
+	if r == nil {
+		return 0
+	}
+	total := 0
+	for _, c := range r.Criteria {
+		total += c.MaxPoints
+	}
+	return total
+}
+
+func (r *Rubric) Validate() error {
+	seen := map[string]bool{}
+	for i, c := range r.Criteria {
+		title := strings.ToLower(strings.TrimSpace(c.Title))
+		if title == "" || seen[title] {
+			return fmt.Errorf("%w: criterion %d needs a unique title", ErrInvalidRubric, i+1)
+		}
+		if c.MaxPoints <= 0 {
+			return fmt.Errorf("%w: criterion %q needs max_points > 0", ErrInvalidRubric, c.Title)
+		}
+		seen[title] = true
+	}
+	return nil
+}
+
+// Score проверяет баллы (по одному на каждый критерий, в пределах его диапазона)
+// и возвращает разбивку в порядке критериев вместе с суммой.
+func (r *Rubric) Score(scores []CriterionScore) ([]CriterionScore, int, error) {
+	if r.Empty() {
+		if len(scores) > 0 {
+			return nil, 0, fmt.Errorf("%w: this work has no rubric", ErrInvalidRubricScores)
+		}
+		return []CriterionScore{}, 0, nil
+	}
+	given := make(map[string]CriterionScore, len(scores))
+	for _, s := range scores {
+		if _, dup := given[s.CriterionID]; dup {
+			return nil, 0, fmt.Errorf("%w: criterion %s scored twice", ErrInvalidRubricScores, s.CriterionID)
+		}
+		given[s.CriterionID] = s
+	}
+	if len(given) != len(r.Criteria) {
+		return nil, 0, fmt.Errorf("%w: every criterion must be scored exactly once", ErrInvalidRubricScores)
+	}
+
+	breakdown := make([]CriterionScore, 0, len(r.Criteria))
+	total := 0
+	for _, c := range r.Criteria {
+		s, ok := given[c.ID]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: criterion %q is not scored", ErrInvalidRubricScores, c.Title)
+		}
+		if s.Score < 0 || s.Score > c.MaxPoints {
+			return nil, 0, fmt.Errorf("%w: %q must be between 0 and %d", ErrInvalidRubricScores, c.Title, c.MaxPoints)
+		}
+		breakdown = append(breakdown, CriterionScore{CriterionID: c.ID, Title: c.Title, MaxPoints: c.MaxPoints, Score: s.Score, Comment: s.Comment})
+		total += s.Score
+	}
+	return breakdown, total, nil
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "errors"
+
+// ErrOutOfScope — пользователь не связан с учеником, уроком или группой, над которыми пытается работать.
+var ErrOutOfScope = errors.New("resource is out of scope")
+
+// Actor — кто выполняет действие. Передаётся в usecase, чтобы проверять доступ к конкретным ресурсам.
+type Actor struct {
+	UserID string
+	Role   Role
+}
+
+// HasGlobalScope — админ и модератор видят всех учеников без ограничений по группам.
+func (a Actor) HasGlobalScope() bool {
+	return a.Role == RoleAdmin || a.Role == RoleModerator
+}
+
+// StudentScope — множество учеников, доступных пользователю. All означает «все».
+type StudentScope struct {
+	All bool
+	IDs map[string]bool
+}
+
+func (s *StudentScope) Contains(studentID string) bool {
+	return s != nil && (s.All || s.IDs[studentID])
+}
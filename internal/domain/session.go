@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+type Session struct {
+	ID          string     `json:"id" db:"id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	DeviceID    string     `json:"device_id" db:"device_id"`
+	UserAgent   string     `json:"user_agent" db:"user_agent"`
+	IPAddress   string     `json:"ip_address" db:"ip_address"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt  time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	MFAVerified bool       `json:"mfa_verified" db:"mfa_verified"`
+	IsCurrent   bool       `json:"is_current" db:"-"`
+}
+
+// RefreshToken — звено цепочки ротации. Семейство токенов = сессия:
+// повторное использование уже обменянного токена отзывает сессию целиком.
+type RefreshToken struct {
+	ID        string     `json:"id" db:"id"`
+	SessionID string     `json:"session_id" db:"session_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+}
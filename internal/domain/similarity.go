@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SimilarityThreshold — оценочная схожесть, начиная с которой пара работ отмечается в очереди проверки.
+const SimilarityThreshold = 0.5
+
+const (
+	shingleSize   = 5
+	minHashLength = 128
+)
+
+// MinHashSignature — минимальные хеши шинглов (по shingleSize слов) для minHashLength хеш-функций.
+// Доля совпадающих позиций двух подписей оценивает коэффициент Жаккара их множеств шинглов.
+type MinHashSignature []int64
+
+// SubmissionFingerprint — текст версии ДЗ (ответ и текстовые вложения) и его подпись.
+type SubmissionFingerprint struct {
+	VersionID string
+	UserID    string
+	Content   string
+	Signature MinHashSignature
+}
+
+// SimilarPassage — совпадающий фрагмент двух работ. Границы — в символах от начала текста каждой работы.
+type SimilarPassage struct {
+	Start        int    `json:"start"`
+	End          int    `json:"end"`
+	Text         string `json:"text"`
+	MatchedStart int    `json:"matched_start"`
+	MatchedEnd   int    `json:"matched_end"`
+	MatchedText  string `json:"matched_text"`
+}
+
+// SimilarityMatch — работа другого ученика, похожая на проверяемую. У работы ученика вне области
+// доступа проверяющего (Anonymized) остаются только схожесть и обезличенное имя.
+type SimilarityMatch struct {
+	VersionID   string           `json:"version_id,omitempty"`
+	StudentID   string           `json:"student_id,omitempty"`
+	StudentName string           `json:"student_name"`
+	Version     int              `json:"version,omitempty"`
+	SubmittedAt time.Time        `json:"submitted_at"`
+	Score       float64          `json:"score"`
+	Content     string           `json:"content,omitempty"`
+	Passages    []SimilarPassage `json:"passages"`
+	Anonymized  bool             `json:"anonymized,omitempty"`
+}
+
+// Anonymize убирает из совпадения всё, кроме схожести, и подписывает его label.
+func (m *SimilarityMatch) Anonymize(label string) {
+	*m = SimilarityMatch{StudentName: label, Score: m.Score, Passages: []SimilarPassage{}, Anonymized: true}
+}
+
+// SimilarityReport — похожие работы для последней версии ДЗ ученика, от самых похожих.
+type SimilarityReport struct {
+	AssignmentID string             `json:"assignment_id"`
+	UserID       string             `json:"user_id"`
+	VersionID    string             `json:"version_id"`
+	Version      int                `json:"version"`
+	Content      string             `json:"content"`
+	Matches      []*SimilarityMatch `json:"matches"`
+}
+
+type textToken struct {
+	word       string
+	start, end int
+}
+
+// tokenize разбивает текст на слова в нижнем регистре; пунктуация и пробелы не учитываются,
+// поэтому переформатирование и замена знаков препинания не скрывают совпадение.
+func tokenize(runes []rune) []textToken {
+	var tokens []textToken
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, textToken{word: strings.ToLower(string(runes[start:i])), start: start, end: i})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// shingleLength — длина шингла; текст короче shingleSize слов целиком считается одним шинглом.
+func shingleLength(tokens ...[]textToken) int {
+	k := shingleSize
+	for _, t := range tokens {
+		k = min(k, len(t))
+	}
+	return k
+}
+
+func shingleKey(tokens []textToken) string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word
+	}
+	return strings.Join(words, " ")
+}
+
+// mix64 — финализатор splitmix64, из одного хеша шингла получаются независимые хеш-функции.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Fingerprint строит MinHash-подпись текста; у текста без слов подписи нет.
+func Fingerprint(text string) MinHashSignature {
+	tokens := tokenize([]rune(text))
+	k := shingleLength(tokens)
+	if k == 0 {
+		return nil
+	}
+	mins := make([]uint64, minHashLength)
+	for i := range mins {
+		mins[i] = math.MaxUint64
+	}
+	for i := 0; i+k <= len(tokens); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(shingleKey(tokens[i : i+k])))
+		sum := h.Sum64()
+		for j := range mins {
+			if v := mix64(sum ^ uint64(j+1)*0x9e3779b97f4a7c15); v < mins[j] {
+				mins[j] = v
+			}
+		}
+	}
+	sig := make(MinHashSignature, minHashLength)
+	for i, v := range mins {
+		sig[i] = int64(v)
+	}
+	return sig
+}
+
+// Similarity — оценка схожести двух подписей от 0 до 1.
+func Similarity(a, b MinHashSignature) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// MatchingPassages находит фрагменты text, дословно (с точностью до пунктуации и регистра)
+// встречающиеся в other, — для сравнения работ бок о бок. Фрагменты не пересекаются и идут по порядку text.
+func MatchingPassages(text, other string) []SimilarPassage {
+	a, b := []rune(text), []rune(other)
+	ta, tb := tokenize(a), tokenize(b)
+	passages := []SimilarPassage{}
+	k := shingleLength(ta, tb)
+	if k == 0 {
+		return passages
+	}
+	index := make(map[string][]int)
+	for j := 0; j+k <= len(tb); j++ {
+		key := shingleKey(tb[j : j+k])
+		index[key] = append(index[key], j)
+	}
+	for i := 0; i+k <= len(ta); {
+		starts, ok := index[shingleKey(ta[i:i+k])]
+		if !ok {
+			i++
+			continue
+		}
+		// Из всех вхождений берётся самое длинное совпадение.
+		bestJ, bestLen := 0, 0
+		for _, j := range starts {
+			n := k
+			for i+n < len(ta) && j+n < len(tb) && ta[i+n].word == tb[j+n].word {
+				n++
+			}
+			if n > bestLen {
+				bestJ, bestLen = j, n
+			}
+		}
+		p := SimilarPassage{
+			Start:        ta[i].start,
+			End:          ta[i+bestLen-1].end,
+			MatchedStart: tb[bestJ].start,
+			MatchedEnd:   tb[bestJ+bestLen-1].end,
+		}
+		p.Text = string(a[p.Start:p.End])
+		p.MatchedText = string(b[p.MatchedStart:p.MatchedEnd])
+		passages = append(passages, p)
+		i += bestLen
+	}
+	return passages
+}
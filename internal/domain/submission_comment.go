@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// SubmissionComment — сообщение в обсуждении сдачи ДЗ. Тред определяется парой
+// (AssignmentID, StudentID), как и сама работа.
+type SubmissionComment struct {
+	ID           string         `json:"id"`
+	AssignmentID string         `json:"assignment_id"`
+	StudentID    string         `json:"student_id"`
+	AuthorID     string         `json:"author_id"`
+	AuthorName   string         `json:"author_name"`
+	AuthorRole   Role           `json:"author_role"`
+	Version      int            `json:"version,omitempty"`
+	Body         string         `json:"body"`
+	Attachments  []string       `json:"attachments"`
+	Anchor       *CommentAnchor `json:"anchor,omitempty"`
+	ReadBy       []CommentRead  `json:"read_by"`
+	// IsRead — прочитано текущим пользователем; свои сообщения всегда прочитаны.
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentAnchor привязывает замечание к файлу версии и/или диапазону строк.
+// Без File строки относятся к тексту ответа.
+type CommentAnchor struct {
+	File      string `json:"file,omitempty"`
+	LineStart int    `json:"line_start,omitempty"`
+	LineEnd   int    `json:"line_end,omitempty"`
+}
+
+type CommentRead struct {
+	UserID string    `json:"user_id"`
+	ReadAt time.Time `json:"read_at"`
+}
+
+type SubmissionThread struct {
+	AssignmentID string               `json:"assignment_id"`
+	StudentID    string               `json:"student_id"`
+	Unread       int                  `json:"unread"`
+	Comments     []*SubmissionComment `json:"comments"`
+}
@@ -48,6 +48,7 @@ type User struct {
 	CoursesCompleted       int           `json:"courses_completed,omitempty" db:"-"`
 	GroupsCount            int           `json:"groups_count,omitempty" db:"-"`
 	Parents                []ParentInfo  `json:"parents,omitempty" db:"-"`
+	DisabledAt             *time.Time    `json:"disabled_at,omitempty" db:"disabled_at"`
 }
 
 type UserFilter struct {
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrInvalidVideoPing = errors.New("invalid video progress")
+
+// VideoCompletionPercent — доля просмотренного видео, после которой урок считается пройденным.
+const VideoCompletionPercent = 80
+
+// Отрезки сообщения не могут быть длиннее времени, прошедшего с прошлого сообщения, плюс
+// VideoPingSlack на задержки сети. Для первого сообщения отсчитывать не от чего, поэтому
+// в нём допускается не больше VideoFirstPingMax просмотра.
+const (
+	VideoPingSlack    = 5 * time.Second
+	VideoFirstPingMax = time.Minute
+)
+
+// WatchedRanges — просмотренные отрезки видео [начало, конец) в секундах, объединённые
+// и упорядоченные; хранятся в таком виде, чтобы повторные просмотры не раздували запись.
+type WatchedRanges [][2]int
+
+// Add добавляет отрезки, обрезая их по длительности видео, и объединяет пересекающиеся и смежные.
+func (r WatchedRanges) Add(segments [][2]int, duration int) WatchedRanges {
+	all := make([][2]int, 0, len(r)+len(segments))
+	all = append(all, r...)
+	for _, s := range segments {
+		start, end := max(s[0], 0), min(s[1], duration)
+		if start < end {
+			all = append(all, [2]int{start, end})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i][0] < all[j][0] })
+
+	merged := WatchedRanges{}
+	for _, s := range all {
+		if n := len(merged); n > 0 && s[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], s[1])
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// Total — сколько секунд видео просмотрено хотя бы раз.
+func (r WatchedRanges) Total() int {
+	total := 0
+	for _, s := range r {
+		total += s[1] - s[0]
+	}
+	return total
+}
+
+// VideoPing — периодическое сообщение плеера: текущая позиция, длительность видео
+// и отрезки, просмотренные с прошлого сообщения.
+type VideoPing struct {
+	PositionSec int `json:"position_sec"`
+	// DurationSec плеера не учитывается: сервер подставляет длительность урока.
+	DurationSec int      `json:"duration_sec"`
+	Segments    [][2]int `json:"segments"`
+}
+
+// watchedSec — суммарная длина отрезков сообщения.
+func (p VideoPing) watchedSec() int {
+	total := 0
+	for _, s := range p.Segments {
+		total += s[1] - s[0]
+	}
+	return total
+}
+
+func (p VideoPing) Validate() error {
+	if p.DurationSec <= 0 || p.PositionSec < 0 {
+		return fmt.Errorf("%w: duration_sec must be > 0 and position_sec >= 0", ErrInvalidVideoPing)
+	}
+	for _, s := range p.Segments {
+		if s[0] < 0 || s[1] <= s[0] {
+			return fmt.Errorf("%w: segments must be [start, end] with 0 <= start < end", ErrInvalidVideoPing)
+		}
+	}
+	return nil
+}
+
+// VideoProgress — просмотр видео урока учеником. PositionSec — позиция для продолжения просмотра.
+type VideoProgress struct {
+	LessonID       string        `json:"lesson_id"`
+	PositionSec    int           `json:"position_sec"`
+	DurationSec    int           `json:"duration_sec"`
+	WatchedSec     int           `json:"watched_sec"`
+	WatchedPercent int           `json:"watched_percent"`
+	Ranges         WatchedRanges `json:"-"`
+	CompletedAt    *time.Time    `json:"completed_at,omitempty"`
+	// UpdatedAt — момент прошлого сообщения плеера; нулевой, пока сообщений не было.
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Record учитывает сообщение плеера; при достижении VideoCompletionPercent фиксирует момент прохождения.
+// Досмотренное до конца видео начинается заново с начала. Сообщение с отрезками длиннее
+// прошедшего с прошлого сообщения времени отклоняется с ErrInvalidVideoPing.
+func (v *VideoProgress) Record(p VideoPing, now time.Time) error {
+	allowed := VideoFirstPingMax
+	if !v.UpdatedAt.IsZero() {
+		allowed = now.Sub(v.UpdatedAt)
+	}
+	if time.Duration(p.watchedSec())*time.Second > allowed+VideoPingSlack {
+		return fmt.Errorf("%w: segments are longer than the time since the previous ping", ErrInvalidVideoPing)
+	}
+	v.DurationSec = p.DurationSec
+	v.UpdatedAt = now
+	v.Ranges = v.Ranges.Add(p.Segments, p.DurationSec)
+	v.WatchedSec = v.Ranges.Total()
+	v.WatchedPercent = clampPercent(v.WatchedSec * 100 / p.DurationSec)
+	v.PositionSec = min(p.PositionSec, p.DurationSec)
+	if v.PositionSec >= p.DurationSec {
+		v.PositionSec = 0
+	}
+	if v.CompletedAt == nil && v.WatchedPercent >= VideoCompletionPercent {
+		v.CompletedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
+	"lms_backend/internal/domain"
+	"lms_backend/internal/extension/usecase"
+	"lms_backend/internal/httperror"
+)
+
+type ExtensionHandler struct {
+	uc usecase.ExtensionUseCase
+}
+
+func NewExtensionHandler(uc usecase.ExtensionUseCase) *ExtensionHandler {
+	return &ExtensionHandler{uc: uc}
+}
+
+type CreateExtensionRequestReq struct {
+	DueAt  time.Time `json:"due_at"`
+	Reason string    `json:"reason"`
+}
+
+type ReviewExtensionRequestReq struct {
+	ReviewComment *string `json:"review_comment,omitempty"`
+}
+
+func respondError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidExtension):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrRequestNotPending):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, sql.ErrNoRows):
+		httperror.NotFound(w, err)
+	default:
+		httperror.Respond(w, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// CreateExtensionRequest godoc
+// @Summary УЧЕНИК: Попросить продлить срок ДЗ урока
+// @Description due_at (RFC 3339) должен быть позже текущего срока; по заданию может ждать решения один запрос.
+// @Tags Extensions
+// @Param id path string true "ID урока"
+// @Param body body CreateExtensionRequestReq true "Новый срок и причина"
+// @Success 200 {object} domain.ExtensionRequest
+// @Router /lessons/{id}/assignment/extension-requests [post]
+func (h *ExtensionHandler) CreateExtensionRequest(w http.ResponseWriter, r *http.Request) {
+	var req CreateExtensionRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	created, err := h.uc.CreateRequest(r.Context(), actor, chi.URLParam(r, "id"), req.DueAt, req.Reason)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	writeJSON(w, created)
+}
+
+// GetMyExtensionRequests godoc
+// @Summary УЧЕНИК: Мои запросы на продление срока ДЗ урока
+// @Tags Extensions
+// @Param id path string true "ID урока"
+// @Success 200 {array} domain.ExtensionRequest
+// @Router /lessons/{id}/assignment/extension-requests [get]
+func (h *ExtensionHandler) GetMyExtensionRequests(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.uc.GetMyRequests(r.Context(), actor, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	writeJSON(w, requests)
+}
+
+// GetPendingRequests godoc
+// @Summary Получить список ожидающих запросов на продление срока ДЗ
+// @Tags Extensions
+// @Success 200 {array} domain.ExtensionRequest
+// @Router /api/extension-requests [get]
+func (h *ExtensionHandler) GetPendingRequests(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.uc.GetPendingRequests(r.Context(), actor)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	writeJSON(w, requests)
+}
+
+// ApproveRequest godoc
+// @Summary Одобрить продление срока ДЗ
+// @Tags Extensions
+// @Param requestId path string true "Request ID"
+// @Param body body ReviewExtensionRequestReq true "Review data"
+// @Success 200 {object} map[string]string
+// @Router /api/extension-requests/{requestId}/approve [patch]
+func (h *ExtensionHandler) ApproveRequest(w http.ResponseWriter, r *http.Request) {
+	var req ReviewExtensionRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.uc.ApproveRequest(r.Context(), actor, chi.URLParam(r, "requestId"), req.ReviewComment); err != nil {
+		respondError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"message": "Extension request approved"})
+}
+
+// RejectRequest godoc
+// @Summary Отклонить продление срока ДЗ
+// @Tags Extensions
+// @Param requestId path string true "Request ID"
+// @Param body body ReviewExtensionRequestReq true "Review data"
+// @Success 200 {object} map[string]string
+// @Router /api/extension-requests/{requestId}/reject [patch]
+func (h *ExtensionHandler) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	var req ReviewExtensionRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.uc.RejectRequest(r.Context(), actor, chi.URLParam(r, "requestId"), req.ReviewComment); err != nil {
+		respondError(w, err)
+		return
+	}
+	writeJSON(w, map[string]string{"message": "Extension request rejected"})
+}
@@ -0,0 +1,125 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/extension/repository"
+)
+
+type ExtensionRepositoryMock struct {
+	mu sync.Mutex
+	// Deadlines — общий срок ДЗ по ID урока; одобренные продления применяются при чтении.
+	Deadlines map[string]*domain.StudentDeadline
+	Requests  map[string]*domain.ExtensionRequest
+	// LateSubmissions — момент сдачи опоздавших работ по ключу "assignmentID/studentID".
+	LateSubmissions map[string]time.Time
+	nextID          int
+}
+
+var _ repository.ExtensionRepository = (*ExtensionRepositoryMock)(nil)
+
+func NewExtensionRepositoryMock() *ExtensionRepositoryMock {
+	return &ExtensionRepositoryMock{
+		Deadlines:       make(map[string]*domain.StudentDeadline),
+		Requests:        make(map[string]*domain.ExtensionRequest),
+		LateSubmissions: make(map[string]time.Time),
+		nextID:          1,
+	}
+}
+
+func (r *ExtensionRepositoryMock) GetStudentDeadline(ctx context.Context, lessonID, studentID string) (*domain.StudentDeadline, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	base, ok := r.Deadlines[lessonID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	d := *base
+	for _, req := range r.Requests {
+		if req.AssignmentID == d.AssignmentID && req.StudentID == studentID && req.Status == domain.ExtensionStatusApproved &&
+			d.DueAt != nil && req.RequestedDueAt.After(*d.DueAt) {
+			until := req.RequestedDueAt
+			d.DueAt, d.ExtendedUntil = &until, &until
+		}
+	}
+	return &d, nil
+}
+
+func (r *ExtensionRepositoryMock) CreateRequest(ctx context.Context, req *domain.ExtensionRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req.ID = fmt.Sprintf("ext-req-%d", r.nextID)
+	r.nextID++
+	req.CreatedAt = time.Now()
+	req.UpdatedAt = req.CreatedAt
+	r.Requests[req.ID] = req
+	return nil
+}
+
+func (r *ExtensionRepositoryMock) GetRequestByID(ctx context.Context, id string) (*domain.ExtensionRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.Requests[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return req, nil
+}
+
+func (r *ExtensionRepositoryMock) GetRequestsByStudent(ctx context.Context, studentID string) ([]*domain.ExtensionRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*domain.ExtensionRequest
+	for _, req := range r.Requests {
+		if req.StudentID == studentID {
+			result = append(result, req)
+		}
+	}
+	return result, nil
+}
+
+func (r *ExtensionRepositoryMock) GetPendingRequests(ctx context.Context) ([]*domain.ExtensionRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*domain.ExtensionRequest
+	for _, req := range r.Requests {
+		if req.Status == domain.ExtensionStatusPending {
+			result = append(result, req)
+		}
+	}
+	return result, nil
+}
+
+func (r *ExtensionRepositoryMock) UpdateRequestStatus(ctx context.Context, id string, status domain.ExtensionStatus, reviewedBy, reviewComment *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.Requests[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	req.Status = status
+	req.ReviewedBy = reviewedBy
+	req.ReviewComment = reviewComment
+	req.ReviewedAt = &now
+	req.UpdatedAt = now
+	return nil
+}
+
+func (r *ExtensionRepositoryMock) ApproveRequest(ctx context.Context, req *domain.ExtensionRequest, reviewedBy, reviewComment *string) error {
+	if err := r.UpdateRequestStatus(ctx, req.ID, domain.ExtensionStatusApproved, reviewedBy, reviewComment); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := req.AssignmentID + "/" + req.StudentID
+	if submittedAt, ok := r.LateSubmissions[key]; ok && !submittedAt.After(req.RequestedDueAt) {
+		delete(r.LateSubmissions, key)
+	}
+	return nil
+}
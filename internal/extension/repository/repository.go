@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+type ExtensionRepository interface {
+	GetStudentDeadline(ctx context.Context, lessonID, studentID string) (*domain.StudentDeadline, error)
+	CreateRequest(ctx context.Context, req *domain.ExtensionRequest) error
+	GetRequestByID(ctx context.Context, id string) (*domain.ExtensionRequest, error)
+	GetRequestsByStudent(ctx context.Context, studentID string) ([]*domain.ExtensionRequest, error)
+	GetPendingRequests(ctx context.Context) ([]*domain.ExtensionRequest, error)
+	UpdateRequestStatus(ctx context.Context, id string, status domain.ExtensionStatus, reviewedBy, reviewComment *string) error
+	ApproveRequest(ctx context.Context, req *domain.ExtensionRequest, reviewedBy, reviewComment *string) error
+}
+
+type extensionRepository struct {
+	db *sql.DB
+}
+
+func NewExtensionRepository(db *sql.DB) ExtensionRepository {
+	return &extensionRepository{db: db}
+}
+
+// GetStudentDeadline — срок ДЗ урока для ученика с учётом его одобренных продлений.
+func (r *extensionRepository) GetStudentDeadline(ctx context.Context, lessonID, studentID string) (*domain.StudentDeadline, error) {
+	query := `
+		SELECT a.id, a.due_at, a.due_offset_hours, a.late_policy, a.late_penalty_percent, l.lesson_time,
+			(SELECT MAX(e.requested_due_at) FROM assignment_extension_requests e
+			 WHERE e.assignment_id = a.id AND e.student_id = $2 AND e.status = 'APPROVED')
+		FROM assignments a
+		JOIN lessons l ON a.lesson_id = l.id
+		WHERE a.lesson_id = $1
+		LIMIT 1
+	`
+	var id string
+	var d domain.AssignmentDeadline
+	var dueAt, lessonTime, extendedUntil sql.NullTime
+	var offset sql.NullInt32
+	err := r.db.QueryRowContext(ctx, query, lessonID, studentID).Scan(
+		&id, &dueAt, &offset, &d.LatePolicy, &d.LatePenaltyPercent, &lessonTime, &extendedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if dueAt.Valid {
+		d.DueAt = &dueAt.Time
+	}
+	if offset.Valid {
+		hours := int(offset.Int32)
+		d.DueOffsetHours = &hours
+	}
+	var lessonAt, extended *time.Time
+	if lessonTime.Valid {
+		lessonAt = &lessonTime.Time
+	}
+	if extendedUntil.Valid {
+		extended = &extendedUntil.Time
+	}
+	return domain.NewStudentDeadline(id, d, lessonAt, extended), nil
+}
+
+func (r *extensionRepository) CreateRequest(ctx context.Context, req *domain.ExtensionRequest) error {
+	query := `
+		INSERT INTO assignment_extension_requests (id, assignment_id, student_id, requested_due_at, reason, status)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		req.AssignmentID, req.StudentID, req.RequestedDueAt, req.Reason, req.Status,
+	).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+}
+
+const requestSelect = `
+	SELECT e.id, e.assignment_id, a.lesson_id, l.title, e.student_id, u.first_name || ' ' || u.last_name,
+	       e.requested_due_at, e.reason, e.status, e.reviewed_by, e.reviewed_at, e.review_comment,
+	       e.created_at, e.updated_at
+	FROM assignment_extension_requests e
+	JOIN assignments a ON e.assignment_id = a.id
+	JOIN lessons l ON a.lesson_id = l.id
+	JOIN users u ON e.student_id = u.id
+`
+
+func scanRequest(row interface{ Scan(...any) error }) (*domain.ExtensionRequest, error) {
+	var req domain.ExtensionRequest
+	err := row.Scan(
+		&req.ID, &req.AssignmentID, &req.LessonID, &req.LessonTitle, &req.StudentID, &req.StudentName,
+		&req.RequestedDueAt, &req.Reason, &req.Status, &req.ReviewedBy, &req.ReviewedAt, &req.ReviewComment,
+		&req.CreatedAt, &req.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *extensionRepository) GetRequestByID(ctx context.Context, id string) (*domain.ExtensionRequest, error) {
+	return scanRequest(r.db.QueryRowContext(ctx, requestSelect+" WHERE e.id = $1", id))
+}
+
+func (r *extensionRepository) GetRequestsByStudent(ctx context.Context, studentID string) ([]*domain.ExtensionRequest, error) {
+	return r.queryRequests(ctx, requestSelect+" WHERE e.student_id = $1 ORDER BY e.created_at DESC", studentID)
+}
+
+func (r *extensionRepository) GetPendingRequests(ctx context.Context) ([]*domain.ExtensionRequest, error) {
+	return r.queryRequests(ctx, requestSelect+" WHERE e.status = 'PENDING' ORDER BY e.created_at ASC")
+}
+
+func (r *extensionRepository) queryRequests(ctx context.Context, query string, args ...any) ([]*domain.ExtensionRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*domain.ExtensionRequest{}
+	for rows.Next() {
+		req, err := scanRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
+}
+
+const updateRequestStatusQuery = `
+	UPDATE assignment_extension_requests
+	SET status = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP, review_comment = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $4
+`
+
+func (r *extensionRepository) UpdateRequestStatus(ctx context.Context, id string, status domain.ExtensionStatus, reviewedBy, reviewComment *string) error {
+	_, err := r.db.ExecContext(ctx, updateRequestStatusQuery, status, reviewedBy, reviewComment, id)
+	return err
+}
+
+// ApproveRequest одобряет продление и в той же транзакции снимает отметку об опоздании с работы
+// и её последней версии, если они отправлены не позже нового срока.
+func (r *extensionRepository) ApproveRequest(ctx context.Context, req *domain.ExtensionRequest, reviewedBy, reviewComment *string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, updateRequestStatusQuery, domain.ExtensionStatusApproved, reviewedBy, reviewComment, req.ID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_assignments_submission SET is_late = FALSE
+		WHERE assignment_id = $1 AND user_id = $2 AND is_late AND submitted_at <= $3
+	`, req.AssignmentID, req.StudentID, req.RequestedDueAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE assignment_submission_versions SET is_late = FALSE
+		WHERE assignment_id = $1 AND user_id = $2 AND is_late AND submitted_at <= $3 AND version = (
+			SELECT MAX(version) FROM assignment_submission_versions WHERE assignment_id = $1 AND user_id = $2
+		)
+	`, req.AssignmentID, req.StudentID, req.RequestedDueAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
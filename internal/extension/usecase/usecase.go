@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/extension/repository"
+)
+
+var ErrRequestNotPending = errors.New("request is not pending")
+
+type ExtensionUseCase interface {
+	CreateRequest(ctx context.Context, actor domain.Actor, lessonID string, dueAt time.Time, reason string) (*domain.ExtensionRequest, error)
+	GetMyRequests(ctx context.Context, actor domain.Actor, lessonID string) ([]*domain.ExtensionRequest, error)
+	GetPendingRequests(ctx context.Context, actor domain.Actor) ([]*domain.ExtensionRequest, error)
+	ApproveRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error
+	RejectRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error
+}
+
+// ScopeChecker — проверки доступа к ученику (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	VisibleStudents(ctx context.Context, actor domain.Actor) (*domain.StudentScope, error)
+}
+
+type extensionUseCase struct {
+	repo  repository.ExtensionRepository
+	scope ScopeChecker
+}
+
+func NewExtensionUseCase(repo repository.ExtensionRepository, scope ScopeChecker) ExtensionUseCase {
+	return &extensionUseCase{repo: repo, scope: scope}
+}
+
+// CreateRequest — ученик просит продлить срок ДЗ урока до dueAt. Продлить можно только ДЗ со сроком,
+// новый срок должен быть позже текущего, а по одному заданию может ждать решения лишь один запрос.
+func (uc *extensionUseCase) CreateRequest(ctx context.Context, actor domain.Actor, lessonID string, dueAt time.Time, reason string) (*domain.ExtensionRequest, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: reason is required", domain.ErrInvalidExtension)
+	}
+	deadline, err := uc.repo.GetStudentDeadline(ctx, lessonID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if deadline.DueAt == nil {
+		return nil, fmt.Errorf("%w: the assignment has no deadline", domain.ErrInvalidExtension)
+	}
+	if !dueAt.After(*deadline.DueAt) || !dueAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: due_at must be later than the current deadline and in the future", domain.ErrInvalidExtension)
+	}
+	requests, err := uc.repo.GetRequestsByStudent(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		if req.AssignmentID == deadline.AssignmentID && req.Status == domain.ExtensionStatusPending {
+			return nil, fmt.Errorf("%w: a request for this assignment is already pending", domain.ErrInvalidExtension)
+		}
+	}
+
+	req := &domain.ExtensionRequest{
+		AssignmentID:   deadline.AssignmentID,
+		LessonID:       lessonID,
+		StudentID:      actor.UserID,
+		RequestedDueAt: dueAt,
+		Reason:         reason,
+		Status:         domain.ExtensionStatusPending,
+	}
+	if err := uc.repo.CreateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// GetMyRequests — запросы ученика по ДЗ урока, от новых к старым.
+func (uc *extensionUseCase) GetMyRequests(ctx context.Context, actor domain.Actor, lessonID string) ([]*domain.ExtensionRequest, error) {
+	requests, err := uc.repo.GetRequestsByStudent(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	mine := make([]*domain.ExtensionRequest, 0, len(requests))
+	for _, req := range requests {
+		if req.LessonID == lessonID {
+			mine = append(mine, req)
+		}
+	}
+	return mine, nil
+}
+
+// GetPendingRequests — сотрудник видит запросы только доступных ему учеников.
+func (uc *extensionUseCase) GetPendingRequests(ctx context.Context, actor domain.Actor) ([]*domain.ExtensionRequest, error) {
+	requests, err := uc.repo.GetPendingRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if scope.All {
+		return requests, nil
+	}
+	visible := make([]*domain.ExtensionRequest, 0, len(requests))
+	for _, req := range requests {
+		if scope.Contains(req.StudentID) {
+			visible = append(visible, req)
+		}
+	}
+	return visible, nil
+}
+
+// getPendingInScope загружает запрос, ожидающий решения, и проверяет, что его ученик доступен пользователю.
+func (uc *extensionUseCase) getPendingInScope(ctx context.Context, actor domain.Actor, requestID string) (*domain.ExtensionRequest, error) {
+	req, err := uc.repo.GetRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, req.StudentID); err != nil {
+		return nil, err
+	}
+	if req.Status != domain.ExtensionStatusPending {
+		return nil, ErrRequestNotPending
+	}
+	return req, nil
+}
+
+// ApproveRequest одобряет продление. Если работа уже сдана после старого срока, но до нового,
+// отметка об опоздании снимается в той же транзакции.
+func (uc *extensionUseCase) ApproveRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error {
+	reviewedBy := actor.UserID
+	req, err := uc.getPendingInScope(ctx, actor, requestID)
+	if err != nil {
+		return err
+	}
+	return uc.repo.ApproveRequest(ctx, req, &reviewedBy, reviewComment)
+}
+
+func (uc *extensionUseCase) RejectRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error {
+	reviewedBy := actor.UserID
+	if _, err := uc.getPendingInScope(ctx, actor, requestID); err != nil {
+		return err
+	}
+	return uc.repo.UpdateRequestStatus(ctx, requestID, domain.ExtensionStatusRejected, &reviewedBy, reviewComment)
+}
@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/extension/mocks"
+	"lms_backend/internal/extension/usecase"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
+)
+
+var (
+	studentA = domain.Actor{UserID: "student-a", Role: domain.RoleStudent}
+	teacherA = domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+	teacherB = domain.Actor{UserID: "teacher-b", Role: domain.RoleTeacher}
+)
+
+// newUseCase — у ДЗ урока lesson-a срок истёк час назад, teacher-a ведёт student-a.
+func newUseCase() (usecase.ExtensionUseCase, *mocks.ExtensionRepositoryMock, time.Time) {
+	repo := mocks.NewExtensionRepositoryMock()
+	due := time.Now().Add(-time.Hour)
+	repo.Deadlines["lesson-a"] = &domain.StudentDeadline{AssignmentID: "asg-a", DueAt: &due, LatePolicy: domain.LatePolicyReject}
+	return usecase.NewExtensionUseCase(repo, scopeUseCase.NewScopeUseCase(scopeMocks.TwoGroups())), repo, due
+}
+
+func TestExtensionUseCase_CreateRequest(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+		req, err := uc.CreateRequest(ctx, studentA, "lesson-a", tomorrow, "was ill")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if req.AssignmentID != "asg-a" || req.StudentID != "student-a" || req.Status != domain.ExtensionStatusPending {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		mine, _ := uc.GetMyRequests(ctx, studentA, "lesson-a")
+		if len(mine) != 1 {
+			t.Errorf("expected 1 own request, got %d", len(mine))
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		uc, _, due := newUseCase()
+		cases := []struct {
+			dueAt  time.Time
+			reason string
+		}{
+			{tomorrow, " "},
+			{due.Add(-time.Hour), "earlier than the deadline"},
+			{time.Now().Add(-time.Minute), "already in the past"},
+		}
+		for i, c := range cases {
+			if _, err := uc.CreateRequest(ctx, studentA, "lesson-a", c.dueAt, c.reason); !errors.Is(err, domain.ErrInvalidExtension) {
+				t.Errorf("case %d: expected ErrInvalidExtension, got %v", i, err)
+			}
+		}
+	})
+
+	t.Run("NoDeadline", func(t *testing.T) {
+		uc, repo, _ := newUseCase()
+		repo.Deadlines["lesson-b"] = &domain.StudentDeadline{AssignmentID: "asg-b", LatePolicy: domain.LatePolicyAccept}
+		if _, err := uc.CreateRequest(ctx, studentA, "lesson-b", tomorrow, "please"); !errors.Is(err, domain.ErrInvalidExtension) {
+			t.Fatalf("expected ErrInvalidExtension without a deadline, got %v", err)
+		}
+	})
+
+	t.Run("OnePendingPerAssignment", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+		if _, err := uc.CreateRequest(ctx, studentA, "lesson-a", tomorrow, "was ill"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.CreateRequest(ctx, studentA, "lesson-a", tomorrow.Add(time.Hour), "still ill"); !errors.Is(err, domain.ErrInvalidExtension) {
+			t.Fatalf("expected ErrInvalidExtension for a second pending request, got %v", err)
+		}
+	})
+}
+
+func TestExtensionUseCase_Review(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	t.Run("ApproveExtendsDeadline", func(t *testing.T) {
+		uc, repo, due := newUseCase()
+		repo.LateSubmissions["asg-a/student-a"] = due.Add(30 * time.Minute)
+		req, err := uc.CreateRequest(ctx, studentA, "lesson-a", tomorrow, "was ill")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pending, _ := uc.GetPendingRequests(ctx, teacherA)
+		if len(pending) != 1 {
+			t.Fatalf("expected 1 pending request, got %d", len(pending))
+		}
+		comment := "ok"
+		if err := uc.ApproveRequest(ctx, teacherA, req.ID, &comment); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		deadline, _ := repo.GetStudentDeadline(ctx, "lesson-a", "student-a")
+		if deadline.IsLate(time.Now()) || deadline.ExtendedUntil == nil {
+			t.Errorf("deadline was not extended: %+v", deadline)
+		}
+		if _, late := repo.LateSubmissions["asg-a/student-a"]; late {
+			t.Error("work sent before the new deadline must not stay late")
+		}
+		if err := uc.RejectRequest(ctx, teacherA, req.ID, nil); !errors.Is(err, usecase.ErrRequestNotPending) {
+			t.Errorf("expected ErrRequestNotPending, got %v", err)
+		}
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		uc, repo, _ := newUseCase()
+		req, _ := uc.CreateRequest(ctx, studentA, "lesson-a", tomorrow, "was ill")
+		if err := uc.RejectRequest(ctx, teacherA, req.ID, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.Requests[req.ID].Status != domain.ExtensionStatusRejected {
+			t.Errorf("expected REJECTED, got %s", repo.Requests[req.ID].Status)
+		}
+	})
+
+	t.Run("OutOfScope", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+		req, _ := uc.CreateRequest(ctx, studentA, "lesson-a", tomorrow, "was ill")
+		pending, _ := uc.GetPendingRequests(ctx, teacherB)
+		if len(pending) != 0 {
+			t.Errorf("teacher-b must not see requests of student-a, got %d", len(pending))
+		}
+		if err := uc.ApproveRequest(ctx, teacherB, req.ID, nil); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
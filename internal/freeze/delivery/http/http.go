@@ -55,16 +55,15 @@ func (h *FreezeHandler) CreateFreezeRequest(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
-	if !ok || userCtxData == nil {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userID := userCtxData.UserID
 
-	err = h.uc.CreateRequest(r.Context(), req.StudentID, userID, startDate, endDate, req.Reason)
+	err = h.uc.CreateRequest(r.Context(), actor, req.StudentID, startDate, endDate, req.Reason)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -78,9 +77,15 @@ func (h *FreezeHandler) CreateFreezeRequest(w http.ResponseWriter, r *http.Reque
 // @Success 200 {array} domain.FreezeRequest
 // @Router /api/freeze-requests [get]
 func (h *FreezeHandler) GetPendingRequests(w http.ResponseWriter, r *http.Request) {
-	requests, err := h.uc.GetPendingRequests(r.Context())
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.uc.GetPendingRequests(r.Context(), actor)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -104,16 +109,15 @@ func (h *FreezeHandler) ApproveRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
-	if !ok || userCtxData == nil {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userID := userCtxData.UserID
 
-	err := h.uc.ApproveRequest(r.Context(), requestID, userID, req.ReviewComment)
+	err := h.uc.ApproveRequest(r.Context(), actor, requestID, req.ReviewComment)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -137,16 +141,15 @@ func (h *FreezeHandler) RejectRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
-	if !ok || userCtxData == nil {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userID := userCtxData.UserID
 
-	err := h.uc.RejectRequest(r.Context(), requestID, userID, req.ReviewComment)
+	err := h.uc.RejectRequest(r.Context(), actor, requestID, req.ReviewComment)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -161,11 +164,16 @@ func (h *FreezeHandler) RejectRequest(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} domain.FreezePeriod
 // @Router /api/students/{studentId}/freeze-status [get]
 func (h *FreezeHandler) GetStudentFreezeStatus(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := chi.URLParam(r, "studentId")
 
-	status, err := h.uc.GetStudentFreezeStatus(r.Context(), studentID)
+	status, err := h.uc.GetStudentFreezeStatus(r.Context(), actor, studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
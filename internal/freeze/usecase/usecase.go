@@ -9,23 +9,34 @@ import (
 )
 
 type FreezeUseCase interface {
-	CreateRequest(ctx context.Context, studentID, requestedBy string, startDate, endDate time.Time, reason string) error
-	GetPendingRequests(ctx context.Context) ([]*domain.FreezeRequest, error)
-	ApproveRequest(ctx context.Context, requestID, reviewedBy string, reviewComment *string) error
-	RejectRequest(ctx context.Context, requestID, reviewedBy string, reviewComment *string) error
-	GetStudentFreezeStatus(ctx context.Context, studentID string) (*domain.FreezePeriod, error)
-	GetStudentRequests(ctx context.Context, studentID string) ([]*domain.FreezeRequest, error)
+	CreateRequest(ctx context.Context, actor domain.Actor, studentID string, startDate, endDate time.Time, reason string) error
+	GetPendingRequests(ctx context.Context, actor domain.Actor) ([]*domain.FreezeRequest, error)
+	ApproveRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error
+	RejectRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error
+	GetStudentFreezeStatus(ctx context.Context, actor domain.Actor, studentID string) (*domain.FreezePeriod, error)
+	GetStudentRequests(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.FreezeRequest, error)
+}
+
+// ScopeChecker — проверки доступа к ученику (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	VisibleStudents(ctx context.Context, actor domain.Actor) (*domain.StudentScope, error)
 }
 
 type freezeUseCase struct {
-	repo repository.FreezeRepository
+	repo  repository.FreezeRepository
+	scope ScopeChecker
 }
 
-func NewFreezeUseCase(repo repository.FreezeRepository) FreezeUseCase {
-	return &freezeUseCase{repo: repo}
+func NewFreezeUseCase(repo repository.FreezeRepository, scope ScopeChecker) FreezeUseCase {
+	return &freezeUseCase{repo: repo, scope: scope}
 }
 
-func (uc *freezeUseCase) CreateRequest(ctx context.Context, studentID, requestedBy string, startDate, endDate time.Time, reason string) error {
+func (uc *freezeUseCase) CreateRequest(ctx context.Context, actor domain.Actor, studentID string, startDate, endDate time.Time, reason string) error {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return err
+	}
+	requestedBy := actor.UserID
 	if endDate.Before(startDate) {
 		return errors.New("end_date must be after start_date")
 	}
@@ -41,13 +52,44 @@ func (uc *freezeUseCase) CreateRequest(ctx context.Context, studentID, requested
 	return uc.repo.CreateRequest(ctx, req)
 }
 
-func (uc *freezeUseCase) GetPendingRequests(ctx context.Context) ([]*domain.FreezeRequest, error) {
-	return uc.repo.GetPendingRequests(ctx)
+// GetPendingRequests — куратор видит заявки только своих учеников.
+func (uc *freezeUseCase) GetPendingRequests(ctx context.Context, actor domain.Actor) ([]*domain.FreezeRequest, error) {
+	requests, err := uc.repo.GetPendingRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if scope.All {
+		return requests, nil
+	}
+	visible := make([]*domain.FreezeRequest, 0, len(requests))
+	for _, req := range requests {
+		if scope.Contains(req.StudentID) {
+			visible = append(visible, req)
+		}
+	}
+	return visible, nil
+}
+
+// getRequestInScope загружает заявку и проверяет, что её ученик доступен пользователю.
+func (uc *freezeUseCase) getRequestInScope(ctx context.Context, actor domain.Actor, requestID string) (*domain.FreezeRequest, error) {
+	req, err := uc.repo.GetRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, req.StudentID); err != nil {
+		return nil, err
+	}
+	return req, nil
 }
 
-func (uc *freezeUseCase) ApproveRequest(ctx context.Context, requestID, reviewedBy string, reviewComment *string) error {
+func (uc *freezeUseCase) ApproveRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error {
+	reviewedBy := actor.UserID
 	// Получаем запрос
-	req, err := uc.repo.GetRequestByID(ctx, requestID)
+	req, err := uc.getRequestInScope(ctx, actor, requestID)
 	if err != nil {
 		return err
 	}
@@ -74,8 +116,9 @@ func (uc *freezeUseCase) ApproveRequest(ctx context.Context, requestID, reviewed
 	return uc.repo.CreatePeriod(ctx, period)
 }
 
-func (uc *freezeUseCase) RejectRequest(ctx context.Context, requestID, reviewedBy string, reviewComment *string) error {
-	req, err := uc.repo.GetRequestByID(ctx, requestID)
+func (uc *freezeUseCase) RejectRequest(ctx context.Context, actor domain.Actor, requestID string, reviewComment *string) error {
+	reviewedBy := actor.UserID
+	req, err := uc.getRequestInScope(ctx, actor, requestID)
 	if err != nil {
 		return err
 	}
@@ -87,10 +130,16 @@ func (uc *freezeUseCase) RejectRequest(ctx context.Context, requestID, reviewedB
 	return uc.repo.UpdateRequestStatus(ctx, requestID, domain.FreezeStatusRejected, &reviewedBy, reviewComment)
 }
 
-func (uc *freezeUseCase) GetStudentFreezeStatus(ctx context.Context, studentID string) (*domain.FreezePeriod, error) {
+func (uc *freezeUseCase) GetStudentFreezeStatus(ctx context.Context, actor domain.Actor, studentID string) (*domain.FreezePeriod, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetStudentFreezeStatus(ctx, studentID)
 }
 
-func (uc *freezeUseCase) GetStudentRequests(ctx context.Context, studentID string) ([]*domain.FreezeRequest, error) {
+func (uc *freezeUseCase) GetStudentRequests(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.FreezeRequest, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetRequestsByStudent(ctx, studentID)
 }
@@ -2,21 +2,37 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"lms_backend/internal/domain"
 	"lms_backend/internal/freeze/mocks"
 	"lms_backend/internal/freeze/usecase"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 )
 
+var (
+	curator = domain.Actor{UserID: "curator-1", Role: domain.RoleCurator}
+	admin   = domain.Actor{UserID: "admin-1", Role: domain.RoleAdmin}
+)
+
+// newUseCase — curator-1 курирует student-1 и student-2.
+func newUseCase(repo *mocks.FreezeRepositoryMock) usecase.FreezeUseCase {
+	scopeRepo := scopeMocks.TwoGroups()
+	scopeRepo.CuratorStudents["curator-1"] = []string{"student-1", "student-2"}
+	return usecase.NewFreezeUseCase(repo, scopeUseCase.NewScopeUseCase(scopeRepo))
+}
+
 func TestFreezeUseCase_CreateRequest(t *testing.T) {
 	repoMock := mocks.NewFreezeRepositoryMock()
-	uc := usecase.NewFreezeUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 	now := time.Now()
 
 	t.Run("Success", func(t *testing.T) {
-		err := uc.CreateRequest(ctx, "student-1", "curator-1",
+		err := uc.CreateRequest(ctx, curator, "student-1",
 			now, now.Add(7*24*time.Hour), "need break")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -24,7 +40,7 @@ func TestFreezeUseCase_CreateRequest(t *testing.T) {
 	})
 
 	t.Run("EndDateBeforeStartDate", func(t *testing.T) {
-		err := uc.CreateRequest(ctx, "student-2", "curator-1",
+		err := uc.CreateRequest(ctx, curator, "student-2",
 			now.Add(7*24*time.Hour), now, "invalid dates")
 		if err == nil {
 			t.Error("expected error for end_date before start_date, got nil")
@@ -34,29 +50,29 @@ func TestFreezeUseCase_CreateRequest(t *testing.T) {
 
 func TestFreezeUseCase_ApproveRequest(t *testing.T) {
 	repoMock := mocks.NewFreezeRepositoryMock()
-	uc := usecase.NewFreezeUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 	now := time.Now()
 
 	t.Run("Success", func(t *testing.T) {
-		err := uc.CreateRequest(ctx, "student-1", "curator-1",
+		err := uc.CreateRequest(ctx, curator, "student-1",
 			now, now.Add(7*24*time.Hour), "need break")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		reqs, _ := uc.GetPendingRequests(ctx)
+		reqs, _ := uc.GetPendingRequests(ctx, admin)
 		if len(reqs) != 1 {
 			t.Fatalf("expected 1 pending request, got %d", len(reqs))
 		}
 
 		comment := "approved"
-		err = uc.ApproveRequest(ctx, reqs[0].ID, "admin-1", &comment)
+		err = uc.ApproveRequest(ctx, admin, reqs[0].ID, &comment)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		status, err := uc.GetStudentFreezeStatus(ctx, "student-1")
+		status, err := uc.GetStudentFreezeStatus(ctx, curator, "student-1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -66,28 +82,28 @@ func TestFreezeUseCase_ApproveRequest(t *testing.T) {
 	})
 
 	t.Run("NotPending", func(t *testing.T) {
-		err := uc.CreateRequest(ctx, "student-2", "curator-1",
+		err := uc.CreateRequest(ctx, curator, "student-2",
 			now, now.Add(7*24*time.Hour), "need break")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		reqs, _ := uc.GetPendingRequests(ctx)
+		reqs, _ := uc.GetPendingRequests(ctx, admin)
 		comment := "approved"
 
-		err = uc.ApproveRequest(ctx, reqs[0].ID, "admin-1", &comment)
+		err = uc.ApproveRequest(ctx, admin, reqs[0].ID, &comment)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		err = uc.ApproveRequest(ctx, reqs[0].ID, "admin-1", &comment)
+		err = uc.ApproveRequest(ctx, admin, reqs[0].ID, &comment)
 		if err == nil {
 			t.Error("expected error for already approved request, got nil")
 		}
 	})
 
 	t.Run("NotFound", func(t *testing.T) {
-		err := uc.ApproveRequest(ctx, "nonexistent", "admin-1", nil)
+		err := uc.ApproveRequest(ctx, admin, "nonexistent", nil)
 		if err == nil {
 			t.Error("expected error for nonexistent request, got nil")
 		}
@@ -96,20 +112,20 @@ func TestFreezeUseCase_ApproveRequest(t *testing.T) {
 
 func TestFreezeUseCase_RejectRequest(t *testing.T) {
 	repoMock := mocks.NewFreezeRepositoryMock()
-	uc := usecase.NewFreezeUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 	now := time.Now()
 
 	t.Run("Success", func(t *testing.T) {
-		err := uc.CreateRequest(ctx, "student-1", "curator-1",
+		err := uc.CreateRequest(ctx, curator, "student-1",
 			now, now.Add(7*24*time.Hour), "need break")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		reqs, _ := uc.GetPendingRequests(ctx)
+		reqs, _ := uc.GetPendingRequests(ctx, admin)
 		comment := "not eligible"
-		err = uc.RejectRequest(ctx, reqs[0].ID, "admin-1", &comment)
+		err = uc.RejectRequest(ctx, admin, reqs[0].ID, &comment)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -118,14 +134,14 @@ func TestFreezeUseCase_RejectRequest(t *testing.T) {
 
 func TestFreezeUseCase_GetStudentRequests(t *testing.T) {
 	repoMock := mocks.NewFreezeRepositoryMock()
-	uc := usecase.NewFreezeUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 	now := time.Now()
 
-	uc.CreateRequest(ctx, "student-1", "curator-1", now, now.Add(7*24*time.Hour), "reason 1")
-	uc.CreateRequest(ctx, "student-1", "curator-1", now, now.Add(14*24*time.Hour), "reason 2")
+	uc.CreateRequest(ctx, curator, "student-1", now, now.Add(7*24*time.Hour), "reason 1")
+	uc.CreateRequest(ctx, curator, "student-1", now, now.Add(14*24*time.Hour), "reason 2")
 
-	reqs, err := uc.GetStudentRequests(ctx, "student-1")
+	reqs, err := uc.GetStudentRequests(ctx, curator, "student-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -133,3 +149,51 @@ func TestFreezeUseCase_GetStudentRequests(t *testing.T) {
 		t.Errorf("expected 2 requests, got %d", len(reqs))
 	}
 }
+
+func TestFreezeUseCase_CrossGroupAccess(t *testing.T) {
+	repoMock := mocks.NewFreezeRepositoryMock()
+	uc := newUseCase(repoMock)
+	ctx := context.Background()
+	now := time.Now()
+	curatorA := domain.Actor{UserID: "curator-a", Role: domain.RoleCurator}
+	curatorB := domain.Actor{UserID: "curator-b", Role: domain.RoleCurator}
+
+	if err := uc.CreateRequest(ctx, curatorA, "student-a", now, now.Add(24*time.Hour), "trip"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("CreateForForeignStudent", func(t *testing.T) {
+		err := uc.CreateRequest(ctx, curatorB, "student-a", now, now.Add(24*time.Hour), "trip")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("PendingListFiltered", func(t *testing.T) {
+		reqs, err := uc.GetPendingRequests(ctx, curatorB)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reqs) != 0 {
+			t.Fatalf("expected no visible requests, got %d", len(reqs))
+		}
+	})
+
+	t.Run("ApproveForeignRequest", func(t *testing.T) {
+		reqs, _ := uc.GetPendingRequests(ctx, curatorA)
+		if len(reqs) != 1 {
+			t.Fatalf("expected 1 pending request, got %d", len(reqs))
+		}
+		err := uc.ApproveRequest(ctx, curatorB, reqs[0].ID, nil)
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("ForeignStatus", func(t *testing.T) {
+		_, err := uc.GetStudentFreezeStatus(ctx, curatorB, "student-a")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
 )
 
 type GroupHandler struct {
@@ -38,6 +40,11 @@ type ChangeGroupRequest struct {
 // @Success 200 {object} map[string]string
 // @Router /api/groups/{groupId} [patch]
 func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	groupID := chi.URLParam(r, "groupId")
 
 	var req UpdateGroupRequest
@@ -46,9 +53,9 @@ func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.uc.UpdateGroup(r.Context(), groupID, req.Name, req.TeacherID)
+	err := h.uc.UpdateGroup(r.Context(), actor, groupID, req.Name, req.TeacherID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -64,6 +71,11 @@ func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]string
 // @Router /api/groups/{groupId}/students [post]
 func (h *GroupHandler) AddStudentToGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	groupID := chi.URLParam(r, "groupId")
 
 	var req AddStudentRequest
@@ -72,9 +84,9 @@ func (h *GroupHandler) AddStudentToGroup(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.uc.AddStudentToGroup(r.Context(), groupID, req.StudentID)
+	err := h.uc.AddStudentToGroup(r.Context(), actor, groupID, req.StudentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -90,12 +102,17 @@ func (h *GroupHandler) AddStudentToGroup(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} map[string]string
 // @Router /api/groups/{groupId}/students/{studentId} [delete]
 func (h *GroupHandler) RemoveStudentFromGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	groupID := chi.URLParam(r, "groupId")
 	studentID := chi.URLParam(r, "studentId")
 
-	err := h.uc.RemoveStudentFromGroup(r.Context(), groupID, studentID)
+	err := h.uc.RemoveStudentFromGroup(r.Context(), actor, groupID, studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -111,6 +128,11 @@ func (h *GroupHandler) RemoveStudentFromGroup(w http.ResponseWriter, r *http.Req
 // @Success 200 {object} map[string]string
 // @Router /api/students/{studentId}/group [patch]
 func (h *GroupHandler) ChangeStudentGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := chi.URLParam(r, "studentId")
 
 	var req ChangeGroupRequest
@@ -119,9 +141,9 @@ func (h *GroupHandler) ChangeStudentGroup(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err := h.uc.ChangeStudentGroup(r.Context(), studentID, req.GroupID)
+	err := h.uc.ChangeStudentGroup(r.Context(), actor, studentID, req.GroupID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -137,6 +159,11 @@ func (h *GroupHandler) ChangeStudentGroup(w http.ResponseWriter, r *http.Request
 // @Success 200 {object} map[string]string
 // @Router /api/teachers/{teacherId}/group [patch]
 func (h *GroupHandler) ChangeTeacherGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	teacherID := chi.URLParam(r, "teacherId")
 
 	var req ChangeGroupRequest
@@ -145,9 +172,9 @@ func (h *GroupHandler) ChangeTeacherGroup(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err := h.uc.ChangeTeacherGroup(r.Context(), teacherID, req.GroupID)
+	err := h.uc.ChangeTeacherGroup(r.Context(), actor, teacherID, req.GroupID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -3,26 +3,35 @@ package usecase
 import (
 	"context"
 	"errors"
+	"lms_backend/internal/domain"
 	"lms_backend/internal/groups/repository"
 )
 
 type GroupUseCase interface {
-	UpdateGroup(ctx context.Context, groupID, name string, teacherID *string) error
-	AddStudentToGroup(ctx context.Context, groupID, studentID string) error
-	RemoveStudentFromGroup(ctx context.Context, groupID, studentID string) error
-	ChangeStudentGroup(ctx context.Context, studentID, newGroupID string) error
-	ChangeTeacherGroup(ctx context.Context, teacherID, newGroupID string) error
+	UpdateGroup(ctx context.Context, actor domain.Actor, groupID, name string, teacherID *string) error
+	AddStudentToGroup(ctx context.Context, actor domain.Actor, groupID, studentID string) error
+	RemoveStudentFromGroup(ctx context.Context, actor domain.Actor, groupID, studentID string) error
+	ChangeStudentGroup(ctx context.Context, actor domain.Actor, studentID, newGroupID string) error
+	ChangeTeacherGroup(ctx context.Context, actor domain.Actor, teacherID, newGroupID string) error
+}
+
+// ScopeChecker — проверки доступа к группам и ученикам (реализует scope.ScopeUseCase).
+// Куратор управляет только своими группами.
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	CanManageGroup(ctx context.Context, actor domain.Actor, groupID string) error
 }
 
 type groupUseCase struct {
-	repo repository.GroupRepository
+	repo  repository.GroupRepository
+	scope ScopeChecker
 }
 
-func NewGroupUseCase(repo repository.GroupRepository) GroupUseCase {
-	return &groupUseCase{repo: repo}
+func NewGroupUseCase(repo repository.GroupRepository, scope ScopeChecker) GroupUseCase {
+	return &groupUseCase{repo: repo, scope: scope}
 }
 
-func (uc *groupUseCase) UpdateGroup(ctx context.Context, groupID, name string, teacherID *string) error {
+func (uc *groupUseCase) UpdateGroup(ctx context.Context, actor domain.Actor, groupID, name string, teacherID *string) error {
 	if name == "" {
 		return errors.New("group name cannot be empty")
 	}
@@ -32,46 +41,64 @@ func (uc *groupUseCase) UpdateGroup(ctx context.Context, groupID, name string, t
 	if err != nil {
 		return errors.New("group not found")
 	}
+	if err := uc.scope.CanManageGroup(ctx, actor, groupID); err != nil {
+		return err
+	}
 
 	return uc.repo.UpdateGroup(ctx, groupID, name, teacherID)
 }
 
-func (uc *groupUseCase) AddStudentToGroup(ctx context.Context, groupID, studentID string) error {
+func (uc *groupUseCase) AddStudentToGroup(ctx context.Context, actor domain.Actor, groupID, studentID string) error {
 	// Проверяем, что группа существует
 	_, err := uc.repo.GetByID(ctx, groupID)
 	if err != nil {
 		return errors.New("group not found")
 	}
+	if err := uc.scope.CanManageGroup(ctx, actor, groupID); err != nil {
+		return err
+	}
 
 	return uc.repo.AddStudentToGroup(ctx, groupID, studentID)
 }
 
-func (uc *groupUseCase) RemoveStudentFromGroup(ctx context.Context, groupID, studentID string) error {
+func (uc *groupUseCase) RemoveStudentFromGroup(ctx context.Context, actor domain.Actor, groupID, studentID string) error {
 	// Проверяем, что группа существует
 	_, err := uc.repo.GetByID(ctx, groupID)
 	if err != nil {
 		return errors.New("group not found")
 	}
+	if err := uc.scope.CanManageGroup(ctx, actor, groupID); err != nil {
+		return err
+	}
 
 	return uc.repo.RemoveStudentFromGroup(ctx, groupID, studentID)
 }
 
-func (uc *groupUseCase) ChangeStudentGroup(ctx context.Context, studentID, newGroupID string) error {
+func (uc *groupUseCase) ChangeStudentGroup(ctx context.Context, actor domain.Actor, studentID, newGroupID string) error {
 	// Проверяем, что новая группа существует
 	_, err := uc.repo.GetByID(ctx, newGroupID)
 	if err != nil {
 		return errors.New("new group not found")
 	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return err
+	}
+	if err := uc.scope.CanManageGroup(ctx, actor, newGroupID); err != nil {
+		return err
+	}
 
 	return uc.repo.ChangeStudentGroup(ctx, studentID, newGroupID)
 }
 
-func (uc *groupUseCase) ChangeTeacherGroup(ctx context.Context, teacherID, newGroupID string) error {
+func (uc *groupUseCase) ChangeTeacherGroup(ctx context.Context, actor domain.Actor, teacherID, newGroupID string) error {
 	// Проверяем, что новая группа существует
 	_, err := uc.repo.GetByID(ctx, newGroupID)
 	if err != nil {
 		return errors.New("new group not found")
 	}
+	if err := uc.scope.CanManageGroup(ctx, actor, newGroupID); err != nil {
+		return err
+	}
 
 	return uc.repo.ChangeTeacherGroup(ctx, teacherID, newGroupID)
 }
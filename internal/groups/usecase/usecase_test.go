@@ -7,11 +7,19 @@ import (
 
 	"lms_backend/internal/domain"
 	"lms_backend/internal/groups/mocks"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 )
 
+var admin = domain.Actor{UserID: "admin-1", Role: domain.RoleAdmin}
+
+func newScope() ScopeChecker {
+	return scopeUseCase.NewScopeUseCase(scopeMocks.TwoGroups())
+}
+
 func TestUpdateGroup(t *testing.T) {
 	mock := mocks.NewGroupRepoMock()
-	uc := NewGroupUseCase(mock)
+	uc := NewGroupUseCase(mock, newScope())
 
 	mock.GetByIDFunc = func(ctx context.Context, id string) (*domain.Group, error) {
 		if id == "g1" {
@@ -25,21 +33,21 @@ func TestUpdateGroup(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		err := uc.UpdateGroup(context.Background(), "g1", "New Name", nil)
+		err := uc.UpdateGroup(context.Background(), admin, "g1", "New Name", nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("empty name", func(t *testing.T) {
-		err := uc.UpdateGroup(context.Background(), "g1", "", nil)
+		err := uc.UpdateGroup(context.Background(), admin, "g1", "", nil)
 		if err == nil {
 			t.Error("expected error for empty name")
 		}
 	})
 
 	t.Run("group not found", func(t *testing.T) {
-		err := uc.UpdateGroup(context.Background(), "nonexistent", "Name", nil)
+		err := uc.UpdateGroup(context.Background(), admin, "nonexistent", "Name", nil)
 		if err == nil || err.Error() != "group not found" {
 			t.Error("expected 'group not found'")
 		}
@@ -48,7 +56,7 @@ func TestUpdateGroup(t *testing.T) {
 
 func TestAddStudentToGroup(t *testing.T) {
 	mock := mocks.NewGroupRepoMock()
-	uc := NewGroupUseCase(mock)
+	uc := NewGroupUseCase(mock, newScope())
 
 	mock.GetByIDFunc = func(ctx context.Context, id string) (*domain.Group, error) {
 		if id == "g1" {
@@ -61,14 +69,14 @@ func TestAddStudentToGroup(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		err := uc.AddStudentToGroup(context.Background(), "g1", "s1")
+		err := uc.AddStudentToGroup(context.Background(), admin, "g1", "s1")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("group not found", func(t *testing.T) {
-		err := uc.AddStudentToGroup(context.Background(), "bad", "s1")
+		err := uc.AddStudentToGroup(context.Background(), admin, "bad", "s1")
 		if err == nil || err.Error() != "group not found" {
 			t.Error("expected 'group not found'")
 		}
@@ -77,7 +85,7 @@ func TestAddStudentToGroup(t *testing.T) {
 
 func TestRemoveStudentFromGroup(t *testing.T) {
 	mock := mocks.NewGroupRepoMock()
-	uc := NewGroupUseCase(mock)
+	uc := NewGroupUseCase(mock, newScope())
 
 	mock.GetByIDFunc = func(ctx context.Context, id string) (*domain.Group, error) {
 		if id == "g1" {
@@ -90,14 +98,14 @@ func TestRemoveStudentFromGroup(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		err := uc.RemoveStudentFromGroup(context.Background(), "g1", "s1")
+		err := uc.RemoveStudentFromGroup(context.Background(), admin, "g1", "s1")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("group not found", func(t *testing.T) {
-		err := uc.RemoveStudentFromGroup(context.Background(), "bad", "s1")
+		err := uc.RemoveStudentFromGroup(context.Background(), admin, "bad", "s1")
 		if err == nil {
 			t.Error("expected error")
 		}
@@ -106,7 +114,7 @@ func TestRemoveStudentFromGroup(t *testing.T) {
 
 func TestChangeStudentGroup(t *testing.T) {
 	mock := mocks.NewGroupRepoMock()
-	uc := NewGroupUseCase(mock)
+	uc := NewGroupUseCase(mock, newScope())
 
 	mock.GetByIDFunc = func(ctx context.Context, id string) (*domain.Group, error) {
 		if id == "g2" {
@@ -119,14 +127,14 @@ func TestChangeStudentGroup(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		err := uc.ChangeStudentGroup(context.Background(), "s1", "g2")
+		err := uc.ChangeStudentGroup(context.Background(), admin, "s1", "g2")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("group not found", func(t *testing.T) {
-		err := uc.ChangeStudentGroup(context.Background(), "s1", "bad")
+		err := uc.ChangeStudentGroup(context.Background(), admin, "s1", "bad")
 		if err == nil || err.Error() != "new group not found" {
 			t.Error("expected 'new group not found'")
 		}
@@ -135,7 +143,7 @@ func TestChangeStudentGroup(t *testing.T) {
 
 func TestChangeTeacherGroup(t *testing.T) {
 	mock := mocks.NewGroupRepoMock()
-	uc := NewGroupUseCase(mock)
+	uc := NewGroupUseCase(mock, newScope())
 
 	mock.GetByIDFunc = func(ctx context.Context, id string) (*domain.Group, error) {
 		if id == "g2" {
@@ -148,16 +156,52 @@ func TestChangeTeacherGroup(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		err := uc.ChangeTeacherGroup(context.Background(), "t1", "g2")
+		err := uc.ChangeTeacherGroup(context.Background(), admin, "t1", "g2")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("group not found", func(t *testing.T) {
-		err := uc.ChangeTeacherGroup(context.Background(), "t1", "bad")
+		err := uc.ChangeTeacherGroup(context.Background(), admin, "t1", "bad")
 		if err == nil || err.Error() != "new group not found" {
 			t.Error("expected 'new group not found'")
 		}
 	})
 }
+
+func TestCuratorGroupScope(t *testing.T) {
+	mock := mocks.NewGroupRepoMock()
+	uc := NewGroupUseCase(mock, newScope())
+	curatorA := domain.Actor{UserID: "curator-a", Role: domain.RoleCurator}
+
+	mock.GetByIDFunc = func(ctx context.Context, id string) (*domain.Group, error) {
+		return &domain.Group{ID: id}, nil
+	}
+	mock.AddStudentToGroupFunc = func(ctx context.Context, groupID, studentID string) error {
+		return nil
+	}
+	mock.ChangeStudentGroupFunc = func(ctx context.Context, studentID, newGroupID string) error {
+		return nil
+	}
+
+	t.Run("own group", func(t *testing.T) {
+		if err := uc.AddStudentToGroup(context.Background(), curatorA, "group-a", "student-a"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("foreign group", func(t *testing.T) {
+		err := uc.AddStudentToGroup(context.Background(), curatorA, "group-b", "student-a")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("move foreign student", func(t *testing.T) {
+		err := uc.ChangeStudentGroup(context.Background(), curatorA, "student-b", "group-a")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
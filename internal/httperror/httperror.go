@@ -1,8 +1,11 @@
 package httperror
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+
+	"lms_backend/internal/domain"
 )
 
 func Internal(w http.ResponseWriter, err error) {
@@ -38,3 +41,13 @@ func Status(w http.ResponseWriter, err error, status int) {
 	slog.Warn("request error", slog.String("error", err.Error()), slog.Int("status", status))
 	http.Error(w, http.StatusText(status), status)
 }
+
+// Respond отвечает 403 на попытку работать с чужим учеником/уроком/группой (domain.ErrOutOfScope), иначе 500.
+func Respond(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrOutOfScope) {
+		slog.Warn("out of scope", slog.String("error", err.Error()))
+		Forbidden(w)
+		return
+	}
+	Internal(w, err)
+}
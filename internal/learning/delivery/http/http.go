@@ -71,7 +71,7 @@ func (h *LearningHandler) GetCourseContent(w http.ResponseWriter, r *http.Reques
 
 // GetLessonDetail godoc
 // @Summary УЧЕНИК: Просмотр урока
-// @Description Получить контент конкретного урока (видео, текст).
+// @Description Получить контент конкретного урока (видео, текст). Закрытый урок — 403.
 // @Tags Student-Learning
 // @Produce json
 // @Param id path string true "ID урока"
@@ -86,6 +86,10 @@ func (h *LearningHandler) GetLessonDetail(w http.ResponseWriter, r *http.Request
 	lessonID := chi.URLParam(r, "id")
 	lesson, err := h.uc.GetLessonDetail(r.Context(), lessonID, userCtxData.UserID)
 	if err != nil {
+		if errors.Is(err, domain.ErrLessonLocked) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		httperror.Internal(w, err)
 		return
 	}
@@ -95,7 +99,8 @@ func (h *LearningHandler) GetLessonDetail(w http.ResponseWriter, r *http.Request
 
 // SubmitAssignment godoc
 // @Summary УЧЕНИК: Сдать домашнее задание
-// @Description Отправка текстового ответа или файла.
+// @Description Отправка текстового ответа или файла. После срока работа отмечается как опоздавшая,
+// @Description а при политике reject отклоняется с 409. Закрытый урок — 403.
 // @Tags Student-Learning
 // @Accept multipart/form-data
 // @Produce json
@@ -129,6 +134,18 @@ func (h *LearningHandler) SubmitAssignment(w http.ResponseWriter, r *http.Reques
 		FileHeaders: fileHeaders,
 	}
 	if err := h.uc.SubmitAssignment(r.Context(), input); err != nil {
+		if errors.Is(err, domain.ErrDeadlinePassed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, usecase.ErrTextAnswerTooLong) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrLessonLocked) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		httperror.BadRequest(w, err)
 		return
 	}
@@ -136,6 +153,47 @@ func (h *LearningHandler) SubmitAssignment(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(map[string]string{"status": "submitted"})
 }
 
+// RecordVideoProgress godoc
+// @Summary УЧЕНИК: Прогресс просмотра видео
+// @Description Плеер периодически отправляет позицию и отрезки, просмотренные с прошлого сообщения; длительность видео
+// @Description берётся из урока. После просмотра 80% видео урок считается пройденным. Урок без видео или отрезки
+// @Description длиннее времени с прошлого сообщения — 400. Закрытый урок — 403.
+// @Tags Student-Learning
+// @Accept json
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param request body domain.VideoPing true "Позиция и просмотренные отрезки"
+// @Success 200 {object} domain.VideoProgress
+// @Router /lessons/{id}/video-progress [post]
+func (h *LearningHandler) RecordVideoProgress(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var ping domain.VideoPing
+	if err := json.NewDecoder(r.Body).Decode(&ping); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	progress, err := h.uc.RecordVideoProgress(r.Context(), userCtxData.UserID, chi.URLParam(r, "id"), ping)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidVideoPing):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrLessonLocked):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, sql.ErrNoRows):
+			httperror.NotFound(w, err)
+		default:
+			httperror.Internal(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
 type SetAttendanceRequest struct {
 	Status         string `json:"status"`
 	RecordingURL   string `json:"recording_url,omitempty"`
@@ -144,7 +202,8 @@ type SetAttendanceRequest struct {
 
 // SetLessonAttendance godoc
 // @Summary УЧЕНИК: Отметить посещение урока
-// @Description Отметить урок с указанием статуса посещения, ссылки на запись и комментария.
+// @Description Отметить урок с указанием статуса посещения, ссылки на запись и комментария. Закрытый урок — 403.
+// @Description Самоотметка не засчитывает урок и не завершает курс — это делает отметка сотрудника.
 // @Tags Student-Learning
 // @Accept json
 // @Produce json
@@ -174,6 +233,10 @@ func (h *LearningHandler) SetLessonAttendance(w http.ResponseWriter, r *http.Req
 		TeacherComment: req.TeacherComment,
 	}
 	if err := h.uc.SetLessonAttendance(r.Context(), input); err != nil {
+		if errors.Is(err, domain.ErrLessonLocked) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		httperror.Internal(w, err)
 		return
 	}
@@ -260,18 +323,23 @@ func (h *LearningHandler) AddReview(w http.ResponseWriter, r *http.Request) {
 
 // GetTest godoc
 // @Summary USER: Детали теста
+// @Description Проекция для ученика: правильные ответы приходят только после сдачи, если это разрешает answers_reveal теста.
 // @Tags Student-Learning
 // @Produce json
 // @Param id path string true "Test ID"
-// @Success 200 {object} domain.Test
+// @Success 200 {object} domain.StudentTest
 // @Router /tests/{id} [get]
 func (h *LearningHandler) GetTest(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	id := chi.URLParam(r, "id")
-	test, err := h.uc.GetTest(r.Context(), id)
+	test, err := h.uc.GetTest(r.Context(), id, userCtxData.UserID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(&domain.Test{ID: id})
+			httperror.NotFound(w, err)
 			return
 		}
 		httperror.Internal(w, err)
@@ -289,8 +357,13 @@ func (h *LearningHandler) GetTest(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} domain.Project
 // @Router /projects/{id} [get]
 func (h *LearningHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	id := chi.URLParam(r, "id")
-	proj, err := h.uc.GetProject(r.Context(), id)
+	proj, err := h.uc.GetProject(r.Context(), id, userCtxData.UserID)
 	if err != nil {
 		httperror.Internal(w, err)
 		return
@@ -322,50 +395,302 @@ func (h *LearningHandler) GetAllCourses(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(courses)
 }
 
+type SubmitTestRequest struct {
+	Answers []domain.TestAnswer `json:"answers"`
+}
+
 // SubmitTest godoc
 // @Summary УЧЕНИК: Отправить тест
+// @Description Проверяет ответы, сохраняет попытку и возвращает баллы и результат (сдан/не сдан). Разбор по вопросам — только после раскрытия ответов.
 // @Tags Student-Learning
 // @Accept json
 // @Produce json
 // @Param id path string true "Test ID"
-// @Success 200 {object} map[string]any
+// @Param input body SubmitTestRequest true "Ответы"
+// @Success 200 {object} domain.TestAttempt
 // @Router /tests/{id}/submit [post]
 func (h *LearningHandler) SubmitTest(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req SubmitTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	attempt, err := h.uc.SubmitTest(r.Context(), usecase.SubmitTestInput{
+		TestID:  chi.URLParam(r, "id"),
+		UserID:  userCtxData.UserID,
+		Answers: req.Answers,
+	})
+	if err != nil {
+		respondTestError(w, err)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "submitted"})
+	json.NewEncoder(w).Encode(attempt)
+}
+
+// StartTest godoc
+// @Summary УЧЕНИК: Начать попытку теста
+// @Description Фиксирует время старта и набор вопросов. Повторный вызов во время попытки возвращает её же.
+// @Description Тест закрытого урока — 403.
+// @Tags Student-Learning
+// @Produce json
+// @Param id path string true "Test ID"
+// @Success 200 {object} domain.StudentTest
+// @Router /tests/{id}/start [post]
+func (h *LearningHandler) StartTest(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	test, err := h.uc.StartTest(r.Context(), chi.URLParam(r, "id"), userCtxData.UserID)
+	if err != nil {
+		respondTestError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(test)
+}
+
+// respondTestError — ошибки попыток отдаются с текстом, чтобы клиент отличал
+// исчерпанные попытки от просроченной отправки.
+func respondTestError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		httperror.NotFound(w, err)
+	case errors.Is(err, usecase.ErrTestHasNoQuestions):
+		httperror.BadRequest(w, err)
+	case errors.Is(err, domain.ErrLessonLocked):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.ErrNoAttemptsLeft),
+		errors.Is(err, usecase.ErrAttemptNotStarted),
+		errors.Is(err, usecase.ErrTimeLimitExceeded),
+		errors.Is(err, domain.ErrTestAttemptActive):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		httperror.Internal(w, err)
+	}
+}
+
+// GetTestAttempts godoc
+// @Summary УЧЕНИК: Мои попытки теста
+// @Tags Student-Learning
+// @Produce json
+// @Param id path string true "Test ID"
+// @Success 200 {array} domain.TestAttempt
+// @Router /tests/{id}/attempts [get]
+func (h *LearningHandler) GetTestAttempts(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	attempts, err := h.uc.GetTestAttempts(r.Context(), userCtxData.UserID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondTestError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attempts)
 }
 
 // SubmitProject godoc
 // @Summary УЧЕНИК: Отправить проект
+// @Description Каждая отправка сохраняется новой версией. Принятый проект пересдать нельзя.
+// @Description Проект закрытого урока — 403.
 // @Tags Student-Learning
 // @Accept mpfd
 // @Produce json
 // @Param id path string true "Project ID"
-// @Param file formData file false "File attachment"
-// @Success 200 {object} map[string]any
+// @Param text_answer formData string false "Текст ответа"
+// @Param file formData file false "Файл проекта (можно несколько)"
+// @Success 200 {object} domain.ProjectSubmission
 // @Router /projects/{id}/submission [post]
 func (h *LearningHandler) SubmitProject(w http.ResponseWriter, r *http.Request) {
+	const MAX_SIZE = 50 << 20
+	if err := r.ParseMultipartForm(MAX_SIZE); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	sub, err := h.uc.SubmitProject(r.Context(), usecase.SubmitProjectInput{
+		ProjectID:   chi.URLParam(r, "id"),
+		UserID:      userCtxData.UserID,
+		TextAnswer:  r.FormValue("text_answer"),
+		FileHeaders: r.MultipartForm.File["file"],
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			httperror.NotFound(w, err)
+		case errors.Is(err, usecase.ErrEmptySubmission), errors.Is(err, usecase.ErrTextAnswerTooLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrProjectAlreadyAccepted):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, domain.ErrLessonLocked):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			httperror.Internal(w, err)
+		}
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "submitted"})
+	json.NewEncoder(w).Encode(sub)
 }
 
-// GetTeacherDashboard godoc
-// @Summary ТИЧЕР: Дашборд ЛК
-// @Tags Teacher-Dashboard
+// GetProjectSubmissions godoc
+// @Summary УЧЕНИК: История сдачи проекта
+// @Tags Student-Learning
 // @Produce json
-// @Success 200 {object} domain.TeacherDashboardData
-// @Router /teacher/profile [get]
+// @Param id path string true "Project ID"
+// @Success 200 {array} domain.ProjectSubmission
+// @Router /projects/{id}/submissions [get]
+func (h *LearningHandler) GetProjectSubmissions(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	subs, err := h.uc.GetProjectSubmissions(r.Context(), userCtxData.UserID, chi.URLParam(r, "id"))
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subs)
+}
+
 // GetTeacherCertificates godoc
-// @Summary USER: Сертификаты преподавателя
+// @Summary ТИЧЕР: Сертификаты учеников
+// @Description Сертификаты об окончании курсов, выданные ученикам из групп преподавателя, от новых к старым.
 // @Tags Teacher-Certificates
 // @Produce json
 // @Success 200 {array} domain.TeacherCertificate
 // @Router /teacher/certificates [get]
 func (h *LearningHandler) GetTeacherCertificates(w http.ResponseWriter, r *http.Request) {
+	userData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userData.Role != domain.RoleTeacher {
+		http.Error(w, "Forbidden: Only for teachers", http.StatusForbidden)
+		return
+	}
+	certs, err := h.uc.GetTeacherCertificates(r.Context(), userData.UserID)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(certs)
+}
+
+// GetMyCertificates godoc
+// @Summary УЧЕНИК: Мои сертификаты
+// @Description Сертификаты об окончании курсов со ссылками на PDF. Сертификат выдаётся автоматически, когда курс завершён.
+// @Tags Student-Learning
+// @Produce json
+// @Success 200 {array} domain.Certificate
+// @Router /certificates [get]
+func (h *LearningHandler) GetMyCertificates(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	certs, err := h.uc.GetMyCertificates(r.Context(), userCtxData.UserID)
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(certs)
+}
+
+// VerifyCertificate godoc
+// @Summary ПУБЛИЧНО: Проверка сертификата
+// @Description Проверка подлинности сертификата по коду без авторизации: владелец, курс, дата выдачи и действителен ли он.
+// @Tags Certificates
+// @Produce json
+// @Param code path string true "Код сертификата"
+// @Success 200 {object} domain.CertificateVerification
+// @Failure 404 {string} string "Сертификат не найден"
+// @Router /certificates/verify/{code} [get]
+func (h *LearningHandler) VerifyCertificate(w http.ResponseWriter, r *http.Request) {
+	verification, err := h.uc.VerifyCertificate(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		if errors.Is(err, domain.ErrCertificateNotFound) {
+			httperror.NotFound(w, err)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(verification)
+}
+
+type RevokeCertificateRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RevokeCertificate godoc
+// @Summary АДМИН: Отозвать сертификат
+// @Description Отзыв сертификата по коду с обязательной причиной. Отозванный сертификат при проверке недействителен
+// @Description и не выдаётся заново. Действие пишется в audit_logs. Право: certificates.revoke.
+// @Tags Admin-Certificates
+// @Accept json
+// @Produce json
+// @Param code path string true "Код сертификата"
+// @Param request body RevokeCertificateRequest true "Причина отзыва"
+// @Success 200 {object} domain.Certificate
+// @Failure 400 {string} string "Не указана причина"
+// @Failure 404 {string} string "Сертификат не найден"
+// @Failure 409 {string} string "Сертификат уже отозван"
+// @Router /admin/certificates/{code}/revoke [post]
+func (h *LearningHandler) RevokeCertificate(w http.ResponseWriter, r *http.Request) {
+	userCtxData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtxData == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req RevokeCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	ip := authMiddleware.ClientIP(r)
+	ua := r.UserAgent()
+	cert, err := h.uc.RevokeCertificate(r.Context(), userCtxData.UserID, chi.URLParam(r, "code"), req.Reason, &ip, &ua)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRevokeReasonRequired):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrCertificateNotFound):
+			httperror.NotFound(w, err)
+		case errors.Is(err, domain.ErrCertificateRevoked):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			httperror.Internal(w, err)
+		}
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode([]domain.TeacherCertificate{})
+	json.NewEncoder(w).Encode(cert)
 }
 
+// GetTeacherDashboard godoc
+// @Summary ТИЧЕР: Дашборд ЛК
+// @Tags Teacher-Dashboard
+// @Produce json
+// @Success 200 {object} domain.TeacherDashboardData
+// @Router /teacher/profile [get]
 func (h *LearningHandler) GetTeacherDashboard(w http.ResponseWriter, r *http.Request) {
 	userData, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
 	if !ok || userData.Role != domain.RoleTeacher {
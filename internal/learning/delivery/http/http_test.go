@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
+	"lms_backend/internal/domain"
+	"lms_backend/internal/learning/mocks"
+	"lms_backend/internal/learning/usecase"
+	pkgMocks "lms_backend/pkg/storage/mocks"
+)
+
+func TestGetTest_NeverLeaksAnswers(t *testing.T) {
+	repo := mocks.NewLearningRepoMock()
+	repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+		return &domain.Test{
+			ID:            testID,
+			PassingScore:  100,
+			AnswersReveal: domain.AnswersReveal(testID),
+			TestPolicy:    domain.TestPolicy{MaxAttempts: 1},
+			Questions: []domain.TestQuestion{
+				{ID: "q1", Question: "Capital of France?", Options: []string{"Paris", "Rome"}, CorrectAnswer: "Paris", Points: 1},
+			},
+		}, nil
+	}
+	repo.GetTestAttemptsFunc = func(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error) {
+		if userID == "submitted" {
+			return []*domain.TestAttempt{{TestID: testID, Percent: 0, Passed: false}}, nil
+		}
+		return []*domain.TestAttempt{}, nil
+	}
+	h := NewLearningHandler(usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil))
+
+	get := func(testID, userID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/tests/"+testID, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", testID)
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, authMiddleware.ContextUserDataKey,
+			&authMiddleware.UserContextData{UserID: userID, Role: domain.RoleStudent})
+		w := httptest.NewRecorder()
+		h.GetTest(w, req.WithContext(ctx))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		return w.Body.String()
+	}
+
+	cases := []struct {
+		testID, userID string
+	}{
+		{"after_submit", "fresh"},
+		{"after_pass", "fresh"},
+		{"after_pass", "submitted"},
+		{"never", "submitted"},
+		{"", "submitted"},
+	}
+	for _, c := range cases {
+		body := get(c.testID, c.userID)
+		if strings.Contains(body, "correct_answer") {
+			t.Errorf("policy %q, user %q: answers leaked: %s", c.testID, c.userID, body)
+		}
+	}
+
+	if body := get("after_submit", "submitted"); !strings.Contains(body, `"correct_answer":"Paris"`) {
+		t.Errorf("answers should be revealed once attempts are used up: %s", body)
+	}
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+)
+
+type AuditEntry struct {
+	UserID   *string
+	Action   string
+	EntityID string
+	Details  interface{}
+}
+
+type AuditLoggerMock struct {
+	mu      sync.Mutex
+	Entries []AuditEntry
+}
+
+func (a *AuditLoggerMock) LogAction(ctx context.Context, userID *string, action, entityType, entityID string, oldValues, newValues interface{}, ipAddress, userAgent *string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Entries = append(a.Entries, AuditEntry{UserID: userID, Action: action, EntityID: entityID, Details: newValues})
+	return nil
+}
@@ -14,7 +14,8 @@ type LearningRepoMock struct {
 	GetLessonDetailFunc         func(ctx context.Context, lessonID, userID string) (*domain.StudentLessonDetail, error)
 	GetAssignmentIDByLessonFunc func(ctx context.Context, lessonID string) (string, error)
 	EnsureAssignmentFunc        func(ctx context.Context, lessonID, title string) error
-	SaveSubmissionFunc          func(ctx context.Context, userID, assignmentID, text string, files []string) error
+	GetStudentDeadlineFunc      func(ctx context.Context, assignmentID, userID string) (*domain.StudentDeadline, error)
+	SaveSubmissionFunc          func(ctx context.Context, userID, assignmentID, text string, files []string, isLate bool) (string, error)
 	SetLessonAttendanceFunc     func(ctx context.Context, userID, lessonID, status, recordingURL, teacherComment string) error
 	GetTeachersListFunc         func(ctx context.Context) ([]*domain.TeacherPublicInfo, error)
 	GetTeacherByIDFunc          func(ctx context.Context, id string) (*domain.TeacherPublicInfo, error)
@@ -22,13 +23,36 @@ type LearningRepoMock struct {
 	GetTeacherReviewsFunc       func(ctx context.Context, teacherID string) ([]*domain.TeacherReview, error)
 	GetTeacherCoursesFunc       func(ctx context.Context, teacherID string) ([]*domain.StudentCoursePreview, error)
 	GetTestByIDFunc             func(ctx context.Context, testID string) (*domain.Test, error)
+	StartTestAttemptFunc        func(ctx context.Context, attempt *domain.TestAttempt) error
+	FinishTestAttemptFunc       func(ctx context.Context, attempt *domain.TestAttempt) error
+	GetActiveTestAttemptFunc    func(ctx context.Context, userID, testID string) (*domain.TestAttempt, error)
+	GetTestAttemptsFunc         func(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error)
 	GetProjectByIDFunc          func(ctx context.Context, projectID string) (*domain.Project, error)
+	SaveProjectSubmissionFunc   func(ctx context.Context, sub *domain.ProjectSubmission) error
+	GetProjectSubmissionsFunc   func(ctx context.Context, userID, projectID string) ([]*domain.ProjectSubmission, error)
 	GetTeacherSubstitutionsFunc       func(ctx context.Context, teacherID string) ([]*domain.Lesson, error)
 	GetTeacherUpcomingLessonsFunc     func(ctx context.Context, teacherID string) ([]*domain.Lesson, error)
 	GetTeacherCancelledLessonsFunc    func(ctx context.Context, teacherID string) ([]*domain.Lesson, error)
 	GetAllCoursesFunc                 func(ctx context.Context) ([]*domain.Course, error)
 	GetLessonOrderNumFunc             func(ctx context.Context, lessonID string) (int, error)
 	GetTeacherCertificatesFunc        func(ctx context.Context, teacherID string) ([]*domain.TeacherCertificate, error)
+	IsAntiCopyEnabledFunc             func(ctx context.Context, assignmentID string) (bool, error)
+	SaveFingerprintFunc               func(ctx context.Context, fp *domain.SubmissionFingerprint) error
+	GetAssignmentFingerprintsFunc     func(ctx context.Context, assignmentID, excludeUserID string) ([]*domain.SubmissionFingerprint, error)
+	SaveSimilarityMatchesFunc         func(ctx context.Context, versionID string, matches []*domain.SimilarityMatch) error
+	GetCourseRulesFunc                func(ctx context.Context, courseID string) (*domain.Course, error)
+	GetLessonCourseIDFunc             func(ctx context.Context, lessonID string) (string, error)
+	GetLessonVideoFunc                func(ctx context.Context, lessonID string) (string, int, error)
+	GetLessonGatesFunc                func(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error)
+	GetVideoProgressFunc              func(ctx context.Context, userID, lessonID string) (*domain.VideoProgress, error)
+	SaveVideoProgressFunc             func(ctx context.Context, userID string, progress *domain.VideoProgress) error
+	GetCourseCompletionFunc           func(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error)
+	GetCertificateHolderFunc          func(ctx context.Context, userID, courseID string) (string, string, error)
+	GetCertificateFunc                func(ctx context.Context, userID, courseID string) (*domain.Certificate, error)
+	GetCertificateByCodeFunc          func(ctx context.Context, code string) (*domain.Certificate, error)
+	CreateCertificateFunc             func(ctx context.Context, c *domain.Certificate) error
+	RevokeCertificateFunc             func(ctx context.Context, c *domain.Certificate, revokedBy, reason string) error
+	GetUserCertificatesFunc           func(ctx context.Context, userID string) ([]*domain.Certificate, error)
 }
 
 func NewLearningRepoMock() *LearningRepoMock {
@@ -47,6 +71,58 @@ func (m *LearningRepoMock) GetLessonDetail(ctx context.Context, lessonID, userID
 	return m.GetLessonDetailFunc(ctx, lessonID, userID)
 }
 
+func (m *LearningRepoMock) GetCourseRules(ctx context.Context, courseID string) (*domain.Course, error) {
+	return m.GetCourseRulesFunc(ctx, courseID)
+}
+
+func (m *LearningRepoMock) GetLessonCourseID(ctx context.Context, lessonID string) (string, error) {
+	return m.GetLessonCourseIDFunc(ctx, lessonID)
+}
+
+func (m *LearningRepoMock) GetLessonVideo(ctx context.Context, lessonID string) (string, int, error) {
+	return m.GetLessonVideoFunc(ctx, lessonID)
+}
+
+func (m *LearningRepoMock) GetLessonGates(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error) {
+	return m.GetLessonGatesFunc(ctx, courseID, userID)
+}
+
+func (m *LearningRepoMock) GetVideoProgress(ctx context.Context, userID, lessonID string) (*domain.VideoProgress, error) {
+	return m.GetVideoProgressFunc(ctx, userID, lessonID)
+}
+
+func (m *LearningRepoMock) SaveVideoProgress(ctx context.Context, userID string, progress *domain.VideoProgress) error {
+	return m.SaveVideoProgressFunc(ctx, userID, progress)
+}
+
+func (m *LearningRepoMock) GetCourseCompletion(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error) {
+	return m.GetCourseCompletionFunc(ctx, courseID, userID)
+}
+
+func (m *LearningRepoMock) GetCertificateHolder(ctx context.Context, userID, courseID string) (string, string, error) {
+	return m.GetCertificateHolderFunc(ctx, userID, courseID)
+}
+
+func (m *LearningRepoMock) GetCertificate(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+	return m.GetCertificateFunc(ctx, userID, courseID)
+}
+
+func (m *LearningRepoMock) GetCertificateByCode(ctx context.Context, code string) (*domain.Certificate, error) {
+	return m.GetCertificateByCodeFunc(ctx, code)
+}
+
+func (m *LearningRepoMock) CreateCertificate(ctx context.Context, c *domain.Certificate) error {
+	return m.CreateCertificateFunc(ctx, c)
+}
+
+func (m *LearningRepoMock) RevokeCertificate(ctx context.Context, c *domain.Certificate, revokedBy, reason string) error {
+	return m.RevokeCertificateFunc(ctx, c, revokedBy, reason)
+}
+
+func (m *LearningRepoMock) GetUserCertificates(ctx context.Context, userID string) ([]*domain.Certificate, error) {
+	return m.GetUserCertificatesFunc(ctx, userID)
+}
+
 func (m *LearningRepoMock) GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
 	return m.GetAssignmentIDByLessonFunc(ctx, lessonID)
 }
@@ -55,8 +131,28 @@ func (m *LearningRepoMock) EnsureAssignment(ctx context.Context, lessonID, title
 	return m.EnsureAssignmentFunc(ctx, lessonID, title)
 }
 
-func (m *LearningRepoMock) SaveSubmission(ctx context.Context, userID, assignmentID, text string, files []string) error {
-	return m.SaveSubmissionFunc(ctx, userID, assignmentID, text, files)
+func (m *LearningRepoMock) GetStudentDeadline(ctx context.Context, assignmentID, userID string) (*domain.StudentDeadline, error) {
+	return m.GetStudentDeadlineFunc(ctx, assignmentID, userID)
+}
+
+func (m *LearningRepoMock) SaveSubmission(ctx context.Context, userID, assignmentID, text string, files []string, isLate bool) (string, error) {
+	return m.SaveSubmissionFunc(ctx, userID, assignmentID, text, files, isLate)
+}
+
+func (m *LearningRepoMock) IsAntiCopyEnabled(ctx context.Context, assignmentID string) (bool, error) {
+	return m.IsAntiCopyEnabledFunc(ctx, assignmentID)
+}
+
+func (m *LearningRepoMock) SaveFingerprint(ctx context.Context, fp *domain.SubmissionFingerprint) error {
+	return m.SaveFingerprintFunc(ctx, fp)
+}
+
+func (m *LearningRepoMock) GetAssignmentFingerprints(ctx context.Context, assignmentID, excludeUserID string) ([]*domain.SubmissionFingerprint, error) {
+	return m.GetAssignmentFingerprintsFunc(ctx, assignmentID, excludeUserID)
+}
+
+func (m *LearningRepoMock) SaveSimilarityMatches(ctx context.Context, versionID string, matches []*domain.SimilarityMatch) error {
+	return m.SaveSimilarityMatchesFunc(ctx, versionID, matches)
 }
 
 func (m *LearningRepoMock) SetLessonAttendance(ctx context.Context, userID, lessonID, status, recordingURL, teacherComment string) error {
@@ -87,10 +183,34 @@ func (m *LearningRepoMock) GetTestByID(ctx context.Context, testID string) (*dom
 	return m.GetTestByIDFunc(ctx, testID)
 }
 
+func (m *LearningRepoMock) StartTestAttempt(ctx context.Context, attempt *domain.TestAttempt) error {
+	return m.StartTestAttemptFunc(ctx, attempt)
+}
+
+func (m *LearningRepoMock) FinishTestAttempt(ctx context.Context, attempt *domain.TestAttempt) error {
+	return m.FinishTestAttemptFunc(ctx, attempt)
+}
+
+func (m *LearningRepoMock) GetActiveTestAttempt(ctx context.Context, userID, testID string) (*domain.TestAttempt, error) {
+	return m.GetActiveTestAttemptFunc(ctx, userID, testID)
+}
+
+func (m *LearningRepoMock) GetTestAttempts(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error) {
+	return m.GetTestAttemptsFunc(ctx, userID, testID)
+}
+
 func (m *LearningRepoMock) GetProjectByID(ctx context.Context, projectID string) (*domain.Project, error) {
 	return m.GetProjectByIDFunc(ctx, projectID)
 }
 
+func (m *LearningRepoMock) SaveProjectSubmission(ctx context.Context, sub *domain.ProjectSubmission) error {
+	return m.SaveProjectSubmissionFunc(ctx, sub)
+}
+
+func (m *LearningRepoMock) GetProjectSubmissions(ctx context.Context, userID, projectID string) ([]*domain.ProjectSubmission, error) {
+	return m.GetProjectSubmissionsFunc(ctx, userID, projectID)
+}
+
 func (m *LearningRepoMock) GetTeacherSubstitutions(ctx context.Context, teacherID string) ([]*domain.Lesson, error) {
 	return m.GetTeacherSubstitutionsFunc(ctx, teacherID)
 }
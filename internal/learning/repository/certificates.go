@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+)
+
+const certificateSelect = `
+	SELECT id, code, user_id, course_id, student_name, course_title, pdf_url, issued_at, revoked_at, revoke_reason
+	FROM certificates
+`
+
+func scanCertificate(row interface{ Scan(...any) error }) (*domain.Certificate, error) {
+	c := &domain.Certificate{}
+	err := row.Scan(&c.ID, &c.Code, &c.UserID, &c.CourseID, &c.StudentName, &c.CourseTitle, &c.PDFURL, &c.IssuedAt, &c.RevokedAt, &c.RevokeReason)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCourseCompletion — выполнение курса учеником. Прогресс берётся из user_courses, поэтому
+// для ученика, не записанного на курс, возвращается sql.ErrNoRows. Задания считаются по lessons.course_id:
+// module_id у урока может быть пустым.
+func (r *LearningRepoImpl) GetCourseCompletion(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error) {
+	cc := &domain.CourseCompletion{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT uc.progress_percent,
+			(SELECT COUNT(*) FROM assignments a
+			 JOIN lessons l ON a.lesson_id = l.id
+			 WHERE l.course_id = $1),
+			(SELECT COUNT(*) FROM lessons l
+			 WHERE l.course_id = $1 AND l.is_published = true AND (
+				EXISTS (SELECT 1 FROM attendance_records ar
+				        WHERE ar.lesson_id = l.id AND ar.student_id = $2 AND ar.status = 'ATTENDED')
+				OR EXISTS (SELECT 1 FROM assignments a
+				           JOIN user_assignments_submission uas ON uas.assignment_id = a.id AND uas.user_id = $2
+				           WHERE a.lesson_id = l.id AND uas.status = 'accepted')
+				OR EXISTS (SELECT 1 FROM lesson_video_progress vp
+				           WHERE vp.lesson_id = l.id AND vp.user_id = $2 AND vp.completed_at IS NOT NULL)))
+		FROM user_courses uc
+		WHERE uc.course_id = $1 AND uc.user_id = $2
+	`, courseID, userID).Scan(&cc.ProgressPercent, &cc.Assignments, &cc.LessonsDone)
+	if err != nil {
+		return nil, err
+	}
+	if cc.Lessons, err = r.GetLessonGates(ctx, courseID, userID); err != nil {
+		return nil, err
+	}
+	return cc, nil
+}
+
+// GetCertificateHolder — имя ученика и название курса для нового сертификата.
+func (r *LearningRepoImpl) GetCertificateHolder(ctx context.Context, userID, courseID string) (string, string, error) {
+	var studentName, courseTitle string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT TRIM(u.first_name || ' ' || u.last_name), c.title
+		FROM users u, courses c
+		WHERE u.id = $1 AND c.id = $2
+	`, userID, courseID).Scan(&studentName, &courseTitle)
+	return studentName, courseTitle, err
+}
+
+// GetCertificate — сертификат ученика по курсу; если его нет — sql.ErrNoRows.
+func (r *LearningRepoImpl) GetCertificate(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+	return scanCertificate(r.db.QueryRowContext(ctx, certificateSelect+`WHERE user_id = $1 AND course_id = $2`, userID, courseID))
+}
+
+func (r *LearningRepoImpl) GetCertificateByCode(ctx context.Context, code string) (*domain.Certificate, error) {
+	return scanCertificate(r.db.QueryRowContext(ctx, certificateSelect+`WHERE code = $1`, code))
+}
+
+// CreateCertificate сохраняет сертификат и заполняет ID. Если сертификат по этому курсу
+// уже выдан (параллельная проверка завершения), возвращает sql.ErrNoRows.
+func (r *LearningRepoImpl) CreateCertificate(ctx context.Context, c *domain.Certificate) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO certificates (code, user_id, course_id, student_name, course_title, pdf_url, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, course_id) DO NOTHING
+		RETURNING id
+	`, c.Code, c.UserID, c.CourseID, c.StudentName, c.CourseTitle, c.PDFURL, c.IssuedAt).Scan(&c.ID)
+}
+
+// RevokeCertificate отзывает сертификат и заполняет RevokedAt и RevokeReason. Уже отозванный
+// сертификат не меняется — sql.ErrNoRows.
+func (r *LearningRepoImpl) RevokeCertificate(ctx context.Context, c *domain.Certificate, revokedBy, reason string) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE certificates SET revoked_at = NOW(), revoked_by = $2, revoke_reason = $3
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING revoked_at, revoke_reason
+	`, c.ID, revokedBy, reason).Scan(&c.RevokedAt, &c.RevokeReason)
+	return err
+}
+
+func (r *LearningRepoImpl) GetUserCertificates(ctx context.Context, userID string) ([]*domain.Certificate, error) {
+	rows, err := r.db.QueryContext(ctx, certificateSelect+`WHERE user_id = $1 ORDER BY issued_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []*domain.Certificate{}
+	for rows.Next() {
+		c, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	return certs, rows.Err()
+}
+
+// GetTeacherCertificates — действующие сертификаты учеников из групп преподавателя по курсам
+// этих групп; Title — код сертификата.
+func (r *LearningRepoImpl) GetTeacherCertificates(ctx context.Context, teacherID string) ([]*domain.TeacherCertificate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cert.id, cert.code, cert.student_name, cert.issued_at, cert.course_title, cert.pdf_url
+		FROM certificates cert
+		JOIN user_courses uc ON uc.user_id = cert.user_id AND uc.course_id = cert.course_id
+		JOIN groups g ON g.id = uc.group_id
+		WHERE g.teacher_id = $1 AND cert.revoked_at IS NULL
+		ORDER BY cert.issued_at DESC
+	`, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []*domain.TeacherCertificate{}
+	for rows.Next() {
+		c := &domain.TeacherCertificate{}
+		if err := rows.Scan(&c.ID, &c.Title, &c.StudentName, &c.IssuedAt, &c.CourseName, &c.CertificateURL); err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	return certs, rows.Err()
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+func (r *LearningRepoImpl) GetStudentDeadline(ctx context.Context, assignmentID, userID string) (*domain.StudentDeadline, error) {
+	return r.getStudentDeadline(ctx, "a.id = $1", assignmentID, userID)
+}
+
+// getStudentDeadline — срок ДЗ, отобранного условием filter с параметром $1, с учётом
+// самого позднего одобренного продления ученика.
+func (r *LearningRepoImpl) getStudentDeadline(ctx context.Context, filter, arg, userID string) (*domain.StudentDeadline, error) {
+	query := `
+		SELECT a.id, a.due_at, a.due_offset_hours, a.late_policy, a.late_penalty_percent, l.lesson_time,
+			(SELECT MAX(e.requested_due_at) FROM assignment_extension_requests e
+			 WHERE e.assignment_id = a.id AND e.student_id = $2 AND e.status = 'APPROVED')
+		FROM assignments a
+		JOIN lessons l ON a.lesson_id = l.id
+		WHERE ` + filter + `
+		LIMIT 1`
+
+	var id string
+	var d domain.AssignmentDeadline
+	var dueAt, lessonTime, extendedUntil sql.NullTime
+	var offset sql.NullInt32
+	err := r.db.QueryRowContext(ctx, query, arg, userID).Scan(
+		&id, &dueAt, &offset, &d.LatePolicy, &d.LatePenaltyPercent, &lessonTime, &extendedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if dueAt.Valid {
+		d.DueAt = &dueAt.Time
+	}
+	if offset.Valid {
+		hours := int(offset.Int32)
+		d.DueOffsetHours = &hours
+	}
+	return domain.NewStudentDeadline(id, d, nullTimePtr(lessonTime), nullTimePtr(extendedUntil)), nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+)
+
+// GetCourseRules — курс с флагами обязательности ДЗ, тестов и проектов.
+func (r *LearningRepoImpl) GetCourseRules(ctx context.Context, courseID string) (*domain.Course, error) {
+	c := &domain.Course{ID: courseID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT is_homework_mandatory, is_test_mandatory, is_project_mandatory
+		FROM courses WHERE id = $1
+	`, courseID).Scan(&c.IsHomeworkMandatory, &c.IsTestMandatory, &c.IsProjectMandatory)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *LearningRepoImpl) GetLessonCourseID(ctx context.Context, lessonID string) (string, error) {
+	var courseID string
+	err := r.db.QueryRowContext(ctx, `SELECT course_id FROM lessons WHERE id = $1`, lessonID).Scan(&courseID)
+	return courseID, err
+}
+
+// GetLessonGates — выполнение опубликованных уроков курса учеником в порядке прохождения (order_num).
+// Тест считается сданным по тем же правилам, что и в сводке результатов (getTestResults).
+func (r *LearningRepoImpl) GetLessonGates(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT l.id,
+			l.has_homework,
+			EXISTS (SELECT 1 FROM assignments a
+			        JOIN user_assignments_submission uas ON uas.assignment_id = a.id AND uas.user_id = $2
+			        WHERE a.lesson_id = l.id AND uas.status = 'accepted'),
+			(SELECT COUNT(*) FROM projects p WHERE p.lesson_id = l.id),
+			(SELECT COUNT(*) FROM projects p
+			 WHERE p.lesson_id = l.id AND (
+				SELECT ps.status::text FROM project_submissions ps
+				WHERE ps.project_id = p.id AND ps.user_id = $2
+				ORDER BY ps.version DESC LIMIT 1) = 'accepted'),
+			EXISTS (SELECT 1 FROM lesson_unlocks u WHERE u.lesson_id = l.id AND u.student_id = $2)
+		FROM lessons l
+		WHERE l.course_id = $1 AND l.is_published = true
+		ORDER BY l.order_num ASC, l.id
+	`, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gates []domain.LessonGate
+	index := make(map[string]int)
+	for rows.Next() {
+		var g domain.LessonGate
+		if err := rows.Scan(&g.LessonID, &g.HasHomework, &g.HomeworkAccepted, &g.Projects, &g.ProjectsAccepted, &g.Unlocked); err != nil {
+			return nil, err
+		}
+		index[g.LessonID] = len(gates)
+		gates = append(gates, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := r.getTestResults(ctx, userID, "t.lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)", courseID)
+	if err != nil {
+		return nil, err
+	}
+	passed := make(map[string]bool, len(results))
+	for _, res := range results {
+		passed[res.TestID] = res.Passed
+	}
+	testRows, err := r.db.QueryContext(ctx, `SELECT id, lesson_id FROM tests WHERE lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)`, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer testRows.Close()
+	for testRows.Next() {
+		var testID, lessonID string
+		if err := testRows.Scan(&testID, &lessonID); err != nil {
+			return nil, err
+		}
+		i, ok := index[lessonID]
+		if !ok {
+			continue
+		}
+		gates[i].Tests++
+		if passed[testID] {
+			gates[i].TestsPassed++
+		}
+	}
+	return gates, testRows.Err()
+}
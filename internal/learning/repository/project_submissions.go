@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"lms_backend/internal/domain"
+)
+
+// SaveProjectSubmission добавляет новую версию сдачи проекта; номер версии назначает БД.
+// При гонке двух отправок вторая упадёт на UNIQUE (project_id, user_id, version).
+func (r *LearningRepoImpl) SaveProjectSubmission(ctx context.Context, sub *domain.ProjectSubmission) error {
+	filesJSON, err := json.Marshal(nonNilFiles(sub.Files))
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO project_submissions (project_id, user_id, version, submission_text, submission_files)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(version), 0) + 1 FROM project_submissions WHERE project_id = $1 AND user_id = $2), $3, $4)
+		RETURNING id, version, status, submitted_at
+	`
+	sub.RubricScores = []domain.CriterionScore{}
+	return r.db.QueryRowContext(ctx, query, sub.ProjectID, sub.UserID, sub.Text, filesJSON).
+		Scan(&sub.ID, &sub.Version, &sub.Status, &sub.SubmittedAt)
+}
+
+// GetProjectSubmissions — все версии сдачи проекта учеником, начиная с последней.
+func (r *LearningRepoImpl) GetProjectSubmissions(ctx context.Context, userID, projectID string) ([]*domain.ProjectSubmission, error) {
+	query := `
+		SELECT ps.id, ps.project_id, ps.user_id, ps.version, ps.submission_text, ps.submission_files, ps.status,
+		       COALESCE(ps.grade, 0), COALESCE(ps.teacher_comment, ''), p.max_score, ps.rubric_scores, ps.reviewed_by, ps.reviewed_at, ps.submitted_at
+		FROM project_submissions ps
+		JOIN projects p ON p.id = ps.project_id
+		WHERE ps.user_id = $1 AND ps.project_id = $2
+		ORDER BY ps.version DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []*domain.ProjectSubmission{}
+	for rows.Next() {
+		s := &domain.ProjectSubmission{}
+		var filesRaw, scoresRaw []byte
+		if err := rows.Scan(&s.ID, &s.ProjectID, &s.UserID, &s.Version, &s.Text, &filesRaw, &s.Status,
+			&s.Grade, &s.TeacherComment, &s.MaxScore, &scoresRaw, &s.ReviewedBy, &s.ReviewedAt, &s.SubmittedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(filesRaw, &s.Files); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(scoresRaw, &s.RubricScores); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+func nonNilFiles(files []string) []string {
+	if files == nil {
+		return []string{}
+	}
+	return files
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"lms_backend/internal/domain"
+	"time"
 
+	"github.com/lib/pq"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -14,9 +17,27 @@ type LearningRepository interface {
 	GetMyCourses(ctx context.Context, userID string) ([]*domain.StudentCoursePreview, error)
 	GetCourseContent(ctx context.Context, courseID, userID string) (*domain.StudentCourseView, error)
 	GetLessonDetail(ctx context.Context, lessonID, userID string) (*domain.StudentLessonDetail, error)
+	GetCourseRules(ctx context.Context, courseID string) (*domain.Course, error)
+	GetLessonCourseID(ctx context.Context, lessonID string) (string, error)
+	GetLessonVideo(ctx context.Context, lessonID string) (string, int, error)
+	GetLessonGates(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error)
+	GetVideoProgress(ctx context.Context, userID, lessonID string) (*domain.VideoProgress, error)
+	SaveVideoProgress(ctx context.Context, userID string, progress *domain.VideoProgress) error
+	GetCourseCompletion(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error)
+	GetCertificateHolder(ctx context.Context, userID, courseID string) (string, string, error)
+	GetCertificate(ctx context.Context, userID, courseID string) (*domain.Certificate, error)
+	GetCertificateByCode(ctx context.Context, code string) (*domain.Certificate, error)
+	CreateCertificate(ctx context.Context, c *domain.Certificate) error
+	RevokeCertificate(ctx context.Context, c *domain.Certificate, revokedBy, reason string) error
+	GetUserCertificates(ctx context.Context, userID string) ([]*domain.Certificate, error)
 	GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error)
 	EnsureAssignment(ctx context.Context, lessonID, title string) error
-	SaveSubmission(ctx context.Context, userID, assignmentID, text string, files []string) error
+	GetStudentDeadline(ctx context.Context, assignmentID, userID string) (*domain.StudentDeadline, error)
+	SaveSubmission(ctx context.Context, userID, assignmentID, text string, files []string, isLate bool) (string, error)
+	IsAntiCopyEnabled(ctx context.Context, assignmentID string) (bool, error)
+	SaveFingerprint(ctx context.Context, fp *domain.SubmissionFingerprint) error
+	GetAssignmentFingerprints(ctx context.Context, assignmentID, excludeUserID string) ([]*domain.SubmissionFingerprint, error)
+	SaveSimilarityMatches(ctx context.Context, versionID string, matches []*domain.SimilarityMatch) error
 	SetLessonAttendance(ctx context.Context, userID, lessonID, status, recordingURL, teacherComment string) error
 
 	GetTeachersList(ctx context.Context) ([]*domain.TeacherPublicInfo, error)
@@ -26,7 +47,13 @@ type LearningRepository interface {
 	GetTeacherCourses(ctx context.Context, teacherID string) ([]*domain.StudentCoursePreview, error)
 
 	GetTestByID(ctx context.Context, testID string) (*domain.Test, error)
+	StartTestAttempt(ctx context.Context, attempt *domain.TestAttempt) error
+	FinishTestAttempt(ctx context.Context, attempt *domain.TestAttempt) error
+	GetActiveTestAttempt(ctx context.Context, userID, testID string) (*domain.TestAttempt, error)
+	GetTestAttempts(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error)
 	GetProjectByID(ctx context.Context, projectID string) (*domain.Project, error)
+	SaveProjectSubmission(ctx context.Context, sub *domain.ProjectSubmission) error
+	GetProjectSubmissions(ctx context.Context, userID, projectID string) ([]*domain.ProjectSubmission, error)
 	GetTeacherSubstitutions(ctx context.Context, teacherID string) ([]*domain.Lesson, error)
 	GetTeacherUpcomingLessons(ctx context.Context, teacherID string) ([]*domain.Lesson, error)
 	GetTeacherCancelledLessons(ctx context.Context, teacherID string) ([]*domain.Lesson, error)
@@ -109,11 +136,12 @@ func (r *LearningRepoImpl) GetCourseContent(ctx context.Context, courseID, userI
 	queryL := `
 		SELECT 
 			l.id, l.module_id, l.title, l.order_num, l.duration_min,
-			CASE WHEN ula.status IN ('visited', 'trial') OR uas.status = 'accepted' THEN true ELSE false END as is_completed
+			CASE WHEN ar.status = 'ATTENDED' OR uas.status = 'accepted' OR vp.completed_at IS NOT NULL THEN true ELSE false END as is_completed
 		FROM lessons l
-		LEFT JOIN user_lesson_attendance ula ON l.id = ula.lesson_id AND ula.user_id = $2
+		LEFT JOIN attendance_records ar ON l.id = ar.lesson_id AND ar.student_id = $2
 		LEFT JOIN assignments a ON l.id = a.lesson_id
 		LEFT JOIN user_assignments_submission uas ON a.id = uas.assignment_id AND uas.user_id = $2
+		LEFT JOIN lesson_video_progress vp ON l.id = vp.lesson_id AND vp.user_id = $2
 		WHERE l.course_id = $1 AND l.is_published = true
 		ORDER BY l.order_num ASC`
 
@@ -150,6 +178,17 @@ func (r *LearningRepoImpl) GetCourseContent(ctx context.Context, courseID, userI
 	eg2, egCtx2 := errgroup.WithContext(ctx)
 
 	eg2.Go(func() error {
+		results, err := r.getTestResults(egCtx2, userID, "t.lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)", courseID)
+		if err != nil {
+			return err
+		}
+		resultByTest := make(map[string]*domain.TestResult, len(results))
+		for i := range results {
+			if results[i].Attempts > 0 {
+				resultByTest[results[i].TestID] = &results[i]
+			}
+		}
+
 		testsQuery := `SELECT id, lesson_id, title, description, passing_score FROM tests WHERE lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)`
 		rowsT, err := r.db.QueryContext(egCtx2, testsQuery, courseID)
 		if err != nil {
@@ -162,6 +201,7 @@ func (r *LearningRepoImpl) GetCourseContent(ctx context.Context, courseID, userI
 			if err := rowsT.Scan(&t.ID, &lid, &t.Title, &t.Description, &t.PassingScore); err != nil {
 				return err
 			}
+			t.Result = resultByTest[t.ID]
 			if lid.Valid {
 				s := lid.String
 				t.LessonID = &s
@@ -176,8 +216,19 @@ func (r *LearningRepoImpl) GetCourseContent(ctx context.Context, courseID, userI
 	})
 
 	eg2.Go(func() error {
-		projQuery := `SELECT id, lesson_id, title, description, max_score FROM projects WHERE lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)`
-		rowsP, err := r.db.QueryContext(egCtx2, projQuery, courseID)
+		projQuery := `
+			SELECT p.id, p.lesson_id, p.title, p.description, p.max_score,
+			       COALESCE(ps.status::text, ''), COALESCE(ps.version, 0), ps.submitted_at,
+			       COALESCE(ps.grade, 0), COALESCE(ps.teacher_comment, '')
+			FROM projects p
+			LEFT JOIN LATERAL (
+				SELECT status, version, submitted_at, grade, teacher_comment
+				FROM project_submissions
+				WHERE project_id = p.id AND user_id = $2
+				ORDER BY version DESC LIMIT 1
+			) ps ON TRUE
+			WHERE p.lesson_id IN (SELECT id FROM lessons WHERE course_id = $1)`
+		rowsP, err := r.db.QueryContext(egCtx2, projQuery, courseID, userID)
 		if err != nil {
 			return err
 		}
@@ -185,7 +236,8 @@ func (r *LearningRepoImpl) GetCourseContent(ctx context.Context, courseID, userI
 		for rowsP.Next() {
 			var p domain.Project
 			var lid sql.NullString
-			if err := rowsP.Scan(&p.ID, &lid, &p.Title, &p.Description, &p.MaxScore); err != nil {
+			if err := rowsP.Scan(&p.ID, &lid, &p.Title, &p.Description, &p.MaxScore,
+				&p.SubmissionStatus, &p.SubmissionVersion, &p.SubmittedAt, &p.Grade, &p.TeacherComment); err != nil {
 				return err
 			}
 			if lid.Valid {
@@ -241,11 +293,19 @@ func (r *LearningRepoImpl) GetLessonDetail(ctx context.Context, lessonID, userID
 	if err := r.db.QueryRowContext(ctx, attendanceQuery, lessonID, userID).Scan(&attStatus, &recURL, &attComment); err == nil {
 		res.AttendanceStatus = attStatus
 		res.RecordingURL = recURL
-		res.IsCompleted = (attStatus == "visited" || attStatus == "trial")
+	}
+	// Урок засчитывает только посещение, отмеченное сотрудником; самоотметка ученика — справочная.
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM attendance_records WHERE lesson_id = $1 AND student_id = $2 AND status = 'ATTENDED')`,
+		lessonID, userID).Scan(&res.IsCompleted); err != nil {
+		return nil, err
 	}
 
 	homeworkQuery := `
-		SELECT COALESCE(uas.status, ''), COALESCE(uas.grade, 0), COALESCE(uas.teacher_comment, '')
+		SELECT COALESCE(uas.status, ''), COALESCE(uas.grade, 0), COALESCE(uas.teacher_comment, ''), uas.rubric_scores, uas.is_late,
+			(SELECT COUNT(*) FROM submission_comments c
+			 WHERE c.assignment_id = a.id AND c.student_id = $2 AND c.author_id != $2
+			   AND NOT EXISTS (SELECT 1 FROM submission_comment_reads cr WHERE cr.comment_id = c.id AND cr.user_id = $2))
 		FROM assignments a
 		JOIN user_assignments_submission uas ON a.id = uas.assignment_id
 		WHERE a.lesson_id = $1 AND uas.user_id = $2
@@ -254,7 +314,9 @@ func (r *LearningRepoImpl) GetLessonDetail(ctx context.Context, lessonID, userID
 	var hwStatus string
 	var grade int
 	var hwComment string
-	err = r.db.QueryRowContext(ctx, homeworkQuery, lessonID, userID).Scan(&hwStatus, &grade, &hwComment)
+	var unread int
+	var scoresRaw []byte
+	err = r.db.QueryRowContext(ctx, homeworkQuery, lessonID, userID).Scan(&hwStatus, &grade, &hwComment, &scoresRaw, &res.IsLate, &unread)
 
 	if err == nil {
 		res.AssignmentStatus = hwStatus
@@ -262,14 +324,83 @@ func (r *LearningRepoImpl) GetLessonDetail(ctx context.Context, lessonID, userID
 			res.TeacherComment = hwComment
 		}
 		res.Grade = grade
+		res.UnreadComments = unread
 		if !res.IsCompleted && hwStatus == "accepted" {
 			res.IsCompleted = true
 		}
+		if err := json.Unmarshal(scoresRaw, &res.RubricScores); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.Rubric, err = r.getLessonRubric(ctx, lessonID); err != nil {
+		return nil, err
+	}
+	deadline, err := r.getStudentDeadline(ctx, "a.lesson_id = $1", lessonID, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if deadline != nil && deadline.DueAt != nil {
+		res.Deadline = deadline
+	}
+
+	video, err := r.GetVideoProgress(ctx, userID, lessonID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if video != nil {
+		res.VideoProgress = video
+		res.IsCompleted = res.IsCompleted || video.CompletedAt != nil
+	}
+
+	res.TestResults, err = r.getTestResults(ctx, userID, "t.lesson_id = $1", lessonID)
+	if err != nil {
+		return nil, err
 	}
 
 	return res, nil
 }
 
+// getTestResults сводит отправленные попытки ученика по тестам, отобранным условием filter с параметром $1.
+// Тесты без попыток тоже попадают в ответ с Attempts = 0. Правила зачёта — как в usecase.summarizeAttempts.
+func (r *LearningRepoImpl) getTestResults(ctx context.Context, userID, filter, arg string) ([]domain.TestResult, error) {
+	query := `
+		SELECT t.id, t.title, COUNT(ta.id), COALESCE(MAX(ta.percent), 0),
+			COALESCE((ARRAY_AGG(ta.percent ORDER BY ta.submitted_at DESC))[1], 0),
+			COALESCE(CASE WHEN t.scoring = 'last'
+				THEN (ARRAY_AGG(ta.percent ORDER BY ta.submitted_at DESC) FILTER (WHERE ta.status IN ('graded', 'expired')))[1]
+				ELSE MAX(ta.percent) FILTER (WHERE ta.status IN ('graded', 'expired')) END, 0),
+			COALESCE(CASE WHEN t.scoring = 'last'
+				THEN (ARRAY_AGG(ta.passed ORDER BY ta.submitted_at DESC) FILTER (WHERE ta.status IN ('graded', 'expired')))[1]
+				ELSE BOOL_OR(ta.passed) END, FALSE),
+			MAX(ta.submitted_at)
+		FROM tests t
+		LEFT JOIN test_attempts ta ON ta.test_id = t.id AND ta.user_id = $2 AND ta.status <> 'in_progress'
+		WHERE ` + filter + `
+		GROUP BY t.id, t.title, t.scoring, t.created_at
+		ORDER BY t.created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, arg, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []domain.TestResult{}
+	for rows.Next() {
+		var res domain.TestResult
+		var last sql.NullTime
+		if err := rows.Scan(&res.TestID, &res.Title, &res.Attempts, &res.BestPercent, &res.LastPercent, &res.Percent, &res.Passed, &last); err != nil {
+			return nil, err
+		}
+		if last.Valid {
+			res.LastAttemptAt = &last.Time
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
 func (r *LearningRepoImpl) GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
 	var id string
 	query := `SELECT id FROM assignments WHERE lesson_id = $1 LIMIT 1`
@@ -292,21 +423,59 @@ func (r *LearningRepoImpl) EnsureAssignment(ctx context.Context, lessonID, title
 	return err
 }
 
-func (r *LearningRepoImpl) SaveSubmission(ctx context.Context, userID, assignmentID, text string, files []string) error {
-	filesJSON, _ := json.Marshal(files)
+// SaveSubmission добавляет новую версию ДЗ и делает её текущим состоянием в
+// user_assignments_submission (оценка и комментарий прошлой версии остаются в истории).
+// Принятую работу пересдать нельзя: отправка молча игнорируется, как и раньше.
+// Возвращает ID новой версии, для проигнорированной отправки — пустую строку.
+func (r *LearningRepoImpl) SaveSubmission(ctx context.Context, userID, assignmentID, text string, files []string, isLate bool) (string, error) {
+	filesJSON, err := json.Marshal(nonNilFiles(files))
+	if err != nil {
+		return "", err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO user_assignments_submission (user_id, assignment_id, submission_text, submission_files, status, submitted_at)
-		VALUES ($1, $2, $3, $4, 'pending_check', NOW())
+		INSERT INTO user_assignments_submission (user_id, assignment_id, submission_text, submission_files, status, submitted_at, is_late)
+		VALUES ($1, $2, $3, $4, 'pending_check', NOW(), $5)
 		ON CONFLICT (user_id, assignment_id) 
 		DO UPDATE SET 
 			submission_text = EXCLUDED.submission_text, 
 			submission_files = EXCLUDED.submission_files, 
 			status = 'pending_check', 
-			submitted_at = NOW()
+			grade = NULL,
+			teacher_comment = NULL,
+			rubric_scores = '[]',
+			submitted_at = NOW(),
+			is_late = EXCLUDED.is_late
 		WHERE user_assignments_submission.status != 'accepted'
+		RETURNING submitted_at
 	`
-	_, err := r.db.ExecContext(ctx, query, userID, assignmentID, text, filesJSON)
-	return err
+	var submittedAt time.Time
+	err = tx.QueryRowContext(ctx, query, userID, assignmentID, text, filesJSON, isLate).Scan(&submittedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var versionID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO assignment_submission_versions (assignment_id, user_id, version, submission_text, submission_files, submitted_at, is_late)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(version), 0) + 1 FROM assignment_submission_versions WHERE assignment_id = $1 AND user_id = $2), $3, $4, $5, $6)
+		RETURNING id
+	`, assignmentID, userID, text, filesJSON, submittedAt, isLate).Scan(&versionID)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return versionID, nil
 }
 
 func (r *LearningRepoImpl) SetLessonAttendance(ctx context.Context, userID, lessonID, status, recordingURL, teacherComment string) error {
@@ -434,8 +603,12 @@ func (r *LearningRepoImpl) GetTeacherReviews(ctx context.Context, teacherID stri
 }
 func (r *LearningRepoImpl) GetTestByID(ctx context.Context, testID string) (*domain.Test, error) {
 	t := &domain.Test{}
-	err := r.db.QueryRowContext(ctx, "SELECT id, lesson_id, title, description, passing_score, created_at FROM tests WHERE id = $1", testID).
-		Scan(&t.ID, &t.LessonID, &t.Title, &t.Description, &t.PassingScore, &t.CreatedAt)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, lesson_id, title, description, passing_score, answers_reveal,
+			time_limit_min, max_attempts, scoring, shuffle_questions, shuffle_options, pool_size, created_at
+		FROM tests WHERE id = $1`, testID).
+		Scan(&t.ID, &t.LessonID, &t.Title, &t.Description, &t.PassingScore, &t.AnswersReveal,
+			&t.TimeLimitMin, &t.MaxAttempts, &t.Scoring, &t.ShuffleQuestions, &t.ShuffleOptions, &t.PoolSize, &t.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -443,8 +616,116 @@ func (r *LearningRepoImpl) GetTestByID(ctx context.Context, testID string) (*dom
 	return t, nil
 }
 
+// StartTestAttempt создаёт попытку в статусе in_progress. Вторую незавершённую попытку
+// не даст создать уникальный индекс idx_test_attempts_in_progress.
+func (r *LearningRepoImpl) StartTestAttempt(ctx context.Context, attempt *domain.TestAttempt) error {
+	questionsJSON, err := json.Marshal(attempt.QuestionIDs)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO test_attempts (test_id, user_id, question_ids, seed, status, started_at, deadline_at)
+		VALUES ($1, $2, $3, $4, 'in_progress', $5, $6)
+		RETURNING id
+	`
+	attempt.Status = domain.TestAttemptInProgress
+	err = r.db.QueryRowContext(ctx, query,
+		attempt.TestID, attempt.UserID, questionsJSON, attempt.Seed, attempt.StartedAt, attempt.DeadlineAt,
+	).Scan(&attempt.ID)
+	// Уникальный индекс допускает одну попытку in_progress на ученика и тест.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return domain.ErrTestAttemptActive
+	}
+	return err
+}
+
+// FinishTestAttempt записывает результат; завершить можно только попытку в статусе in_progress.
+func (r *LearningRepoImpl) FinishTestAttempt(ctx context.Context, attempt *domain.TestAttempt) error {
+	answersJSON, err := json.Marshal(attempt.Answers)
+	if err != nil {
+		return err
+	}
+	resultsJSON, err := json.Marshal(attempt.Results)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE test_attempts
+		SET answers = $1, results = $2, score = $3, max_score = $4, percent = $5, passed = $6, status = $7, submitted_at = $8
+		WHERE id = $9 AND status = 'in_progress'
+	`
+	res, err := r.db.ExecContext(ctx, query,
+		answersJSON, resultsJSON, attempt.Score, attempt.MaxScore, attempt.Percent, attempt.Passed, attempt.Status, attempt.SubmittedAt, attempt.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const testAttemptColumns = `id, test_id, user_id, question_ids, seed, answers, results, score, max_score, percent,
+	passed, status, started_at, deadline_at, submitted_at, graded_at`
+
+func scanTestAttempt(row interface{ Scan(...any) error }) (*domain.TestAttempt, error) {
+	a := &domain.TestAttempt{}
+	var questionsRaw, answersRaw, resultsRaw []byte
+	err := row.Scan(&a.ID, &a.TestID, &a.UserID, &questionsRaw, &a.Seed, &answersRaw, &resultsRaw, &a.Score, &a.MaxScore, &a.Percent,
+		&a.Passed, &a.Status, &a.StartedAt, &a.DeadlineAt, &a.SubmittedAt, &a.GradedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(questionsRaw, &a.QuestionIDs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(answersRaw, &a.Answers); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(resultsRaw, &a.Results); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetActiveTestAttempt возвращает незавершённую попытку или nil, если её нет.
+func (r *LearningRepoImpl) GetActiveTestAttempt(ctx context.Context, userID, testID string) (*domain.TestAttempt, error) {
+	query := `SELECT ` + testAttemptColumns + ` FROM test_attempts WHERE user_id = $1 AND test_id = $2 AND status = 'in_progress'`
+	a, err := scanTestAttempt(r.db.QueryRowContext(ctx, query, userID, testID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return a, err
+}
+
+// GetTestAttempts — все попытки ученика, начиная с последней начатой.
+func (r *LearningRepoImpl) GetTestAttempts(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error) {
+	query := `SELECT ` + testAttemptColumns + ` FROM test_attempts WHERE user_id = $1 AND test_id = $2 ORDER BY started_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*domain.TestAttempt{}
+	for rows.Next() {
+		a, err := scanTestAttempt(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
 func (r *LearningRepoImpl) getTestQuestions(ctx context.Context, testID string) []domain.TestQuestion {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, question, options, correct_answer, points FROM test_questions WHERE test_id = $1 ORDER BY created_at ASC", testID)
+	query := `
+		SELECT id, type, question, options, match_options, correct_answer, correct_answers, tolerance, partial_credit, points
+		FROM test_questions WHERE test_id = $1 ORDER BY position ASC, created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, testID)
 	if err != nil {
 		return []domain.TestQuestion{}
 	}
@@ -452,8 +733,8 @@ func (r *LearningRepoImpl) getTestQuestions(ctx context.Context, testID string)
 	var questions []domain.TestQuestion
 	for rows.Next() {
 		var q domain.TestQuestion
-		var optionsRaw []byte
-		if err := rows.Scan(&q.ID, &q.Question, &optionsRaw, &q.CorrectAnswer, &q.Points); err != nil {
+		var optionsRaw, matchRaw, correctRaw []byte
+		if err := rows.Scan(&q.ID, &q.Type, &q.Question, &optionsRaw, &matchRaw, &q.CorrectAnswer, &correctRaw, &q.Tolerance, &q.PartialCredit, &q.Points); err != nil {
 			continue
 		}
 		if len(optionsRaw) > 0 {
@@ -462,6 +743,8 @@ func (r *LearningRepoImpl) getTestQuestions(ctx context.Context, testID string)
 		if q.Options == nil {
 			q.Options = []string{}
 		}
+		json.Unmarshal(matchRaw, &q.MatchOptions)
+		json.Unmarshal(correctRaw, &q.CorrectAnswers)
 		questions = append(questions, q)
 	}
 	return questions
@@ -593,10 +876,6 @@ func (r *LearningRepoImpl) GetLessonOrderNum(ctx context.Context, lessonID strin
 	return orderNum, err
 }
 
-func (r *LearningRepoImpl) GetTeacherCertificates(ctx context.Context, teacherID string) ([]*domain.TeacherCertificate, error) {
-	return []*domain.TeacherCertificate{}, nil
-}
-
 func (r *LearningRepoImpl) GetTeacherCancelledLessons(ctx context.Context, teacherID string) ([]*domain.Lesson, error) {
 	query := `
 		SELECT id, course_id, module_id, teacher_id, title, lesson_time, duration_min, order_num,
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+)
+
+// getLessonRubric — критерии ДЗ урока; nil, если рубрики нет.
+func (r *LearningRepoImpl) getLessonRubric(ctx context.Context, lessonID string) (*domain.Rubric, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rc.assignment_id, rc.id, rc.title, rc.description, rc.max_points
+		FROM rubric_criteria rc
+		JOIN assignments a ON a.id = rc.assignment_id
+		WHERE a.lesson_id = $1
+		ORDER BY rc.position ASC
+	`, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rubric *domain.Rubric
+	for rows.Next() {
+		var assignmentID string
+		var c domain.RubricCriterion
+		if err := rows.Scan(&assignmentID, &c.ID, &c.Title, &c.Description, &c.MaxPoints); err != nil {
+			return nil, err
+		}
+		if rubric == nil {
+			rubric = &domain.Rubric{AssignmentID: assignmentID}
+		}
+		rubric.Criteria = append(rubric.Criteria, c)
+	}
+	return rubric, rows.Err()
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+
+	"lms_backend/internal/domain"
+)
+
+// IsAntiCopyEnabled — включена ли защита от копирования в курсе задания. Курс берётся из урока
+// (у урока может не быть модуля); если курс не найден, защита считается выключенной.
+func (r *LearningRepoImpl) IsAntiCopyEnabled(ctx context.Context, assignmentID string) (bool, error) {
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT c.is_anti_copy_enabled
+		FROM assignments a
+		JOIN lessons l ON a.lesson_id = l.id
+		JOIN courses c ON l.course_id = c.id
+		WHERE a.id = $1
+	`, assignmentID).Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return enabled, err
+}
+
+func (r *LearningRepoImpl) SaveFingerprint(ctx context.Context, fp *domain.SubmissionFingerprint) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO submission_fingerprints (version_id, content, signature)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (version_id) DO UPDATE SET content = EXCLUDED.content, signature = EXCLUDED.signature
+	`, fp.VersionID, fp.Content, pq.Array([]int64(fp.Signature)))
+	return err
+}
+
+// GetAssignmentFingerprints — отпечатки последних версий задания у всех учеников, кроме excludeUserID.
+func (r *LearningRepoImpl) GetAssignmentFingerprints(ctx context.Context, assignmentID, excludeUserID string) ([]*domain.SubmissionFingerprint, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (v.user_id) v.id, v.user_id, f.content, f.signature
+		FROM assignment_submission_versions v
+		JOIN submission_fingerprints f ON f.version_id = v.id
+		WHERE v.assignment_id = $1 AND v.user_id != $2
+		ORDER BY v.user_id, v.version DESC
+	`, assignmentID, excludeUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.SubmissionFingerprint
+	for rows.Next() {
+		fp := &domain.SubmissionFingerprint{}
+		var sig []int64
+		if err := rows.Scan(&fp.VersionID, &fp.UserID, &fp.Content, pq.Array(&sig)); err != nil {
+			return nil, err
+		}
+		fp.Signature = sig
+		result = append(result, fp)
+	}
+	return result, rows.Err()
+}
+
+// SaveSimilarityMatches связывает версию versionID с похожими версиями других учеников (VersionID и Score матча).
+func (r *LearningRepoImpl) SaveSimilarityMatches(ctx context.Context, versionID string, matches []*domain.SimilarityMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, m := range matches {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO submission_similarities (version_id, matched_version_id, score)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (version_id, matched_version_id) DO UPDATE SET score = EXCLUDED.score
+		`, versionID, m.VersionID, m.Score); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
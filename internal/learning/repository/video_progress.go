@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"lms_backend/internal/domain"
+)
+
+// GetLessonVideo — ссылка на видео урока (пустая, если видео нет) и длительность урока в минутах.
+func (r *LearningRepoImpl) GetLessonVideo(ctx context.Context, lessonID string) (string, int, error) {
+	var videoURL string
+	var durationMin int
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(video_url, ''), duration_min FROM lessons WHERE id = $1`, lessonID).Scan(&videoURL, &durationMin)
+	return videoURL, durationMin, err
+}
+
+// GetVideoProgress — просмотр видео урока учеником; если просмотра не было — sql.ErrNoRows.
+func (r *LearningRepoImpl) GetVideoProgress(ctx context.Context, userID, lessonID string) (*domain.VideoProgress, error) {
+	v := &domain.VideoProgress{LessonID: lessonID}
+	var rangesRaw []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT position_sec, duration_sec, watched_ranges, watched_sec, completed_at, COALESCE(updated_at, 'epoch')
+		FROM lesson_video_progress
+		WHERE user_id = $1 AND lesson_id = $2
+	`, userID, lessonID).Scan(&v.PositionSec, &v.DurationSec, &rangesRaw, &v.WatchedSec, &v.CompletedAt, &v.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rangesRaw, &v.Ranges); err != nil {
+		return nil, err
+	}
+	if v.DurationSec > 0 {
+		v.WatchedPercent = min(v.WatchedSec*100/v.DurationSec, 100)
+	}
+	return v, nil
+}
+
+func (r *LearningRepoImpl) SaveVideoProgress(ctx context.Context, userID string, v *domain.VideoProgress) error {
+	rangesRaw, err := json.Marshal(v.Ranges)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO lesson_video_progress (user_id, lesson_id, position_sec, duration_sec, watched_ranges, watched_sec, completed_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, lesson_id) DO UPDATE SET
+			position_sec = EXCLUDED.position_sec,
+			duration_sec = EXCLUDED.duration_sec,
+			watched_ranges = EXCLUDED.watched_ranges,
+			watched_sec = EXCLUDED.watched_sec,
+			completed_at = COALESCE(lesson_video_progress.completed_at, EXCLUDED.completed_at),
+			updated_at = EXCLUDED.updated_at
+	`, userID, v.LessonID, v.PositionSec, v.DurationSec, rangesRaw, v.WatchedSec, v.CompletedAt, v.UpdatedAt)
+	return err
+}
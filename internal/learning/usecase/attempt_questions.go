@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"sort"
+
+	"lms_backend/internal/domain"
+)
+
+func newAttemptSeed() int64 {
+	return rand.Int64()
+}
+
+// drawQuestions выбирает вопросы попытки: PoolSize случайных из всех (или все),
+// при ShuffleQuestions — в случайном порядке, иначе в порядке теста.
+// Один и тот же seed всегда даёт один и тот же набор.
+func drawQuestions(test *domain.Test, seed int64) []string {
+	n := len(test.Questions)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	if test.PoolSize > 0 && test.PoolSize < n || test.ShuffleQuestions {
+		rng := rand.New(rand.NewPCG(uint64(seed), 0))
+		rng.Shuffle(n, func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+	}
+	if test.PoolSize > 0 && test.PoolSize < n {
+		idx = idx[:test.PoolSize]
+	}
+	if !test.ShuffleQuestions {
+		sort.Ints(idx)
+	}
+
+	ids := make([]string, 0, len(idx))
+	for _, i := range idx {
+		ids = append(ids, test.Questions[i].ID)
+	}
+	return ids
+}
+
+// attemptQuestions возвращает вопросы попытки в её порядке. Пустой ids означает все вопросы
+// (попытки, начатые до появления пулов). Удалённые из теста вопросы пропускаются.
+func attemptQuestions(test *domain.Test, ids []string) []domain.TestQuestion {
+	if len(ids) == 0 {
+		return test.Questions
+	}
+	byID := make(map[string]domain.TestQuestion, len(test.Questions))
+	for _, q := range test.Questions {
+		byID[q.ID] = q
+	}
+	questions := make([]domain.TestQuestion, 0, len(ids))
+	for _, id := range ids {
+		if q, ok := byID[id]; ok {
+			questions = append(questions, q)
+		}
+	}
+	return questions
+}
+
+// displayQuestion — вопрос в том виде, в каком его видит ученик во время попытки. Варианты вопроса
+// на порядок перемешиваются всегда, независимо от ShuffleOptions: иначе ученик видел бы их
+// в правильном порядке, в котором их обычно вводит автор теста.
+func displayQuestion(test *domain.Test, q domain.TestQuestion, seed int64) domain.TestQuestion {
+	if test.ShuffleOptions || q.Type == domain.QuestionOrdering {
+		return shuffleOptions(q, seed)
+	}
+	return q
+}
+
+func testSeed(testID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(testID))
+	return int64(h.Sum64())
+}
+
+// shuffleOptions перемешивает варианты вопроса детерминированно по seed попытки и ID вопроса.
+// У вопроса на порядок перемешанные варианты никогда не совпадают с правильным ответом.
+func shuffleOptions(q domain.TestQuestion, seed int64) domain.TestQuestion {
+	h := fnv.New64a()
+	h.Write([]byte(q.ID))
+	rng := rand.New(rand.NewPCG(uint64(seed), h.Sum64()))
+
+	shuffle := func(src []string) []string {
+		out := append([]string(nil), src...)
+		rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+		return out
+	}
+	switch q.Type {
+	case domain.QuestionMatching:
+		q.MatchOptions = shuffle(q.MatchOptions)
+	case domain.QuestionOrdering:
+		q.Options = shuffle(q.Options)
+		if len(q.Options) > 1 && gradePositional(q.CorrectAnswers, q.Options) == 1 {
+			q.Options = append(q.Options[1:], q.Options[0])
+		}
+	default:
+		q.Options = shuffle(q.Options)
+	}
+	return q
+}
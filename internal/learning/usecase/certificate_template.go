@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"lms_backend/internal/domain"
+	"lms_backend/pkg/pdf"
+)
+
+// CertificateTemplate — макет PDF-сертификата, задаётся JSON-файлом (CERTIFICATE_TEMPLATE_PATH).
+// Text элементов — шаблоны text/template над domain.Certificate: {{.StudentName}}, {{.CourseTitle}},
+// {{.Code}}, {{.IssuedAt.Format "02.01.2006"}}. Размеры в пунктах, координаты — от левого нижнего угла.
+// Без FontPath используется Helvetica, которая не умеет кириллицу; для русских имён нужен TTF-шрифт.
+type CertificateTemplate struct {
+	PageWidth  float64              `json:"page_width"`
+	PageHeight float64              `json:"page_height"`
+	FontPath   string               `json:"font_path"`
+	Frame      float64              `json:"frame"`
+	Elements   []CertificateElement `json:"elements"`
+
+	font  *pdf.TrueTypeFont
+	texts []*template.Template
+}
+
+type CertificateElement struct {
+	Text  string    `json:"text"`
+	X     float64   `json:"x"`
+	Y     float64   `json:"y"`
+	Size  float64   `json:"size"`
+	Align pdf.Align `json:"align"`
+}
+
+// DefaultCertificateTemplate — альбомный A4 с латинским текстом (шрифт не требуется).
+func DefaultCertificateTemplate() *CertificateTemplate {
+	t := &CertificateTemplate{
+		PageWidth:  842,
+		PageHeight: 595,
+		Frame:      30,
+		Elements: []CertificateElement{
+			{Text: "CERTIFICATE OF COMPLETION", X: 421, Y: 440, Size: 32, Align: pdf.AlignCenter},
+			{Text: "This certifies that", X: 421, Y: 380, Size: 14, Align: pdf.AlignCenter},
+			{Text: "{{.StudentName}}", X: 421, Y: 335, Size: 28, Align: pdf.AlignCenter},
+			{Text: "has successfully completed the course", X: 421, Y: 295, Size: 14, Align: pdf.AlignCenter},
+			{Text: "{{.CourseTitle}}", X: 421, Y: 255, Size: 22, Align: pdf.AlignCenter},
+			{Text: `Issued {{.IssuedAt.Format "02.01.2006"}}`, X: 80, Y: 90, Size: 12, Align: pdf.AlignLeft},
+			{Text: "Verification code: {{.Code}}", X: 762, Y: 90, Size: 12, Align: pdf.AlignRight},
+		},
+	}
+	if err := t.compile(); err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadCertificateTemplate читает макет из JSON-файла; пустой путь — макет по умолчанию.
+func LoadCertificateTemplate(path string) (*CertificateTemplate, error) {
+	if path == "" {
+		return DefaultCertificateTemplate(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &CertificateTemplate{}
+	if err := json.Unmarshal(raw, t); err != nil {
+		return nil, fmt.Errorf("certificate template %s: %w", path, err)
+	}
+	if err := t.compile(); err != nil {
+		return nil, fmt.Errorf("certificate template %s: %w", path, err)
+	}
+	return t, nil
+}
+
+func (t *CertificateTemplate) compile() error {
+	if t.PageWidth <= 0 || t.PageHeight <= 0 {
+		return fmt.Errorf("page_width and page_height must be > 0")
+	}
+	if t.FontPath != "" {
+		data, err := os.ReadFile(t.FontPath)
+		if err != nil {
+			return err
+		}
+		if t.font, err = pdf.ParseTrueType(data); err != nil {
+			return err
+		}
+	}
+	t.texts = make([]*template.Template, len(t.Elements))
+	for i, el := range t.Elements {
+		tmpl, err := template.New(fmt.Sprintf("element%d", i)).Option("missingkey=error").Parse(el.Text)
+		if err != nil {
+			return err
+		}
+		t.texts[i] = tmpl
+	}
+	return nil
+}
+
+// Render — PDF сертификата.
+func (t *CertificateTemplate) Render(c *domain.Certificate) ([]byte, error) {
+	doc := pdf.New(t.PageWidth, t.PageHeight, t.font)
+	if t.Frame > 0 {
+		doc.AddRect(pdf.Rect{X: t.Frame, Y: t.Frame, Width: t.PageWidth - 2*t.Frame, Height: t.PageHeight - 2*t.Frame, LineWidth: 2})
+	}
+	for i, el := range t.Elements {
+		var text strings.Builder
+		if err := t.texts[i].Execute(&text, c); err != nil {
+			return nil, err
+		}
+		doc.AddText(pdf.Text{X: el.X, Y: el.Y, Size: el.Size, Align: el.Align, Value: text.String()})
+	}
+	return doc.Bytes()
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+// Действия с сертификатами в audit_logs (entity_type CERTIFICATE).
+const (
+	auditCertificateIssued  = "CERTIFICATE_ISSUED"
+	auditCertificateRevoked = "CERTIFICATE_REVOKED"
+)
+
+// CompleteCourse проверяет, завершил ли ученик курс (domain.CourseCompletion), и выдаёт сертификат,
+// если его ещё нет. Возвращает выданный сертификат; nil — курс не завершён или ученик на него не записан.
+func (uc *LearningUseCase) CompleteCourse(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+	cert, err := uc.repo.GetCertificate(ctx, userID, courseID)
+	if err == nil {
+		return cert, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	course, err := uc.repo.GetCourseRules(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	completion, err := uc.repo.GetCourseCompletion(ctx, courseID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !completion.Completed(course) {
+		return nil, nil
+	}
+	return uc.issueCertificate(ctx, userID, courseID)
+}
+
+// issueCertificate генерирует PDF по макету, загружает его в хранилище и сохраняет сертификат.
+// Если сертификат успели выдать параллельно, возвращается он.
+func (uc *LearningUseCase) issueCertificate(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+	code, err := domain.NewCertificateCode()
+	if err != nil {
+		return nil, err
+	}
+	studentName, courseTitle, err := uc.repo.GetCertificateHolder(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	cert := &domain.Certificate{
+		Code:        code,
+		UserID:      userID,
+		CourseID:    courseID,
+		StudentName: studentName,
+		CourseTitle: courseTitle,
+		IssuedAt:    time.Now().UTC(),
+	}
+	data, err := uc.certificates.Render(cert)
+	if err != nil {
+		return nil, err
+	}
+	s3Ctx, cancel := s3Context(ctx)
+	defer cancel()
+	key, err := uc.s3Storage.UploadFile(s3Ctx, bytes.NewReader(data), "certificates/"+code+".pdf", int64(len(data)), "application/pdf")
+	if err != nil {
+		return nil, err
+	}
+	cert.PDFURL, _ = uc.s3Storage.GetPublicURL(ctx, key)
+
+	err = uc.repo.CreateCertificate(ctx, cert)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uc.repo.GetCertificate(ctx, userID, courseID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Выдача автоматическая, поэтому в журнале нет автора действия.
+	uc.logCertificateAction(ctx, nil, auditCertificateIssued, cert.ID, nil,
+		map[string]interface{}{"code": cert.Code, "user_id": userID, "course_id": courseID}, nil, nil)
+	return cert, nil
+}
+
+// checkCourseCompletion вызывается после событий ученика, которые могут завершить курс.
+// Ошибка выдачи сертификата не отменяет само событие, поэтому только логируется.
+func (uc *LearningUseCase) checkCourseCompletion(ctx context.Context, userID, courseID string) {
+	if _, err := uc.CompleteCourse(ctx, userID, courseID); err != nil {
+		slog.Error("course completion", slog.String("user_id", userID), slog.String("course_id", courseID), slog.String("error", err.Error()))
+	}
+}
+
+// CheckLessonCourseCompletion — то же для событий вне модуля обучения, где известен только урок
+// (отметка посещения сотрудником).
+func (uc *LearningUseCase) CheckLessonCourseCompletion(ctx context.Context, userID, lessonID string) {
+	courseID, err := uc.repo.GetLessonCourseID(ctx, lessonID)
+	if err != nil {
+		slog.Error("course completion", slog.String("user_id", userID), slog.String("lesson_id", lessonID), slog.String("error", err.Error()))
+		return
+	}
+	uc.checkCourseCompletion(ctx, userID, courseID)
+}
+
+func (uc *LearningUseCase) GetMyCertificates(ctx context.Context, userID string) ([]*domain.Certificate, error) {
+	return uc.repo.GetUserCertificates(ctx, userID)
+}
+
+// RevokeCertificate отзывает сертификат по коду. Отозванный сертификат при проверке недействителен
+// и не выдаётся заново при следующей проверке завершения курса.
+func (uc *LearningUseCase) RevokeCertificate(ctx context.Context, adminID, code, reason string, ipAddress, userAgent *string) (*domain.Certificate, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.ErrRevokeReasonRequired
+	}
+	cert, err := uc.repo.GetCertificateByCode(ctx, domain.NormalizeCertificateCode(code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cert.Valid() {
+		return nil, domain.ErrCertificateRevoked
+	}
+	err = uc.repo.RevokeCertificate(ctx, cert, adminID, reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCertificateRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.logCertificateAction(ctx, &adminID, auditCertificateRevoked, cert.ID,
+		map[string]interface{}{"code": cert.Code, "revoked": false},
+		map[string]interface{}{"code": cert.Code, "revoked": true, "reason": reason}, ipAddress, userAgent)
+	return cert, nil
+}
+
+// logCertificateAction пишет действие с сертификатом в audit_logs; сбой журнала не отменяет действие.
+func (uc *LearningUseCase) logCertificateAction(ctx context.Context, actorID *string, action, certID string, oldValues, newValues interface{}, ipAddress, userAgent *string) {
+	if uc.audit == nil {
+		return
+	}
+	if err := uc.audit.LogAction(ctx, actorID, action, "CERTIFICATE", certID, oldValues, newValues, ipAddress, userAgent); err != nil {
+		slog.Warn("failed to write audit log", slog.String("action", action), slog.String("error", err.Error()))
+	}
+}
+
+// VerifyCertificate — публичная проверка сертификата по коду.
+func (uc *LearningUseCase) VerifyCertificate(ctx context.Context, code string) (*domain.CertificateVerification, error) {
+	cert, err := uc.repo.GetCertificateByCode(ctx, domain.NormalizeCertificateCode(code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CertificateVerification{
+		Code:        cert.Code,
+		StudentName: cert.StudentName,
+		CourseTitle: cert.CourseTitle,
+		IssuedAt:    cert.IssuedAt,
+		Valid:       cert.Valid(),
+		RevokedAt:   cert.RevokedAt,
+	}, nil
+}
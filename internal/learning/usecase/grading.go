@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"lms_backend/internal/domain"
+)
+
+// gradeQuestion возвращает долю баллов (0..1) за ответ и признак ручной проверки.
+// Для вопросов без частичного зачёта любая доля меньше 1 превращается в 0.
+func gradeQuestion(q domain.TestQuestion, a *domain.TestAnswer) domain.QuestionResult {
+	res := domain.QuestionResult{QuestionID: q.ID, MaxScore: q.Points}
+	if a == nil {
+		return res
+	}
+
+	var credit float64
+	switch q.Type {
+	case domain.QuestionMultiple:
+		credit = gradeMultiple(q.CorrectAnswers, a.Answers)
+	case domain.QuestionNumeric:
+		credit = gradeNumeric(q.CorrectAnswer, q.Tolerance, a.Answer)
+	case domain.QuestionOrdering, domain.QuestionMatching:
+		credit = gradePositional(q.CorrectAnswers, a.Answers)
+	case domain.QuestionText:
+		res.NeedsReview = strings.TrimSpace(a.Answer) != ""
+		return res
+	default:
+		if sameAnswer(a.Answer, q.CorrectAnswer) {
+			credit = 1
+		}
+	}
+
+	if !q.PartialCredit && credit < 1 {
+		credit = 0
+	}
+	res.Score = math.Round(credit*float64(q.Points)*100) / 100
+	return res
+}
+
+func sameAnswer(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// gradeMultiple: (верно отмеченные − ошибочно отмеченные) / число верных, но не меньше нуля.
+// Так отметка всех вариантов подряд не приносит баллов.
+func gradeMultiple(correct, given []string) float64 {
+	if len(correct) == 0 {
+		return 0
+	}
+	seen := make(map[string]bool, len(given))
+	hits, misses := 0, 0
+	for _, g := range given {
+		key := strings.ToLower(strings.TrimSpace(g))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		matched := false
+		for _, c := range correct {
+			if sameAnswer(g, c) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	return math.Max(0, float64(hits-misses)/float64(len(correct)))
+}
+
+func gradeNumeric(correct string, tolerance float64, given string) float64 {
+	want, err := parseNumber(correct)
+	if err != nil {
+		return 0
+	}
+	got, err := parseNumber(given)
+	if err != nil {
+		return 0
+	}
+	// небольшой запас на погрешность float, чтобы 0.3 при допуске 0.1 от 0.2 засчитывался
+	if math.Abs(got-want) <= tolerance+1e-9 {
+		return 1
+	}
+	return 0
+}
+
+// parseNumber принимает и десятичную запятую.
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+}
+
+// gradePositional — доля позиций, совпавших с ключом (порядок для ordering, пары для matching).
+func gradePositional(correct, given []string) float64 {
+	if len(correct) == 0 {
+		return 0
+	}
+	hits := 0
+	for i, c := range correct {
+		if i < len(given) && sameAnswer(given[i], c) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(correct))
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lms_backend/internal/domain"
+)
+
+// MaxTextAnswerLength — предел текстового ответа ДЗ и проекта в символах. Версии ответов
+// сравниваются построчно (review DiffSubmission), поэтому размер текста ограничен.
+const MaxTextAnswerLength = 100_000
+
+var (
+	ErrEmptySubmission        = errors.New("submission must contain text or files")
+	ErrProjectAlreadyAccepted = errors.New("project is already accepted")
+	ErrTextAnswerTooLong      = fmt.Errorf("text answer must not exceed %d characters", MaxTextAnswerLength)
+)
+
+func checkTextAnswer(text string) error {
+	if utf8.RuneCountInString(text) > MaxTextAnswerLength {
+		return ErrTextAnswerTooLong
+	}
+	return nil
+}
+
+type SubmitProjectInput struct {
+	ProjectID   string
+	UserID      string
+	TextAnswer  string
+	FileHeaders []*multipart.FileHeader
+}
+
+// SubmitProject сохраняет новую версию сдачи проекта. Принятый проект пересдать нельзя.
+// Ошибка репозитория (в т.ч. sql.ErrNoRows для несуществующего проекта) возвращается как есть.
+func (uc *LearningUseCase) SubmitProject(ctx context.Context, input SubmitProjectInput) (*domain.ProjectSubmission, error) {
+	if strings.TrimSpace(input.TextAnswer) == "" && len(input.FileHeaders) == 0 {
+		return nil, ErrEmptySubmission
+	}
+	if err := checkTextAnswer(input.TextAnswer); err != nil {
+		return nil, err
+	}
+	project, err := uc.repo.GetProjectByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.LessonID != nil {
+		if _, err := uc.unlockedLessonCourse(ctx, *project.LessonID, input.UserID); err != nil {
+			return nil, err
+		}
+	}
+	prev, err := uc.repo.GetProjectSubmissions(ctx, input.UserID, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(prev) > 0 && prev[0].Status == "accepted" {
+		return nil, ErrProjectAlreadyAccepted
+	}
+
+	// У каждой версии свои файлы, поэтому в ключ входит момент отправки.
+	stamp := time.Now().UnixNano()
+	sub := &domain.ProjectSubmission{
+		ProjectID: project.ID,
+		UserID:    input.UserID,
+		Text:      input.TextAnswer,
+		Files:     []string{},
+		MaxScore:  project.MaxScore,
+	}
+	for _, fh := range input.FileHeaders {
+		key := fmt.Sprintf("project-submissions/%s_%s_%d_%s", input.UserID, project.ID, stamp, fh.Filename)
+		url, err := uc.uploadSubmissionFile(ctx, fh, key)
+		if err != nil {
+			return nil, err
+		}
+		sub.Files = append(sub.Files, url)
+	}
+	if err := uc.repo.SaveProjectSubmission(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (uc *LearningUseCase) GetProjectSubmissions(ctx context.Context, userID, projectID string) ([]*domain.ProjectSubmission, error) {
+	return uc.repo.GetProjectSubmissions(ctx, userID, projectID)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"lms_backend/internal/domain"
+)
+
+// maxAttachmentText — сколько байт текстового вложения учитывается при проверке на списывание.
+const maxAttachmentText = 1 << 20
+
+var textAttachmentExts = map[string]bool{
+	".txt": true, ".md": true, ".csv": true, ".json": true, ".xml": true, ".yaml": true, ".yml": true,
+	".html": true, ".css": true, ".sql": true, ".py": true, ".ipynb": true, ".go": true, ".js": true,
+	".ts": true, ".java": true, ".kt": true, ".c": true, ".h": true, ".cpp": true, ".hpp": true,
+	".cs": true, ".php": true, ".rb": true, ".swift": true, ".rs": true, ".sh": true,
+}
+
+func isTextAttachment(fh *multipart.FileHeader) bool {
+	return strings.HasPrefix(fh.Header.Get("Content-Type"), "text/") ||
+		textAttachmentExts[strings.ToLower(filepath.Ext(fh.Filename))]
+}
+
+// submissionContent — текст ответа и текстовых вложений (в UTF-8), по которому строится отпечаток работы.
+func submissionContent(text string, files []*multipart.FileHeader) string {
+	var parts []string
+	if strings.TrimSpace(text) != "" {
+		parts = append(parts, text)
+	}
+	for _, fh := range files {
+		if !isTextAttachment(fh) {
+			continue
+		}
+		f, err := fh.Open()
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(f, maxAttachmentText))
+		f.Close()
+		if err != nil || !utf8.Valid(data) {
+			continue
+		}
+		parts = append(parts, string(data))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// checkSimilarity сохраняет отпечаток новой версии и отмечает последние версии других учеников,
+// схожесть с которыми не ниже domain.SimilarityThreshold.
+func (uc *LearningUseCase) checkSimilarity(ctx context.Context, assignmentID, versionID, userID, content string) error {
+	fp := &domain.SubmissionFingerprint{VersionID: versionID, UserID: userID, Content: content, Signature: domain.Fingerprint(content)}
+	if len(fp.Signature) == 0 {
+		return nil
+	}
+	if err := uc.repo.SaveFingerprint(ctx, fp); err != nil {
+		return err
+	}
+	others, err := uc.repo.GetAssignmentFingerprints(ctx, assignmentID, userID)
+	if err != nil {
+		return err
+	}
+	var matches []*domain.SimilarityMatch
+	for _, other := range others {
+		if score := domain.Similarity(fp.Signature, other.Signature); score >= domain.SimilarityThreshold {
+			matches = append(matches, &domain.SimilarityMatch{VersionID: other.VersionID, StudentID: other.UserID, Score: score})
+		}
+	}
+	return uc.repo.SaveSimilarityMatches(ctx, versionID, matches)
+}
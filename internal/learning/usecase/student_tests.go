@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+// testSubmitGrace — запас на сетевую задержку при отправке теста с лимитом времени.
+const testSubmitGrace = 30 * time.Second
+
+var (
+	ErrTestHasNoQuestions = errors.New("test has no questions")
+	ErrNoAttemptsLeft     = errors.New("no attempts left for this test")
+	ErrAttemptNotStarted  = errors.New("test attempt is not started")
+	ErrTimeLimitExceeded  = errors.New("test time limit exceeded")
+)
+
+// GetTest отдаёт ученику проекцию теста. Правильные ответы попадают в неё,
+// только если это разрешает answers_reveal теста с учётом попыток ученика.
+// Просроченная незавершённая попытка при этом закрывается как expired.
+func (uc *LearningUseCase) GetTest(ctx context.Context, testID, userID string) (*domain.StudentTest, error) {
+	test, err := uc.repo.GetTestByID(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := uc.repo.GetTestAttempts(ctx, userID, testID)
+	if err != nil {
+		return nil, err
+	}
+	active, err := uc.closeLateAttempt(ctx, activeAttempt(attempts), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return studentTestView(test, attempts, active), nil
+}
+
+// StartTest начинает попытку: фиксирует время старта, дедлайн и набор вопросов.
+// Если попытка уже идёт, возвращает её же — повторный вызов не тратит попытку.
+func (uc *LearningUseCase) StartTest(ctx context.Context, testID, userID string) (*domain.StudentTest, error) {
+	test, err := uc.repo.GetTestByID(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	if len(test.Questions) == 0 {
+		return nil, ErrTestHasNoQuestions
+	}
+	if err := uc.checkTestLesson(ctx, test, userID); err != nil {
+		return nil, err
+	}
+	attempts, err := uc.repo.GetTestAttempts(ctx, userID, testID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	active, err := uc.closeLateAttempt(ctx, activeAttempt(attempts), now)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		active, err = uc.startAttempt(ctx, test, userID, len(attempts), now)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append([]*domain.TestAttempt{active}, attempts...)
+	}
+	return studentTestView(test, attempts, active), nil
+}
+
+func (uc *LearningUseCase) startAttempt(ctx context.Context, test *domain.Test, userID string, used int, now time.Time) (*domain.TestAttempt, error) {
+	if test.MaxAttempts > 0 && used >= test.MaxAttempts {
+		return nil, ErrNoAttemptsLeft
+	}
+	attempt := &domain.TestAttempt{
+		TestID:    test.ID,
+		UserID:    userID,
+		Seed:      newAttemptSeed(),
+		StartedAt: now,
+	}
+	attempt.QuestionIDs = drawQuestions(test, attempt.Seed)
+	if test.TimeLimitMin > 0 {
+		deadline := now.Add(time.Duration(test.TimeLimitMin) * time.Minute)
+		attempt.DeadlineAt = &deadline
+	}
+	err := uc.repo.StartTestAttempt(ctx, attempt)
+	if errors.Is(err, domain.ErrTestAttemptActive) {
+		// Попытку только что начал параллельный запрос — продолжаем её.
+		active, err := uc.repo.GetActiveTestAttempt(ctx, userID, test.ID)
+		if err != nil {
+			return nil, err
+		}
+		if active == nil {
+			return nil, domain.ErrTestAttemptActive
+		}
+		return active, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func isLate(attempt *domain.TestAttempt, now time.Time) bool {
+	return attempt.DeadlineAt != nil && now.After(attempt.DeadlineAt.Add(testSubmitGrace))
+}
+
+// closeLateAttempt закрывает просроченную попытку с нулевым результатом и возвращает nil;
+// непросроченную возвращает как есть.
+func (uc *LearningUseCase) closeLateAttempt(ctx context.Context, attempt *domain.TestAttempt, now time.Time) (*domain.TestAttempt, error) {
+	if attempt == nil || !isLate(attempt, now) {
+		return attempt, nil
+	}
+	attempt.Results = []domain.QuestionResult{}
+	attempt.Answers = []domain.TestAnswer{}
+	attempt.Score, attempt.MaxScore, attempt.Percent, attempt.Passed = 0, 0, 0, false
+	attempt.Status = domain.TestAttemptExpired
+	attempt.SubmittedAt = attempt.DeadlineAt
+	if err := uc.repo.FinishTestAttempt(ctx, attempt); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func activeAttempt(attempts []*domain.TestAttempt) *domain.TestAttempt {
+	for _, a := range attempts {
+		if a.Status == domain.TestAttemptInProgress {
+			return a
+		}
+	}
+	return nil
+}
+
+// summarizeAttempts собирает TestResult по отправленным попыткам (от новых к старым) так же,
+// как repository.getTestResults: в зачёт идут проверенные и просроченные попытки по Scoring теста.
+func summarizeAttempts(test *domain.Test, attempts []*domain.TestAttempt) *domain.TestResult {
+	var res *domain.TestResult
+	lastTaken := false
+	for _, a := range attempts {
+		if a.Status == domain.TestAttemptInProgress {
+			continue
+		}
+		if res == nil {
+			res = &domain.TestResult{
+				TestID:        test.ID,
+				Title:         test.Title,
+				LastPercent:   a.Percent,
+				LastAttemptAt: a.SubmittedAt,
+			}
+		}
+		res.Attempts++
+		if a.Percent > res.BestPercent {
+			res.BestPercent = a.Percent
+		}
+		if a.Status == domain.TestAttemptPendingReview {
+			continue
+		}
+		if test.Scoring == domain.TestScoringLast {
+			if !lastTaken {
+				res.Percent, res.Passed = a.Percent, a.Passed
+				lastTaken = true
+			}
+			continue
+		}
+		if a.Percent > res.Percent {
+			res.Percent = a.Percent
+		}
+		res.Passed = res.Passed || a.Passed
+	}
+	return res
+}
+
+// answersRevealed — after_submit открывает ответы, только когда пересдать уже нельзя: тест сдан
+// или попытки исчерпаны. Иначе ученик подсмотрел бы ключ и набрал 100% в следующей попытке.
+func answersRevealed(test *domain.Test, result *domain.TestResult) bool {
+	if result == nil {
+		return false
+	}
+	switch test.AnswersReveal {
+	case domain.AnswersRevealAfterSubmit:
+		return result.Passed || (test.MaxAttempts > 0 && result.Attempts >= test.MaxAttempts)
+	case domain.AnswersRevealAfterPass:
+		return result.Passed
+	}
+	return false
+}
+
+// studentTestView собирает ученическую проекцию. Во время попытки ответы не показываются
+// никогда, даже если прошлые попытки их уже открыли.
+func studentTestView(test *domain.Test, attempts []*domain.TestAttempt, active *domain.TestAttempt) *domain.StudentTest {
+	result := summarizeAttempts(test, attempts)
+	view := &domain.StudentTest{
+		ID:            test.ID,
+		LessonID:      test.LessonID,
+		Title:         test.Title,
+		Description:   test.Description,
+		PassingScore:  test.PassingScore,
+		AnswersReveal: test.AnswersReveal,
+		TestPolicy:    test.TestPolicy,
+		AttemptsUsed:  len(attempts),
+		Questions:     []domain.StudentTestQuestion{},
+		Result:        result,
+	}
+
+	if active != nil {
+		view.Attempt = &domain.ActiveTestAttempt{ID: active.ID, StartedAt: active.StartedAt, DeadlineAt: active.DeadlineAt}
+		for _, q := range attemptQuestions(test, active.QuestionIDs) {
+			view.Questions = append(view.Questions, studentQuestion(displayQuestion(test, q, active.Seed), false))
+		}
+		return view
+	}
+
+	view.AnswersRevealed = answersRevealed(test, result)
+	if view.AnswersRevealed {
+		for _, q := range test.Questions {
+			view.Questions = append(view.Questions, studentQuestion(q, true))
+		}
+	} else if !test.RequiresStart() {
+		// Тест без попыток отправляется сразу, seed попытки нет — варианты перемешиваются по ID теста.
+		seed := testSeed(test.ID)
+		for _, q := range test.Questions {
+			view.Questions = append(view.Questions, studentQuestion(displayQuestion(test, q, seed), false))
+		}
+	}
+	return view
+}
+
+func studentQuestion(q domain.TestQuestion, reveal bool) domain.StudentTestQuestion {
+	sq := domain.StudentTestQuestion{
+		ID:           q.ID,
+		Type:         q.Type,
+		Question:     q.Question,
+		Options:      q.Options,
+		MatchOptions: q.MatchOptions,
+		Points:       q.Points,
+	}
+	if reveal {
+		answer := q.CorrectAnswer
+		sq.CorrectAnswer = &answer
+		sq.CorrectAnswers = q.CorrectAnswers
+	}
+	return sq
+}
+
+type SubmitTestInput struct {
+	TestID  string
+	UserID  string
+	Answers []domain.TestAnswer
+}
+
+// SubmitTest проверяет ответы начатой попытки по ключам test_questions и сохраняет результат.
+// Тест без ограничений (см. TestPolicy.RequiresStart) можно отправить без StartTest — попытка
+// начнётся и завершится сразу. Если в тесте есть вопросы со свободным ответом, попытка ждёт
+// ручной проверки. Сданный тест может завершить курс. Ошибка репозитория (в т.ч. sql.ErrNoRows)
+// возвращается как есть.
+func (uc *LearningUseCase) SubmitTest(ctx context.Context, input SubmitTestInput) (*domain.TestAttempt, error) {
+	test, err := uc.repo.GetTestByID(ctx, input.TestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(test.Questions) == 0 {
+		return nil, ErrTestHasNoQuestions
+	}
+	if err := uc.checkTestLesson(ctx, test, input.UserID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	attempt, err := uc.repo.GetActiveTestAttempt(ctx, input.UserID, input.TestID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == nil {
+		if test.RequiresStart() {
+			return nil, ErrAttemptNotStarted
+		}
+		attempts, err := uc.repo.GetTestAttempts(ctx, input.UserID, input.TestID)
+		if err != nil {
+			return nil, err
+		}
+		if attempt, err = uc.startAttempt(ctx, test, input.UserID, len(attempts), now); err != nil {
+			return nil, err
+		}
+	}
+	if isLate(attempt, now) {
+		if _, err := uc.closeLateAttempt(ctx, attempt, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrTimeLimitExceeded
+	}
+
+	graded := gradeTest(test.PassingScore, attemptQuestions(test, attempt.QuestionIDs), input.Answers)
+	attempt.Answers = graded.Answers
+	attempt.Results = graded.Results
+	attempt.Finalize(test.PassingScore)
+	attempt.SubmittedAt = &now
+	if err := uc.repo.FinishTestAttempt(ctx, attempt); err != nil {
+		return nil, err
+	}
+	if attempt.Passed && test.LessonID != nil {
+		if courseID, err := uc.repo.GetLessonCourseID(ctx, *test.LessonID); err == nil {
+			uc.checkCourseCompletion(ctx, input.UserID, courseID)
+		}
+	}
+	attempts, err := uc.repo.GetTestAttempts(ctx, input.UserID, input.TestID)
+	if err != nil {
+		return nil, err
+	}
+	return studentAttempts(test, attempts, []*domain.TestAttempt{attempt})[0], nil
+}
+
+// GetTestAttempts — попытки ученика; разбор по вопросам виден только после раскрытия ответов.
+func (uc *LearningUseCase) GetTestAttempts(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error) {
+	test, err := uc.repo.GetTestByID(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := uc.repo.GetTestAttempts(ctx, userID, testID)
+	if err != nil {
+		return nil, err
+	}
+	return studentAttempts(test, attempts, attempts), nil
+}
+
+// studentAttempts отдаёт ученику копии попыток shown. Пока ответы теста не раскрыты (answersRevealed
+// по всем попыткам), остаются только итоги: по результатам вопросов между пересдачами можно подобрать ответы.
+func studentAttempts(test *domain.Test, attempts, shown []*domain.TestAttempt) []*domain.TestAttempt {
+	revealed := answersRevealed(test, summarizeAttempts(test, attempts))
+	out := make([]*domain.TestAttempt, 0, len(shown))
+	for _, a := range shown {
+		cp := *a
+		if !revealed {
+			cp.Results = nil
+		}
+		out = append(out, &cp)
+	}
+	return out
+}
+
+// gradeTest оценивает каждый вопрос своим грейдером (см. gradeQuestion).
+// Ответы на вопросы не из попытки отбрасываются, повторный ответ на тот же вопрос игнорируется.
+func gradeTest(passingScore int, questions []domain.TestQuestion, answers []domain.TestAnswer) *domain.TestAttempt {
+	given := make(map[string]domain.TestAnswer, len(answers))
+	for _, a := range answers {
+		if _, seen := given[a.QuestionID]; !seen {
+			given[a.QuestionID] = a
+		}
+	}
+
+	attempt := &domain.TestAttempt{
+		Answers: []domain.TestAnswer{},
+		Results: make([]domain.QuestionResult, 0, len(questions)),
+	}
+	for _, q := range questions {
+		var answer *domain.TestAnswer
+		if a, ok := given[q.ID]; ok {
+			answer = &a
+			attempt.Answers = append(attempt.Answers, a)
+		}
+		attempt.Results = append(attempt.Results, gradeQuestion(q, answer))
+	}
+	attempt.Finalize(passingScore)
+	return attempt
+}
+
+// checkTestLesson не даёт проходить тест закрытого урока; тест без урока доступен всегда.
+func (uc *LearningUseCase) checkTestLesson(ctx context.Context, test *domain.Test, userID string) error {
+	if test.LessonID == nil {
+		return nil
+	}
+	_, err := uc.unlockedLessonCourse(ctx, *test.LessonID, userID)
+	return err
+}
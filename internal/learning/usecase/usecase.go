@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"mime"
 	"mime/multipart"
 	"path/filepath"
@@ -20,13 +21,25 @@ func s3Context(ctx context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, s3UploadTimeout)
 }
 
+// AuditLogger — то, что нужно от audit.AuditUseCase.
+type AuditLogger interface {
+	LogAction(ctx context.Context, userID *string, action, entityType, entityID string, oldValues, newValues interface{}, ipAddress, userAgent *string) error
+}
+
 type LearningUseCase struct {
-	repo      repository.LearningRepository
-	s3Storage storageService.ObjectStorage
+	repo         repository.LearningRepository
+	s3Storage    storageService.ObjectStorage
+	certificates *CertificateTemplate
+	audit        AuditLogger
 }
 
-func NewLearningUseCase(repo repository.LearningRepository, s3Storage storageService.ObjectStorage) *LearningUseCase {
-	return &LearningUseCase{repo: repo, s3Storage: s3Storage}
+// NewLearningUseCase — certificates == nil означает макет сертификата по умолчанию,
+// audit == nil — выдача и отзыв сертификатов не пишутся в audit_logs.
+func NewLearningUseCase(repo repository.LearningRepository, s3Storage storageService.ObjectStorage, certificates *CertificateTemplate, audit AuditLogger) *LearningUseCase {
+	if certificates == nil {
+		certificates = DefaultCertificateTemplate()
+	}
+	return &LearningUseCase{repo: repo, s3Storage: s3Storage, certificates: certificates, audit: audit}
 }
 
 func (uc *LearningUseCase) GetMyCourses(ctx context.Context, userID string) ([]*domain.StudentCoursePreview, error) {
@@ -36,14 +49,64 @@ func (uc *LearningUseCase) GetMyCourses(ctx context.Context, userID string) ([]*
 	return uc.repo.GetMyCourses(ctx, userID)
 }
 
+// GetCourseContent отдаёт структуру курса; уроки, предыдущий урок которых ещё не выполнен
+// по обязательным правилам курса, помечаются IsLocked.
 func (uc *LearningUseCase) GetCourseContent(ctx context.Context, courseID, userID string) (*domain.StudentCourseView, error) {
-	return uc.repo.GetCourseContent(ctx, courseID, userID)
+	view, err := uc.repo.GetCourseContent(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := uc.lockedLessons(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	lessons := view.RootLessons
+	for _, m := range view.Modules {
+		lessons = append(lessons, m.Lessons...)
+	}
+	for _, l := range lessons {
+		l.IsLocked = locked[l.ID]
+	}
+	return view, nil
 }
 
+// GetLessonDetail отказывает в закрытом уроке с domain.ErrLessonLocked.
 func (uc *LearningUseCase) GetLessonDetail(ctx context.Context, lessonID, userID string) (*domain.StudentLessonDetail, error) {
+	if _, err := uc.unlockedLessonCourse(ctx, lessonID, userID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetLessonDetail(ctx, lessonID, userID)
 }
 
+// unlockedLessonCourse возвращает курс урока или domain.ErrLessonLocked, если урок ученику ещё закрыт.
+// Через неё проходят все действия ученика с уроком: просмотр, видео, сдачи, тесты и посещение.
+func (uc *LearningUseCase) unlockedLessonCourse(ctx context.Context, lessonID, userID string) (string, error) {
+	courseID, err := uc.repo.GetLessonCourseID(ctx, lessonID)
+	if err != nil {
+		return "", fmt.Errorf("lesson not found: %w", err)
+	}
+	locked, err := uc.lockedLessons(ctx, courseID, userID)
+	if err != nil {
+		return "", err
+	}
+	if locked[lessonID] {
+		return "", domain.ErrLessonLocked
+	}
+	return courseID, nil
+}
+
+func (uc *LearningUseCase) lockedLessons(ctx context.Context, courseID, userID string) (map[string]bool, error) {
+	course, err := uc.repo.GetCourseRules(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	gates, err := uc.repo.GetLessonGates(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.LockedLessons(course, gates), nil
+}
+
 type SubmitAssignmentInput struct {
 	LessonID    string
 	UserID      string
@@ -52,6 +115,12 @@ type SubmitAssignmentInput struct {
 }
 
 func (uc *LearningUseCase) SubmitAssignment(ctx context.Context, input SubmitAssignmentInput) error {
+	if err := checkTextAnswer(input.TextAnswer); err != nil {
+		return err
+	}
+	if _, err := uc.unlockedLessonCourse(ctx, input.LessonID, input.UserID); err != nil {
+		return err
+	}
 	assignmentID, err := uc.repo.GetAssignmentIDByLesson(ctx, input.LessonID)
 	if err != nil {
 		if err := uc.repo.EnsureAssignment(ctx, input.LessonID, ""); err != nil {
@@ -62,32 +131,68 @@ func (uc *LearningUseCase) SubmitAssignment(ctx context.Context, input SubmitAss
 			return fmt.Errorf("assignment not found after ensure: %w", err)
 		}
 	}
+	// Срок проверяется до загрузки файлов, чтобы отклонённая работа не оставляла их в хранилище.
+	now := time.Now()
+	deadline, err := uc.repo.GetStudentDeadline(ctx, assignmentID, input.UserID)
+	if err != nil {
+		return err
+	}
+	isLate, err := deadline.CheckSubmission(now)
+	if err != nil {
+		return err
+	}
+	antiCopy, err := uc.repo.IsAntiCopyEnabled(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	var content string
+	if antiCopy {
+		content = submissionContent(input.TextAnswer, input.FileHeaders)
+	}
+	// Каждая версия хранит свои файлы, поэтому в ключ входит момент отправки.
+	stamp := now.UnixNano()
 	var fileURLs []string
 	for _, fh := range input.FileHeaders {
-		file, err := fh.Open()
+		url, err := uc.uploadSubmissionFile(ctx, fh, fmt.Sprintf("submissions/%s_%s_%d_%s", input.UserID, assignmentID, stamp, fh.Filename))
 		if err != nil {
 			return err
 		}
-		defer file.Close()
-		s3Ctx, cancel := s3Context(ctx)
-		defer cancel()
-		s3Key := fmt.Sprintf("submissions/%s_%s_%s", input.UserID, assignmentID, fh.Filename)
-		mimeType := fh.Header.Get("Content-Type")
+		fileURLs = append(fileURLs, url)
+	}
+	versionID, err := uc.repo.SaveSubmission(ctx, input.UserID, assignmentID, input.TextAnswer, fileURLs, isLate)
+	if err != nil || versionID == "" || !antiCopy {
+		return err
+	}
+	// Работа уже сохранена, поэтому сбой проверки на списывание не отклоняет отправку.
+	if err := uc.checkSimilarity(ctx, assignmentID, versionID, input.UserID, content); err != nil {
+		slog.Error("similarity check", slog.String("version_id", versionID), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// uploadSubmissionFile загружает файл работы в S3 и возвращает его публичный URL.
+func (uc *LearningUseCase) uploadSubmissionFile(ctx context.Context, fh *multipart.FileHeader, s3Key string) (string, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	s3Ctx, cancel := s3Context(ctx)
+	defer cancel()
+	mimeType := fh.Header.Get("Content-Type")
+	if mimeType == "" {
+		ext := filepath.Ext(fh.Filename)
+		mimeType = mime.TypeByExtension(ext)
 		if mimeType == "" {
-			ext := filepath.Ext(fh.Filename)
-			mimeType = mime.TypeByExtension(ext)
-			if mimeType == "" {
-				mimeType = "application/octet-stream"
-			}
+			mimeType = "application/octet-stream"
 		}
-		key, err := uc.s3Storage.UploadFile(s3Ctx, file, s3Key, fh.Size, mimeType)
-		if err != nil {
-			return err
-		}
-		url, _ := uc.s3Storage.GetPublicURL(ctx, key)
-		fileURLs = append(fileURLs, url)
 	}
-	return uc.repo.SaveSubmission(ctx, input.UserID, assignmentID, input.TextAnswer, fileURLs)
+	key, err := uc.s3Storage.UploadFile(s3Ctx, file, s3Key, fh.Size, mimeType)
+	if err != nil {
+		return "", err
+	}
+	url, _ := uc.s3Storage.GetPublicURL(ctx, key)
+	return url, nil
 }
 
 type SetAttendanceInput struct {
@@ -99,6 +204,9 @@ type SetAttendanceInput struct {
 }
 
 func (uc *LearningUseCase) SetLessonAttendance(ctx context.Context, input SetAttendanceInput) error {
+	if _, err := uc.unlockedLessonCourse(ctx, input.LessonID, input.UserID); err != nil {
+		return err
+	}
 	return uc.repo.SetLessonAttendance(ctx, input.UserID, input.LessonID, input.Status, input.RecordingURL, input.TeacherComment)
 }
 
@@ -135,20 +243,26 @@ func (uc *LearningUseCase) AddReview(ctx context.Context, input AddReviewInput)
 	}
 	return uc.repo.AddTeacherReview(ctx, review)
 }
-func (uc *LearningUseCase) GetProject(ctx context.Context, projectID string) (*domain.Project, error) {
+
+// GetProject отдаёт проект вместе с состоянием последней версии сдачи ученика.
+func (uc *LearningUseCase) GetProject(ctx context.Context, projectID, userID string) (*domain.Project, error) {
 	p, err := uc.repo.GetProjectByID(ctx, projectID)
 	if err != nil {
 		return nil, nil
 	}
-	return p, nil
-}
-
-func (uc *LearningUseCase) GetTest(ctx context.Context, testID string) (*domain.Test, error) {
-	t, err := uc.repo.GetTestByID(ctx, testID)
+	subs, err := uc.repo.GetProjectSubmissions(ctx, userID, projectID)
 	if err != nil {
-		return nil, nil
+		return nil, err
+	}
+	if len(subs) > 0 {
+		latest := subs[0]
+		p.SubmissionStatus = latest.Status
+		p.SubmissionVersion = latest.Version
+		p.SubmittedAt = &latest.SubmittedAt
+		p.Grade = latest.Grade
+		p.TeacherComment = latest.TeacherComment
 	}
-	return t, nil
+	return p, nil
 }
 
 func (uc *LearningUseCase) GetAllCourses(ctx context.Context) ([]*domain.Course, error) {
@@ -2,8 +2,13 @@ package usecase_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"lms_backend/internal/domain"
 	"lms_backend/internal/learning/mocks"
@@ -14,7 +19,7 @@ import (
 func TestGetMyCourses(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
 
 	repo.GetMyCoursesFunc = func(ctx context.Context, userID string) ([]*domain.StudentCoursePreview, error) {
 		if userID == "" {
@@ -56,7 +61,7 @@ func TestGetMyCourses(t *testing.T) {
 func TestGetCourseContent(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
 
 	repo.GetCourseContentFunc = func(ctx context.Context, courseID, userID string) (*domain.StudentCourseView, error) {
 		if courseID == "" {
@@ -66,6 +71,12 @@ func TestGetCourseContent(t *testing.T) {
 			Course: &domain.Course{ID: courseID, Title: "Go Basics"},
 		}, nil
 	}
+	repo.GetCourseRulesFunc = func(ctx context.Context, courseID string) (*domain.Course, error) {
+		return &domain.Course{ID: courseID}, nil
+	}
+	repo.GetLessonGatesFunc = func(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error) {
+		return nil, nil
+	}
 
 	t.Run("success", func(t *testing.T) {
 		view, err := uc.GetCourseContent(context.Background(), "c1", "u1")
@@ -85,10 +96,133 @@ func TestGetCourseContent(t *testing.T) {
 	})
 }
 
+func TestLessonLocks(t *testing.T) {
+	repo := mocks.NewLearningRepoMock()
+	s3 := pkgMocks.NewS3StorageMock()
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
+
+	rules := &domain.Course{ID: "c1", IsHomeworkMandatory: true, IsTestMandatory: true}
+	gates := []domain.LessonGate{
+		{LessonID: "l1", HasHomework: true, HomeworkAccepted: true, Tests: 1, TestsPassed: 1},
+		{LessonID: "l2", HasHomework: true, Tests: 1, TestsPassed: 1},
+		{LessonID: "l3"},
+		{LessonID: "l4"},
+	}
+	repo.GetCourseRulesFunc = func(ctx context.Context, courseID string) (*domain.Course, error) {
+		return rules, nil
+	}
+	repo.GetLessonGatesFunc = func(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error) {
+		return gates, nil
+	}
+	repo.GetCourseContentFunc = func(ctx context.Context, courseID, userID string) (*domain.StudentCourseView, error) {
+		return &domain.StudentCourseView{
+			Course: &domain.Course{ID: courseID},
+			Modules: []*domain.StudentModuleView{{Lessons: []*domain.StudentLessonRef{
+				{ID: "l1"}, {ID: "l2"}, {ID: "l3"},
+			}}},
+			RootLessons: []*domain.StudentLessonRef{{ID: "l4"}},
+		}, nil
+	}
+	repo.GetLessonCourseIDFunc = func(ctx context.Context, lessonID string) (string, error) {
+		return "c1", nil
+	}
+	repo.GetLessonDetailFunc = func(ctx context.Context, lessonID, userID string) (*domain.StudentLessonDetail, error) {
+		return &domain.StudentLessonDetail{Lesson: &domain.Lesson{ID: lessonID}}, nil
+	}
+
+	lockState := func(t *testing.T) map[string]bool {
+		t.Helper()
+		view, err := uc.GetCourseContent(context.Background(), "c1", "u1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := map[string]bool{}
+		for _, l := range append(view.Modules[0].Lessons, view.RootLessons...) {
+			state[l.ID] = l.IsLocked
+		}
+		return state
+	}
+
+	t.Run("next lesson waits for accepted homework", func(t *testing.T) {
+		state := lockState(t)
+		if state["l1"] || state["l2"] || !state["l3"] || !state["l4"] {
+			t.Fatalf("expected l3 and l4 locked, got %v", state)
+		}
+	})
+
+	t.Run("locked lesson detail is refused", func(t *testing.T) {
+		if _, err := uc.GetLessonDetail(context.Background(), "l3", "u1"); !errors.Is(err, domain.ErrLessonLocked) {
+			t.Fatalf("expected ErrLessonLocked, got %v", err)
+		}
+		if _, err := uc.GetLessonDetail(context.Background(), "l2", "u1"); err != nil {
+			t.Fatalf("expected open lesson, got %v", err)
+		}
+	})
+
+	t.Run("locked lesson refuses student actions", func(t *testing.T) {
+		ctx := context.Background()
+		lessonID := "l3"
+		repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+			return &domain.Test{ID: testID, LessonID: &lessonID, Questions: []domain.TestQuestion{{ID: "q1"}}}, nil
+		}
+		repo.GetProjectByIDFunc = func(ctx context.Context, projectID string) (*domain.Project, error) {
+			return &domain.Project{ID: projectID, LessonID: &lessonID}, nil
+		}
+		actions := map[string]func() error{
+			"assignment": func() error {
+				return uc.SubmitAssignment(ctx, usecase.SubmitAssignmentInput{LessonID: lessonID, UserID: "u1", TextAnswer: "answer"})
+			},
+			"start test": func() error {
+				_, err := uc.StartTest(ctx, "t1", "u1")
+				return err
+			},
+			"submit test": func() error {
+				_, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1"})
+				return err
+			},
+			"project": func() error {
+				_, err := uc.SubmitProject(ctx, usecase.SubmitProjectInput{ProjectID: "p1", UserID: "u1", TextAnswer: "answer"})
+				return err
+			},
+			"attendance": func() error {
+				return uc.SetLessonAttendance(ctx, usecase.SetAttendanceInput{LessonID: lessonID, UserID: "u1", Status: "visited"})
+			},
+		}
+		for name, action := range actions {
+			if err := action(); !errors.Is(err, domain.ErrLessonLocked) {
+				t.Errorf("%s: expected ErrLessonLocked, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("optional homework does not lock", func(t *testing.T) {
+		rules.IsHomeworkMandatory = false
+		defer func() { rules.IsHomeworkMandatory = true }()
+		if state := lockState(t); state["l3"] || state["l4"] {
+			t.Fatalf("expected all lessons open, got %v", state)
+		}
+	})
+
+	t.Run("admin unlock opens only that lesson", func(t *testing.T) {
+		gates[2].Unlocked = true
+		defer func() { gates[2].Unlocked = false }()
+		state := lockState(t)
+		if state["l3"] || state["l4"] {
+			t.Fatalf("expected l3 unlocked and l4 open after empty l3, got %v", state)
+		}
+		gates[2].Tests = 1
+		defer func() { gates[2].Tests = 0 }()
+		if state := lockState(t); state["l3"] || !state["l4"] {
+			t.Fatalf("expected l4 to wait for the test of unlocked l3, got %v", state)
+		}
+	})
+}
+
 func TestSubmitAssignment(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
+	unlocked(repo)
 
 	repo.GetAssignmentIDByLessonFunc = func(ctx context.Context, lessonID string) (string, error) {
 		if lessonID == "bad" {
@@ -97,10 +231,45 @@ func TestSubmitAssignment(t *testing.T) {
 		return "assign-1", nil
 	}
 
-	repo.SaveSubmissionFunc = func(ctx context.Context, userID, assignmentID, text string, files []string) error {
+	repo.EnsureAssignmentFunc = func(ctx context.Context, lessonID, title string) error {
+		return errors.New("lesson not found")
+	}
+
+	deadline := &domain.StudentDeadline{AssignmentID: "assign-1", LatePolicy: domain.LatePolicyAccept}
+	repo.GetStudentDeadlineFunc = func(ctx context.Context, assignmentID, userID string) (*domain.StudentDeadline, error) {
+		return deadline, nil
+	}
+
+	var savedLate bool
+	repo.SaveSubmissionFunc = func(ctx context.Context, userID, assignmentID, text string, files []string, isLate bool) (string, error) {
 		if assignmentID == "fail" {
-			return errors.New("save failed")
+			return "", errors.New("save failed")
 		}
+		savedLate = isLate
+		return "version-" + userID, nil
+	}
+
+	antiCopy := false
+	repo.IsAntiCopyEnabledFunc = func(ctx context.Context, assignmentID string) (bool, error) {
+		return antiCopy, nil
+	}
+	fingerprints := map[string]*domain.SubmissionFingerprint{}
+	repo.SaveFingerprintFunc = func(ctx context.Context, fp *domain.SubmissionFingerprint) error {
+		fingerprints[fp.VersionID] = fp
+		return nil
+	}
+	repo.GetAssignmentFingerprintsFunc = func(ctx context.Context, assignmentID, excludeUserID string) ([]*domain.SubmissionFingerprint, error) {
+		var result []*domain.SubmissionFingerprint
+		for _, fp := range fingerprints {
+			if fp.UserID != excludeUserID {
+				result = append(result, fp)
+			}
+		}
+		return result, nil
+	}
+	similar := map[string][]*domain.SimilarityMatch{}
+	repo.SaveSimilarityMatchesFunc = func(ctx context.Context, versionID string, matches []*domain.SimilarityMatch) error {
+		similar[versionID] = matches
 		return nil
 	}
 
@@ -115,6 +284,62 @@ func TestSubmitAssignment(t *testing.T) {
 		}
 	})
 
+	t.Run("late submission is marked", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		deadline.DueAt = &past
+		defer func() { deadline.DueAt = nil }()
+		err := uc.SubmitAssignment(context.Background(), usecase.SubmitAssignmentInput{
+			LessonID:   "l1",
+			UserID:     "u1",
+			TextAnswer: "late homework",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !savedLate {
+			t.Error("expected submission to be saved as late")
+		}
+	})
+
+	t.Run("late submission rejected by policy", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		deadline.DueAt, deadline.LatePolicy = &past, domain.LatePolicyReject
+		defer func() { deadline.DueAt, deadline.LatePolicy = nil, domain.LatePolicyAccept }()
+		err := uc.SubmitAssignment(context.Background(), usecase.SubmitAssignmentInput{
+			LessonID:   "l1",
+			UserID:     "u1",
+			TextAnswer: "late homework",
+		})
+		if !errors.Is(err, domain.ErrDeadlinePassed) {
+			t.Fatalf("expected ErrDeadlinePassed, got %v", err)
+		}
+	})
+
+	t.Run("similar submissions are flagged when anti-copy is enabled", func(t *testing.T) {
+		antiCopy = true
+		defer func() { antiCopy = false }()
+		answer := "def fib(n):\n    a, b = 0, 1\n    for _ in range(n):\n        a, b = b, a + b\n    return a"
+		for _, input := range []usecase.SubmitAssignmentInput{
+			{LessonID: "l1", UserID: "u1", TextAnswer: answer},
+			{LessonID: "l1", UserID: "u2", TextAnswer: "# my solution\n" + answer},
+			{LessonID: "l1", UserID: "u3", TextAnswer: "Ответ: рекурсия с мемоизацией через словарь, без циклов и без лишних переменных"},
+		} {
+			if err := uc.SubmitAssignment(context.Background(), input); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(fingerprints) != 3 {
+			t.Fatalf("expected 3 fingerprints, got %d", len(fingerprints))
+		}
+		matches := similar["version-u2"]
+		if len(matches) != 1 || matches[0].VersionID != "version-u1" || matches[0].Score < domain.SimilarityThreshold {
+			t.Fatalf("expected u2 to match u1, got %+v", matches)
+		}
+		if len(similar["version-u3"]) != 0 {
+			t.Errorf("expected no matches for an original answer, got %+v", similar["version-u3"])
+		}
+	})
+
 	t.Run("assignment not found", func(t *testing.T) {
 		err := uc.SubmitAssignment(context.Background(), usecase.SubmitAssignmentInput{
 			LessonID: "bad",
@@ -140,10 +365,146 @@ func TestSubmitAssignment(t *testing.T) {
 	})
 }
 
+func TestRecordVideoProgress(t *testing.T) {
+	repo := mocks.NewLearningRepoMock()
+	s3 := pkgMocks.NewS3StorageMock()
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
+	ctx := context.Background()
+
+	repo.GetLessonCourseIDFunc = func(ctx context.Context, lessonID string) (string, error) {
+		if lessonID == "missing" {
+			return "", sql.ErrNoRows
+		}
+		return "c1", nil
+	}
+	repo.GetCourseRulesFunc = func(ctx context.Context, courseID string) (*domain.Course, error) {
+		return &domain.Course{ID: courseID}, nil
+	}
+	repo.GetLessonGatesFunc = func(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error) {
+		return nil, nil
+	}
+	// Урок длится 20 минут, поэтому видео считается 1200-секундным.
+	repo.GetLessonVideoFunc = func(ctx context.Context, lessonID string) (string, int, error) {
+		if lessonID == "no-video" {
+			return "", 20, nil
+		}
+		return "https://cdn.example/video.mp4", 20, nil
+	}
+	stored := map[string]*domain.VideoProgress{}
+	repo.GetVideoProgressFunc = func(ctx context.Context, userID, lessonID string) (*domain.VideoProgress, error) {
+		v, ok := stored[userID+"/"+lessonID]
+		if !ok {
+			return nil, sql.ErrNoRows
+		}
+		cp := *v
+		return &cp, nil
+	}
+	repo.SaveVideoProgressFunc = func(ctx context.Context, userID string, progress *domain.VideoProgress) error {
+		stored[userID+"/"+progress.LessonID] = progress
+		return nil
+	}
+	// elapse сдвигает прошлые сообщения плеера назад, будто между ними прошло время d.
+	elapse := func(d time.Duration) {
+		for _, v := range stored {
+			v.UpdatedAt = v.UpdatedAt.Add(-d)
+		}
+	}
+	notEnrolled(repo)
+
+	t.Run("segments are merged and completion is reached at 80%", func(t *testing.T) {
+		pings := []domain.VideoPing{
+			{PositionSec: 0, DurationSec: 1000},
+			{PositionSec: 300, DurationSec: 1000, Segments: [][2]int{{0, 300}}},
+			{PositionSec: 200, DurationSec: 1000, Segments: [][2]int{{100, 200}}},
+			{PositionSec: 700, DurationSec: 1000, Segments: [][2]int{{250, 700}}},
+		}
+		var progress *domain.VideoProgress
+		for _, p := range pings {
+			elapse(10 * time.Minute)
+			var err error
+			if progress, err = uc.RecordVideoProgress(ctx, "u1", "l1", p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if progress.WatchedSec != 700 || progress.PositionSec != 700 || progress.CompletedAt != nil {
+			t.Fatalf("expected 700s watched, resume at 700 and not completed, got %+v", progress)
+		}
+		if len(progress.Ranges) != 1 {
+			t.Errorf("expected ranges to be merged into one, got %v", progress.Ranges)
+		}
+
+		elapse(10 * time.Minute)
+		progress, err := uc.RecordVideoProgress(ctx, "u1", "l1", domain.VideoPing{PositionSec: 1200, DurationSec: 1000, Segments: [][2]int{{700, 1300}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if progress.WatchedPercent != 100 || progress.CompletedAt == nil || progress.PositionSec != 0 {
+			t.Fatalf("expected completed video starting over, got %+v", progress)
+		}
+	})
+
+	t.Run("invalid ping", func(t *testing.T) {
+		bad := []domain.VideoPing{
+			{PositionSec: -1},
+			{PositionSec: 10, DurationSec: 100, Segments: [][2]int{{50, 20}}},
+		}
+		for _, p := range bad {
+			if _, err := uc.RecordVideoProgress(ctx, "u1", "l1", p); !errors.Is(err, domain.ErrInvalidVideoPing) {
+				t.Errorf("expected ErrInvalidVideoPing for %+v, got %v", p, err)
+			}
+		}
+	})
+
+	t.Run("fake duration from the player does not complete the lesson", func(t *testing.T) {
+		progress, err := uc.RecordVideoProgress(ctx, "u2", "l1", domain.VideoPing{PositionSec: 1, DurationSec: 1, Segments: [][2]int{{0, 1}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if progress.DurationSec != 1200 || progress.WatchedPercent != 0 || progress.CompletedAt != nil {
+			t.Fatalf("expected the lesson duration to be used, got %+v", progress)
+		}
+	})
+
+	t.Run("lesson without video", func(t *testing.T) {
+		_, err := uc.RecordVideoProgress(ctx, "u2", "no-video", domain.VideoPing{PositionSec: 30, DurationSec: 60, Segments: [][2]int{{0, 30}}})
+		if !errors.Is(err, domain.ErrInvalidVideoPing) {
+			t.Fatalf("expected ErrInvalidVideoPing, got %v", err)
+		}
+	})
+
+	t.Run("segments cannot outrun the time since the previous ping", func(t *testing.T) {
+		_, err := uc.RecordVideoProgress(ctx, "u3", "l1", domain.VideoPing{PositionSec: 900, DurationSec: 1000, Segments: [][2]int{{0, 900}}})
+		if !errors.Is(err, domain.ErrInvalidVideoPing) {
+			t.Fatalf("expected the first ping to be limited, got %v", err)
+		}
+		if _, err := uc.RecordVideoProgress(ctx, "u3", "l1", domain.VideoPing{PositionSec: 30, DurationSec: 1000, Segments: [][2]int{{0, 30}}}); err != nil {
+			t.Fatal(err)
+		}
+		elapse(time.Minute)
+		_, err = uc.RecordVideoProgress(ctx, "u3", "l1", domain.VideoPing{PositionSec: 900, DurationSec: 1000, Segments: [][2]int{{30, 900}}})
+		if !errors.Is(err, domain.ErrInvalidVideoPing) {
+			t.Fatalf("expected ErrInvalidVideoPing for 870s watched in a minute, got %v", err)
+		}
+		progress, err := uc.RecordVideoProgress(ctx, "u3", "l1", domain.VideoPing{PositionSec: 90, DurationSec: 1000, Segments: [][2]int{{30, 90}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if progress.WatchedSec != 90 {
+			t.Errorf("expected 90s watched, got %d", progress.WatchedSec)
+		}
+	})
+
+	t.Run("unknown lesson", func(t *testing.T) {
+		if _, err := uc.RecordVideoProgress(ctx, "u1", "missing", domain.VideoPing{DurationSec: 100}); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
+
 func TestSetLessonAttendance(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
 
 	repo.SetLessonAttendanceFunc = func(ctx context.Context, userID, lessonID, status, recordingURL, teacherComment string) error {
 		if lessonID == "fail" {
@@ -151,6 +512,12 @@ func TestSetLessonAttendance(t *testing.T) {
 		}
 		return nil
 	}
+	unlocked(repo)
+	// Самоотметка ученика не засчитывает урок, поэтому завершение курса не проверяется.
+	repo.GetCourseCompletionFunc = func(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error) {
+		t.Error("self-marked attendance must not check course completion")
+		return nil, sql.ErrNoRows
+	}
 
 	t.Run("success", func(t *testing.T) {
 		err := uc.SetLessonAttendance(context.Background(), usecase.SetAttendanceInput{
@@ -177,7 +544,7 @@ func TestSetLessonAttendance(t *testing.T) {
 func TestGetTeacherDetails(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
 
 	repo.GetTeacherByIDFunc = func(ctx context.Context, id string) (*domain.TeacherPublicInfo, error) {
 		if id == "t1" {
@@ -228,7 +595,7 @@ func TestGetTeacherDetails(t *testing.T) {
 func TestAddReview(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
 
 	saved := false
 	repo.AddTeacherReviewFunc = func(ctx context.Context, review *domain.TeacherReview) error {
@@ -279,7 +646,7 @@ func TestAddReview(t *testing.T) {
 func TestGetTeacherDashboard(t *testing.T) {
 	repo := mocks.NewLearningRepoMock()
 	s3 := pkgMocks.NewS3StorageMock()
-	uc := usecase.NewLearningUseCase(repo, s3)
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
 
 	repo.GetTeacherByIDFunc = func(ctx context.Context, id string) (*domain.TeacherPublicInfo, error) {
 		if id == "t1" {
@@ -331,3 +698,831 @@ func TestGetTeacherDashboard(t *testing.T) {
 		}
 	})
 }
+
+// attemptStore держит попытки в памяти вместо test_attempts.
+type attemptStore struct {
+	attempts []*domain.TestAttempt
+}
+
+func newAttemptStore(repo *mocks.LearningRepoMock) *attemptStore {
+	st := &attemptStore{}
+	repo.StartTestAttemptFunc = func(ctx context.Context, attempt *domain.TestAttempt) error {
+		attempt.ID = fmt.Sprintf("attempt-%d", len(st.attempts)+1)
+		attempt.Status = domain.TestAttemptInProgress
+		st.attempts = append([]*domain.TestAttempt{attempt}, st.attempts...)
+		return nil
+	}
+	repo.FinishTestAttemptFunc = func(ctx context.Context, attempt *domain.TestAttempt) error {
+		return nil
+	}
+	repo.GetActiveTestAttemptFunc = func(ctx context.Context, userID, testID string) (*domain.TestAttempt, error) {
+		for _, a := range st.attempts {
+			if a.UserID == userID && a.TestID == testID && a.Status == domain.TestAttemptInProgress {
+				return a, nil
+			}
+		}
+		return nil, nil
+	}
+	repo.GetTestAttemptsFunc = func(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error) {
+		var list []*domain.TestAttempt
+		for _, a := range st.attempts {
+			if a.UserID == userID && a.TestID == testID {
+				list = append(list, a)
+			}
+		}
+		return list, nil
+	}
+	return st
+}
+
+func TestSubmitTest(t *testing.T) {
+	repo := mocks.NewLearningRepoMock()
+	s3 := pkgMocks.NewS3StorageMock()
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
+
+	repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+		switch testID {
+		case "empty":
+			return &domain.Test{ID: testID, PassingScore: 50}, nil
+		case "missing":
+			return nil, errors.New("not found")
+		}
+		return &domain.Test{
+			ID:           testID,
+			PassingScore: 60,
+			Questions: []domain.TestQuestion{
+				{ID: "q1", CorrectAnswer: "Paris", Points: 2},
+				{ID: "q2", CorrectAnswer: "4", Points: 1},
+				{ID: "q3", CorrectAnswer: "Go", Points: 2},
+			},
+		}, nil
+	}
+	store := newAttemptStore(repo)
+
+	t.Run("passed with case-insensitive match", func(t *testing.T) {
+		attempt, err := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{
+			TestID: "t1",
+			UserID: "u1",
+			Answers: []domain.TestAnswer{
+				{QuestionID: "q1", Answer: " paris "},
+				{QuestionID: "q3", Answer: "go"},
+				{QuestionID: "q3", Answer: "rust"},
+				{QuestionID: "unknown", Answer: "x"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempt.Score != 4 || attempt.MaxScore != 5 || attempt.Percent != 80 || !attempt.Passed {
+			t.Errorf("unexpected attempt: %+v", attempt)
+		}
+		if attempt.UserID != "u1" || len(attempt.Answers) != 2 {
+			t.Errorf("unexpected attempt data: %+v", attempt)
+		}
+		if len(store.attempts) != 1 || store.attempts[0].ID != "attempt-1" || store.attempts[0].Status != domain.TestAttemptGraded {
+			t.Errorf("attempt was not saved")
+		}
+	})
+
+	t.Run("failed below passing score", func(t *testing.T) {
+		attempt, err := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{
+			TestID:  "t1",
+			UserID:  "u1",
+			Answers: []domain.TestAnswer{{QuestionID: "q1", Answer: "London"}, {QuestionID: "q2", Answer: "4"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempt.Score != 1 || attempt.Percent != 20 || attempt.Passed {
+			t.Errorf("unexpected attempt: %+v", attempt)
+		}
+	})
+
+	t.Run("test without questions", func(t *testing.T) {
+		_, err := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{TestID: "empty", UserID: "u1"})
+		if !errors.Is(err, usecase.ErrTestHasNoQuestions) {
+			t.Fatalf("expected ErrTestHasNoQuestions, got %v", err)
+		}
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		if _, err := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{TestID: "missing", UserID: "u1"}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestGetTest_AnswersReveal(t *testing.T) {
+	repo := mocks.NewLearningRepoMock()
+	s3 := pkgMocks.NewS3StorageMock()
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
+
+	policy := domain.AnswersRevealAfterPass
+	maxAttempts := 0
+	repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+		return &domain.Test{
+			ID:            testID,
+			AnswersReveal: policy,
+			TestPolicy:    domain.TestPolicy{MaxAttempts: maxAttempts},
+			Questions:     []domain.TestQuestion{{ID: "q1", CorrectAnswer: "42", Points: 1}},
+		}, nil
+	}
+	attempts := map[string][]*domain.TestAttempt{
+		"failed": {{Percent: 0}},
+		"passed": {{Percent: 100, Passed: true}, {Percent: 0}},
+	}
+	repo.GetTestAttemptsFunc = func(ctx context.Context, userID, testID string) ([]*domain.TestAttempt, error) {
+		return attempts[userID], nil
+	}
+
+	t.Run("hidden before first attempt", func(t *testing.T) {
+		view, err := uc.GetTest(context.Background(), "t1", "new")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if view.AnswersRevealed || view.Questions[0].CorrectAnswer != nil || view.Result != nil {
+			t.Errorf("unexpected view: %+v", view)
+		}
+	})
+
+	t.Run("after_pass keeps answers hidden for failed attempts", func(t *testing.T) {
+		view, _ := uc.GetTest(context.Background(), "t1", "failed")
+		if view.AnswersRevealed || view.Questions[0].CorrectAnswer != nil {
+			t.Errorf("answers revealed before pass: %+v", view)
+		}
+		if view.Result == nil || view.Result.Attempts != 1 {
+			t.Errorf("expected attempt summary, got %+v", view.Result)
+		}
+	})
+
+	t.Run("after_pass reveals once passed", func(t *testing.T) {
+		view, _ := uc.GetTest(context.Background(), "t1", "passed")
+		if !view.AnswersRevealed || view.Questions[0].CorrectAnswer == nil || *view.Questions[0].CorrectAnswer != "42" {
+			t.Errorf("answers should be revealed: %+v", view)
+		}
+		if view.Result.BestPercent != 100 || view.Result.LastPercent != 100 || !view.Result.Passed {
+			t.Errorf("unexpected summary: %+v", view.Result)
+		}
+	})
+
+	t.Run("after_submit keeps answers hidden while attempts remain", func(t *testing.T) {
+		policy = domain.AnswersRevealAfterSubmit
+		view, _ := uc.GetTest(context.Background(), "t1", "failed")
+		if view.AnswersRevealed || view.Questions[0].CorrectAnswer != nil {
+			t.Errorf("answers revealed with attempts left: %+v", view)
+		}
+		maxAttempts = 1
+		defer func() { maxAttempts = 0 }()
+		view, _ = uc.GetTest(context.Background(), "t1", "failed")
+		if !view.AnswersRevealed || view.Questions[0].CorrectAnswer == nil {
+			t.Errorf("answers should be revealed once attempts are used up: %+v", view)
+		}
+	})
+
+	t.Run("never stays hidden", func(t *testing.T) {
+		policy = domain.AnswersRevealNever
+		view, _ := uc.GetTest(context.Background(), "t1", "passed")
+		if view.AnswersRevealed || view.Questions[0].CorrectAnswer != nil {
+			t.Errorf("answers must stay hidden: %+v", view)
+		}
+	})
+}
+
+func TestSubmitTest_QuestionTypes(t *testing.T) {
+	repo := mocks.NewLearningRepoMock()
+	s3 := pkgMocks.NewS3StorageMock()
+	uc := usecase.NewLearningUseCase(repo, s3, nil, nil)
+
+	questions := []domain.TestQuestion{
+		{ID: "multi", Type: domain.QuestionMultiple, Options: []string{"a", "b", "c", "d"}, CorrectAnswers: []string{"a", "b"}, PartialCredit: true, Points: 2},
+		{ID: "num", Type: domain.QuestionNumeric, CorrectAnswer: "3.14", Tolerance: 0.01, Points: 1},
+		{ID: "order", Type: domain.QuestionOrdering, Options: []string{"c", "a", "b", "d"}, CorrectAnswers: []string{"a", "b", "c", "d"}, PartialCredit: true, Points: 4},
+		{ID: "match", Type: domain.QuestionMatching, Options: []string{"France", "Italy"}, MatchOptions: []string{"Rome", "Paris"}, CorrectAnswers: []string{"Paris", "Rome"}, PartialCredit: false, Points: 2},
+		{ID: "essay", Type: domain.QuestionText, Points: 5},
+	}
+	repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+		qs := questions
+		if testID == "auto" {
+			qs = questions[:4]
+		}
+		return &domain.Test{ID: testID, PassingScore: 50, Questions: qs}, nil
+	}
+	store := newAttemptStore(repo)
+	// results — разбор попытки, как он сохранён; ученику он не отдаётся, пока ответы скрыты.
+	results := func(t *testing.T, a *domain.TestAttempt) []domain.QuestionResult {
+		t.Helper()
+		for _, stored := range store.attempts {
+			if stored.ID == a.ID {
+				return stored.Results
+			}
+		}
+		t.Fatalf("attempt %s not stored", a.ID)
+		return nil
+	}
+
+	answers := []domain.TestAnswer{
+		{QuestionID: "multi", Answers: []string{"a", "c"}},
+		{QuestionID: "num", Answer: "3,141"},
+		{QuestionID: "order", Answers: []string{"a", "b", "d", "c"}},
+		{QuestionID: "match", Answers: []string{"Paris", "Paris"}},
+		{QuestionID: "essay", Answer: "Goroutines are lightweight threads"},
+	}
+
+	t.Run("per-type scores", func(t *testing.T) {
+		attempt, err := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{TestID: "auto", UserID: "u1", Answers: answers})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]float64{"multi": 0, "num": 1, "order": 2, "match": 0}
+		for _, r := range results(t, attempt) {
+			if r.Score != want[r.QuestionID] {
+				t.Errorf("question %s: expected %v, got %v", r.QuestionID, want[r.QuestionID], r.Score)
+			}
+		}
+		if attempt.Score != 3 || attempt.MaxScore != 9 || attempt.Percent != 33 || attempt.Passed {
+			t.Errorf("unexpected totals: %+v", attempt)
+		}
+		if attempt.Status != domain.TestAttemptGraded {
+			t.Errorf("expected graded, got %s", attempt.Status)
+		}
+	})
+
+	t.Run("multiple partial credit", func(t *testing.T) {
+		attempt, _ := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{
+			TestID: "auto", UserID: "u1",
+			Answers: []domain.TestAnswer{{QuestionID: "multi", Answers: []string{"A", "b", "b"}}},
+		})
+		if results(t, attempt)[0].Score != 2 {
+			t.Errorf("expected full credit, got %v", results(t, attempt)[0].Score)
+		}
+		attempt, _ = uc.SubmitTest(context.Background(), usecase.SubmitTestInput{
+			TestID: "auto", UserID: "u1",
+			Answers: []domain.TestAnswer{{QuestionID: "multi", Answers: []string{"a"}}},
+		})
+		if results(t, attempt)[0].Score != 1 {
+			t.Errorf("expected half credit, got %v", results(t, attempt)[0].Score)
+		}
+	})
+
+	t.Run("free text waits for review", func(t *testing.T) {
+		attempt, err := uc.SubmitTest(context.Background(), usecase.SubmitTestInput{TestID: "mixed", UserID: "u1", Answers: answers})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempt.Status != domain.TestAttemptPendingReview || attempt.Passed {
+			t.Errorf("expected pending review, got %+v", attempt)
+		}
+		if !results(t, attempt)[4].NeedsReview || attempt.MaxScore != 14 {
+			t.Errorf("unexpected essay result: %+v", results(t, attempt)[4])
+		}
+	})
+}
+
+func TestTestAttempts_ResultsHiddenUntilReveal(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewLearningRepoMock()
+	uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil)
+
+	policy := domain.AnswersRevealNever
+	repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+		return &domain.Test{
+			ID:            testID,
+			PassingScore:  50,
+			AnswersReveal: policy,
+			TestPolicy:    domain.TestPolicy{MaxAttempts: 1},
+			Questions:     []domain.TestQuestion{{ID: "q1", CorrectAnswer: "42", Points: 1}},
+		}, nil
+	}
+	newAttemptStore(repo)
+	submit := domain.TestAnswer{QuestionID: "q1", Answer: "41"}
+
+	t.Run("hidden answers keep only totals", func(t *testing.T) {
+		attempt, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1", Answers: []domain.TestAnswer{submit}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempt.Results != nil || attempt.MaxScore != 1 || attempt.Percent != 0 {
+			t.Errorf("expected totals without results, got %+v", attempt)
+		}
+		list, err := uc.GetTestAttempts(ctx, "u1", "t1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].Results != nil {
+			t.Errorf("expected attempts without results, got %+v", list)
+		}
+	})
+
+	t.Run("revealed answers include results", func(t *testing.T) {
+		policy = domain.AnswersRevealAfterSubmit
+		attempt, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u2", Answers: []domain.TestAnswer{submit}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(attempt.Results) != 1 {
+			t.Errorf("expected results once attempts are used up, got %+v", attempt)
+		}
+		list, _ := uc.GetTestAttempts(ctx, "u2", "t1")
+		if len(list) != 1 || len(list[0].Results) != 1 {
+			t.Errorf("expected attempts with results, got %+v", list)
+		}
+	})
+}
+
+func TestTestAttemptPolicies(t *testing.T) {
+	ctx := context.Background()
+	questions := []domain.TestQuestion{
+		{ID: "q1", Type: domain.QuestionSingle, Options: []string{"a", "b", "c", "d"}, CorrectAnswer: "a", Points: 1},
+		{ID: "q2", Type: domain.QuestionSingle, Options: []string{"a", "b", "c", "d"}, CorrectAnswer: "b", Points: 1},
+		{ID: "q3", Type: domain.QuestionSingle, Options: []string{"a", "b", "c", "d"}, CorrectAnswer: "c", Points: 1},
+		{ID: "q4", Type: domain.QuestionSingle, Options: []string{"a", "b", "c", "d"}, CorrectAnswer: "d", Points: 1},
+	}
+	setup := func(policy domain.TestPolicy) (*usecase.LearningUseCase, *attemptStore) {
+		repo := mocks.NewLearningRepoMock()
+		repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+			return &domain.Test{ID: testID, PassingScore: 50, AnswersReveal: domain.AnswersRevealAfterSubmit, TestPolicy: policy, Questions: questions}, nil
+		}
+		store := newAttemptStore(repo)
+		return usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil), store
+	}
+
+	t.Run("questions hidden until start", func(t *testing.T) {
+		uc, _ := setup(domain.TestPolicy{TimeLimitMin: 10})
+		view, err := uc.GetTest(ctx, "t1", "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(view.Questions) != 0 || view.Attempt != nil {
+			t.Errorf("questions must not be shown before start: %+v", view)
+		}
+		if _, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1"}); !errors.Is(err, usecase.ErrAttemptNotStarted) {
+			t.Errorf("expected ErrAttemptNotStarted, got %v", err)
+		}
+	})
+
+	t.Run("start is idempotent and sets deadline", func(t *testing.T) {
+		uc, store := setup(domain.TestPolicy{TimeLimitMin: 10})
+		first, err := uc.StartTest(ctx, "t1", "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, _ := uc.StartTest(ctx, "t1", "u1")
+		if len(store.attempts) != 1 || first.Attempt.ID != second.Attempt.ID {
+			t.Errorf("second start must resume the attempt")
+		}
+		if first.Attempt.DeadlineAt == nil || first.Attempt.DeadlineAt.Sub(first.Attempt.StartedAt) != 10*time.Minute {
+			t.Errorf("unexpected deadline: %+v", first.Attempt)
+		}
+		for _, q := range first.Questions {
+			if q.CorrectAnswer != nil {
+				t.Errorf("answers must stay hidden during an attempt")
+			}
+		}
+	})
+
+	t.Run("concurrent start resumes the winner's attempt", func(t *testing.T) {
+		repo := mocks.NewLearningRepoMock()
+		repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+			return &domain.Test{ID: testID, PassingScore: 50, TestPolicy: domain.TestPolicy{TimeLimitMin: 10}, Questions: questions}, nil
+		}
+		store := newAttemptStore(repo)
+		insert := repo.StartTestAttemptFunc
+		repo.StartTestAttemptFunc = func(ctx context.Context, attempt *domain.TestAttempt) error {
+			// Параллельный запрос успел вставить свою попытку после нашей проверки активной.
+			winner := &domain.TestAttempt{TestID: attempt.TestID, UserID: attempt.UserID, QuestionIDs: attempt.QuestionIDs, StartedAt: attempt.StartedAt}
+			if err := insert(ctx, winner); err != nil {
+				return err
+			}
+			return domain.ErrTestAttemptActive
+		}
+		uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil)
+
+		view, err := uc.StartTest(ctx, "t1", "u1")
+		if err != nil {
+			t.Fatalf("expected the active attempt, got error %v", err)
+		}
+		if len(store.attempts) != 1 || view.Attempt == nil || view.Attempt.ID != store.attempts[0].ID {
+			t.Errorf("expected the winner's attempt, got %+v", view.Attempt)
+		}
+	})
+
+	t.Run("late submission rejected", func(t *testing.T) {
+		uc, store := setup(domain.TestPolicy{TimeLimitMin: 10})
+		if _, err := uc.StartTest(ctx, "t1", "u1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		late := time.Now().Add(-time.Minute)
+		store.attempts[0].DeadlineAt = &late
+
+		_, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1", Answers: []domain.TestAnswer{{QuestionID: "q1", Answer: "a"}}})
+		if !errors.Is(err, usecase.ErrTimeLimitExceeded) {
+			t.Fatalf("expected ErrTimeLimitExceeded, got %v", err)
+		}
+		if store.attempts[0].Status != domain.TestAttemptExpired || store.attempts[0].Score != 0 {
+			t.Errorf("late attempt must expire with zero score: %+v", store.attempts[0])
+		}
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		uc, _ := setup(domain.TestPolicy{MaxAttempts: 2})
+		for i := 0; i < 2; i++ {
+			if _, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1"}); err != nil {
+				t.Fatalf("attempt %d: unexpected error: %v", i+1, err)
+			}
+		}
+		if _, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1"}); !errors.Is(err, usecase.ErrNoAttemptsLeft) {
+			t.Errorf("expected ErrNoAttemptsLeft, got %v", err)
+		}
+		if _, err := uc.StartTest(ctx, "t1", "u1"); !errors.Is(err, usecase.ErrNoAttemptsLeft) {
+			t.Errorf("expected ErrNoAttemptsLeft on start, got %v", err)
+		}
+	})
+
+	t.Run("pool draws stable shuffled subset", func(t *testing.T) {
+		uc, store := setup(domain.TestPolicy{PoolSize: 2, ShuffleQuestions: true, ShuffleOptions: true})
+		view, err := uc.StartTest(ctx, "t1", "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(view.Questions) != 2 || len(store.attempts[0].QuestionIDs) != 2 {
+			t.Fatalf("expected 2 drawn questions, got %d", len(view.Questions))
+		}
+		again, _ := uc.GetTest(ctx, "t1", "u1")
+		for i := range view.Questions {
+			if view.Questions[i].ID != again.Questions[i].ID ||
+				fmt.Sprint(view.Questions[i].Options) != fmt.Sprint(again.Questions[i].Options) {
+				t.Errorf("view must be stable within an attempt")
+			}
+		}
+
+		answers := []domain.TestAnswer{}
+		for _, q := range questions {
+			answers = append(answers, domain.TestAnswer{QuestionID: q.ID, Answer: q.CorrectAnswer})
+		}
+		attempt, err := uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1", Answers: answers})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempt.MaxScore != 2 || len(attempt.Answers) != 2 || attempt.Percent != 100 {
+			t.Errorf("only drawn questions must be graded: %+v", attempt)
+		}
+	})
+
+	t.Run("last scoring", func(t *testing.T) {
+		uc, _ := setup(domain.TestPolicy{Scoring: domain.TestScoringLast})
+		all := []domain.TestAnswer{{QuestionID: "q1", Answer: "a"}, {QuestionID: "q2", Answer: "b"}, {QuestionID: "q3", Answer: "c"}, {QuestionID: "q4", Answer: "d"}}
+		uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1", Answers: all})
+		uc.SubmitTest(ctx, usecase.SubmitTestInput{TestID: "t1", UserID: "u1", Answers: all[:1]})
+
+		view, _ := uc.GetTest(ctx, "t1", "u1")
+		if view.Result.BestPercent != 100 || view.Result.Percent != 25 || view.Result.Passed {
+			t.Errorf("last attempt must count: %+v", view.Result)
+		}
+	})
+}
+
+func TestOrderingOptionsShuffled(t *testing.T) {
+	ctx := context.Background()
+	var questions []domain.TestQuestion
+	for i := 0; i < 10; i++ {
+		order := []string{"a", "b", "c", "d"}
+		if i%2 == 0 {
+			order = []string{"a", "b"}
+		}
+		questions = append(questions, domain.TestQuestion{
+			ID: fmt.Sprintf("q%d", i), Type: domain.QuestionOrdering, Options: order, CorrectAnswers: order, Points: 1,
+		})
+	}
+	for _, policy := range []domain.TestPolicy{{}, {TimeLimitMin: 10}} {
+		repo := mocks.NewLearningRepoMock()
+		repo.GetTestByIDFunc = func(ctx context.Context, testID string) (*domain.Test, error) {
+			return &domain.Test{ID: testID, TestPolicy: policy, Questions: questions}, nil
+		}
+		newAttemptStore(repo)
+		uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil)
+
+		view, err := uc.GetTest(ctx, "t1", "u1")
+		if policy.RequiresStart() {
+			view, err = uc.StartTest(ctx, "t1", "u1")
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(view.Questions) != len(questions) {
+			t.Fatalf("expected %d questions, got %d", len(questions), len(view.Questions))
+		}
+		for i, q := range view.Questions {
+			if fmt.Sprint(q.Options) == fmt.Sprint(questions[i].CorrectAnswers) {
+				t.Errorf("policy %+v: %s options shown in the correct order: %v", policy, q.ID, q.Options)
+			}
+		}
+	}
+}
+
+func TestSubmitProject(t *testing.T) {
+	ctx := context.Background()
+	setup := func() (*usecase.LearningUseCase, *[]*domain.ProjectSubmission) {
+		repo := mocks.NewLearningRepoMock()
+		var saved []*domain.ProjectSubmission
+		repo.GetProjectByIDFunc = func(ctx context.Context, projectID string) (*domain.Project, error) {
+			if projectID == "missing" {
+				return nil, sql.ErrNoRows
+			}
+			return &domain.Project{ID: projectID, Title: "Bot", MaxScore: 50}, nil
+		}
+		repo.GetProjectSubmissionsFunc = func(ctx context.Context, userID, projectID string) ([]*domain.ProjectSubmission, error) {
+			list := []*domain.ProjectSubmission{}
+			for i := len(saved) - 1; i >= 0; i-- {
+				list = append(list, saved[i])
+			}
+			return list, nil
+		}
+		repo.SaveProjectSubmissionFunc = func(ctx context.Context, sub *domain.ProjectSubmission) error {
+			sub.ID = fmt.Sprintf("sub-%d", len(saved)+1)
+			sub.Version = len(saved) + 1
+			sub.Status = "pending_check"
+			saved = append(saved, sub)
+			return nil
+		}
+		return usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil), &saved
+	}
+
+	t.Run("resubmission adds a version", func(t *testing.T) {
+		uc, saved := setup()
+		for i := 0; i < 2; i++ {
+			if _, err := uc.SubmitProject(ctx, usecase.SubmitProjectInput{ProjectID: "p1", UserID: "u1", TextAnswer: "repo link"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(*saved) != 2 || (*saved)[1].Version != 2 {
+			t.Fatalf("expected two versions, got %d", len(*saved))
+		}
+
+		(*saved)[1].Status, (*saved)[1].Grade, (*saved)[1].TeacherComment = "accepted", 45, "great"
+		p, err := uc.GetProject(ctx, "p1", "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.SubmissionStatus != "accepted" || p.SubmissionVersion != 2 || p.Grade != 45 || p.TeacherComment != "great" {
+			t.Errorf("project must show the latest version: %+v", p)
+		}
+
+		_, err = uc.SubmitProject(ctx, usecase.SubmitProjectInput{ProjectID: "p1", UserID: "u1", TextAnswer: "v3"})
+		if !errors.Is(err, usecase.ErrProjectAlreadyAccepted) {
+			t.Errorf("expected ErrProjectAlreadyAccepted, got %v", err)
+		}
+	})
+
+	t.Run("empty submission", func(t *testing.T) {
+		uc, _ := setup()
+		_, err := uc.SubmitProject(ctx, usecase.SubmitProjectInput{ProjectID: "p1", UserID: "u1", TextAnswer: "  "})
+		if !errors.Is(err, usecase.ErrEmptySubmission) {
+			t.Errorf("expected ErrEmptySubmission, got %v", err)
+		}
+	})
+
+	t.Run("text answer too long", func(t *testing.T) {
+		uc, saved := setup()
+		long := strings.Repeat("ы", usecase.MaxTextAnswerLength+1)
+		_, err := uc.SubmitProject(ctx, usecase.SubmitProjectInput{ProjectID: "p1", UserID: "u1", TextAnswer: long})
+		if !errors.Is(err, usecase.ErrTextAnswerTooLong) || len(*saved) != 0 {
+			t.Errorf("expected ErrTextAnswerTooLong, got %v", err)
+		}
+		err = uc.SubmitAssignment(ctx, usecase.SubmitAssignmentInput{LessonID: "l1", UserID: "u1", TextAnswer: long})
+		if !errors.Is(err, usecase.ErrTextAnswerTooLong) {
+			t.Errorf("expected ErrTextAnswerTooLong for homework, got %v", err)
+		}
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		uc, _ := setup()
+		_, err := uc.SubmitProject(ctx, usecase.SubmitProjectInput{ProjectID: "missing", UserID: "u1", TextAnswer: "x"})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
+
+// notEnrolled — ученик не записан на курс: проверка завершения курса ничего не выдаёт.
+// unlocked — у курса нет обязательных правил, поэтому все его уроки открыты.
+func unlocked(repo *mocks.LearningRepoMock) {
+	repo.GetLessonCourseIDFunc = func(ctx context.Context, lessonID string) (string, error) {
+		return "c1", nil
+	}
+	repo.GetCourseRulesFunc = func(ctx context.Context, courseID string) (*domain.Course, error) {
+		return &domain.Course{ID: courseID}, nil
+	}
+	repo.GetLessonGatesFunc = func(ctx context.Context, courseID, userID string) ([]domain.LessonGate, error) {
+		return nil, nil
+	}
+}
+
+func notEnrolled(repo *mocks.LearningRepoMock) {
+	repo.GetCertificateFunc = func(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+		return nil, sql.ErrNoRows
+	}
+	repo.GetCourseRulesFunc = func(ctx context.Context, courseID string) (*domain.Course, error) {
+		return &domain.Course{ID: courseID}, nil
+	}
+	repo.GetCourseCompletionFunc = func(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error) {
+		return nil, sql.ErrNoRows
+	}
+}
+
+func TestCourseCompletion(t *testing.T) {
+	course := &domain.Course{IsHomeworkMandatory: true}
+	lessons := []domain.LessonGate{{LessonID: "l1", HasHomework: true, HomeworkAccepted: true}, {LessonID: "l2"}}
+
+	cases := []struct {
+		name       string
+		completion domain.CourseCompletion
+		want       bool
+	}{
+		{"all lessons done", domain.CourseCompletion{Lessons: lessons, LessonsDone: 2, Assignments: 1, ProgressPercent: 75}, true},
+		{"lesson not done", domain.CourseCompletion{Lessons: lessons, LessonsDone: 1, Assignments: 1, ProgressPercent: 75}, false},
+		{"low progress", domain.CourseCompletion{Lessons: lessons, LessonsDone: 2, Assignments: 1, ProgressPercent: domain.CertificateMinProgress - 1}, false},
+		{"no assignments", domain.CourseCompletion{Lessons: []domain.LessonGate{{LessonID: "l1"}}, LessonsDone: 1}, true},
+		{"mandatory homework not accepted", domain.CourseCompletion{
+			Lessons: []domain.LessonGate{{LessonID: "l1", HasHomework: true}}, LessonsDone: 1, Assignments: 1, ProgressPercent: 100,
+		}, false},
+		{"empty course", domain.CourseCompletion{}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.completion.Completed(course); got != tc.want {
+				t.Fatalf("Completed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompleteCourse(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func(completion *domain.CourseCompletion) (*mocks.LearningRepoMock, map[string]*domain.Certificate) {
+		repo := mocks.NewLearningRepoMock()
+		certs := map[string]*domain.Certificate{}
+		repo.GetCertificateFunc = func(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+			if c, ok := certs[userID+"/"+courseID]; ok {
+				return c, nil
+			}
+			return nil, sql.ErrNoRows
+		}
+		repo.GetCourseRulesFunc = func(ctx context.Context, courseID string) (*domain.Course, error) {
+			return &domain.Course{ID: courseID, IsTestMandatory: true}, nil
+		}
+		repo.GetCourseCompletionFunc = func(ctx context.Context, courseID, userID string) (*domain.CourseCompletion, error) {
+			return completion, nil
+		}
+		repo.GetCertificateHolderFunc = func(ctx context.Context, userID, courseID string) (string, string, error) {
+			return "Anna Ivanova", "Go Basics", nil
+		}
+		repo.CreateCertificateFunc = func(ctx context.Context, c *domain.Certificate) error {
+			c.ID = "cert-1"
+			certs[c.UserID+"/"+c.CourseID] = c
+			return nil
+		}
+		return repo, certs
+	}
+	done := &domain.CourseCompletion{Lessons: []domain.LessonGate{{LessonID: "l1", Tests: 1, TestsPassed: 1}}, LessonsDone: 1}
+
+	t.Run("completed course issues certificate once", func(t *testing.T) {
+		repo, certs := newRepo(done)
+		audit := &mocks.AuditLoggerMock{}
+		uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, audit)
+		cert, err := uc.CompleteCourse(ctx, "u1", "c1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cert == nil || cert.ID != "cert-1" || cert.StudentName != "Anna Ivanova" || cert.CourseTitle != "Go Basics" {
+			t.Fatalf("unexpected certificate %+v", cert)
+		}
+		if !regexp.MustCompile(`^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`).MatchString(cert.Code) {
+			t.Errorf("unexpected code %q", cert.Code)
+		}
+		if cert.PDFURL != "https://mock-s3-url.com/certificates/"+cert.Code+".pdf" {
+			t.Errorf("unexpected pdf url %q", cert.PDFURL)
+		}
+		repo.CreateCertificateFunc = func(ctx context.Context, c *domain.Certificate) error {
+			t.Fatalf("certificate must not be issued twice")
+			return nil
+		}
+		again, err := uc.CompleteCourse(ctx, "u1", "c1")
+		if err != nil || again != certs["u1/c1"] {
+			t.Fatalf("expected the issued certificate, got %+v, %v", again, err)
+		}
+		if len(audit.Entries) != 1 || audit.Entries[0].Action != "CERTIFICATE_ISSUED" || audit.Entries[0].EntityID != "cert-1" || audit.Entries[0].UserID != nil {
+			t.Fatalf("expected one system CERTIFICATE_ISSUED entry, got %+v", audit.Entries)
+		}
+	})
+
+	t.Run("mandatory test not passed", func(t *testing.T) {
+		repo, certs := newRepo(&domain.CourseCompletion{Lessons: []domain.LessonGate{{LessonID: "l1", Tests: 1}}, LessonsDone: 1})
+		uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil)
+		cert, err := uc.CompleteCourse(ctx, "u1", "c1")
+		if err != nil || cert != nil || len(certs) != 0 {
+			t.Fatalf("expected no certificate, got %+v, %v", cert, err)
+		}
+	})
+
+	t.Run("concurrent issue returns stored certificate", func(t *testing.T) {
+		repo, certs := newRepo(done)
+		stored := &domain.Certificate{ID: "cert-0", Code: "AAAA-BBBB-CCCC", UserID: "u1", CourseID: "c1"}
+		repo.CreateCertificateFunc = func(ctx context.Context, c *domain.Certificate) error {
+			certs["u1/c1"] = stored
+			return sql.ErrNoRows
+		}
+		uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil)
+		cert, err := uc.CompleteCourse(ctx, "u1", "c1")
+		if err != nil || cert != stored {
+			t.Fatalf("expected stored certificate, got %+v, %v", cert, err)
+		}
+	})
+
+	t.Run("verify by code", func(t *testing.T) {
+		repo := mocks.NewLearningRepoMock()
+		repo.GetCertificateByCodeFunc = func(ctx context.Context, code string) (*domain.Certificate, error) {
+			if code != "AAAA-BBBB-CCCC" {
+				return nil, sql.ErrNoRows
+			}
+			return &domain.Certificate{Code: code, StudentName: "Anna Ivanova", CourseTitle: "Go Basics"}, nil
+		}
+		uc := usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, nil)
+		v, err := uc.VerifyCertificate(ctx, " aaaa-bbbb-cccc ")
+		if err != nil || !v.Valid || v.StudentName != "Anna Ivanova" {
+			t.Fatalf("unexpected verification %+v, %v", v, err)
+		}
+		if _, err := uc.VerifyCertificate(ctx, "ZZZZ-ZZZZ-ZZZZ"); !errors.Is(err, domain.ErrCertificateNotFound) {
+			t.Fatalf("expected ErrCertificateNotFound, got %v", err)
+		}
+	})
+}
+
+func TestRevokeCertificate(t *testing.T) {
+	ctx := context.Background()
+	newUseCase := func() (*usecase.LearningUseCase, *mocks.AuditLoggerMock, *domain.Certificate) {
+		repo := mocks.NewLearningRepoMock()
+		cert := &domain.Certificate{ID: "cert-1", Code: "AAAA-BBBB-CCCC", UserID: "u1", CourseID: "c1", StudentName: "Anna Ivanova"}
+		repo.GetCertificateByCodeFunc = func(ctx context.Context, code string) (*domain.Certificate, error) {
+			if code != cert.Code {
+				return nil, sql.ErrNoRows
+			}
+			cp := *cert
+			return &cp, nil
+		}
+		repo.RevokeCertificateFunc = func(ctx context.Context, c *domain.Certificate, revokedBy, reason string) error {
+			if cert.RevokedAt != nil {
+				return sql.ErrNoRows
+			}
+			now := time.Now()
+			cert.RevokedAt, cert.RevokeReason = &now, reason
+			c.RevokedAt, c.RevokeReason = &now, reason
+			return nil
+		}
+		repo.GetCertificateFunc = func(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+			return cert, nil
+		}
+		audit := &mocks.AuditLoggerMock{}
+		return usecase.NewLearningUseCase(repo, pkgMocks.NewS3StorageMock(), nil, audit), audit, cert
+	}
+
+	t.Run("revoked certificate fails verification and is not reissued", func(t *testing.T) {
+		uc, audit, _ := newUseCase()
+		revoked, err := uc.RevokeCertificate(ctx, "admin-1", "aaaa-bbbb-cccc", "  plagiarism  ", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revoked.RevokedAt == nil || revoked.RevokeReason != "plagiarism" {
+			t.Fatalf("unexpected revoked certificate %+v", revoked)
+		}
+		v, err := uc.VerifyCertificate(ctx, "AAAA-BBBB-CCCC")
+		if err != nil || v.Valid || v.RevokedAt == nil {
+			t.Fatalf("expected invalid certificate, got %+v, %v", v, err)
+		}
+		cert, err := uc.CompleteCourse(ctx, "u1", "c1")
+		if err != nil || cert.Valid() {
+			t.Fatalf("expected the revoked certificate to stay revoked, got %+v, %v", cert, err)
+		}
+		if len(audit.Entries) != 1 || audit.Entries[0].Action != "CERTIFICATE_REVOKED" || *audit.Entries[0].UserID != "admin-1" {
+			t.Fatalf("expected CERTIFICATE_REVOKED by admin-1, got %+v", audit.Entries)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		uc, audit, _ := newUseCase()
+		if _, err := uc.RevokeCertificate(ctx, "admin-1", "AAAA-BBBB-CCCC", " ", nil, nil); !errors.Is(err, domain.ErrRevokeReasonRequired) {
+			t.Fatalf("expected ErrRevokeReasonRequired, got %v", err)
+		}
+		if _, err := uc.RevokeCertificate(ctx, "admin-1", "ZZZZ-ZZZZ-ZZZZ", "fraud", nil, nil); !errors.Is(err, domain.ErrCertificateNotFound) {
+			t.Fatalf("expected ErrCertificateNotFound, got %v", err)
+		}
+		if _, err := uc.RevokeCertificate(ctx, "admin-1", "AAAA-BBBB-CCCC", "fraud", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.RevokeCertificate(ctx, "admin-1", "AAAA-BBBB-CCCC", "fraud", nil, nil); !errors.Is(err, domain.ErrCertificateRevoked) {
+			t.Fatalf("expected ErrCertificateRevoked, got %v", err)
+		}
+		if len(audit.Entries) != 1 {
+			t.Fatalf("only the successful revoke must be logged, got %+v", audit.Entries)
+		}
+	})
+}
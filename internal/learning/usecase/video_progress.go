@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+// RecordVideoProgress учитывает сообщение плеера о просмотре видео урока. Когда просмотрено
+// domain.VideoCompletionPercent процентов видео, урок считается пройденным и проверяется завершение курса.
+// Длительность видео берётся из урока (duration_min), а не из сообщения плеера; урок без видео — ErrInvalidVideoPing.
+func (uc *LearningUseCase) RecordVideoProgress(ctx context.Context, userID, lessonID string, ping domain.VideoPing) (*domain.VideoProgress, error) {
+	courseID, err := uc.unlockedLessonCourse(ctx, lessonID, userID)
+	if err != nil {
+		return nil, err
+	}
+	videoURL, durationMin, err := uc.repo.GetLessonVideo(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if videoURL == "" || durationMin <= 0 {
+		return nil, fmt.Errorf("%w: lesson has no video", domain.ErrInvalidVideoPing)
+	}
+	ping.DurationSec = durationMin * 60
+	if err := ping.Validate(); err != nil {
+		return nil, err
+	}
+
+	progress, err := uc.repo.GetVideoProgress(ctx, userID, lessonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		progress, err = &domain.VideoProgress{LessonID: lessonID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	wasCompleted := progress.CompletedAt != nil
+	if err := progress.Record(ping, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SaveVideoProgress(ctx, userID, progress); err != nil {
+		return nil, err
+	}
+	if !wasCompleted && progress.CompletedAt != nil {
+		uc.checkCourseCompletion(ctx, userID, courseID)
+	}
+	return progress, nil
+}
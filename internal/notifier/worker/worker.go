@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"net/url"
 	"sync"
 	"time"
 
@@ -14,12 +15,14 @@ import (
 type NotificationWorker struct {
 	broker      *broker.EventBroker
 	workerCount int
+	resetURL    string
 }
 
-func NewNotificationWorker(b *broker.EventBroker, count int) *NotificationWorker {
+func NewNotificationWorker(b *broker.EventBroker, count int, resetURL string) *NotificationWorker {
 	return &NotificationWorker{
 		broker:      b,
 		workerCount: count,
+		resetURL:    resetURL,
 	}
 }
 
@@ -78,6 +81,8 @@ func (w *NotificationWorker) startWorker(id int, tasks <-chan broker.Task) {
 			w.handleEmail(t.Payload)
 		case "NEW_SUBMISSION":
 			w.handleNewSubmission(t.Payload)
+		case "PASSWORD_RESET":
+			w.handlePasswordReset(t.Payload)
 		}
 	}
 }
@@ -90,3 +95,17 @@ func (w *NotificationWorker) handleEmail(payload map[string]string) {
 func (w *NotificationWorker) handleNewSubmission(payload map[string]string) {
 	slog.Info("Notifying staff about new submission", slog.String("student_id", payload["student_id"]))
 }
+
+// handlePasswordReset отправляет ссылку сброса пароля. Сам токен в лог не пишем.
+func (w *NotificationWorker) handlePasswordReset(payload map[string]string) {
+	link := w.resetURL + "?token=" + url.QueryEscape(payload["token"])
+	body := "Здравствуйте, " + payload["first_name"] + "!\n\n" +
+		"Для сброса пароля перейдите по ссылке: " + link + "\n" +
+		"Ссылка действительна до " + payload["expires_at"] + ". Если вы не запрашивали сброс, проигнорируйте это письмо."
+	w.sendEmail(payload["email"], "Сброс пароля Cap Education", body)
+}
+
+func (w *NotificationWorker) sendEmail(to, subject, body string) {
+	slog.Info("Sending email", slog.String("email", to), slog.String("subject", subject), slog.Int("body_len", len(body)))
+	time.Sleep(time.Second * 1)
+}
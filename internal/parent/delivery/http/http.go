@@ -0,0 +1,239 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
+	"lms_backend/internal/httperror"
+	"lms_backend/internal/parent/usecase"
+)
+
+type ParentHandler struct {
+	uc usecase.ParentUseCase
+}
+
+func NewParentHandler(uc usecase.ParentUseCase) *ParentHandler {
+	return &ParentHandler{uc: uc}
+}
+
+type FreezeRequestReq struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason"`
+}
+
+// GetChildren godoc
+// @Summary PARENT: Привязанные дети
+// @Tags Parent
+// @Produce json
+// @Success 200 {array} domain.ParentChild
+// @Router /api/parent/children [get]
+func (h *ParentHandler) GetChildren(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	children, err := h.uc.GetChildren(r.Context(), actor)
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(children)
+}
+
+// GetChildSchedule godoc
+// @Summary PARENT: Расписание ребёнка на неделю
+// @Tags Parent
+// @Param childId path string true "Child ID"
+// @Param date query string false "Дата внутри недели (YYYY-MM-DD)"
+// @Success 200 {object} domain.WeeklySchedule
+// @Router /api/parent/children/{childId}/schedule [get]
+func (h *ParentHandler) GetChildSchedule(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	date := time.Now()
+	if s := r.URL.Query().Get("date"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, "Invalid date format", http.StatusBadRequest)
+			return
+		}
+		date = d
+	}
+
+	schedule, err := h.uc.GetChildSchedule(r.Context(), actor, chi.URLParam(r, "childId"), date)
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// GetChildAttendance godoc
+// @Summary PARENT: Календарь посещаемости ребёнка
+// @Tags Parent
+// @Param childId path string true "Child ID"
+// @Param start_date query string false "YYYY-MM-DD, по умолчанию месяц назад"
+// @Param end_date query string false "YYYY-MM-DD, по умолчанию сегодня"
+// @Success 200 {array} domain.AttendanceRecord
+// @Router /api/parent/children/{childId}/attendance [get]
+func (h *ParentHandler) GetChildAttendance(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	startDate := time.Now().AddDate(0, -1, 0)
+	endDate := time.Now()
+	var err error
+	if s := r.URL.Query().Get("start_date"); s != "" {
+		if startDate, err = time.Parse("2006-01-02", s); err != nil {
+			http.Error(w, "Invalid start_date format", http.StatusBadRequest)
+			return
+		}
+	}
+	if s := r.URL.Query().Get("end_date"); s != "" {
+		if endDate, err = time.Parse("2006-01-02", s); err != nil {
+			http.Error(w, "Invalid end_date format", http.StatusBadRequest)
+			return
+		}
+	}
+
+	records, err := h.uc.GetChildAttendance(r.Context(), actor, chi.URLParam(r, "childId"), startDate, endDate)
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// GetChildGrades godoc
+// @Summary PARENT: Оценки ребёнка за ДЗ
+// @Tags Parent
+// @Param childId path string true "Child ID"
+// @Success 200 {array} domain.ChildGrade
+// @Router /api/parent/children/{childId}/grades [get]
+func (h *ParentHandler) GetChildGrades(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	grades, err := h.uc.GetChildGrades(r.Context(), actor, chi.URLParam(r, "childId"))
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grades)
+}
+
+// GetChildFreeze godoc
+// @Summary PARENT: Заморозка ребёнка — текущий статус и заявки
+// @Tags Parent
+// @Param childId path string true "Child ID"
+// @Success 200 {object} domain.ChildFreezeInfo
+// @Router /api/parent/children/{childId}/freeze [get]
+func (h *ParentHandler) GetChildFreeze(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	info, err := h.uc.GetChildFreeze(r.Context(), actor, chi.URLParam(r, "childId"))
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// RequestFreeze godoc
+// @Summary PARENT: Подать заявку на заморозку за ребёнка
+// @Tags Parent
+// @Param childId path string true "Child ID"
+// @Param body body FreezeRequestReq true "Период и причина"
+// @Success 200 {object} map[string]string
+// @Router /api/parent/children/{childId}/freeze-requests [post]
+func (h *ParentHandler) RequestFreeze(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req FreezeRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		http.Error(w, "Invalid start_date format", http.StatusBadRequest)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		http.Error(w, "Invalid end_date format", http.StatusBadRequest)
+		return
+	}
+
+	err = h.uc.RequestFreeze(r.Context(), actor, chi.URLParam(r, "childId"), startDate, endDate, req.Reason)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPeriod) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Freeze request created successfully"})
+}
+
+// GetChildComments godoc
+// @Summary PARENT: Комментарии преподавателей о ребёнке
+// @Tags Parent
+// @Param childId path string true "Child ID"
+// @Success 200 {array} domain.Comment
+// @Router /api/parent/children/{childId}/comments [get]
+func (h *ParentHandler) GetChildComments(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	comments, err := h.uc.GetChildComments(r.Context(), actor, chi.URLParam(r, "childId"))
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/parent/repository"
+)
+
+// ParentRepositoryMock — Children хранит только активные связи, как и запрос в БД.
+type ParentRepositoryMock struct {
+	Children map[string][]*domain.ParentChild
+	Grades   map[string][]*domain.ChildGrade
+}
+
+var _ repository.ParentRepository = (*ParentRepositoryMock)(nil)
+
+func NewParentRepositoryMock() *ParentRepositoryMock {
+	return &ParentRepositoryMock{
+		Children: make(map[string][]*domain.ParentChild),
+		Grades:   make(map[string][]*domain.ChildGrade),
+	}
+}
+
+func (r *ParentRepositoryMock) GetChildren(ctx context.Context, parentID string) ([]*domain.ParentChild, error) {
+	children := r.Children[parentID]
+	if children == nil {
+		children = []*domain.ParentChild{}
+	}
+	return children, nil
+}
+
+func (r *ParentRepositoryMock) GetChildGrades(ctx context.Context, childID string) ([]*domain.ChildGrade, error) {
+	grades := r.Grades[childID]
+	if grades == nil {
+		grades = []*domain.ChildGrade{}
+	}
+	return grades, nil
+}
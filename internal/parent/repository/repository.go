@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"lms_backend/internal/domain"
+)
+
+type ParentRepository interface {
+	GetChildren(ctx context.Context, parentID string) ([]*domain.ParentChild, error)
+	GetChildGrades(ctx context.Context, childID string) ([]*domain.ChildGrade, error)
+}
+
+type parentRepository struct {
+	db *sql.DB
+}
+
+func NewParentRepository(db *sql.DB) ParentRepository {
+	return &parentRepository{db: db}
+}
+
+// GetChildren возвращает только активные связи родитель — ребёнок.
+func (r *parentRepository) GetChildren(ctx context.Context, parentID string) ([]*domain.ParentChild, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.first_name, u.last_name, COALESCE(u.avatar_url, '')
+		FROM child_parent_link cpl
+		JOIN users u ON u.id = cpl.child_id
+		WHERE cpl.parent_id = $1 AND cpl.is_active = TRUE
+		ORDER BY u.first_name, u.last_name
+	`, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := []*domain.ParentChild{}
+	byID := make(map[string]*domain.ParentChild)
+	var ids []string
+	for rows.Next() {
+		c := &domain.ParentChild{Courses: []domain.ChildCourse{}}
+		if err := rows.Scan(&c.ID, &c.FirstName, &c.LastName, &c.AvatarURL); err != nil {
+			return nil, err
+		}
+		children = append(children, c)
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return children, nil
+	}
+
+	courseRows, err := r.db.QueryContext(ctx, `
+		SELECT uc.user_id, c.id, c.title, COALESCE(g.title, ''), uc.progress_percent
+		FROM user_courses uc
+		JOIN courses c ON c.id = uc.course_id
+		LEFT JOIN groups g ON g.id = uc.group_id
+		WHERE uc.user_id = ANY($1)
+		ORDER BY c.title
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer courseRows.Close()
+
+	for courseRows.Next() {
+		var childID string
+		var cc domain.ChildCourse
+		if err := courseRows.Scan(&childID, &cc.CourseID, &cc.Title, &cc.GroupTitle, &cc.ProgressPercent); err != nil {
+			return nil, err
+		}
+		if c, ok := byID[childID]; ok {
+			c.Courses = append(c.Courses, cc)
+		}
+	}
+	return children, courseRows.Err()
+}
+
+func (r *parentRepository) GetChildGrades(ctx context.Context, childID string) ([]*domain.ChildGrade, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.title, l.id, l.title, m.order_num, l.order_num,
+			uas.status, COALESCE(uas.grade, 0), COALESCE(uas.teacher_comment, ''), uas.submitted_at
+		FROM user_assignments_submission uas
+		JOIN assignments a ON a.id = uas.assignment_id
+		JOIN lessons l ON l.id = a.lesson_id
+		JOIN modules m ON m.id = l.module_id
+		JOIN courses c ON c.id = m.course_id
+		WHERE uas.user_id = $1
+		ORDER BY c.title, m.order_num, l.order_num
+	`, childID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grades := []*domain.ChildGrade{}
+	for rows.Next() {
+		g := &domain.ChildGrade{}
+		if err := rows.Scan(
+			&g.CourseID, &g.CourseTitle, &g.LessonID, &g.LessonTitle, &g.ModuleOrder, &g.LessonOrder,
+			&g.Status, &g.Grade, &g.TeacherComment, &g.SubmittedAt,
+		); err != nil {
+			return nil, err
+		}
+		grades = append(grades, g)
+	}
+	return grades, rows.Err()
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/parent/repository"
+)
+
+var ErrInvalidPeriod = errors.New("end_date must be after start_date")
+
+// ParentUseCase — кабинет родителя. Каждый метод с childID сначала проверяет
+// активную связь в child_parent_link, остальные модули вызываются уже от имени родителя.
+type ParentUseCase interface {
+	GetChildren(ctx context.Context, actor domain.Actor) ([]*domain.ParentChild, error)
+	GetChildSchedule(ctx context.Context, actor domain.Actor, childID string, date time.Time) (*domain.WeeklySchedule, error)
+	GetChildAttendance(ctx context.Context, actor domain.Actor, childID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error)
+	GetChildGrades(ctx context.Context, actor domain.Actor, childID string) ([]*domain.ChildGrade, error)
+	GetChildFreeze(ctx context.Context, actor domain.Actor, childID string) (*domain.ChildFreezeInfo, error)
+	GetChildComments(ctx context.Context, actor domain.Actor, childID string) ([]*domain.Comment, error)
+	RequestFreeze(ctx context.Context, actor domain.Actor, childID string, startDate, endDate time.Time, reason string) error
+}
+
+// ScopeChecker реализует scope.ScopeUseCase.
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+}
+
+type ScheduleReader interface {
+	GetWeeklySchedule(ctx context.Context, userID string, date time.Time) (*domain.WeeklySchedule, error)
+}
+
+type AttendanceReader interface {
+	GetStudentCalendar(ctx context.Context, actor domain.Actor, studentID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error)
+}
+
+type FreezeService interface {
+	CreateRequest(ctx context.Context, actor domain.Actor, studentID string, startDate, endDate time.Time, reason string) error
+	GetStudentFreezeStatus(ctx context.Context, actor domain.Actor, studentID string) (*domain.FreezePeriod, error)
+	GetStudentRequests(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.FreezeRequest, error)
+}
+
+type CommentReader interface {
+	GetStudentComments(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.Comment, error)
+}
+
+type parentUseCase struct {
+	repo       repository.ParentRepository
+	scope      ScopeChecker
+	schedule   ScheduleReader
+	attendance AttendanceReader
+	freeze     FreezeService
+	comments   CommentReader
+}
+
+func NewParentUseCase(
+	repo repository.ParentRepository,
+	scope ScopeChecker,
+	schedule ScheduleReader,
+	attendance AttendanceReader,
+	freeze FreezeService,
+	comments CommentReader,
+) ParentUseCase {
+	return &parentUseCase{
+		repo:       repo,
+		scope:      scope,
+		schedule:   schedule,
+		attendance: attendance,
+		freeze:     freeze,
+		comments:   comments,
+	}
+}
+
+func (uc *parentUseCase) GetChildren(ctx context.Context, actor domain.Actor) ([]*domain.ParentChild, error) {
+	if actor.Role != domain.RoleParent {
+		return nil, domain.ErrOutOfScope
+	}
+	return uc.repo.GetChildren(ctx, actor.UserID)
+}
+
+// checkChild пропускает только родителя с активной связью с ребёнком.
+func (uc *parentUseCase) checkChild(ctx context.Context, actor domain.Actor, childID string) error {
+	if actor.Role != domain.RoleParent {
+		return domain.ErrOutOfScope
+	}
+	return uc.scope.CanAccessStudent(ctx, actor, childID)
+}
+
+func (uc *parentUseCase) GetChildSchedule(ctx context.Context, actor domain.Actor, childID string, date time.Time) (*domain.WeeklySchedule, error) {
+	if err := uc.checkChild(ctx, actor, childID); err != nil {
+		return nil, err
+	}
+	return uc.schedule.GetWeeklySchedule(ctx, childID, date)
+}
+
+func (uc *parentUseCase) GetChildAttendance(ctx context.Context, actor domain.Actor, childID string, startDate, endDate time.Time) ([]*domain.AttendanceRecord, error) {
+	if err := uc.checkChild(ctx, actor, childID); err != nil {
+		return nil, err
+	}
+	return uc.attendance.GetStudentCalendar(ctx, actor, childID, startDate, endDate)
+}
+
+func (uc *parentUseCase) GetChildGrades(ctx context.Context, actor domain.Actor, childID string) ([]*domain.ChildGrade, error) {
+	if err := uc.checkChild(ctx, actor, childID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetChildGrades(ctx, childID)
+}
+
+func (uc *parentUseCase) GetChildFreeze(ctx context.Context, actor domain.Actor, childID string) (*domain.ChildFreezeInfo, error) {
+	if err := uc.checkChild(ctx, actor, childID); err != nil {
+		return nil, err
+	}
+	status, err := uc.freeze.GetStudentFreezeStatus(ctx, actor, childID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := uc.freeze.GetStudentRequests(ctx, actor, childID)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []*domain.FreezeRequest{}
+	}
+	return &domain.ChildFreezeInfo{Status: status, Requests: requests}, nil
+}
+
+// GetChildComments отдаёт комментарии, адресованные ученику или родителю;
+// переписка сотрудников между собой родителю не видна.
+func (uc *parentUseCase) GetChildComments(ctx context.Context, actor domain.Actor, childID string) ([]*domain.Comment, error) {
+	if err := uc.checkChild(ctx, actor, childID); err != nil {
+		return nil, err
+	}
+	all, err := uc.comments.GetStudentComments(ctx, actor, childID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]*domain.Comment, 0, len(all))
+	for _, c := range all {
+		if c.RecipientID == nil || *c.RecipientID == childID || *c.RecipientID == actor.UserID {
+			visible = append(visible, c)
+		}
+	}
+	return visible, nil
+}
+
+func (uc *parentUseCase) RequestFreeze(ctx context.Context, actor domain.Actor, childID string, startDate, endDate time.Time, reason string) error {
+	if err := uc.checkChild(ctx, actor, childID); err != nil {
+		return err
+	}
+	if endDate.Before(startDate) {
+		return ErrInvalidPeriod
+	}
+	return uc.freeze.CreateRequest(ctx, actor, childID, startDate, endDate, reason)
+}
@@ -0,0 +1,173 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	attendanceMocks "lms_backend/internal/attendance/mocks"
+	attendanceUseCase "lms_backend/internal/attendance/usecase"
+	commentMocks "lms_backend/internal/comment/mocks"
+	commentUseCase "lms_backend/internal/comment/usecase"
+	"lms_backend/internal/domain"
+	freezeMocks "lms_backend/internal/freeze/mocks"
+	freezeUseCase "lms_backend/internal/freeze/usecase"
+	"lms_backend/internal/parent/mocks"
+	"lms_backend/internal/parent/usecase"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
+)
+
+var (
+	parent  = domain.Actor{UserID: "parent-a", Role: domain.RoleParent}
+	teacher = domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+)
+
+type scheduleStub struct{ calledFor string }
+
+func (s *scheduleStub) GetWeeklySchedule(ctx context.Context, userID string, date time.Time) (*domain.WeeklySchedule, error) {
+	s.calledFor = userID
+	return &domain.WeeklySchedule{Days: map[string][]domain.ScheduleLesson{}}, nil
+}
+
+type fixture struct {
+	uc       usecase.ParentUseCase
+	repo     *mocks.ParentRepositoryMock
+	scope    *scopeMocks.ScopeRepositoryMock
+	schedule *scheduleStub
+	comments commentUseCase.CommentUseCase
+}
+
+// newFixture — parent-a привязан к student-a, student-b ему чужой.
+func newFixture() *fixture {
+	scopeRepo := scopeMocks.TwoGroups()
+	scope := scopeUseCase.NewScopeUseCase(scopeRepo)
+	comments := commentUseCase.NewCommentUseCase(commentMocks.NewCommentRepositoryMock(), scope)
+	schedule := &scheduleStub{}
+
+	repo := mocks.NewParentRepositoryMock()
+	repo.Children["parent-a"] = []*domain.ParentChild{{ID: "student-a", FirstName: "Alice"}}
+	repo.Grades["student-a"] = []*domain.ChildGrade{{LessonID: "lesson-a", Grade: 80, Status: "accepted"}}
+	repo.Grades["student-b"] = []*domain.ChildGrade{{LessonID: "lesson-b", Grade: 40, Status: "accepted"}}
+
+	uc := usecase.NewParentUseCase(
+		repo,
+		scope,
+		schedule,
+		attendanceUseCase.NewAttendanceUseCase(attendanceMocks.NewAttendanceRepositoryMock(), scope, nil),
+		freezeUseCase.NewFreezeUseCase(freezeMocks.NewFreezeRepositoryMock(), scope),
+		comments,
+	)
+	return &fixture{uc: uc, repo: repo, scope: scopeRepo, schedule: schedule, comments: comments}
+}
+
+func TestGetChildren(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	children, err := f.uc.GetChildren(ctx, parent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(children) != 1 || children[0].ID != "student-a" {
+		t.Fatalf("expected student-a, got %+v", children)
+	}
+
+	if _, err := f.uc.GetChildren(ctx, teacher); !errors.Is(err, domain.ErrOutOfScope) {
+		t.Fatalf("non-parent must be rejected, got %v", err)
+	}
+}
+
+func TestChildAccess(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	t.Run("own child", func(t *testing.T) {
+		grades, err := f.uc.GetChildGrades(ctx, parent, "student-a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(grades) != 1 || grades[0].Grade != 80 {
+			t.Fatalf("unexpected grades: %+v", grades)
+		}
+		if _, err := f.uc.GetChildSchedule(ctx, parent, "student-a", time.Now()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.schedule.calledFor != "student-a" {
+			t.Fatalf("schedule must be loaded for the child, got %q", f.schedule.calledFor)
+		}
+		if _, err := f.uc.GetChildAttendance(ctx, parent, "student-a", time.Now().AddDate(0, -1, 0), time.Now()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("foreign child", func(t *testing.T) {
+		if _, err := f.uc.GetChildGrades(ctx, parent, "student-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+		if _, err := f.uc.GetChildSchedule(ctx, parent, "student-b", time.Now()); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+		if _, err := f.uc.GetChildFreeze(ctx, parent, "student-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("inactive link", func(t *testing.T) {
+		f.scope.ParentChildren["parent-a"] = nil
+		defer func() { f.scope.ParentChildren["parent-a"] = []string{"student-a"} }()
+
+		if _, err := f.uc.GetChildGrades(ctx, parent, "student-a"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("staff cannot use parent routes", func(t *testing.T) {
+		if _, err := f.uc.GetChildGrades(ctx, teacher, "student-a"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
+
+func TestRequestFreeze(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := f.uc.RequestFreeze(ctx, parent, "student-a", now, now.Add(7*24*time.Hour), "family trip"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := f.uc.GetChildFreeze(ctx, parent, "student-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(info.Requests) != 1 || info.Requests[0].RequestedBy != "parent-a" {
+		t.Fatalf("expected one request by parent-a, got %+v", info.Requests)
+	}
+
+	if err := f.uc.RequestFreeze(ctx, parent, "student-a", now, now.Add(-time.Hour), "oops"); !errors.Is(err, usecase.ErrInvalidPeriod) {
+		t.Fatalf("expected ErrInvalidPeriod, got %v", err)
+	}
+	if err := f.uc.RequestFreeze(ctx, parent, "student-b", now, now.Add(time.Hour), "not mine"); !errors.Is(err, domain.ErrOutOfScope) {
+		t.Fatalf("expected ErrOutOfScope, got %v", err)
+	}
+}
+
+func TestChildCommentsHideStaffNotes(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	curator := "curator-a"
+
+	f.comments.CreateComment(ctx, teacher, "student-a", nil, nil, "Great progress", nil)
+	f.comments.CreateComment(ctx, teacher, "student-a", nil, &curator, "Talk to the parents", nil)
+
+	comments, err := f.uc.GetChildComments(ctx, parent, "student-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Content != "Great progress" {
+		t.Fatalf("expected only the comment addressed to the student, got %+v", comments)
+	}
+}
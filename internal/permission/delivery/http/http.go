@@ -0,0 +1,92 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
+	"lms_backend/internal/domain"
+	"lms_backend/internal/httperror"
+	"lms_backend/internal/permission/usecase"
+)
+
+type PermissionHandler struct {
+	uc usecase.PermissionUseCase
+}
+
+func NewPermissionHandler(uc usecase.PermissionUseCase) *PermissionHandler {
+	return &PermissionHandler{uc: uc}
+}
+
+type PermissionMatrixResponse struct {
+	Permissions []*domain.Permission      `json:"permissions"`
+	Roles       []*domain.RolePermissions `json:"roles"`
+}
+
+type UpdateRolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// GetPermissions godoc
+// @Summary ADMIN: Справочник прав и права ролей
+// @Tags Admin-Security
+// @Produce json
+// @Success 200 {object} PermissionMatrixResponse
+// @Router /api/admin/permissions [get]
+func (h *PermissionHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	perms, err := h.uc.ListPermissions(r.Context())
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+	roles, err := h.uc.GetRolePermissions(r.Context())
+	if err != nil {
+		httperror.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PermissionMatrixResponse{Permissions: perms, Roles: roles})
+}
+
+// UpdateRolePermissions godoc
+// @Summary ADMIN: Задать права роли
+// @Description Набор прав роли заменяется целиком. У роли admin нельзя убрать permissions.manage. Изменение пишется в audit_logs.
+// @Tags Admin-Security
+// @Accept json
+// @Produce json
+// @Param role path string true "Роль (admin, moderator, curator, teacher)"
+// @Param request body UpdateRolePermissionsRequest true "Коды прав"
+// @Success 200 {object} map[string]string
+// @Router /api/admin/permissions/roles/{role} [put]
+func (h *PermissionHandler) UpdateRolePermissions(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateRolePermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	role := domain.Role(chi.URLParam(r, "role"))
+	ip := authMiddleware.ClientIP(r)
+	ua := r.UserAgent()
+	if err := h.uc.SetRolePermissions(r.Context(), userCtx.UserID, role, req.Permissions, &ip, &ua); err != nil {
+		if errors.Is(err, usecase.ErrUnknownRole) || errors.Is(err, usecase.ErrUnknownPermission) || errors.Is(err, usecase.ErrAdminLockout) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		httperror.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Role permissions updated"})
+}
@@ -0,0 +1,85 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/permission/repository"
+)
+
+type PermissionRepositoryMock struct {
+	mu          sync.Mutex
+	Permissions []*domain.Permission
+	Roles       map[domain.Role][]string
+	Loads       int
+}
+
+var _ repository.PermissionRepository = (*PermissionRepositoryMock)(nil)
+
+func NewPermissionRepositoryMock(catalog ...string) *PermissionRepositoryMock {
+	m := &PermissionRepositoryMock{Roles: make(map[domain.Role][]string)}
+	for _, code := range catalog {
+		m.Permissions = append(m.Permissions, &domain.Permission{Code: code})
+	}
+	return m
+}
+
+func (m *PermissionRepositoryMock) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Permissions, nil
+}
+
+func (m *PermissionRepositoryMock) GetRolePermissions(ctx context.Context, role domain.Role) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.Roles[role]...), nil
+}
+
+func (m *PermissionRepositoryMock) GetAllRolePermissions(ctx context.Context) (map[domain.Role][]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Loads++
+	result := make(map[domain.Role][]string, len(m.Roles))
+	for role, codes := range m.Roles {
+		result[role] = append([]string{}, codes...)
+	}
+	return result, nil
+}
+
+func (m *PermissionRepositoryMock) SetRolePermissions(ctx context.Context, role domain.Role, codes []string, grantedBy string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, code := range codes {
+		known := false
+		for _, p := range m.Permissions {
+			if p.Code == code {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return repository.ErrUnknownPermission
+		}
+	}
+	m.Roles[role] = append([]string{}, codes...)
+	return nil
+}
+
+type AuditEntry struct {
+	UserID *string
+	Action string
+}
+
+type AuditLoggerMock struct {
+	mu      sync.Mutex
+	Entries []AuditEntry
+}
+
+func (a *AuditLoggerMock) LogAction(ctx context.Context, userID *string, action, entityType, entityID string, oldValues, newValues interface{}, ipAddress, userAgent *string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Entries = append(a.Entries, AuditEntry{UserID: userID, Action: action})
+	return nil
+}
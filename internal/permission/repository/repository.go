@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"lms_backend/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var ErrUnknownPermission = errors.New("unknown permission")
+
+type PermissionRepository interface {
+	ListPermissions(ctx context.Context) ([]*domain.Permission, error)
+	GetRolePermissions(ctx context.Context, role domain.Role) ([]string, error)
+	GetAllRolePermissions(ctx context.Context) (map[domain.Role][]string, error)
+	SetRolePermissions(ctx context.Context, role domain.Role, codes []string, grantedBy string) error
+}
+
+type permissionRepository struct {
+	db *sql.DB
+}
+
+func NewPermissionRepository(db *sql.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT code, description FROM permissions ORDER BY code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var perms []*domain.Permission
+	for rows.Next() {
+		p := &domain.Permission{}
+		if err := rows.Scan(&p.Code, &p.Description); err != nil {
+			return nil, err
+		}
+		perms = append(perms, p)
+	}
+	return perms, rows.Err()
+}
+
+func (r *permissionRepository) GetRolePermissions(ctx context.Context, role domain.Role) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT permission_code FROM role_permissions WHERE role = $1 ORDER BY permission_code`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+func (r *permissionRepository) GetAllRolePermissions(ctx context.Context) (map[domain.Role][]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT role, permission_code FROM role_permissions ORDER BY role, permission_code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[domain.Role][]string)
+	for rows.Next() {
+		var role domain.Role
+		var code string
+		if err := rows.Scan(&role, &code); err != nil {
+			return nil, err
+		}
+		result[role] = append(result[role], code)
+	}
+	return result, rows.Err()
+}
+
+// SetRolePermissions полностью заменяет набор прав роли одной транзакцией.
+func (r *permissionRepository) SetRolePermissions(ctx context.Context, role domain.Role, codes []string, grantedBy string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var known int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM permissions WHERE code = ANY($1)`, pq.Array(codes),
+	).Scan(&known); err != nil {
+		return err
+	}
+	if known != len(codes) {
+		return ErrUnknownPermission
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role = $1`, role); err != nil {
+		return err
+	}
+	if len(codes) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO role_permissions (role, permission_code, granted_by)
+			SELECT $1, unnest($2::text[]), $3
+		`, role, pq.Array(codes), grantedBy); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
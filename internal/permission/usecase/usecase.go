@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/permission/repository"
+)
+
+var (
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrAdminLockout      = errors.New("admin role must keep permissions.manage")
+)
+
+// cacheTTL — сколько живёт закэшированная матрица прав. Изменение через API сбрасывает кэш
+// сразу на этом инстансе; остальные инстансы подхватят его не позже чем через cacheTTL.
+const cacheTTL = 30 * time.Second
+
+// audit_logs.entity_id обязателен и имеет тип UUID, а у роли своего UUID нет.
+const auditEntityID = "00000000-0000-0000-0000-000000000000"
+
+// AuditLogger — то, что нужно от audit.AuditUseCase.
+type AuditLogger interface {
+	LogAction(ctx context.Context, userID *string, action, entityType, entityID string, oldValues, newValues interface{}, ipAddress, userAgent *string) error
+}
+
+type PermissionUseCase interface {
+	HasPermission(ctx context.Context, role domain.Role, permission string) (bool, error)
+	ListPermissions(ctx context.Context) ([]*domain.Permission, error)
+	GetRolePermissions(ctx context.Context) ([]*domain.RolePermissions, error)
+	SetRolePermissions(ctx context.Context, actorID string, role domain.Role, permissions []string, ipAddress, userAgent *string) error
+}
+
+type permissionUseCase struct {
+	repo  repository.PermissionRepository
+	audit AuditLogger
+
+	mu       sync.RWMutex
+	cache    map[domain.Role]map[string]bool
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func NewPermissionUseCase(repo repository.PermissionRepository, audit AuditLogger) PermissionUseCase {
+	return &permissionUseCase{repo: repo, audit: audit, now: time.Now}
+}
+
+// roles — роли, права которых можно настраивать. У студентов и родителей нет доступа
+// к staff-маршрутам, поэтому и настраивать им нечего.
+var roles = []domain.Role{domain.RoleAdmin, domain.RoleModerator, domain.RoleCurator, domain.RoleTeacher}
+
+func isConfigurableRole(role domain.Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *permissionUseCase) HasPermission(ctx context.Context, role domain.Role, permission string) (bool, error) {
+	matrix, err := uc.matrix(ctx)
+	if err != nil {
+		return false, err
+	}
+	return matrix[role][permission], nil
+}
+
+func (uc *permissionUseCase) matrix(ctx context.Context) (map[domain.Role]map[string]bool, error) {
+	uc.mu.RLock()
+	if uc.cache != nil && uc.now().Sub(uc.loadedAt) < cacheTTL {
+		m := uc.cache
+		uc.mu.RUnlock()
+		return m, nil
+	}
+	uc.mu.RUnlock()
+
+	all, err := uc.repo.GetAllRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[domain.Role]map[string]bool, len(all))
+	for role, codes := range all {
+		set := make(map[string]bool, len(codes))
+		for _, code := range codes {
+			set[code] = true
+		}
+		m[role] = set
+	}
+
+	uc.mu.Lock()
+	uc.cache = m
+	uc.loadedAt = uc.now()
+	uc.mu.Unlock()
+	return m, nil
+}
+
+func (uc *permissionUseCase) invalidate() {
+	uc.mu.Lock()
+	uc.cache = nil
+	uc.mu.Unlock()
+}
+
+func (uc *permissionUseCase) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	return uc.repo.ListPermissions(ctx)
+}
+
+func (uc *permissionUseCase) GetRolePermissions(ctx context.Context) ([]*domain.RolePermissions, error) {
+	all, err := uc.repo.GetAllRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*domain.RolePermissions, 0, len(roles))
+	for _, role := range roles {
+		codes := all[role]
+		if codes == nil {
+			codes = []string{}
+		}
+		result = append(result, &domain.RolePermissions{Role: role, Permissions: codes})
+	}
+	return result, nil
+}
+
+// SetRolePermissions заменяет набор прав роли целиком. У администратора нельзя отобрать
+// permissions.manage — иначе восстановить доступ можно будет только через БД.
+func (uc *permissionUseCase) SetRolePermissions(ctx context.Context, actorID string, role domain.Role, permissions []string, ipAddress, userAgent *string) error {
+	if !isConfigurableRole(role) {
+		return ErrUnknownRole
+	}
+
+	codes := dedupe(permissions)
+	if role == domain.RoleAdmin && !contains(codes, domain.PermPermissionsManage) {
+		return ErrAdminLockout
+	}
+
+	old, err := uc.repo.GetRolePermissions(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.SetRolePermissions(ctx, role, codes, actorID); err != nil {
+		if errors.Is(err, repository.ErrUnknownPermission) {
+			return ErrUnknownPermission
+		}
+		return err
+	}
+	uc.invalidate()
+
+	if uc.audit != nil {
+		_ = uc.audit.LogAction(ctx, &actorID, "ROLE_PERMISSIONS_CHANGED", "ROLE_PERMISSIONS", auditEntityID,
+			map[string]interface{}{"role": role, "permissions": old},
+			map[string]interface{}{"role": role, "permissions": codes},
+			ipAddress, userAgent)
+	}
+	return nil
+}
+
+func dedupe(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	result := make([]string, 0, len(codes))
+	for _, c := range codes {
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func contains(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/permission/mocks"
+	"lms_backend/internal/permission/usecase"
+)
+
+func newRepo() *mocks.PermissionRepositoryMock {
+	repo := mocks.NewPermissionRepositoryMock(
+		domain.PermUsersDelete, domain.PermBannersEdit, domain.PermAttendanceMark, domain.PermPermissionsManage,
+	)
+	repo.Roles[domain.RoleAdmin] = []string{domain.PermUsersDelete, domain.PermBannersEdit, domain.PermAttendanceMark, domain.PermPermissionsManage}
+	repo.Roles[domain.RoleTeacher] = []string{domain.PermAttendanceMark}
+	return repo
+}
+
+func TestHasPermission(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo()
+	uc := usecase.NewPermissionUseCase(repo, &mocks.AuditLoggerMock{})
+
+	cases := []struct {
+		role domain.Role
+		perm string
+		want bool
+	}{
+		{domain.RoleAdmin, domain.PermUsersDelete, true},
+		{domain.RoleTeacher, domain.PermAttendanceMark, true},
+		{domain.RoleTeacher, domain.PermUsersDelete, false},
+		{domain.RoleTeacher, domain.PermBannersEdit, false},
+		{domain.RoleStudent, domain.PermAttendanceMark, false},
+	}
+	for _, c := range cases {
+		got, err := uc.HasPermission(ctx, c.role, c.perm)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != c.want {
+			t.Errorf("%s/%s: expected %v, got %v", c.role, c.perm, c.want, got)
+		}
+	}
+
+	if repo.Loads != 1 {
+		t.Errorf("expected matrix to be loaded once and cached, got %d loads", repo.Loads)
+	}
+}
+
+func TestSetRolePermissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Grant And Revoke", func(t *testing.T) {
+		repo := newRepo()
+		audit := &mocks.AuditLoggerMock{}
+		uc := usecase.NewPermissionUseCase(repo, audit)
+
+		if ok, _ := uc.HasPermission(ctx, domain.RoleTeacher, domain.PermBannersEdit); ok {
+			t.Fatal("teacher must not edit banners by default")
+		}
+
+		err := uc.SetRolePermissions(ctx, "admin-1", domain.RoleTeacher, []string{domain.PermBannersEdit, domain.PermBannersEdit}, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok, _ := uc.HasPermission(ctx, domain.RoleTeacher, domain.PermBannersEdit); !ok {
+			t.Error("change must be visible immediately, cache should be invalidated")
+		}
+		if ok, _ := uc.HasPermission(ctx, domain.RoleTeacher, domain.PermAttendanceMark); ok {
+			t.Error("permissions must be replaced, not merged")
+		}
+		if len(repo.Roles[domain.RoleTeacher]) != 1 {
+			t.Errorf("duplicates must be removed, got %v", repo.Roles[domain.RoleTeacher])
+		}
+		if len(audit.Entries) != 1 || audit.Entries[0].Action != "ROLE_PERMISSIONS_CHANGED" {
+			t.Errorf("expected audit entry, got %+v", audit.Entries)
+		}
+	})
+
+	t.Run("Unknown Permission", func(t *testing.T) {
+		uc := usecase.NewPermissionUseCase(newRepo(), &mocks.AuditLoggerMock{})
+		err := uc.SetRolePermissions(ctx, "admin-1", domain.RoleTeacher, []string{"users.fly"}, nil, nil)
+		if !errors.Is(err, usecase.ErrUnknownPermission) {
+			t.Errorf("expected ErrUnknownPermission, got %v", err)
+		}
+	})
+
+	t.Run("Unknown Role", func(t *testing.T) {
+		uc := usecase.NewPermissionUseCase(newRepo(), &mocks.AuditLoggerMock{})
+		err := uc.SetRolePermissions(ctx, "admin-1", domain.RoleStudent, []string{domain.PermUsersDelete}, nil, nil)
+		if !errors.Is(err, usecase.ErrUnknownRole) {
+			t.Errorf("expected ErrUnknownRole, got %v", err)
+		}
+	})
+
+	t.Run("Admin Lockout", func(t *testing.T) {
+		repo := newRepo()
+		uc := usecase.NewPermissionUseCase(repo, &mocks.AuditLoggerMock{})
+		err := uc.SetRolePermissions(ctx, "admin-1", domain.RoleAdmin, []string{domain.PermUsersDelete}, nil, nil)
+		if !errors.Is(err, usecase.ErrAdminLockout) {
+			t.Errorf("expected ErrAdminLockout, got %v", err)
+		}
+		if len(repo.Roles[domain.RoleAdmin]) != 4 {
+			t.Error("admin permissions must stay untouched")
+		}
+	})
+}
+
+func TestGetRolePermissions(t *testing.T) {
+	uc := usecase.NewPermissionUseCase(newRepo(), &mocks.AuditLoggerMock{})
+	roles, err := uc.GetRolePermissions(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roles) != 4 {
+		t.Fatalf("expected all staff roles, got %d", len(roles))
+	}
+	for _, r := range roles {
+		if r.Permissions == nil {
+			t.Errorf("role %s: permissions must be an empty list, not null", r.Role)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"lms_backend/internal/httperror"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 
@@ -20,10 +23,15 @@ func NewReviewHandler(uc *usecase.ReviewUseCase) *ReviewHandler {
 	return &ReviewHandler{uc: uc}
 }
 
+// EvaluateRequest — Letter передаётся вместо Grade, если у курса буквенная схема оценивания;
+// Scores — баллы по критериям, если у работы есть рубрика (тогда Grade не нужен).
 type EvaluateRequest struct {
-	Grade      int    `json:"grade"`
-	Comment    string `json:"comment"`
-	IsAccepted bool   `json:"is_accepted"`
+	StudentID  string                  `json:"student_id"`
+	Grade      int                     `json:"grade"`
+	Letter     string                  `json:"letter"`
+	Scores     []domain.CriterionScore `json:"scores"`
+	Comment    string                  `json:"comment"`
+	IsAccepted bool                    `json:"is_accepted"`
 }
 
 // GetPendingSubmissions godoc
@@ -40,9 +48,9 @@ func (h *ReviewHandler) GetPendingSubmissions(w http.ResponseWriter, r *http.Req
 	}
 
 	studentID := r.URL.Query().Get("student_id")
-	list, err := h.uc.GetPendingList(r.Context(), userCtx.UserID, string(userCtx.Role), studentID)
+	list, err := h.uc.GetPendingList(r.Context(), userCtx.Actor(), studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -85,16 +93,504 @@ func (h *ReviewHandler) EvaluateSubmission(w http.ResponseWriter, r *http.Reques
 
 	input := usecase.EvaluateInput{
 		SubmissionID: submissionID,
+		StudentID:    req.StudentID,
 		Grade:        req.Grade,
+		Letter:       req.Letter,
+		Scores:       req.Scores,
 		Comment:      req.Comment,
 		Status:       status,
 	}
 
-	if err := h.uc.Evaluate(r.Context(), input); err != nil {
-		httperror.Internal(w, err)
+	if err := h.uc.Evaluate(r.Context(), userCtx.Actor(), input); err != nil {
+		if errors.Is(err, usecase.ErrStudentRequired) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidGrade) || errors.Is(err, domain.ErrInvalidRubricScores) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			httperror.NotFound(w, err)
+			return
+		}
+		httperror.Respond(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "evaluated"})
 }
+
+type QuestionGradeRequest struct {
+	QuestionID string  `json:"question_id"`
+	Score      float64 `json:"score"`
+	Comment    string  `json:"comment"`
+}
+
+type GradeTestAttemptRequest struct {
+	Grades []QuestionGradeRequest `json:"grades"`
+}
+
+// GetPendingTestAttempts godoc
+// @Summary STAFF: Попытки тестов на ручную проверку
+// @Tags Staff-Review
+// @Produce json
+// @Param student_id query string false "Фильтр по ученику"
+// @Success 200 {array} domain.TestAttemptReview
+// @Router /staff/test-attempts [get]
+func (h *ReviewHandler) GetPendingTestAttempts(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	list, err := h.uc.GetPendingTestAttempts(r.Context(), userCtx.Actor(), r.URL.Query().Get("student_id"))
+	if err != nil {
+		httperror.Respond(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetTestAttempt godoc
+// @Summary STAFF: Попытка теста с вопросами и ключами
+// @Tags Staff-Review
+// @Produce json
+// @Param id path string true "ID попытки"
+// @Success 200 {object} domain.TestAttemptReview
+// @Router /staff/test-attempts/{id} [get]
+func (h *ReviewHandler) GetTestAttempt(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attempt, err := h.uc.GetTestAttempt(r.Context(), userCtx.Actor(), chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httperror.NotFound(w, err)
+			return
+		}
+		httperror.Respond(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attempt)
+}
+
+// GradeTestAttempt godoc
+// @Summary STAFF: Оценить свободные ответы теста
+// @Description Баллы за вопросы типа text. Попытка считается проверенной, когда оценены все такие вопросы.
+// @Tags Staff-Review
+// @Accept json
+// @Produce json
+// @Param id path string true "ID попытки"
+// @Param request body GradeTestAttemptRequest true "Баллы по вопросам"
+// @Success 200 {object} domain.TestAttempt
+// @Router /staff/test-attempts/{id}/grade [post]
+func (h *ReviewHandler) GradeTestAttempt(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if userCtx.Role == domain.RoleCurator {
+		http.Error(w, "Forbidden: Curators cannot grade tests", http.StatusForbidden)
+		return
+	}
+
+	var req GradeTestAttemptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input := usecase.GradeTestAttemptInput{AttemptID: chi.URLParam(r, "id")}
+	for _, g := range req.Grades {
+		input.Grades = append(input.Grades, usecase.QuestionGrade{QuestionID: g.QuestionID, Score: g.Score, Comment: g.Comment})
+	}
+
+	attempt, err := h.uc.GradeTestAttempt(r.Context(), userCtx.Actor(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			httperror.NotFound(w, err)
+		case errors.Is(err, usecase.ErrAttemptNotPending):
+			httperror.Conflict(w, err)
+		case errors.Is(err, usecase.ErrInvalidQuestionGrade):
+			httperror.BadRequest(w, err)
+		default:
+			httperror.Respond(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attempt)
+}
+
+// EvaluateProjectSubmission godoc
+// @Summary STAFF: Проверить проект
+// @Description Оценивается только последняя версия сдачи; grade — от 0 до max_score проекта, при рубрике — сумма scores.
+// @Tags Staff-Review
+// @Accept json
+// @Produce json
+// @Param id path string true "ID версии сдачи проекта"
+// @Param request body EvaluateRequest true "Результат проверки (student_id не нужен)"
+// @Success 200 {object} domain.ProjectSubmission
+// @Router /staff/project-submissions/{id}/evaluate [post]
+func (h *ReviewHandler) EvaluateProjectSubmission(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if userCtx.Role == domain.RoleCurator {
+		http.Error(w, "Forbidden: Curators cannot evaluate projects", http.StatusForbidden)
+		return
+	}
+
+	var req EvaluateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	status := "on_revision"
+	if req.IsAccepted {
+		status = "accepted"
+	}
+
+	sub, err := h.uc.EvaluateProject(r.Context(), userCtx.Actor(), usecase.EvaluateProjectInput{
+		SubmissionID: chi.URLParam(r, "id"),
+		Grade:        req.Grade,
+		Scores:       req.Scores,
+		Comment:      req.Comment,
+		Status:       status,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			httperror.NotFound(w, err)
+		case errors.Is(err, usecase.ErrSubmissionSuperseded):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, usecase.ErrInvalidProjectGrade), errors.Is(err, domain.ErrInvalidRubricScores):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			httperror.Respond(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// GetSubmissionHistory godoc
+// @Summary STAFF: История версий ДЗ
+// @Tags Staff-Review
+// @Produce json
+// @Param id path string true "ID задания"
+// @Param student_id query string true "ID ученика"
+// @Success 200 {array} domain.SubmissionVersion
+// @Router /staff/submissions/{id}/versions [get]
+func (h *ReviewHandler) GetSubmissionHistory(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	versions, err := h.uc.GetSubmissionHistory(r.Context(), userCtx.Actor(), chi.URLParam(r, "id"), r.URL.Query().Get("student_id"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrStudentRequired) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.Respond(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+// DiffSubmission godoc
+// @Summary STAFF: Сравнение версий ДЗ
+// @Description По умолчанию сравнивает последнюю версию с предыдущей.
+// @Tags Staff-Review
+// @Produce json
+// @Param id path string true "ID задания"
+// @Param student_id query string true "ID ученика"
+// @Param from query int false "Старая версия"
+// @Param to query int false "Новая версия"
+// @Success 200 {object} domain.SubmissionDiff
+// @Router /staff/submissions/{id}/diff [get]
+func (h *ReviewHandler) DiffSubmission(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var versions [2]int
+	for i, name := range []string{"from", "to"} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			http.Error(w, "Invalid "+name+" version", http.StatusBadRequest)
+			return
+		}
+		versions[i] = v
+	}
+
+	diff, err := h.uc.DiffSubmission(r.Context(), userCtx.Actor(), chi.URLParam(r, "id"), r.URL.Query().Get("student_id"), versions[0], versions[1])
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrStudentRequired):
+			httperror.BadRequest(w, err)
+		case errors.Is(err, usecase.ErrVersionNotFound):
+			httperror.NotFound(w, err)
+		default:
+			httperror.Respond(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// GetSimilarityReport godoc
+// @Summary STAFF: Похожие работы
+// @Description Похожие работы других учеников для последней версии ДЗ с совпадающими фрагментами. Заполняется, если в курсе включена защита от копирования.
+// @Tags Staff-Review
+// @Produce json
+// @Param id path string true "ID задания"
+// @Param student_id query string true "ID ученика"
+// @Success 200 {object} domain.SimilarityReport
+// @Router /staff/submissions/{id}/similarity [get]
+func (h *ReviewHandler) GetSimilarityReport(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	report, err := h.uc.GetSimilarityReport(r.Context(), userCtx.Actor(), chi.URLParam(r, "id"), r.URL.Query().Get("student_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrStudentRequired):
+			httperror.BadRequest(w, err)
+		case errors.Is(err, usecase.ErrVersionNotFound):
+			httperror.NotFound(w, err)
+		default:
+			httperror.Respond(w, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// threadError — общие ответы для эндпоинтов обсуждения работы.
+func threadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, usecase.ErrSubmissionNotFound), errors.Is(err, usecase.ErrVersionNotFound):
+		httperror.NotFound(w, err)
+	case errors.Is(err, usecase.ErrStudentRequired), errors.Is(err, usecase.ErrEmptyComment), errors.Is(err, usecase.ErrInvalidAnchor):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		httperror.Respond(w, err)
+	}
+}
+
+// threadRef определяет тред по маршруту: у сотрудника {id} — ID задания и student_id в запросе,
+// у ученика {id} — ID урока, а тред — его собственный.
+func (h *ReviewHandler) threadRef(r *http.Request, userCtx *authMiddleware.UserContextData, staff bool) (string, string, error) {
+	if staff {
+		studentID := r.URL.Query().Get("student_id")
+		if studentID == "" {
+			studentID = r.FormValue("student_id")
+		}
+		return chi.URLParam(r, "id"), studentID, nil
+	}
+	assignmentID, err := h.uc.AssignmentIDByLesson(r.Context(), chi.URLParam(r, "id"))
+	return assignmentID, userCtx.UserID, err
+}
+
+func (h *ReviewHandler) getThread(w http.ResponseWriter, r *http.Request, staff bool) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	assignmentID, studentID, err := h.threadRef(r, userCtx, staff)
+	if err != nil {
+		threadError(w, err)
+		return
+	}
+	thread, err := h.uc.GetThread(r.Context(), userCtx.Actor(), assignmentID, studentID)
+	if err != nil {
+		threadError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(thread)
+}
+
+func (h *ReviewHandler) addComment(w http.ResponseWriter, r *http.Request, staff bool) {
+	const MAX_SIZE = 50 << 20
+	if err := r.ParseMultipartForm(MAX_SIZE); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	assignmentID, studentID, err := h.threadRef(r, userCtx, staff)
+	if err != nil {
+		threadError(w, err)
+		return
+	}
+
+	input := usecase.AddCommentInput{
+		AssignmentID: assignmentID,
+		StudentID:    studentID,
+		Body:         r.FormValue("body"),
+		FileHeaders:  r.MultipartForm.File["file"],
+	}
+	var numbers [3]int
+	for i, name := range []string{"version", "line_start", "line_end"} {
+		raw := r.FormValue(name)
+		if raw == "" {
+			continue
+		}
+		if numbers[i], err = strconv.Atoi(raw); err != nil {
+			http.Error(w, "Invalid "+name, http.StatusBadRequest)
+			return
+		}
+	}
+	input.Version = numbers[0]
+	if file := r.FormValue("anchor_file"); file != "" || numbers[1] != 0 || numbers[2] != 0 {
+		input.Anchor = &domain.CommentAnchor{File: file, LineStart: numbers[1], LineEnd: numbers[2]}
+	}
+
+	comment, err := h.uc.AddComment(r.Context(), userCtx.Actor(), input)
+	if err != nil {
+		threadError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *ReviewHandler) markThreadRead(w http.ResponseWriter, r *http.Request, staff bool) {
+	userCtx, ok := r.Context().Value(authMiddleware.ContextUserDataKey).(*authMiddleware.UserContextData)
+	if !ok || userCtx == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	assignmentID, studentID, err := h.threadRef(r, userCtx, staff)
+	if err != nil {
+		threadError(w, err)
+		return
+	}
+	if err := h.uc.MarkThreadRead(r.Context(), userCtx.Actor(), assignmentID, studentID); err != nil {
+		threadError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "read"})
+}
+
+// GetSubmissionThread godoc
+// @Summary STAFF: Обсуждение ДЗ
+// @Tags Staff-Review
+// @Produce json
+// @Param id path string true "ID задания"
+// @Param student_id query string true "ID ученика"
+// @Success 200 {object} domain.SubmissionThread
+// @Router /staff/submissions/{id}/thread [get]
+func (h *ReviewHandler) GetSubmissionThread(w http.ResponseWriter, r *http.Request) {
+	h.getThread(w, r, true)
+}
+
+// AddSubmissionComment godoc
+// @Summary STAFF: Сообщение в обсуждении ДЗ
+// @Tags Staff-Review
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "ID задания"
+// @Param student_id formData string true "ID ученика"
+// @Param body formData string false "Текст"
+// @Param version formData int false "Версия сдачи (по умолчанию последняя)"
+// @Param anchor_file formData string false "Файл версии, к которому относится замечание"
+// @Param line_start formData int false "Первая строка"
+// @Param line_end formData int false "Последняя строка"
+// @Param file formData file false "Вложение (можно несколько)"
+// @Success 201 {object} domain.SubmissionComment
+// @Router /staff/submissions/{id}/thread [post]
+func (h *ReviewHandler) AddSubmissionComment(w http.ResponseWriter, r *http.Request) {
+	h.addComment(w, r, true)
+}
+
+// MarkSubmissionThreadRead godoc
+// @Summary STAFF: Отметить обсуждение ДЗ прочитанным
+// @Tags Staff-Review
+// @Produce json
+// @Param id path string true "ID задания"
+// @Param student_id query string true "ID ученика"
+// @Success 200 {object} map[string]string
+// @Router /staff/submissions/{id}/thread/read [post]
+func (h *ReviewHandler) MarkSubmissionThreadRead(w http.ResponseWriter, r *http.Request) {
+	h.markThreadRead(w, r, true)
+}
+
+// GetMySubmissionThread godoc
+// @Summary УЧЕНИК: Обсуждение моего ДЗ
+// @Tags Student-Learning
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {object} domain.SubmissionThread
+// @Router /lessons/{id}/assignment/thread [get]
+func (h *ReviewHandler) GetMySubmissionThread(w http.ResponseWriter, r *http.Request) {
+	h.getThread(w, r, false)
+}
+
+// AddMySubmissionComment godoc
+// @Summary УЧЕНИК: Ответ в обсуждении ДЗ
+// @Tags Student-Learning
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param body formData string false "Текст"
+// @Param version formData int false "Версия сдачи (по умолчанию последняя)"
+// @Param anchor_file formData string false "Файл версии"
+// @Param line_start formData int false "Первая строка"
+// @Param line_end formData int false "Последняя строка"
+// @Param file formData file false "Вложение (можно несколько)"
+// @Success 201 {object} domain.SubmissionComment
+// @Router /lessons/{id}/assignment/thread [post]
+func (h *ReviewHandler) AddMySubmissionComment(w http.ResponseWriter, r *http.Request) {
+	h.addComment(w, r, false)
+}
+
+// MarkMySubmissionThreadRead godoc
+// @Summary УЧЕНИК: Отметить обсуждение ДЗ прочитанным
+// @Tags Student-Learning
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {object} map[string]string
+// @Router /lessons/{id}/assignment/thread/read [post]
+func (h *ReviewHandler) MarkMySubmissionThreadRead(w http.ResponseWriter, r *http.Request) {
+	h.markThreadRead(w, r, false)
+}
@@ -2,14 +2,39 @@ package mocks
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"lms_backend/internal/domain"
 	"lms_backend/internal/review/repository"
 	"sync"
+	"time"
 )
 
 type ReviewRepositoryMock struct {
-	mu          sync.Mutex
-	Submissions map[string]*domain.SubmissionRecord
+	mu           sync.Mutex
+	Submissions  map[string]*domain.SubmissionRecord
+	TestAttempts map[string]*domain.TestAttemptReview
+	// Versions — история ДЗ по ключу "assignmentID/studentID", от старых версий к новым.
+	Versions map[string][]*domain.SubmissionVersion
+	// Comments — сообщения всех тредов в порядке добавления.
+	Comments []*domain.SubmissionComment
+	// LessonAssignments — ID задания по ID урока.
+	LessonAssignments map[string]string
+	// ProjectSubmissions — версии сдачи проектов по ID версии.
+	ProjectSubmissions map[string]*domain.ProjectSubmission
+	// Courses — курс по ID задания; задания без записи относятся к "course-1" со схемой по умолчанию.
+	Courses map[string]*domain.Course
+	// Progress — прогресс курса по ключу "userID/courseID".
+	Progress map[string]int
+	// Rubrics — рубрики по ID задания или проекта; RubricScores — разбивка оценки ДЗ по ключу "assignmentID/studentID".
+	Rubrics      map[string]*domain.Rubric
+	RubricScores map[string][]domain.CriterionScore
+	// Deadlines — сроки по ID задания; опоздание работы берётся из SubmissionRecord.IsLate.
+	Deadlines map[string]*domain.AssignmentDeadline
+	// Similarity — отчёты о похожих работах по ключу "assignmentID/studentID" (без Passages).
+	Similarity map[string]*domain.SimilarityReport
+	// ItemCourses — курс по ID проекта или теста.
+	ItemCourses map[string]string
 	nextID      int
 }
 
@@ -17,37 +42,338 @@ var _ repository.ReviewRepository = (*ReviewRepositoryMock)(nil)
 
 func NewReviewRepositoryMock() *ReviewRepositoryMock {
 	return &ReviewRepositoryMock{
-		Submissions: make(map[string]*domain.SubmissionRecord),
-		nextID:      1,
+		Submissions:        make(map[string]*domain.SubmissionRecord),
+		TestAttempts:       make(map[string]*domain.TestAttemptReview),
+		Versions:           make(map[string][]*domain.SubmissionVersion),
+		LessonAssignments:  make(map[string]string),
+		ProjectSubmissions: make(map[string]*domain.ProjectSubmission),
+		Courses:            make(map[string]*domain.Course),
+		Progress:           make(map[string]int),
+		Rubrics:            make(map[string]*domain.Rubric),
+		RubricScores:       make(map[string][]domain.CriterionScore),
+		Deadlines:          make(map[string]*domain.AssignmentDeadline),
+		Similarity:         make(map[string]*domain.SimilarityReport),
+		ItemCourses:        make(map[string]string),
+		nextID:             1,
 	}
 }
 
-func (r *ReviewRepositoryMock) GetPendingSubmissions(ctx context.Context, staffID string, role string, studentID string) ([]*domain.SubmissionRecord, error) {
+func (r *ReviewRepositoryMock) GetPendingSubmissions(ctx context.Context, studentID string) ([]*domain.SubmissionRecord, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	var result []*domain.SubmissionRecord
 	for _, s := range r.Submissions {
-		if s.Status == "pending" {
+		if s.Status == "pending" && (studentID == "" || s.UserID == studentID) {
 			result = append(result, s)
 		}
 	}
 	return result, nil
 }
 
-func (r *ReviewRepositoryMock) EvaluateSubmission(ctx context.Context, submissionID string, grade int, comment string, status string) error {
+func (r *ReviewRepositoryMock) EvaluateSubmission(ctx context.Context, submissionID, studentID string, grade int, comment string, status string, reviewerID string, scores []domain.CriterionScore) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	s, ok := r.Submissions[submissionID]
+	key := submissionID + "/" + studentID
+	s, ok := r.Submissions[key]
 	if !ok {
-		s = &domain.SubmissionRecord{ID: submissionID}
-		r.Submissions[submissionID] = s
+		s = &domain.SubmissionRecord{ID: submissionID, UserID: studentID}
+		r.Submissions[key] = s
 	}
 	s.Grade = grade
 	s.TeacherComment = comment
 	s.Status = status
+	r.RubricScores[key] = scores
+	if versions := r.Versions[key]; len(versions) > 0 {
+		latest := versions[len(versions)-1]
+		now := time.Now()
+		latest.Grade, latest.TeacherComment, latest.Status = grade, comment, status
+		latest.ReviewedBy, latest.ReviewedAt = &reviewerID, &now
+	}
+	return nil
+}
+
+func (r *ReviewRepositoryMock) GetRubric(ctx context.Context, assignmentID, projectID string) (*domain.Rubric, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rubric, ok := r.Rubrics[assignmentID+projectID]; ok {
+		return rubric, nil
+	}
+	return &domain.Rubric{AssignmentID: assignmentID, ProjectID: projectID, Criteria: []domain.RubricCriterion{}}, nil
+}
+
+func (r *ReviewRepositoryMock) GetSubmissionVersions(ctx context.Context, assignmentID, studentID string) ([]*domain.SubmissionVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := r.Versions[assignmentID+"/"+studentID]
+	result := make([]*domain.SubmissionVersion, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		cp := *versions[i]
+		result = append(result, &cp)
+	}
+	return result, nil
+}
+
+func (r *ReviewRepositoryMock) GetSimilarityReport(ctx context.Context, assignmentID, studentID string) (*domain.SimilarityReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report, ok := r.Similarity[assignmentID+"/"+studentID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *report
+	cp.Matches = make([]*domain.SimilarityMatch, 0, len(report.Matches))
+	for _, m := range report.Matches {
+		mc := *m
+		cp.Matches = append(cp.Matches, &mc)
+	}
+	return &cp, nil
+}
+
+func (r *ReviewRepositoryMock) courseOf(assignmentID string) *domain.Course {
+	if c, ok := r.Courses[assignmentID]; ok {
+		return c
+	}
+	return &domain.Course{ID: "course-1", GradeScheme: domain.DefaultGradeScheme()}
+}
+
+func (r *ReviewRepositoryMock) GetSubmissionDeadline(ctx context.Context, assignmentID, studentID string) (*domain.AssignmentDeadline, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.Deadlines[assignmentID]
+	if !ok {
+		d = &domain.AssignmentDeadline{LatePolicy: domain.LatePolicyAccept}
+	}
+	s, ok := r.Submissions[assignmentID+"/"+studentID]
+	return d, ok && s.IsLate, nil
+}
+
+func (r *ReviewRepositoryMock) GetAssignmentCourse(ctx context.Context, assignmentID string) (*domain.Course, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.courseOf(assignmentID), nil
+}
+
+func (r *ReviewRepositoryMock) GetCourseAssignmentResults(ctx context.Context, userID, courseID string) ([]domain.AssignmentResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	assignments := map[string]bool{}
+	for id := range r.Courses {
+		assignments[id] = true
+	}
+	for _, s := range r.Submissions {
+		assignments[s.ID] = true
+	}
+	results := []domain.AssignmentResult{}
+	for id := range assignments {
+		if r.courseOf(id).ID != courseID {
+			continue
+		}
+		res := domain.AssignmentResult{AssignmentID: id}
+		if rubric, ok := r.Rubrics[id]; ok {
+			res.MaxPoints = rubric.MaxPoints()
+		}
+		if s, ok := r.Submissions[id+"/"+userID]; ok {
+			res.Status, res.Grade = s.Status, s.Grade
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (r *ReviewRepositoryMock) UpdateUserCourseProgress(ctx context.Context, userID, courseID string, percent int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Progress[userID+"/"+courseID] = percent
+	return nil
+}
+
+func (r *ReviewRepositoryMock) GetPendingTestAttempts(ctx context.Context, studentID string) ([]*domain.TestAttemptReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := []*domain.TestAttemptReview{}
+	for _, a := range r.TestAttempts {
+		if a.Status == domain.TestAttemptPendingReview && (studentID == "" || a.UserID == studentID) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (r *ReviewRepositoryMock) GetProjectCourseID(ctx context.Context, projectID string) (string, error) {
+	return r.itemCourse(projectID)
+}
+
+func (r *ReviewRepositoryMock) GetTestCourseID(ctx context.Context, testID string) (string, error) {
+	return r.itemCourse(testID)
+}
+
+func (r *ReviewRepositoryMock) itemCourse(id string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	courseID, ok := r.ItemCourses[id]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return courseID, nil
+}
+
+func (r *ReviewRepositoryMock) GetTestAttempt(ctx context.Context, attemptID string) (*domain.TestAttemptReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.TestAttempts[attemptID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *a
+	cp.Results = append([]domain.QuestionResult(nil), a.Results...)
+	return &cp, nil
+}
+
+func (r *ReviewRepositoryMock) SaveTestAttemptGrade(ctx context.Context, attempt *domain.TestAttempt, graderID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.TestAttempts[attempt.ID]
+	if !ok || a.Status != domain.TestAttemptPendingReview {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	attempt.GradedAt = &now
+	a.TestAttempt = *attempt
+	return nil
+}
+
+func (r *ReviewRepositoryMock) latestProjectVersion(sub *domain.ProjectSubmission) bool {
+	for _, other := range r.ProjectSubmissions {
+		if other.ProjectID == sub.ProjectID && other.UserID == sub.UserID && other.Version > sub.Version {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ReviewRepositoryMock) GetProjectSubmissions(ctx context.Context, studentID string) ([]*domain.SubmissionRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := []*domain.SubmissionRecord{}
+	for _, s := range r.ProjectSubmissions {
+		if (studentID == "" || s.UserID == studentID) && r.latestProjectVersion(s) {
+			result = append(result, &domain.SubmissionRecord{
+				ID:          s.ID,
+				Kind:        domain.SubmissionKindProject,
+				UserID:      s.UserID,
+				ProjectID:   s.ProjectID,
+				MaxScore:    s.MaxScore,
+				Version:     s.Version,
+				Status:      s.Status,
+				Grade:       s.Grade,
+				SubmittedAt: s.SubmittedAt,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (r *ReviewRepositoryMock) GetProjectSubmission(ctx context.Context, id string) (*domain.ProjectSubmission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.ProjectSubmissions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *ReviewRepositoryMock) EvaluateProjectSubmission(ctx context.Context, sub *domain.ProjectSubmission, reviewerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.ProjectSubmissions[sub.ID]
+	if !ok || !r.latestProjectVersion(s) {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	sub.ReviewedBy = &reviewerID
+	sub.ReviewedAt = &now
+	*s = *sub
+	return nil
+}
+
+func (r *ReviewRepositoryMock) GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.LessonAssignments[lessonID]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return id, nil
+}
+
+func (r *ReviewRepositoryMock) SubmissionExists(ctx context.Context, assignmentID, studentID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.Submissions[assignmentID+"/"+studentID]
+	return ok, nil
+}
+
+func (r *ReviewRepositoryMock) AddSubmissionComment(ctx context.Context, c *domain.SubmissionComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = fmt.Sprintf("comment-%d", r.nextID)
+	r.nextID++
+	c.CreatedAt = time.Now()
+	cp := *c
+	cp.ReadBy = []domain.CommentRead{}
+	r.Comments = append(r.Comments, &cp)
 	return nil
 }
 
-func (r *ReviewRepositoryMock) UpdateUserCourseProgress(ctx context.Context, userID, courseID string) error {
+func (r *ReviewRepositoryMock) GetSubmissionComments(ctx context.Context, assignmentID, studentID string) ([]*domain.SubmissionComment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := []*domain.SubmissionComment{}
+	for _, c := range r.Comments {
+		if c.AssignmentID == assignmentID && c.StudentID == studentID {
+			cp := *c
+			cp.ReadBy = append([]domain.CommentRead{}, c.ReadBy...)
+			result = append(result, &cp)
+		}
+	}
+	return result, nil
+}
+
+func (r *ReviewRepositoryMock) MarkSubmissionCommentsRead(ctx context.Context, assignmentID, studentID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.Comments {
+		if c.AssignmentID != assignmentID || c.StudentID != studentID || c.AuthorID == userID {
+			continue
+		}
+		read := false
+		for _, rd := range c.ReadBy {
+			read = read || rd.UserID == userID
+		}
+		if !read {
+			c.ReadBy = append(c.ReadBy, domain.CommentRead{UserID: userID, ReadAt: time.Now()})
+		}
+	}
 	return nil
 }
+
+func (r *ReviewRepositoryMock) CountUnreadStudentReplies(ctx context.Context, userID string) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[string]int{}
+	for _, c := range r.Comments {
+		if c.AuthorID != c.StudentID {
+			continue
+		}
+		read := false
+		for _, rd := range c.ReadBy {
+			read = read || rd.UserID == userID
+		}
+		if !read {
+			counts[c.AssignmentID+"/"+c.StudentID]++
+		}
+	}
+	return counts, nil
+}
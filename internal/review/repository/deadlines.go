@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"lms_backend/internal/domain"
+)
+
+// GetSubmissionDeadline — срок и политика опоздания задания и отметка, опоздала ли текущая работа ученика.
+func (r *ReviewRepoImpl) GetSubmissionDeadline(ctx context.Context, assignmentID, studentID string) (*domain.AssignmentDeadline, bool, error) {
+	d := &domain.AssignmentDeadline{}
+	var dueAt sql.NullTime
+	var offset sql.NullInt32
+	var isLate bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT a.due_at, a.due_offset_hours, a.late_policy, a.late_penalty_percent, COALESCE(uas.is_late, FALSE)
+		FROM assignments a
+		LEFT JOIN user_assignments_submission uas ON uas.assignment_id = a.id AND uas.user_id = $2
+		WHERE a.id = $1
+	`, assignmentID, studentID).Scan(&dueAt, &offset, &d.LatePolicy, &d.LatePenaltyPercent, &isLate)
+	if err != nil {
+		return nil, false, err
+	}
+	if dueAt.Valid {
+		d.DueAt = &dueAt.Time
+	}
+	if offset.Valid {
+		hours := int(offset.Int32)
+		d.DueOffsetHours = &hours
+	}
+	return d, isLate, nil
+}
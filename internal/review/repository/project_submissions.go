@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+// GetProjectSubmissions — последние версии сдачи проектов для очереди проверки.
+// Более ранние версии в очередь не попадают: их заменила последняя.
+func (r *ReviewRepoImpl) GetProjectSubmissions(ctx context.Context, studentID string) ([]*domain.SubmissionRecord, error) {
+	query := `
+		SELECT
+			ps.id, ps.user_id, u.first_name || ' ' || u.last_name,
+			COALESCE(c.title, ''), COALESCE(m.order_num, 0), COALESCE(l.order_num, 0), COALESCE(l.title, ''),
+			p.id, p.title, p.max_score, ps.version,
+			ps.submission_text, ps.submission_files, ps.status, COALESCE(ps.grade, 0),
+			COALESCE(ps.teacher_comment, ''), ps.submitted_at
+		FROM project_submissions ps
+		JOIN users u ON ps.user_id = u.id
+		JOIN projects p ON ps.project_id = p.id
+		LEFT JOIN lessons l ON p.lesson_id = l.id
+		LEFT JOIN modules m ON l.module_id = m.id
+		LEFT JOIN courses c ON m.course_id = c.id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM project_submissions n
+			WHERE n.project_id = ps.project_id AND n.user_id = ps.user_id AND n.version > ps.version
+		)
+	`
+	var args []interface{}
+	if studentID != "" {
+		query += fmt.Sprintf(" AND ps.user_id = $%d", len(args)+1)
+		args = append(args, studentID)
+	}
+	query += " ORDER BY ps.submitted_at ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*domain.SubmissionRecord{}
+	for rows.Next() {
+		rec := &domain.SubmissionRecord{Kind: domain.SubmissionKindProject}
+		var filesRaw []byte
+		if err := rows.Scan(
+			&rec.ID, &rec.UserID, &rec.StudentName,
+			&rec.CourseTitle, &rec.ModuleOrder, &rec.LessonOrder, &rec.LessonTitle,
+			&rec.ProjectID, &rec.ProjectTitle, &rec.MaxScore, &rec.Version,
+			&rec.Text, &filesRaw, &rec.Status, &rec.Grade,
+			&rec.TeacherComment, &rec.SubmittedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(filesRaw, &rec.Files); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// GetProjectSubmission — версия сдачи проекта с максимальным баллом проекта.
+func (r *ReviewRepoImpl) GetProjectSubmission(ctx context.Context, id string) (*domain.ProjectSubmission, error) {
+	query := `
+		SELECT ps.id, ps.project_id, ps.user_id, ps.version, ps.submission_text, ps.submission_files, ps.status,
+		       COALESCE(ps.grade, 0), COALESCE(ps.teacher_comment, ''), p.max_score, ps.rubric_scores, ps.reviewed_by, ps.reviewed_at, ps.submitted_at
+		FROM project_submissions ps
+		JOIN projects p ON p.id = ps.project_id
+		WHERE ps.id = $1
+	`
+	s := &domain.ProjectSubmission{}
+	var filesRaw, scoresRaw []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.ProjectID, &s.UserID, &s.Version, &s.Text, &filesRaw, &s.Status,
+		&s.Grade, &s.TeacherComment, &s.MaxScore, &scoresRaw, &s.ReviewedBy, &s.ReviewedAt, &s.SubmittedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filesRaw, &s.Files); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(scoresRaw, &s.RubricScores); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// EvaluateProjectSubmission оценивает версию, только если она всё ещё последняя;
+// иначе возвращает sql.ErrNoRows — ученик успел отправить новую.
+func (r *ReviewRepoImpl) EvaluateProjectSubmission(ctx context.Context, sub *domain.ProjectSubmission, reviewerID string) error {
+	query := `
+		UPDATE project_submissions ps
+		SET status = $1, grade = $2, teacher_comment = $3, reviewed_by = $4, reviewed_at = NOW(), rubric_scores = $6
+		WHERE ps.id = $5 AND NOT EXISTS (
+			SELECT 1 FROM project_submissions n
+			WHERE n.project_id = ps.project_id AND n.user_id = ps.user_id AND n.version > ps.version
+		)
+		RETURNING reviewed_at
+	`
+	scoresRaw, err := json.Marshal(nonNilScores(sub.RubricScores))
+	if err != nil {
+		return err
+	}
+	var reviewedAt time.Time
+	err = r.db.QueryRowContext(ctx, query, sub.Status, sub.Grade, sub.TeacherComment, reviewerID, sub.ID, scoresRaw).Scan(&reviewedAt)
+	if err != nil {
+		return err
+	}
+	sub.ReviewedBy = &reviewerID
+	sub.ReviewedAt = &reviewedAt
+	return nil
+}
@@ -6,12 +6,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"lms_backend/internal/domain"
+	"time"
 )
 
 type ReviewRepository interface {
-	GetPendingSubmissions(ctx context.Context, staffID string, role string, studentID string) ([]*domain.SubmissionRecord, error)
-	EvaluateSubmission(ctx context.Context, submissionID string, grade int, comment string, status string) error
-	UpdateUserCourseProgress(ctx context.Context, userID, courseID string) error
+	GetPendingSubmissions(ctx context.Context, studentID string) ([]*domain.SubmissionRecord, error)
+	EvaluateSubmission(ctx context.Context, submissionID, studentID string, grade int, comment string, status string, reviewerID string, scores []domain.CriterionScore) error
+	GetRubric(ctx context.Context, assignmentID, projectID string) (*domain.Rubric, error)
+	GetSubmissionDeadline(ctx context.Context, assignmentID, studentID string) (*domain.AssignmentDeadline, bool, error)
+	GetSubmissionVersions(ctx context.Context, assignmentID, studentID string) ([]*domain.SubmissionVersion, error)
+	GetSimilarityReport(ctx context.Context, assignmentID, studentID string) (*domain.SimilarityReport, error)
+
+	GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error)
+	SubmissionExists(ctx context.Context, assignmentID, studentID string) (bool, error)
+	AddSubmissionComment(ctx context.Context, c *domain.SubmissionComment) error
+	GetSubmissionComments(ctx context.Context, assignmentID, studentID string) ([]*domain.SubmissionComment, error)
+	MarkSubmissionCommentsRead(ctx context.Context, assignmentID, studentID, userID string) error
+	CountUnreadStudentReplies(ctx context.Context, userID string) (map[string]int, error)
+	GetAssignmentCourse(ctx context.Context, assignmentID string) (*domain.Course, error)
+	GetCourseAssignmentResults(ctx context.Context, userID, courseID string) ([]domain.AssignmentResult, error)
+	UpdateUserCourseProgress(ctx context.Context, userID, courseID string, percent int) error
+	GetProjectCourseID(ctx context.Context, projectID string) (string, error)
+	GetTestCourseID(ctx context.Context, testID string) (string, error)
+
+	GetPendingTestAttempts(ctx context.Context, studentID string) ([]*domain.TestAttemptReview, error)
+	GetTestAttempt(ctx context.Context, attemptID string) (*domain.TestAttemptReview, error)
+	SaveTestAttemptGrade(ctx context.Context, attempt *domain.TestAttempt, graderID string) error
+
+	GetProjectSubmissions(ctx context.Context, studentID string) ([]*domain.SubmissionRecord, error)
+	GetProjectSubmission(ctx context.Context, id string) (*domain.ProjectSubmission, error)
+	EvaluateProjectSubmission(ctx context.Context, sub *domain.ProjectSubmission, reviewerID string) error
 }
 
 type ReviewRepoImpl struct {
@@ -24,13 +48,20 @@ func NewReviewRepository(db *sql.DB) *ReviewRepoImpl {
 	return &ReviewRepoImpl{db: db}
 }
 
-func (r *ReviewRepoImpl) GetPendingSubmissions(ctx context.Context, staffID string, role string, studentID string) ([]*domain.SubmissionRecord, error) {
+func (r *ReviewRepoImpl) GetPendingSubmissions(ctx context.Context, studentID string) ([]*domain.SubmissionRecord, error) {
 	query := `
 		SELECT 
 			uas.assignment_id, uas.user_id, u.first_name || ' ' || u.last_name,
 			c.title, m.order_num, l.order_num, l.title,
 			uas.submission_text, uas.submission_files, uas.status, COALESCE(uas.grade, 0), 
-			COALESCE(uas.teacher_comment, ''), uas.submitted_at
+			COALESCE(uas.teacher_comment, ''), uas.submitted_at, uas.is_late,
+			(SELECT COALESCE(MAX(v.version), 0) FROM assignment_submission_versions v
+			 WHERE v.assignment_id = uas.assignment_id AND v.user_id = uas.user_id),
+			(SELECT COALESCE(MAX(s.score), 0) FROM submission_similarities s
+			 JOIN assignment_submission_versions v ON v.id IN (s.version_id, s.matched_version_id)
+			 WHERE v.assignment_id = uas.assignment_id AND v.user_id = uas.user_id
+			   AND v.version = (SELECT MAX(version) FROM assignment_submission_versions
+			                    WHERE assignment_id = uas.assignment_id AND user_id = uas.user_id))
 		FROM user_assignments_submission uas
 		JOIN users u ON uas.user_id = u.id
 		JOIN assignments a ON uas.assignment_id = a.id
@@ -40,17 +71,9 @@ func (r *ReviewRepoImpl) GetPendingSubmissions(ctx context.Context, staffID stri
 		WHERE 1=1
 	`
 	var args []interface{}
-	argIdx := 1
-	if role == "teacher" {
-		query += fmt.Sprintf(" AND c.id IN (SELECT DISTINCT uc.course_id FROM groups g JOIN user_courses uc ON uc.group_id = g.id WHERE g.teacher_id = $%d)", argIdx)
-		args = append(args, staffID)
-		argIdx++
-	}
-
 	if studentID != "" {
-		query += fmt.Sprintf(" AND uas.user_id = $%d", argIdx)
+		query += fmt.Sprintf(" AND uas.user_id = $%d", len(args)+1)
 		args = append(args, studentID)
-		argIdx++
 	}
 
 	query += " ORDER BY uas.submitted_at ASC"
@@ -63,42 +86,223 @@ func (r *ReviewRepoImpl) GetPendingSubmissions(ctx context.Context, staffID stri
 
 	var records []*domain.SubmissionRecord
 	for rows.Next() {
-		rec := &domain.SubmissionRecord{}
+		rec := &domain.SubmissionRecord{Kind: domain.SubmissionKindHomework}
 		var filesRaw []byte
 		rows.Scan(
 			&rec.ID, &rec.UserID, &rec.StudentName, &rec.CourseTitle, &rec.ModuleOrder,
 			&rec.LessonOrder, &rec.LessonTitle, &rec.Text, &filesRaw, &rec.Status,
-			&rec.Grade, &rec.TeacherComment, &rec.SubmittedAt,
+			&rec.Grade, &rec.TeacherComment, &rec.SubmittedAt, &rec.IsLate, &rec.Version, &rec.Similarity,
 		)
 		json.Unmarshal(filesRaw, &rec.Files)
 		records = append(records, rec)
 	}
 	return records, nil
 }
-func (r *ReviewRepoImpl) EvaluateSubmission(ctx context.Context, submissionID string, grade int, comment string, status string) error {
+
+// EvaluateSubmission оценивает текущее состояние работы и её последнюю версию в одной транзакции.
+func (r *ReviewRepoImpl) EvaluateSubmission(ctx context.Context, submissionID, studentID string, grade int, comment string, status string, reviewerID string, scores []domain.CriterionScore) error {
+	scoresRaw, err := json.Marshal(nonNilScores(scores))
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE user_assignments_submission 
-		SET grade = $1, status = $2, teacher_comment = $3
-		WHERE assignment_id = $4
+		SET grade = $1, status = $2, teacher_comment = $3, rubric_scores = $6
+		WHERE assignment_id = $4 AND user_id = $5
 	`
-	_, err := r.db.ExecContext(ctx, query, grade, status, comment, submissionID)
+	if _, err := tx.ExecContext(ctx, query, grade, status, comment, submissionID, studentID, scoresRaw); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE assignment_submission_versions
+		SET grade = $1, status = $2, teacher_comment = $3, reviewed_by = NULLIF($4, '')::uuid, reviewed_at = NOW(), rubric_scores = $7
+		WHERE assignment_id = $5 AND user_id = $6 AND version = (
+			SELECT MAX(version) FROM assignment_submission_versions WHERE assignment_id = $5 AND user_id = $6
+		)
+	`, grade, status, comment, reviewerID, submissionID, studentID, scoresRaw)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetAssignmentCourse — курс задания со схемой оценивания (заполнены только ID и GradeScheme).
+func (r *ReviewRepoImpl) GetAssignmentCourse(ctx context.Context, assignmentID string) (*domain.Course, error) {
+	c := &domain.Course{}
+	var schemeRaw []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT c.id, c.grading_scheme
+		FROM assignments a
+		JOIN lessons l ON a.lesson_id = l.id
+		JOIN modules m ON l.module_id = m.id
+		JOIN courses c ON m.course_id = c.id
+		WHERE a.id = $1
+	`, assignmentID).Scan(&c.ID, &schemeRaw)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(schemeRaw, &c.GradeScheme); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCourseAssignmentResults — все задания курса с текущим состоянием работы ученика.
+func (r *ReviewRepoImpl) GetCourseAssignmentResults(ctx context.Context, userID, courseID string) ([]domain.AssignmentResult, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, COALESCE(uas.status::text, ''), COALESCE(uas.grade, 0),
+			(SELECT COALESCE(SUM(rc.max_points), 0) FROM rubric_criteria rc WHERE rc.assignment_id = a.id)
+		FROM assignments a
+		JOIN lessons l ON a.lesson_id = l.id
+		JOIN modules m ON l.module_id = m.id
+		LEFT JOIN user_assignments_submission uas ON a.id = uas.assignment_id AND uas.user_id = $1
+		WHERE m.course_id = $2
+	`, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []domain.AssignmentResult{}
+	for rows.Next() {
+		var res domain.AssignmentResult
+		if err := rows.Scan(&res.AssignmentID, &res.Status, &res.Grade, &res.MaxPoints); err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+func (r *ReviewRepoImpl) UpdateUserCourseProgress(ctx context.Context, userID, courseID string, percent int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE user_courses SET progress_percent = $1 WHERE user_id = $2 AND course_id = $3", percent, userID, courseID)
 	return err
 }
 
-func (r *ReviewRepoImpl) UpdateUserCourseProgress(ctx context.Context, userID, courseID string) error {
+func (r *ReviewRepoImpl) GetProjectCourseID(ctx context.Context, projectID string) (string, error) {
+	var courseID string
+	err := r.db.QueryRowContext(ctx, "SELECT l.course_id FROM projects p JOIN lessons l ON l.id = p.lesson_id WHERE p.id = $1", projectID).Scan(&courseID)
+	return courseID, err
+}
+
+// GetTestCourseID — курс урока теста; у теста без урока — sql.ErrNoRows.
+func (r *ReviewRepoImpl) GetTestCourseID(ctx context.Context, testID string) (string, error) {
+	var courseID string
+	err := r.db.QueryRowContext(ctx, "SELECT l.course_id FROM tests t JOIN lessons l ON l.id = t.lesson_id WHERE t.id = $1", testID).Scan(&courseID)
+	return courseID, err
+}
+
+const testAttemptReviewSelect = `
+	SELECT ta.id, ta.test_id, ta.user_id, ta.answers, ta.results, ta.score, ta.max_score, ta.percent,
+		ta.passed, ta.status, ta.submitted_at, u.first_name || ' ' || u.last_name, t.title, t.passing_score
+	FROM test_attempts ta
+	JOIN users u ON ta.user_id = u.id
+	JOIN tests t ON ta.test_id = t.id
+`
+
+func scanTestAttemptReview(row interface{ Scan(...any) error }) (*domain.TestAttemptReview, error) {
+	rec := &domain.TestAttemptReview{}
+	var answersRaw, resultsRaw []byte
+	err := row.Scan(
+		&rec.ID, &rec.TestID, &rec.UserID, &answersRaw, &resultsRaw, &rec.Score, &rec.MaxScore, &rec.Percent,
+		&rec.Passed, &rec.Status, &rec.SubmittedAt, &rec.StudentName, &rec.TestTitle, &rec.PassingScore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(answersRaw, &rec.Answers); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(resultsRaw, &rec.Results); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// GetPendingTestAttempts — попытки с вопросами, которые ждут ручной проверки. Вопросы тестов не подгружаются.
+func (r *ReviewRepoImpl) GetPendingTestAttempts(ctx context.Context, studentID string) ([]*domain.TestAttemptReview, error) {
+	query := testAttemptReviewSelect + " WHERE ta.status = 'pending_review'"
+	var args []interface{}
+	if studentID != "" {
+		query += fmt.Sprintf(" AND ta.user_id = $%d", len(args)+1)
+		args = append(args, studentID)
+	}
+	query += " ORDER BY ta.submitted_at ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*domain.TestAttemptReview{}
+	for rows.Next() {
+		rec, err := scanTestAttemptReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// GetTestAttempt — попытка вместе с вопросами теста (с ключами ответов) для проверяющего.
+func (r *ReviewRepoImpl) GetTestAttempt(ctx context.Context, attemptID string) (*domain.TestAttemptReview, error) {
+	rec, err := scanTestAttemptReview(r.db.QueryRowContext(ctx, testAttemptReviewSelect+" WHERE ta.id = $1", attemptID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, type, question, options, match_options, correct_answer, correct_answers, tolerance, partial_credit, points
+		FROM test_questions WHERE test_id = $1 ORDER BY position ASC, created_at ASC
+	`, rec.TestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rec.Questions = []domain.TestQuestion{}
+	for rows.Next() {
+		var q domain.TestQuestion
+		var optionsRaw, matchRaw, correctRaw []byte
+		if err := rows.Scan(&q.ID, &q.Type, &q.Question, &optionsRaw, &matchRaw, &q.CorrectAnswer, &correctRaw, &q.Tolerance, &q.PartialCredit, &q.Points); err != nil {
+			return nil, err
+		}
+		json.Unmarshal(optionsRaw, &q.Options)
+		json.Unmarshal(matchRaw, &q.MatchOptions)
+		json.Unmarshal(correctRaw, &q.CorrectAnswers)
+		rec.Questions = append(rec.Questions, q)
+	}
+	return rec, rows.Err()
+}
+
+// SaveTestAttemptGrade обновляет только попытку, которая всё ещё ждёт проверки,
+// чтобы две параллельные проверки не перезаписали друг друга.
+func (r *ReviewRepoImpl) SaveTestAttemptGrade(ctx context.Context, attempt *domain.TestAttempt, graderID string) error {
+	resultsJSON, err := json.Marshal(attempt.Results)
+	if err != nil {
+		return err
+	}
 	query := `
-		UPDATE user_courses 
-		SET progress_percent = (
-			SELECT 
-				COUNT(CASE WHEN uas.status = 'accepted' THEN 1 END) * 100 / COUNT(a.id)
-			FROM assignments a
-			JOIN lessons l ON a.lesson_id = l.id
-			JOIN modules m ON l.module_id = m.id
-			LEFT JOIN user_assignments_submission uas ON a.id = uas.assignment_id AND uas.user_id = $1
-			WHERE m.course_id = $2
-		)
-		WHERE user_id = $1 AND course_id = $2
+		UPDATE test_attempts
+		SET results = $1, score = $2, percent = $3, passed = $4, status = $5, graded_by = $6, graded_at = NOW()
+		WHERE id = $7 AND status = 'pending_review'
+		RETURNING graded_at
 	`
-	_, err := r.db.ExecContext(ctx, query, userID, courseID)
-	return err
+	var gradedAt time.Time
+	err = r.db.QueryRowContext(ctx, query,
+		resultsJSON, attempt.Score, attempt.Percent, attempt.Passed, attempt.Status, graderID, attempt.ID,
+	).Scan(&gradedAt)
+	if err != nil {
+		return err
+	}
+	attempt.GradedAt = &gradedAt
+	return nil
 }
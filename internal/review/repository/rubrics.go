@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"lms_backend/internal/domain"
+)
+
+// GetRubric — критерии ДЗ (assignmentID) или проекта (projectID) в порядке следования.
+func (r *ReviewRepoImpl) GetRubric(ctx context.Context, assignmentID, projectID string) (*domain.Rubric, error) {
+	rubric := &domain.Rubric{AssignmentID: assignmentID, ProjectID: projectID, Criteria: []domain.RubricCriterion{}}
+	column, owner := "assignment_id", assignmentID
+	if projectID != "" {
+		column, owner = "project_id", projectID
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, title, description, max_points FROM rubric_criteria WHERE %s = $1 ORDER BY position ASC", column,
+	), owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c domain.RubricCriterion
+		if err := rows.Scan(&c.ID, &c.Title, &c.Description, &c.MaxPoints); err != nil {
+			return nil, err
+		}
+		rubric.Criteria = append(rubric.Criteria, c)
+	}
+	return rubric, rows.Err()
+}
+
+func nonNilScores(scores []domain.CriterionScore) []domain.CriterionScore {
+	if scores == nil {
+		return []domain.CriterionScore{}
+	}
+	return scores
+}
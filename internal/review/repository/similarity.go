@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+)
+
+// GetSimilarityReport — последняя версия ДЗ ученика и похожие на неё работы других учеников
+// (в обе стороны пары), от самых похожих. Passages не заполняются. Без сдачи — sql.ErrNoRows.
+func (r *ReviewRepoImpl) GetSimilarityReport(ctx context.Context, assignmentID, studentID string) (*domain.SimilarityReport, error) {
+	report := &domain.SimilarityReport{AssignmentID: assignmentID, UserID: studentID, Matches: []*domain.SimilarityMatch{}}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT v.id, v.version, COALESCE(f.content, v.submission_text)
+		FROM assignment_submission_versions v
+		LEFT JOIN submission_fingerprints f ON f.version_id = v.id
+		WHERE v.assignment_id = $1 AND v.user_id = $2
+		ORDER BY v.version DESC
+		LIMIT 1
+	`, assignmentID, studentID).Scan(&report.VersionID, &report.Version, &report.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT v.id, v.user_id, u.first_name || ' ' || u.last_name, v.version, v.submitted_at, s.score, f.content
+		FROM submission_similarities s
+		JOIN assignment_submission_versions v
+			ON v.id = CASE WHEN s.version_id = $1 THEN s.matched_version_id ELSE s.version_id END
+		JOIN users u ON u.id = v.user_id
+		JOIN submission_fingerprints f ON f.version_id = v.id
+		WHERE s.version_id = $1 OR s.matched_version_id = $1
+		ORDER BY s.score DESC, v.submitted_at
+	`, report.VersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := &domain.SimilarityMatch{}
+		if err := rows.Scan(&m.VersionID, &m.StudentID, &m.StudentName, &m.Version, &m.SubmittedAt, &m.Score, &m.Content); err != nil {
+			return nil, err
+		}
+		report.Matches = append(report.Matches, m)
+	}
+	return report, rows.Err()
+}
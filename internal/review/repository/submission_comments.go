@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"lms_backend/internal/domain"
+)
+
+func (r *ReviewRepoImpl) GetAssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM assignments WHERE lesson_id = $1 LIMIT 1", lessonID).Scan(&id)
+	return id, err
+}
+
+func (r *ReviewRepoImpl) SubmissionExists(ctx context.Context, assignmentID, studentID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM user_assignments_submission WHERE assignment_id = $1 AND user_id = $2)",
+		assignmentID, studentID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *ReviewRepoImpl) AddSubmissionComment(ctx context.Context, c *domain.SubmissionComment) error {
+	attachmentsJSON, err := json.Marshal(c.Attachments)
+	if err != nil {
+		return err
+	}
+	var file *string
+	var lineStart, lineEnd *int
+	if c.Anchor != nil {
+		if c.Anchor.File != "" {
+			file = &c.Anchor.File
+		}
+		if c.Anchor.LineStart > 0 {
+			lineStart, lineEnd = &c.Anchor.LineStart, &c.Anchor.LineEnd
+		}
+	}
+	query := `
+		INSERT INTO submission_comments (assignment_id, student_id, author_id, version, body, attachments, anchor_file, line_start, line_end)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		c.AssignmentID, c.StudentID, c.AuthorID, c.Version, c.Body, attachmentsJSON, file, lineStart, lineEnd,
+	).Scan(&c.ID, &c.CreatedAt)
+}
+
+// GetSubmissionComments — тред работы в хронологическом порядке вместе с отметками о прочтении.
+func (r *ReviewRepoImpl) GetSubmissionComments(ctx context.Context, assignmentID, studentID string) ([]*domain.SubmissionComment, error) {
+	query := `
+		SELECT c.id, c.assignment_id, c.student_id, c.author_id, u.first_name || ' ' || u.last_name, u.role,
+		       COALESCE(c.version, 0), c.body, c.attachments, COALESCE(c.anchor_file, ''),
+		       COALESCE(c.line_start, 0), COALESCE(c.line_end, 0), c.created_at
+		FROM submission_comments c
+		JOIN users u ON u.id = c.author_id
+		WHERE c.assignment_id = $1 AND c.student_id = $2
+		ORDER BY c.created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*domain.SubmissionComment{}
+	byID := map[string]*domain.SubmissionComment{}
+	for rows.Next() {
+		c := &domain.SubmissionComment{ReadBy: []domain.CommentRead{}}
+		var attachmentsRaw []byte
+		anchor := domain.CommentAnchor{}
+		if err := rows.Scan(&c.ID, &c.AssignmentID, &c.StudentID, &c.AuthorID, &c.AuthorName, &c.AuthorRole,
+			&c.Version, &c.Body, &attachmentsRaw, &anchor.File, &anchor.LineStart, &anchor.LineEnd, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(attachmentsRaw, &c.Attachments); err != nil {
+			return nil, err
+		}
+		if anchor != (domain.CommentAnchor{}) {
+			c.Anchor = &anchor
+		}
+		comments = append(comments, c)
+		byID[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	readRows, err := r.db.QueryContext(ctx, `
+		SELECT cr.comment_id, cr.user_id, cr.read_at
+		FROM submission_comment_reads cr
+		JOIN submission_comments c ON c.id = cr.comment_id
+		WHERE c.assignment_id = $1 AND c.student_id = $2
+		ORDER BY cr.read_at ASC
+	`, assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer readRows.Close()
+	for readRows.Next() {
+		var commentID string
+		var read domain.CommentRead
+		if err := readRows.Scan(&commentID, &read.UserID, &read.ReadAt); err != nil {
+			return nil, err
+		}
+		if c, ok := byID[commentID]; ok {
+			c.ReadBy = append(c.ReadBy, read)
+		}
+	}
+	return comments, readRows.Err()
+}
+
+// MarkSubmissionCommentsRead отмечает прочитанными все чужие сообщения треда; повторная отметка не меняет read_at.
+func (r *ReviewRepoImpl) MarkSubmissionCommentsRead(ctx context.Context, assignmentID, studentID, userID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO submission_comment_reads (comment_id, user_id)
+		SELECT id, $3 FROM submission_comments
+		WHERE assignment_id = $1 AND student_id = $2 AND author_id != $3
+		ON CONFLICT DO NOTHING
+	`, assignmentID, studentID, userID)
+	return err
+}
+
+// CountUnreadStudentReplies — непрочитанные userID сообщения учеников по ключу "assignmentID/studentID".
+func (r *ReviewRepoImpl) CountUnreadStudentReplies(ctx context.Context, userID string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.assignment_id, c.student_id, COUNT(*)
+		FROM submission_comments c
+		WHERE c.author_id = c.student_id
+		  AND NOT EXISTS (SELECT 1 FROM submission_comment_reads cr WHERE cr.comment_id = c.id AND cr.user_id = $1)
+		GROUP BY c.assignment_id, c.student_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var assignmentID, studentID string
+		var n int
+		if err := rows.Scan(&assignmentID, &studentID, &n); err != nil {
+			return nil, err
+		}
+		counts[assignmentID+"/"+studentID] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"lms_backend/internal/domain"
+)
+
+// GetSubmissionVersions — история сдачи ДЗ учеником, начиная с последней версии.
+func (r *ReviewRepoImpl) GetSubmissionVersions(ctx context.Context, assignmentID, studentID string) ([]*domain.SubmissionVersion, error) {
+	query := `
+		SELECT id, assignment_id, user_id, version, submission_text, submission_files, status,
+		       COALESCE(grade, 0), COALESCE(teacher_comment, ''), reviewed_by, reviewed_at, submitted_at
+		FROM assignment_submission_versions
+		WHERE assignment_id = $1 AND user_id = $2
+		ORDER BY version DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*domain.SubmissionVersion{}
+	for rows.Next() {
+		v := &domain.SubmissionVersion{}
+		var filesRaw []byte
+		if err := rows.Scan(&v.ID, &v.AssignmentID, &v.UserID, &v.Version, &v.Text, &filesRaw, &v.Status,
+			&v.Grade, &v.TeacherComment, &v.ReviewedBy, &v.ReviewedAt, &v.SubmittedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(filesRaw, &v.Files); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
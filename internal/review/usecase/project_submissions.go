@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"lms_backend/internal/domain"
+)
+
+var (
+	ErrInvalidProjectGrade  = errors.New("invalid project grade")
+	ErrSubmissionSuperseded = errors.New("a newer version of this submission exists")
+)
+
+type EvaluateProjectInput struct {
+	SubmissionID string
+	Grade        int
+	Scores       []domain.CriterionScore
+	Comment      string
+	Status       string
+}
+
+// EvaluateProject оценивает последнюю версию сдачи проекта. Принятая работа получает
+// оценку от 0 до Project.MaxScore (при рубрике — сумму баллов по критериям), отправленная на доработку — 0.
+func (uc *ReviewUseCase) EvaluateProject(ctx context.Context, actor domain.Actor, input EvaluateProjectInput) (*domain.ProjectSubmission, error) {
+	sub, err := uc.repo.GetProjectSubmission(ctx, input.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, sub.UserID); err != nil {
+		return nil, err
+	}
+	rubric, err := uc.repo.GetRubric(ctx, "", sub.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	scores, total, err := scoreRubric(rubric, input.Scores, input.Status)
+	if err != nil {
+		return nil, err
+	}
+	if input.Status == "accepted" {
+		if !rubric.Empty() {
+			input.Grade = total
+		}
+		if input.Grade < 0 || input.Grade > sub.MaxScore {
+			return nil, fmt.Errorf("%w: grade must be between 0 and %d", ErrInvalidProjectGrade, sub.MaxScore)
+		}
+	} else {
+		input.Grade = 0
+	}
+
+	sub.Status = input.Status
+	sub.Grade = input.Grade
+	sub.TeacherComment = input.Comment
+	sub.RubricScores = scores
+	if err := uc.repo.EvaluateProjectSubmission(ctx, sub, actor.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSubmissionSuperseded
+		}
+		return nil, err
+	}
+	if sub.Status == "accepted" {
+		if courseID, err := uc.repo.GetProjectCourseID(ctx, sub.ProjectID); err == nil {
+			uc.checkCourseCompletion(ctx, sub.UserID, courseID)
+		}
+	}
+	return sub, nil
+}
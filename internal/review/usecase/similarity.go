@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"lms_backend/internal/domain"
+)
+
+// GetSimilarityReport — похожие работы для последней версии ДЗ ученика с совпадающими фрагментами
+// для сравнения бок о бок. Работы учеников вне области доступа проверяющего обезличиваются:
+// остаются только схожесть и подпись «Ученик N».
+func (uc *ReviewUseCase) GetSimilarityReport(ctx context.Context, actor domain.Actor, assignmentID, studentID string) (*domain.SimilarityReport, error) {
+	if studentID == "" {
+		return nil, ErrStudentRequired
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
+	report, err := uc.repo.GetSimilarityReport(ctx, assignmentID, studentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	visible, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	hidden := 0
+	for _, m := range report.Matches {
+		if !visible.Contains(m.StudentID) {
+			hidden++
+			m.Anonymize(fmt.Sprintf("Ученик %d", hidden))
+			continue
+		}
+		m.Passages = domain.MatchingPassages(report.Content, m.Content)
+	}
+	return report, nil
+}
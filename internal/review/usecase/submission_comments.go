@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"lms_backend/internal/domain"
+)
+
+const s3UploadTimeout = 30 * time.Second
+
+var (
+	ErrSubmissionNotFound = errors.New("submission not found")
+	ErrEmptyComment       = errors.New("comment must contain text or attachments")
+	ErrInvalidAnchor      = errors.New("invalid comment anchor")
+)
+
+// AssignmentIDByLesson — ID задания урока для ученических маршрутов треда.
+func (uc *ReviewUseCase) AssignmentIDByLesson(ctx context.Context, lessonID string) (string, error) {
+	return uc.repo.GetAssignmentIDByLesson(ctx, lessonID)
+}
+
+// GetThread — обсуждение работы ученика. IsRead и Unread считаются для actor.
+func (uc *ReviewUseCase) GetThread(ctx context.Context, actor domain.Actor, assignmentID, studentID string) (*domain.SubmissionThread, error) {
+	if err := uc.checkThreadAccess(ctx, actor, assignmentID, studentID); err != nil {
+		return nil, err
+	}
+	comments, err := uc.repo.GetSubmissionComments(ctx, assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	thread := &domain.SubmissionThread{AssignmentID: assignmentID, StudentID: studentID, Comments: comments}
+	for _, c := range comments {
+		c.IsRead = c.AuthorID == actor.UserID
+		for _, read := range c.ReadBy {
+			if read.UserID == actor.UserID {
+				c.IsRead = true
+				break
+			}
+		}
+		if !c.IsRead {
+			thread.Unread++
+		}
+	}
+	return thread, nil
+}
+
+func (uc *ReviewUseCase) MarkThreadRead(ctx context.Context, actor domain.Actor, assignmentID, studentID string) error {
+	if err := uc.checkThreadAccess(ctx, actor, assignmentID, studentID); err != nil {
+		return err
+	}
+	return uc.repo.MarkSubmissionCommentsRead(ctx, assignmentID, studentID, actor.UserID)
+}
+
+type AddCommentInput struct {
+	AssignmentID string
+	StudentID    string
+	Body         string
+	// Version — версия сдачи, к которой относится сообщение; 0 — последняя.
+	Version     int
+	Anchor      *domain.CommentAnchor
+	FileHeaders []*multipart.FileHeader
+}
+
+// AddComment добавляет сообщение в тред. Привязка проверяется по версии сдачи:
+// файл должен быть среди файлов версии, строки без файла — в пределах текста ответа.
+func (uc *ReviewUseCase) AddComment(ctx context.Context, actor domain.Actor, input AddCommentInput) (*domain.SubmissionComment, error) {
+	if err := uc.checkThreadAccess(ctx, actor, input.AssignmentID, input.StudentID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(input.Body) == "" && len(input.FileHeaders) == 0 {
+		return nil, ErrEmptyComment
+	}
+
+	versions, err := uc.repo.GetSubmissionVersions(ctx, input.AssignmentID, input.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := pickVersion(versions, input.Version)
+	if err != nil {
+		return nil, err
+	}
+	if input.Anchor != nil {
+		if err := validateAnchor(input.Anchor, version); err != nil {
+			return nil, err
+		}
+	}
+
+	comment := &domain.SubmissionComment{
+		AssignmentID: input.AssignmentID,
+		StudentID:    input.StudentID,
+		AuthorID:     actor.UserID,
+		AuthorRole:   actor.Role,
+		Body:         input.Body,
+		Attachments:  []string{},
+		Anchor:       input.Anchor,
+		ReadBy:       []domain.CommentRead{},
+		IsRead:       true,
+	}
+	if version != nil {
+		comment.Version = version.Version
+	}
+	stamp := time.Now().UnixNano()
+	for _, fh := range input.FileHeaders {
+		key := fmt.Sprintf("submission-comments/%s_%s_%d_%s", input.StudentID, input.AssignmentID, stamp, fh.Filename)
+		url, err := uc.uploadAttachment(ctx, fh, key)
+		if err != nil {
+			return nil, err
+		}
+		comment.Attachments = append(comment.Attachments, url)
+	}
+	if err := uc.repo.AddSubmissionComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// checkThreadAccess — тред есть только у отправленной работы доступного ученика.
+func (uc *ReviewUseCase) checkThreadAccess(ctx context.Context, actor domain.Actor, assignmentID, studentID string) error {
+	if studentID == "" {
+		return ErrStudentRequired
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return err
+	}
+	exists, err := uc.repo.SubmissionExists(ctx, assignmentID, studentID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrSubmissionNotFound
+	}
+	return nil
+}
+
+// pickVersion возвращает версию number (0 — последнюю). Работы без истории версий
+// допускают только сообщения без привязки к версии.
+func pickVersion(versions []*domain.SubmissionVersion, number int) (*domain.SubmissionVersion, error) {
+	if len(versions) == 0 {
+		if number != 0 {
+			return nil, ErrVersionNotFound
+		}
+		return nil, nil
+	}
+	if number == 0 {
+		return versions[0], nil
+	}
+	for _, v := range versions {
+		if v.Version == number {
+			return v, nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+func validateAnchor(anchor *domain.CommentAnchor, version *domain.SubmissionVersion) error {
+	if version == nil {
+		return fmt.Errorf("%w: submission has no versions", ErrInvalidAnchor)
+	}
+	if anchor.File == "" && anchor.LineStart == 0 {
+		return fmt.Errorf("%w: file or line_start is required", ErrInvalidAnchor)
+	}
+	if anchor.File != "" && !contains(version.Files, anchor.File) {
+		return fmt.Errorf("%w: file is not part of version %d", ErrInvalidAnchor, version.Version)
+	}
+	if anchor.LineEnd == 0 {
+		anchor.LineEnd = anchor.LineStart
+	}
+	if anchor.LineStart < 0 || anchor.LineEnd < anchor.LineStart || anchor.LineStart == 0 && anchor.LineEnd != 0 {
+		return fmt.Errorf("%w: line range must satisfy 1 <= line_start <= line_end", ErrInvalidAnchor)
+	}
+	if anchor.File == "" && anchor.LineEnd > len(splitLines(version.Text)) {
+		return fmt.Errorf("%w: answer text has only %d lines", ErrInvalidAnchor, len(splitLines(version.Text)))
+	}
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *ReviewUseCase) uploadAttachment(ctx context.Context, fh *multipart.FileHeader, s3Key string) (string, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	s3Ctx, cancel := context.WithTimeout(ctx, s3UploadTimeout)
+	defer cancel()
+	mimeType := fh.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(fh.Filename))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+	}
+	key, err := uc.s3Storage.UploadFile(s3Ctx, file, s3Key, fh.Size, mimeType)
+	if err != nil {
+		return "", err
+	}
+	url, _ := uc.s3Storage.GetPublicURL(ctx, key)
+	return url, nil
+}
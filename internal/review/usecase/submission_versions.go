@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"lms_backend/internal/domain"
+)
+
+var ErrVersionNotFound = errors.New("submission version not found")
+
+// GetSubmissionHistory — все версии сдачи ДЗ учеником, последняя первой.
+func (uc *ReviewUseCase) GetSubmissionHistory(ctx context.Context, actor domain.Actor, assignmentID, studentID string) ([]*domain.SubmissionVersion, error) {
+	if studentID == "" {
+		return nil, ErrStudentRequired
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetSubmissionVersions(ctx, assignmentID, studentID)
+}
+
+// DiffSubmission сравнивает две версии сдачи. to = 0 — последняя версия, from = 0 — предыдущая к to.
+func (uc *ReviewUseCase) DiffSubmission(ctx context.Context, actor domain.Actor, assignmentID, studentID string, from, to int) (*domain.SubmissionDiff, error) {
+	versions, err := uc.GetSubmissionHistory(ctx, actor, assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, ErrVersionNotFound
+	}
+	if to == 0 {
+		to = versions[0].Version
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	byVersion := make(map[int]*domain.SubmissionVersion, len(versions))
+	for _, v := range versions {
+		byVersion[v.Version] = v
+	}
+	newer, ok := byVersion[to]
+	if !ok {
+		return nil, ErrVersionNotFound
+	}
+	// Для первой версии сравнение идёт с пустой работой.
+	older := &domain.SubmissionVersion{}
+	if from > 0 {
+		if older, ok = byVersion[from]; !ok {
+			return nil, ErrVersionNotFound
+		}
+	}
+
+	return &domain.SubmissionDiff{
+		AssignmentID: assignmentID,
+		UserID:       studentID,
+		From:         from,
+		To:           to,
+		Text:         diffLines(splitLines(older.Text), splitLines(newer.Text)),
+		FilesAdded:   missingFrom(newer.Files, older.Files),
+		FilesRemoved: missingFrom(older.Files, newer.Files),
+	}, nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// maxDiffLines — предел строк версии для построчного diff. Таблица LCS квадратична по памяти,
+// поэтому версии длиннее (например, сданные до ограничения длины ответа) сравниваются целиком.
+const maxDiffLines = 2000
+
+// diffLines — построчный diff по наибольшей общей подпоследовательности. Если одна из версий
+// длиннее maxDiffLines, старый текст целиком удаляется, а новый целиком вставляется.
+func diffLines(a, b []string) []domain.DiffLine {
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return replaceLines(a, b)
+	}
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := []domain.DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, domain.DiffLine{Op: domain.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, domain.DiffLine{Op: domain.DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, domain.DiffLine{Op: domain.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, domain.DiffLine{Op: domain.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, domain.DiffLine{Op: domain.DiffInsert, Text: b[j]})
+	}
+	return lines
+}
+
+func replaceLines(a, b []string) []domain.DiffLine {
+	lines := make([]domain.DiffLine, 0, len(a)+len(b))
+	for _, l := range a {
+		lines = append(lines, domain.DiffLine{Op: domain.DiffDelete, Text: l})
+	}
+	for _, l := range b {
+		lines = append(lines, domain.DiffLine{Op: domain.DiffInsert, Text: l})
+	}
+	return lines
+}
+
+// missingFrom — элементы list, которых нет в other.
+func missingFrom(list, other []string) []string {
+	seen := make(map[string]bool, len(other))
+	for _, s := range other {
+		seen[s] = true
+	}
+	result := []string{}
+	for _, s := range list {
+		if !seen[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"lms_backend/internal/domain"
+)
+
+var (
+	ErrAttemptNotPending    = errors.New("test attempt is not waiting for review")
+	ErrInvalidQuestionGrade = errors.New("invalid question grade")
+)
+
+// GetPendingTestAttempts — попытки тестов со свободными ответами, доступные проверяющему.
+func (uc *ReviewUseCase) GetPendingTestAttempts(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.TestAttemptReview, error) {
+	if studentID != "" {
+		if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+			return nil, err
+		}
+	}
+	attempts, err := uc.repo.GetPendingTestAttempts(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if scope.All {
+		return attempts, nil
+	}
+	visible := make([]*domain.TestAttemptReview, 0, len(attempts))
+	for _, a := range attempts {
+		if scope.Contains(a.UserID) {
+			visible = append(visible, a)
+		}
+	}
+	return visible, nil
+}
+
+// GetTestAttempt — попытка с вопросами и ключами для экрана проверки.
+func (uc *ReviewUseCase) GetTestAttempt(ctx context.Context, actor domain.Actor, attemptID string) (*domain.TestAttemptReview, error) {
+	attempt, err := uc.repo.GetTestAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, attempt.UserID); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+type QuestionGrade struct {
+	QuestionID string
+	Score      float64
+	Comment    string
+}
+
+type GradeTestAttemptInput struct {
+	AttemptID string
+	Grades    []QuestionGrade
+}
+
+// GradeTestAttempt выставляет баллы за вопросы ручной проверки. Можно проверять по частям:
+// попытка становится graded, когда не остаётся вопросов с needs_review.
+func (uc *ReviewUseCase) GradeTestAttempt(ctx context.Context, actor domain.Actor, input GradeTestAttemptInput) (*domain.TestAttempt, error) {
+	rec, err := uc.GetTestAttempt(ctx, actor, input.AttemptID)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Status != domain.TestAttemptPendingReview {
+		return nil, ErrAttemptNotPending
+	}
+
+	attempt := rec.TestAttempt
+	byQuestion := make(map[string]int, len(attempt.Results))
+	for i, r := range attempt.Results {
+		byQuestion[r.QuestionID] = i
+	}
+	for _, g := range input.Grades {
+		i, ok := byQuestion[g.QuestionID]
+		if !ok || !attempt.Results[i].NeedsReview {
+			return nil, fmt.Errorf("%w: question %s does not need review", ErrInvalidQuestionGrade, g.QuestionID)
+		}
+		if g.Score < 0 || g.Score > float64(attempt.Results[i].MaxScore) {
+			return nil, fmt.Errorf("%w: score for %s must be between 0 and %d", ErrInvalidQuestionGrade, g.QuestionID, attempt.Results[i].MaxScore)
+		}
+		attempt.Results[i].Score = g.Score
+		attempt.Results[i].Comment = g.Comment
+		attempt.Results[i].NeedsReview = false
+	}
+
+	attempt.Finalize(rec.PassingScore)
+	if err := uc.repo.SaveTestAttemptGrade(ctx, &attempt, actor.UserID); err != nil {
+		return nil, err
+	}
+	if attempt.Passed {
+		if courseID, err := uc.repo.GetTestCourseID(ctx, attempt.TestID); err == nil {
+			uc.checkCourseCompletion(ctx, attempt.UserID, courseID)
+		}
+	}
+	return &attempt, nil
+}
@@ -2,38 +2,228 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"lms_backend/internal/domain"
 	"lms_backend/internal/review/repository"
+	storageService "lms_backend/pkg/storage"
+	"log/slog"
+	"sort"
+	"strings"
 )
 
+var ErrStudentRequired = errors.New("student_id is required")
+
+// ScopeChecker — проверки доступа к ученику (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	VisibleStudents(ctx context.Context, actor domain.Actor) (*domain.StudentScope, error)
+}
+
+// CourseCompleter — проверка завершения курса и выдача сертификата (реализует learning LearningUseCase).
+type CourseCompleter interface {
+	CompleteCourse(ctx context.Context, userID, courseID string) (*domain.Certificate, error)
+}
+
 type ReviewUseCase struct {
-	repo repository.ReviewRepository
+	repo       repository.ReviewRepository
+	scope      ScopeChecker
+	s3Storage  storageService.ObjectStorage
+	completion CourseCompleter
 }
 
-func NewReviewUseCase(repo repository.ReviewRepository) *ReviewUseCase {
-	return &ReviewUseCase{repo: repo}
+func NewReviewUseCase(repo repository.ReviewRepository, scope ScopeChecker, s3Storage storageService.ObjectStorage, completion CourseCompleter) *ReviewUseCase {
+	return &ReviewUseCase{repo: repo, scope: scope, s3Storage: s3Storage, completion: completion}
 }
 
-func (uc *ReviewUseCase) GetPendingList(ctx context.Context, staffID string, role string, studentID string) ([]*domain.SubmissionRecord, error) {
-	return uc.repo.GetPendingSubmissions(ctx, staffID, role, studentID)
+// GetPendingList — ДЗ и проекты только тех учеников, которые доступны проверяющему,
+// от самых давних отправок к новым.
+func (uc *ReviewUseCase) GetPendingList(ctx context.Context, actor domain.Actor, studentID string) ([]*domain.SubmissionRecord, error) {
+	if studentID != "" {
+		if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+			return nil, err
+		}
+	}
+	records, err := uc.repo.GetPendingSubmissions(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := uc.repo.GetProjectSubmissions(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := uc.repo.CountUnreadStudentReplies(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		rec.UnreadComments = unread[rec.ID+"/"+rec.UserID]
+	}
+	records = append(records, projects...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].SubmittedAt.Before(records[j].SubmittedAt)
+	})
+	scope, err := uc.scope.VisibleStudents(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	visible := records
+	if !scope.All {
+		visible = make([]*domain.SubmissionRecord, 0, len(records))
+		for _, rec := range records {
+			if scope.Contains(rec.UserID) {
+				visible = append(visible, rec)
+			}
+		}
+	}
+	if err := uc.attachRubrics(ctx, visible); err != nil {
+		return nil, err
+	}
+	return visible, nil
 }
 
+// EvaluateInput — SubmissionID совпадает с ID задания, поэтому работа
+// однозначно определяется только вместе с StudentID. Letter — оценка для буквенной схемы курса.
+// Если у задания есть рубрика, оценка принятой работы — сумма Scores по критериям.
 type EvaluateInput struct {
 	SubmissionID string
+	StudentID    string
 	Grade        int
+	Letter       string
+	Scores       []domain.CriterionScore
 	Comment      string
 	Status       string
 }
 
-func (uc *ReviewUseCase) Evaluate(ctx context.Context, input EvaluateInput) error {
+func (uc *ReviewUseCase) Evaluate(ctx context.Context, actor domain.Actor, input EvaluateInput) error {
+	if input.StudentID == "" {
+		return ErrStudentRequired
+	}
+	if err := uc.scope.CanAccessStudent(ctx, actor, input.StudentID); err != nil {
+		return err
+	}
+	course, err := uc.repo.GetAssignmentCourse(ctx, input.SubmissionID)
+	if err != nil {
+		return err
+	}
+	rubric, err := uc.repo.GetRubric(ctx, input.SubmissionID, "")
+	if err != nil {
+		return err
+	}
+	scores, total, err := scoreRubric(rubric, input.Scores, input.Status)
+	if err != nil {
+		return err
+	}
+	switch {
+	case input.Status != "accepted":
+		input.Grade = 0
+	case !rubric.Empty():
+		input.Grade = total
+	default:
+		if input.Grade, err = course.GradeScheme.AcceptedGrade(input.Grade, input.Letter); err != nil {
+			return err
+		}
+	}
 	if input.Status == "accepted" {
-		validGrades := map[int]bool{20: true, 40: true, 60: true, 80: true, 100: true}
-		if !validGrades[input.Grade] {
-			input.Grade = 100
+		if input.Grade, err = uc.applyLatePenalty(ctx, input.SubmissionID, input.StudentID, input.Grade); err != nil {
+			return err
 		}
-	} else {
-		input.Grade = 0
+		// Оценка без рубрики после штрафа должна остаться допустимой в схеме курса.
+		if rubric.Empty() {
+			input.Grade = course.GradeScheme.SnapGrade(input.Grade)
+		}
+	}
+
+	if err := uc.repo.EvaluateSubmission(ctx, input.SubmissionID, input.StudentID, input.Grade, input.Comment, input.Status, actor.UserID, scores); err != nil {
+		return err
+	}
+	if err := uc.updateCourseProgress(ctx, input.StudentID, course); err != nil {
+		return err
+	}
+	if input.Status == "accepted" {
+		uc.checkCourseCompletion(ctx, input.StudentID, course.ID)
+	}
+	if strings.TrimSpace(input.Comment) == "" {
+		return nil
+	}
+	// Комментарий к оценке остаётся в teacher_comment и попадает в обсуждение работы,
+	// чтобы ученик мог на него ответить.
+	comment := &domain.SubmissionComment{
+		AssignmentID: input.SubmissionID,
+		StudentID:    input.StudentID,
+		AuthorID:     actor.UserID,
+		AuthorRole:   actor.Role,
+		Body:         input.Comment,
+		Attachments:  []string{},
+	}
+	versions, err := uc.repo.GetSubmissionVersions(ctx, input.SubmissionID, input.StudentID)
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 {
+		comment.Version = versions[0].Version
+	}
+	return uc.repo.AddSubmissionComment(ctx, comment)
+}
+
+// scoreRubric проверяет баллы по критериям. Принятая работа с рубрикой должна быть оценена
+// по всем критериям; при отправке на доработку баллы необязательны.
+func scoreRubric(rubric *domain.Rubric, scores []domain.CriterionScore, status string) ([]domain.CriterionScore, int, error) {
+	if len(scores) == 0 && (status != "accepted" || rubric.Empty()) {
+		return []domain.CriterionScore{}, 0, nil
+	}
+	return rubric.Score(scores)
+}
+
+// applyLatePenalty снижает оценку опоздавшей работы, если у задания политика penalize.
+// Разбивка по критериям рубрики остаётся без штрафа.
+func (uc *ReviewUseCase) applyLatePenalty(ctx context.Context, assignmentID, studentID string, grade int) (int, error) {
+	deadline, isLate, err := uc.repo.GetSubmissionDeadline(ctx, assignmentID, studentID)
+	if err != nil {
+		return 0, err
+	}
+	if !isLate || deadline.LatePolicy != domain.LatePolicyPenalize {
+		return grade, nil
 	}
+	return domain.ApplyLatePenalty(grade, deadline.LatePenaltyPercent), nil
+}
 
-	return uc.repo.EvaluateSubmission(ctx, input.SubmissionID, input.Grade, input.Comment, input.Status)
+// updateCourseProgress пересчитывает прогресс ученика по курсу с учётом схемы оценивания.
+func (uc *ReviewUseCase) updateCourseProgress(ctx context.Context, studentID string, course *domain.Course) error {
+	results, err := uc.repo.GetCourseAssignmentResults(ctx, studentID, course.ID)
+	if err != nil {
+		return err
+	}
+	return uc.repo.UpdateUserCourseProgress(ctx, studentID, course.ID, domain.CourseProgress(course.GradeScheme, results))
+}
+
+// checkCourseCompletion выдаёт сертификат, если принятая работа завершила курс. Ошибка выдачи
+// не отменяет проверку работы, поэтому только логируется.
+func (uc *ReviewUseCase) checkCourseCompletion(ctx context.Context, studentID, courseID string) {
+	if _, err := uc.completion.CompleteCourse(ctx, studentID, courseID); err != nil {
+		slog.Error("course completion", slog.String("user_id", studentID), slog.String("course_id", courseID), slog.String("error", err.Error()))
+	}
+}
+
+// attachRubrics добавляет к записям очереди критерии их рубрик; работы без рубрики остаются без поля.
+func (uc *ReviewUseCase) attachRubrics(ctx context.Context, records []*domain.SubmissionRecord) error {
+	cache := map[string]*domain.Rubric{}
+	for _, rec := range records {
+		assignmentID, projectID := rec.ID, ""
+		if rec.Kind == domain.SubmissionKindProject {
+			assignmentID, projectID = "", rec.ProjectID
+		}
+		key := assignmentID + "/" + projectID
+		rubric, ok := cache[key]
+		if !ok {
+			var err error
+			if rubric, err = uc.repo.GetRubric(ctx, assignmentID, projectID); err != nil {
+				return err
+			}
+			cache[key] = rubric
+		}
+		if !rubric.Empty() {
+			rec.Rubric = rubric
+		}
+	}
+	return nil
 }
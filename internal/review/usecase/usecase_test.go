@@ -2,20 +2,48 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"lms_backend/internal/domain"
 	"lms_backend/internal/review/mocks"
 	"lms_backend/internal/review/usecase"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
+	s3Mocks "lms_backend/pkg/storage/mocks"
 )
 
+var teacher = domain.Actor{UserID: "teacher-1", Role: domain.RoleTeacher}
+
+// newUseCase — teacher-1 ведёт user-1.
+func newUseCase(repo *mocks.ReviewRepositoryMock) *usecase.ReviewUseCase {
+	return newUseCaseWithCompleter(repo, &completerStub{})
+}
+
+func newUseCaseWithCompleter(repo *mocks.ReviewRepositoryMock, completer usecase.CourseCompleter) *usecase.ReviewUseCase {
+	scopeRepo := scopeMocks.TwoGroups()
+	scopeRepo.TeacherStudents["teacher-1"] = []string{"user-1"}
+	return usecase.NewReviewUseCase(repo, scopeUseCase.NewScopeUseCase(scopeRepo), s3Mocks.NewS3StorageMock(), completer)
+}
+
+// completerStub запоминает проверки завершения курса в виде "userID/courseID".
+type completerStub struct {
+	calls []string
+}
+
+func (c *completerStub) CompleteCourse(ctx context.Context, userID, courseID string) (*domain.Certificate, error) {
+	c.calls = append(c.calls, userID+"/"+courseID)
+	return nil, nil
+}
+
 func TestReviewUseCase_GetPendingList(t *testing.T) {
 	repoMock := mocks.NewReviewRepositoryMock()
-	uc := usecase.NewReviewUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 
 	t.Run("EmptyList", func(t *testing.T) {
-		list, err := uc.GetPendingList(ctx, "teacher-1", "teacher", "")
+		list, err := uc.GetPendingList(ctx, teacher, "")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -27,50 +55,49 @@ func TestReviewUseCase_GetPendingList(t *testing.T) {
 
 func TestReviewUseCase_Evaluate(t *testing.T) {
 	repoMock := mocks.NewReviewRepositoryMock()
-	uc := usecase.NewReviewUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 
 	t.Run("AcceptWithValidGrade", func(t *testing.T) {
 		input := usecase.EvaluateInput{
 			SubmissionID: "sub-1",
+			StudentID:    "user-1",
 			Grade:        80,
 			Comment:      "Good work",
 			Status:       "accepted",
 		}
-		err := uc.Evaluate(ctx, input)
+		err := uc.Evaluate(ctx, teacher, input)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 	})
 
-	t.Run("AcceptWithInvalidGradeDefaultsTo100", func(t *testing.T) {
+	t.Run("AcceptWithInvalidGradeRejected", func(t *testing.T) {
 		input := usecase.EvaluateInput{
 			SubmissionID: "sub-2",
+			StudentID:    "user-1",
 			Grade:        75,
 			Comment:      "OK",
 			Status:       "accepted",
 		}
-		err := uc.Evaluate(ctx, input)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
+		err := uc.Evaluate(ctx, teacher, input)
+		if !errors.Is(err, domain.ErrInvalidGrade) {
+			t.Fatalf("expected ErrInvalidGrade, got %v", err)
 		}
-
-		list, _ := uc.GetPendingList(ctx, "teacher-1", "teacher", "")
-		for _, s := range list {
-			if s.ID == "sub-2" && s.Grade != 100 {
-				t.Errorf("expected grade 100 for invalid grade, got %d", s.Grade)
-			}
+		if _, ok := repoMock.Submissions["sub-2/user-1"]; ok {
+			t.Errorf("rejected grade must not be saved")
 		}
 	})
 
 	t.Run("RejectSetsGradeZero", func(t *testing.T) {
 		input := usecase.EvaluateInput{
 			SubmissionID: "sub-3",
+			StudentID:    "user-1",
 			Grade:        80,
 			Comment:      "Needs rework",
 			Status:       "rejected",
 		}
-		err := uc.Evaluate(ctx, input)
+		err := uc.Evaluate(ctx, teacher, input)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -81,11 +108,12 @@ func TestReviewUseCase_Evaluate(t *testing.T) {
 		for _, g := range validGrades {
 			input := usecase.EvaluateInput{
 				SubmissionID: "sub-vg" + string(rune('0'+g)),
+				StudentID:    "user-1",
 				Grade:        g,
 				Comment:      "Valid grade",
 				Status:       "accepted",
 			}
-			err := uc.Evaluate(ctx, input)
+			err := uc.Evaluate(ctx, teacher, input)
 			if err != nil {
 				t.Errorf("unexpected error for grade %d: %v", g, err)
 			}
@@ -95,7 +123,7 @@ func TestReviewUseCase_Evaluate(t *testing.T) {
 
 func TestReviewUseCase_Evaluate_GradeEdgeCases(t *testing.T) {
 	repoMock := mocks.NewReviewRepositoryMock()
-	uc := usecase.NewReviewUseCase(repoMock)
+	uc := newUseCase(repoMock)
 	ctx := context.Background()
 
 	submission := &domain.SubmissionRecord{
@@ -115,12 +143,801 @@ func TestReviewUseCase_Evaluate_GradeEdgeCases(t *testing.T) {
 
 	input := usecase.EvaluateInput{
 		SubmissionID: "sub-edge",
+		StudentID:    "user-1",
 		Grade:        0,
 		Comment:      "Invalid",
 		Status:       "rejected",
 	}
-	err := uc.Evaluate(ctx, input)
+	err := uc.Evaluate(ctx, teacher, input)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestReviewUseCase_Scope(t *testing.T) {
+	repoMock := mocks.NewReviewRepositoryMock()
+	uc := newUseCase(repoMock)
+	ctx := context.Background()
+	teacherB := domain.Actor{UserID: "teacher-b", Role: domain.RoleTeacher}
+
+	repoMock.Submissions["asg-1/student-a"] = &domain.SubmissionRecord{ID: "asg-1", UserID: "student-a", Status: "pending"}
+	repoMock.Submissions["asg-1/student-b"] = &domain.SubmissionRecord{ID: "asg-1", UserID: "student-b", Status: "pending"}
+
+	t.Run("PendingListFiltered", func(t *testing.T) {
+		list, err := uc.GetPendingList(ctx, teacherB, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].UserID != "student-b" {
+			t.Fatalf("expected only student-b submission, got %+v", list)
+		}
+	})
+
+	t.Run("PendingListForeignStudent", func(t *testing.T) {
+		_, err := uc.GetPendingList(ctx, teacherB, "student-a")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("EvaluateForeignStudent", func(t *testing.T) {
+		err := uc.Evaluate(ctx, teacherB, usecase.EvaluateInput{
+			SubmissionID: "asg-1", StudentID: "student-a", Grade: 100, Status: "accepted",
+		})
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("EvaluateTouchesOnlyOneStudent", func(t *testing.T) {
+		err := uc.Evaluate(ctx, teacherB, usecase.EvaluateInput{
+			SubmissionID: "asg-1", StudentID: "student-b", Grade: 80, Status: "accepted",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repoMock.Submissions["asg-1/student-a"].Status != "pending" {
+			t.Error("evaluation must not change other students' submissions")
+		}
+		if repoMock.Submissions["asg-1/student-b"].Grade != 80 {
+			t.Errorf("expected grade 80, got %d", repoMock.Submissions["asg-1/student-b"].Grade)
+		}
+	})
+
+	t.Run("StudentRequired", func(t *testing.T) {
+		err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", Status: "accepted"})
+		if !errors.Is(err, usecase.ErrStudentRequired) {
+			t.Fatalf("expected ErrStudentRequired, got %v", err)
+		}
+	})
+}
+
+func TestReviewUseCase_GradeTestAttempt(t *testing.T) {
+	ctx := context.Background()
+	newAttempt := func(userID string) *domain.TestAttemptReview {
+		a := &domain.TestAttemptReview{
+			TestAttempt: domain.TestAttempt{
+				ID:     "attempt-1",
+				UserID: userID,
+				Results: []domain.QuestionResult{
+					{QuestionID: "q1", Score: 1, MaxScore: 1},
+					{QuestionID: "essay", MaxScore: 4, NeedsReview: true},
+				},
+			},
+			PassingScore: 60,
+		}
+		a.Finalize(a.PassingScore)
+		return a
+	}
+
+	t.Run("GradeCompletesAttempt", func(t *testing.T) {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.TestAttempts["attempt-1"] = newAttempt("user-1")
+		uc := newUseCase(repoMock)
+
+		pending, _ := uc.GetPendingTestAttempts(ctx, teacher, "")
+		if len(pending) != 1 {
+			t.Fatalf("expected 1 pending attempt, got %d", len(pending))
+		}
+
+		attempt, err := uc.GradeTestAttempt(ctx, teacher, usecase.GradeTestAttemptInput{
+			AttemptID: "attempt-1",
+			Grades:    []usecase.QuestionGrade{{QuestionID: "essay", Score: 3, Comment: "good"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempt.Status != domain.TestAttemptGraded || attempt.Score != 4 || attempt.Percent != 80 || !attempt.Passed {
+			t.Errorf("unexpected attempt: %+v", attempt)
+		}
+
+		_, err = uc.GradeTestAttempt(ctx, teacher, usecase.GradeTestAttemptInput{AttemptID: "attempt-1"})
+		if !errors.Is(err, usecase.ErrAttemptNotPending) {
+			t.Errorf("expected ErrAttemptNotPending, got %v", err)
+		}
+	})
+
+	t.Run("InvalidGrades", func(t *testing.T) {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.TestAttempts["attempt-1"] = newAttempt("user-1")
+		uc := newUseCase(repoMock)
+
+		for _, g := range []usecase.QuestionGrade{
+			{QuestionID: "essay", Score: 5},
+			{QuestionID: "essay", Score: -1},
+			{QuestionID: "q1", Score: 1},
+		} {
+			_, err := uc.GradeTestAttempt(ctx, teacher, usecase.GradeTestAttemptInput{AttemptID: "attempt-1", Grades: []usecase.QuestionGrade{g}})
+			if !errors.Is(err, usecase.ErrInvalidQuestionGrade) {
+				t.Errorf("grade %+v: expected ErrInvalidQuestionGrade, got %v", g, err)
+			}
+		}
+	})
+
+	t.Run("OtherGroupStudent", func(t *testing.T) {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.TestAttempts["attempt-1"] = newAttempt("student-b")
+		uc := newUseCase(repoMock)
+
+		_, err := uc.GradeTestAttempt(ctx, teacher, usecase.GradeTestAttemptInput{
+			AttemptID: "attempt-1",
+			Grades:    []usecase.QuestionGrade{{QuestionID: "essay", Score: 1}},
+		})
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("expected ErrOutOfScope, got %v", err)
+		}
+		pending, _ := uc.GetPendingTestAttempts(ctx, teacher, "")
+		if len(pending) != 0 {
+			t.Errorf("foreign attempts must be hidden, got %d", len(pending))
+		}
+	})
+}
+
+func TestReviewUseCase_EvaluateProject(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func() *mocks.ReviewRepositoryMock {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.ProjectSubmissions["v1"] = &domain.ProjectSubmission{ID: "v1", ProjectID: "p1", UserID: "user-1", Version: 1, Status: "on_revision", MaxScore: 50}
+		repoMock.ProjectSubmissions["v2"] = &domain.ProjectSubmission{ID: "v2", ProjectID: "p1", UserID: "user-1", Version: 2, Status: "pending_check", MaxScore: 50}
+		return repoMock
+	}
+
+	t.Run("QueueShowsLatestVersion", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		list, err := uc.GetPendingList(ctx, teacher, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].ID != "v2" || list[0].Kind != domain.SubmissionKindProject || list[0].MaxScore != 50 {
+			t.Fatalf("expected only the latest project version, got %+v", list)
+		}
+	})
+
+	t.Run("AcceptWithGrade", func(t *testing.T) {
+		repoMock := newRepo()
+		uc := newUseCase(repoMock)
+		sub, err := uc.EvaluateProject(ctx, teacher, usecase.EvaluateProjectInput{SubmissionID: "v2", Grade: 45, Comment: "ok", Status: "accepted"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sub.ReviewedBy == nil || *sub.ReviewedBy != teacher.UserID {
+			t.Errorf("reviewer not recorded: %+v", sub)
+		}
+		if saved := repoMock.ProjectSubmissions["v2"]; saved.Grade != 45 || saved.Status != "accepted" {
+			t.Errorf("unexpected saved submission: %+v", saved)
+		}
+	})
+
+	t.Run("GradeAboveMaxScore", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		_, err := uc.EvaluateProject(ctx, teacher, usecase.EvaluateProjectInput{SubmissionID: "v2", Grade: 51, Status: "accepted"})
+		if !errors.Is(err, usecase.ErrInvalidProjectGrade) {
+			t.Fatalf("expected ErrInvalidProjectGrade, got %v", err)
+		}
+	})
+
+	t.Run("RevisionResetsGrade", func(t *testing.T) {
+		repoMock := newRepo()
+		uc := newUseCase(repoMock)
+		if _, err := uc.EvaluateProject(ctx, teacher, usecase.EvaluateProjectInput{SubmissionID: "v2", Grade: 40, Status: "on_revision"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repoMock.ProjectSubmissions["v2"].Grade != 0 {
+			t.Errorf("expected grade 0 for on_revision")
+		}
+	})
+
+	t.Run("OldVersionSuperseded", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		_, err := uc.EvaluateProject(ctx, teacher, usecase.EvaluateProjectInput{SubmissionID: "v1", Grade: 10, Status: "accepted"})
+		if !errors.Is(err, usecase.ErrSubmissionSuperseded) {
+			t.Fatalf("expected ErrSubmissionSuperseded, got %v", err)
+		}
+	})
+
+	t.Run("ForeignStudent", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		teacherB := domain.Actor{UserID: "teacher-b", Role: domain.RoleTeacher}
+		_, err := uc.EvaluateProject(ctx, teacherB, usecase.EvaluateProjectInput{SubmissionID: "v2", Grade: 10, Status: "accepted"})
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
+
+func TestReviewUseCase_SubmissionHistory(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func() *mocks.ReviewRepositoryMock {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.Submissions["asg-1/user-1"] = &domain.SubmissionRecord{ID: "asg-1", UserID: "user-1", Status: "pending_check"}
+		repoMock.Versions["asg-1/user-1"] = []*domain.SubmissionVersion{
+			{Version: 1, Text: "def add(a, b):\n    return a - b", Files: []string{"hw.py"}, Status: "on_revision", TeacherComment: "wrong sign"},
+			{Version: 2, Text: "def add(a, b):\n    return a + b", Files: []string{"hw.py", "test.py"}, Status: "pending_check"},
+		}
+		return repoMock
+	}
+
+	t.Run("HistoryNewestFirst", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		versions, err := uc.GetSubmissionHistory(ctx, teacher, "asg-1", "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(versions) != 2 || versions[0].Version != 2 || versions[1].TeacherComment != "wrong sign" {
+			t.Fatalf("unexpected history: %+v", versions)
+		}
+	})
+
+	t.Run("EvaluateTouchesLatestVersionOnly", func(t *testing.T) {
+		repoMock := newRepo()
+		uc := newUseCase(repoMock)
+		err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Grade: 100, Comment: "fixed", Status: "accepted"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		versions := repoMock.Versions["asg-1/user-1"]
+		if versions[0].Status != "on_revision" || versions[0].TeacherComment != "wrong sign" {
+			t.Errorf("earlier version must stay unchanged: %+v", versions[0])
+		}
+		if versions[1].Grade != 100 || versions[1].ReviewedBy == nil || *versions[1].ReviewedBy != teacher.UserID {
+			t.Errorf("latest version must carry the grade: %+v", versions[1])
+		}
+	})
+
+	t.Run("DiffDefaultsToLastTwo", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		diff, err := uc.DiffSubmission(ctx, teacher, "asg-1", "user-1", 0, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []domain.DiffLine{
+			{Op: domain.DiffEqual, Text: "def add(a, b):"},
+			{Op: domain.DiffDelete, Text: "    return a - b"},
+			{Op: domain.DiffInsert, Text: "    return a + b"},
+		}
+		if diff.From != 1 || diff.To != 2 || len(diff.Text) != len(want) {
+			t.Fatalf("unexpected diff: %+v", diff)
+		}
+		for i := range want {
+			if diff.Text[i] != want[i] {
+				t.Errorf("line %d: expected %+v, got %+v", i, want[i], diff.Text[i])
+			}
+		}
+		if len(diff.FilesAdded) != 1 || diff.FilesAdded[0] != "test.py" || len(diff.FilesRemoved) != 0 {
+			t.Errorf("unexpected file changes: %+v / %+v", diff.FilesAdded, diff.FilesRemoved)
+		}
+	})
+
+	t.Run("HugeVersionsReplacedWhole", func(t *testing.T) {
+		repoMock := newRepo()
+		huge := strings.Repeat("x\n", 100_000)
+		repoMock.Versions["asg-1/user-1"][0].Text = huge
+		repoMock.Versions["asg-1/user-1"][1].Text = huge + "y"
+		diff, err := newUseCase(repoMock).DiffSubmission(ctx, teacher, "asg-1", "user-1", 0, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(diff.Text) != 200_002 || diff.Text[0].Op != domain.DiffDelete || diff.Text[len(diff.Text)-1] != (domain.DiffLine{Op: domain.DiffInsert, Text: "y"}) {
+			t.Fatalf("expected whole-text replace, got %d lines", len(diff.Text))
+		}
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		if _, err := uc.DiffSubmission(ctx, teacher, "asg-1", "user-1", 1, 5); !errors.Is(err, usecase.ErrVersionNotFound) {
+			t.Errorf("expected ErrVersionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ForeignStudent", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		teacherB := domain.Actor{UserID: "teacher-b", Role: domain.RoleTeacher}
+		if _, err := uc.GetSubmissionHistory(ctx, teacherB, "asg-1", "user-1"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
+
+func TestReviewUseCase_SubmissionThread(t *testing.T) {
+	ctx := context.Background()
+	student := domain.Actor{UserID: "user-1", Role: domain.RoleStudent}
+	newRepo := func() *mocks.ReviewRepositoryMock {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.Submissions["asg-1/user-1"] = &domain.SubmissionRecord{ID: "asg-1", UserID: "user-1", Status: "pending"}
+		repoMock.Versions["asg-1/user-1"] = []*domain.SubmissionVersion{
+			{Version: 1, Text: "line one", Files: []string{"hw.py"}},
+			{Version: 2, Text: "line one\nline two\nline three", Files: []string{"hw.py", "test.py"}},
+		}
+		return repoMock
+	}
+
+	t.Run("ConversationAndReadReceipts", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		if _, err := uc.AddComment(ctx, teacher, usecase.AddCommentInput{AssignmentID: "asg-1", StudentID: "user-1", Body: "why a loop here?"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		thread, err := uc.GetThread(ctx, student, "asg-1", "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if thread.Unread != 1 || len(thread.Comments) != 1 || thread.Comments[0].Version != 2 || thread.Comments[0].AuthorRole != domain.RoleTeacher {
+			t.Fatalf("unexpected thread for student: %+v", thread)
+		}
+
+		if err := uc.MarkThreadRead(ctx, student, "asg-1", "user-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.AddComment(ctx, student, usecase.AddCommentInput{AssignmentID: "asg-1", StudentID: "user-1", Body: "it was faster"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		thread, _ = uc.GetThread(ctx, student, "asg-1", "user-1")
+		if thread.Unread != 0 || len(thread.Comments[0].ReadBy) != 1 || thread.Comments[0].ReadBy[0].UserID != "user-1" {
+			t.Errorf("teacher comment must be read by the student: %+v", thread.Comments[0])
+		}
+		thread, _ = uc.GetThread(ctx, teacher, "asg-1", "user-1")
+		if thread.Unread != 1 || thread.Comments[0].IsRead != true || thread.Comments[1].IsRead {
+			t.Errorf("teacher must see one unread reply: %+v", thread)
+		}
+	})
+
+	t.Run("UnreadRepliesInQueue", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		uc.AddComment(ctx, student, usecase.AddCommentInput{AssignmentID: "asg-1", StudentID: "user-1", Body: "please take a look"})
+
+		list, err := uc.GetPendingList(ctx, teacher, "")
+		if err != nil || len(list) != 1 || list[0].UnreadComments != 1 {
+			t.Fatalf("expected one unread reply in the queue, got %+v (%v)", list, err)
+		}
+		uc.MarkThreadRead(ctx, teacher, "asg-1", "user-1")
+		list, _ = uc.GetPendingList(ctx, teacher, "")
+		if list[0].UnreadComments != 0 {
+			t.Errorf("expected no unread replies after reading, got %d", list[0].UnreadComments)
+		}
+	})
+
+	t.Run("Anchors", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		cases := []struct {
+			name    string
+			version int
+			anchor  domain.CommentAnchor
+			wantErr error
+		}{
+			{"FileOfLatestVersion", 0, domain.CommentAnchor{File: "test.py", LineStart: 4}, nil},
+			{"TextLines", 0, domain.CommentAnchor{LineStart: 2, LineEnd: 3}, nil},
+			{"FileMissingInOldVersion", 1, domain.CommentAnchor{File: "test.py"}, usecase.ErrInvalidAnchor},
+			{"TextLineOutOfRange", 1, domain.CommentAnchor{LineStart: 2}, usecase.ErrInvalidAnchor},
+			{"ReversedRange", 0, domain.CommentAnchor{LineStart: 3, LineEnd: 2}, usecase.ErrInvalidAnchor},
+			{"UnknownVersion", 7, domain.CommentAnchor{LineStart: 1}, usecase.ErrVersionNotFound},
+		}
+		for _, c := range cases {
+			anchor := c.anchor
+			comment, err := uc.AddComment(ctx, teacher, usecase.AddCommentInput{AssignmentID: "asg-1", StudentID: "user-1", Body: "note", Version: c.version, Anchor: &anchor})
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("%s: expected %v, got %v", c.name, c.wantErr, err)
+			}
+			if err == nil && comment.Anchor.LineEnd < comment.Anchor.LineStart {
+				t.Errorf("%s: line_end must default to line_start: %+v", c.name, comment.Anchor)
+			}
+		}
+	})
+
+	t.Run("EvaluateCommentJoinsThread", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Comment: "rename the variable", Status: "on_revision"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		thread, _ := uc.GetThread(ctx, student, "asg-1", "user-1")
+		if len(thread.Comments) != 1 || thread.Comments[0].Body != "rename the variable" || thread.Comments[0].Version != 2 {
+			t.Errorf("expected evaluation comment in thread, got %+v", thread.Comments)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		if _, err := uc.AddComment(ctx, teacher, usecase.AddCommentInput{AssignmentID: "asg-1", StudentID: "user-1", Body: "  "}); !errors.Is(err, usecase.ErrEmptyComment) {
+			t.Errorf("expected ErrEmptyComment, got %v", err)
+		}
+		if _, err := uc.GetThread(ctx, teacher, "asg-2", "user-1"); !errors.Is(err, usecase.ErrSubmissionNotFound) {
+			t.Errorf("expected ErrSubmissionNotFound, got %v", err)
+		}
+		other := domain.Actor{UserID: "user-2", Role: domain.RoleStudent}
+		if _, err := uc.GetThread(ctx, other, "asg-1", "user-1"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Errorf("expected ErrOutOfScope for another student, got %v", err)
+		}
+	})
+}
+
+func TestReviewUseCase_GradingSchemes(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func(scheme domain.GradeScheme) *mocks.ReviewRepositoryMock {
+		repoMock := mocks.NewReviewRepositoryMock()
+		for _, id := range []string{"asg-1", "asg-2"} {
+			repoMock.Courses[id] = &domain.Course{ID: "course-x", GradeScheme: scheme}
+		}
+		return repoMock
+	}
+	evaluate := func(uc *usecase.ReviewUseCase, grade int, letter string) error {
+		return uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Grade: grade, Letter: letter, Status: "accepted"})
+	}
+
+	t.Run("NumericRange", func(t *testing.T) {
+		repoMock := newRepo(domain.GradeScheme{Type: domain.GradeSchemeNumeric, Min: 0, Max: 10})
+		uc := newUseCase(repoMock)
+		if err := evaluate(uc, 11, ""); !errors.Is(err, domain.ErrInvalidGrade) {
+			t.Fatalf("expected ErrInvalidGrade above max, got %v", err)
+		}
+		if err := evaluate(uc, 7, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 7 из 10 за одно из двух заданий курса
+		if got := repoMock.Progress["user-1/course-x"]; got != 35 {
+			t.Errorf("expected progress 35, got %d", got)
+		}
+	})
+
+	t.Run("Letters", func(t *testing.T) {
+		repoMock := newRepo(domain.GradeScheme{Type: domain.GradeSchemeLetter, Letters: []domain.LetterGrade{{Letter: "A", Value: 100}, {Letter: "B", Value: 80}}})
+		uc := newUseCase(repoMock)
+		if err := evaluate(uc, 0, "C"); !errors.Is(err, domain.ErrInvalidGrade) {
+			t.Fatalf("expected ErrInvalidGrade for unknown letter, got %v", err)
+		}
+		if err := evaluate(uc, 0, "b"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 80 {
+			t.Errorf("expected letter value 80 to be stored, got %d", got)
+		}
+	})
+
+	t.Run("PassFail", func(t *testing.T) {
+		repoMock := newRepo(domain.GradeScheme{Type: domain.GradeSchemePassFail})
+		uc := newUseCase(repoMock)
+		if err := evaluate(uc, 40, ""); !errors.Is(err, domain.ErrInvalidGrade) {
+			t.Fatalf("expected ErrInvalidGrade for a grade in pass/fail, got %v", err)
+		}
+		if err := evaluate(uc, 0, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repoMock.Submissions["asg-1/user-1"].Grade != 100 || repoMock.Progress["user-1/course-x"] != 50 {
+			t.Errorf("pass must count as full credit: grade %d, progress %d", repoMock.Submissions["asg-1/user-1"].Grade, repoMock.Progress["user-1/course-x"])
+		}
+	})
+
+	t.Run("RubricAndRevision", func(t *testing.T) {
+		repoMock := newRepo(domain.GradeScheme{Type: domain.GradeSchemeRubric, Max: 12})
+		uc := newUseCase(repoMock)
+		if err := evaluate(uc, 12, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Progress["user-1/course-x"]; got != 50 {
+			t.Errorf("expected progress 50, got %d", got)
+		}
+		err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Grade: 12, Status: "on_revision"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Progress["user-1/course-x"]; got != 0 {
+			t.Errorf("work sent to revision must not count, got %d", got)
+		}
+	})
+}
+
+func TestReviewUseCase_LatePenalty(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func(late bool) *mocks.ReviewRepositoryMock {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.Submissions["asg-1/user-1"] = &domain.SubmissionRecord{ID: "asg-1", UserID: "user-1", Status: "pending", IsLate: late}
+		repoMock.Deadlines["asg-1"] = &domain.AssignmentDeadline{LatePolicy: domain.LatePolicyPenalize, LatePenaltyPercent: 25}
+		return repoMock
+	}
+	accept := func(uc *usecase.ReviewUseCase) error {
+		return uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Grade: 80, Status: "accepted"})
+	}
+
+	t.Run("LateWorkPenalized", func(t *testing.T) {
+		repoMock := newRepo(true)
+		if err := accept(newUseCase(repoMock)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 60 {
+			t.Errorf("expected 80 - 25%% = 60, got %d", got)
+		}
+	})
+
+	t.Run("PenaltySnapsToStep", func(t *testing.T) {
+		repoMock := newRepo(true)
+		repoMock.Deadlines["asg-1"].LatePenaltyPercent = 30
+		if err := accept(newUseCase(repoMock)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 80 - 30% = 56, в сетке 20/40/60/80/100 — 40
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 40 {
+			t.Errorf("expected 56 snapped down to 40, got %d", got)
+		}
+	})
+
+	t.Run("PenaltySnapsToLetter", func(t *testing.T) {
+		repoMock := newRepo(true)
+		repoMock.Courses["asg-1"] = &domain.Course{ID: "course-x", GradeScheme: domain.GradeScheme{
+			Type: domain.GradeSchemeLetter, Letters: []domain.LetterGrade{{Letter: "A", Value: 100}, {Letter: "B", Value: 80}, {Letter: "C", Value: 50}},
+		}}
+		if err := newUseCase(repoMock).Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Letter: "A", Status: "accepted"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 50 {
+			t.Errorf("expected 75 snapped down to letter C (50), got %d", got)
+		}
+	})
+
+	t.Run("OnTimeWorkKeepsGrade", func(t *testing.T) {
+		repoMock := newRepo(false)
+		if err := accept(newUseCase(repoMock)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 80 {
+			t.Errorf("expected grade 80, got %d", got)
+		}
+	})
+
+	t.Run("AcceptPolicyKeepsGrade", func(t *testing.T) {
+		repoMock := newRepo(true)
+		repoMock.Deadlines["asg-1"].LatePolicy, repoMock.Deadlines["asg-1"].LatePenaltyPercent = domain.LatePolicyAccept, 0
+		if err := accept(newUseCase(repoMock)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 80 {
+			t.Errorf("expected grade 80, got %d", got)
+		}
+	})
+}
+
+func TestGradeScheme_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		scheme domain.GradeScheme
+		valid  bool
+	}{
+		{"Default", domain.DefaultGradeScheme(), true},
+		{"NumericWithoutStep", domain.GradeScheme{Type: domain.GradeSchemeNumeric, Min: 1, Max: 5}, true},
+		{"StepMismatch", domain.GradeScheme{Type: domain.GradeSchemeNumeric, Min: 0, Max: 10, Step: 3}, false},
+		{"MinAboveMax", domain.GradeScheme{Type: domain.GradeSchemeNumeric, Min: 10, Max: 5}, false},
+		{"DuplicateLetters", domain.GradeScheme{Type: domain.GradeSchemeLetter, Letters: []domain.LetterGrade{{Letter: "A", Value: 100}, {Letter: "a", Value: 90}}}, false},
+		{"NoLetters", domain.GradeScheme{Type: domain.GradeSchemeLetter}, false},
+		{"PassFail", domain.GradeScheme{Type: domain.GradeSchemePassFail}, true},
+		{"RubricWithoutMax", domain.GradeScheme{Type: domain.GradeSchemeRubric}, false},
+		{"Unknown", domain.GradeScheme{Type: "stars"}, false},
+	}
+	for _, c := range cases {
+		err := c.scheme.Validate()
+		if c.valid && err != nil || !c.valid && !errors.Is(err, domain.ErrInvalidGradeScheme) {
+			t.Errorf("%s: valid=%v, got %v", c.name, c.valid, err)
+		}
+	}
+}
+
+func TestReviewUseCase_Rubrics(t *testing.T) {
+	ctx := context.Background()
+	homework := &domain.Rubric{AssignmentID: "asg-1", Criteria: []domain.RubricCriterion{
+		{ID: "quality", Title: "Code quality", MaxPoints: 10},
+		{ID: "complete", Title: "Completeness", MaxPoints: 20},
+	}}
+	newRepo := func() *mocks.ReviewRepositoryMock {
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.Rubrics["asg-1"] = homework
+		repoMock.Rubrics["p1"] = &domain.Rubric{ProjectID: "p1", Criteria: []domain.RubricCriterion{{ID: "arch", Title: "Architecture", MaxPoints: 40}}}
+		repoMock.Submissions["asg-1/user-1"] = &domain.SubmissionRecord{ID: "asg-1", UserID: "user-1", Kind: domain.SubmissionKindHomework, Status: "pending"}
+		repoMock.ProjectSubmissions["v1"] = &domain.ProjectSubmission{ID: "v1", ProjectID: "p1", UserID: "user-1", Version: 1, Status: "pending_check", MaxScore: 50}
+		return repoMock
+	}
+	scores := func(quality, complete int) []domain.CriterionScore {
+		return []domain.CriterionScore{{CriterionID: "complete", Score: complete}, {CriterionID: "quality", Score: quality, Comment: "naming"}}
+	}
+
+	t.Run("TotalFromCriteria", func(t *testing.T) {
+		repoMock := newRepo()
+		uc := newUseCase(repoMock)
+		// Grade игнорируется: при рубрике оценка — сумма баллов
+		err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Grade: 100, Scores: scores(8, 16), Status: "accepted"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repoMock.Submissions["asg-1/user-1"].Grade; got != 24 {
+			t.Errorf("expected total 24, got %d", got)
+		}
+		breakdown := repoMock.RubricScores["asg-1/user-1"]
+		if len(breakdown) != 2 || breakdown[0].CriterionID != "quality" || breakdown[0].Title != "Code quality" || breakdown[0].MaxPoints != 10 || breakdown[0].Comment != "naming" {
+			t.Errorf("breakdown must follow rubric order with titles: %+v", breakdown)
+		}
+		// 24 из 30 баллов по единственному заданию курса
+		if got := repoMock.Progress["user-1/course-1"]; got != 80 {
+			t.Errorf("expected progress 80, got %d", got)
+		}
+	})
+
+	t.Run("InvalidScores", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		cases := map[string][]domain.CriterionScore{
+			"Missing":    {{CriterionID: "quality", Score: 5}},
+			"OutOfBand":  scores(11, 10),
+			"Unknown":    {{CriterionID: "quality", Score: 5}, {CriterionID: "style", Score: 5}},
+			"Duplicated": {{CriterionID: "quality", Score: 5}, {CriterionID: "quality", Score: 5}},
+		}
+		for name, s := range cases {
+			err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Scores: s, Status: "accepted"})
+			if !errors.Is(err, domain.ErrInvalidRubricScores) {
+				t.Errorf("%s: expected ErrInvalidRubricScores, got %v", name, err)
+			}
+		}
+		err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-2", StudentID: "user-1", Grade: 80, Scores: scores(1, 1), Status: "accepted"})
+		if !errors.Is(err, domain.ErrInvalidRubricScores) {
+			t.Errorf("scores for a work without rubric must be rejected, got %v", err)
+		}
+	})
+
+	t.Run("RevisionScoresOptional", func(t *testing.T) {
+		repoMock := newRepo()
+		uc := newUseCase(repoMock)
+		if err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Status: "on_revision"}); err != nil {
+			t.Fatalf("unexpected error without scores: %v", err)
+		}
+		if err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "asg-1", StudentID: "user-1", Scores: scores(3, 5), Status: "on_revision"}); err != nil {
+			t.Fatalf("unexpected error with scores: %v", err)
+		}
+		if repoMock.Submissions["asg-1/user-1"].Grade != 0 || len(repoMock.RubricScores["asg-1/user-1"]) != 2 {
+			t.Errorf("revision keeps the breakdown but grade 0: %+v", repoMock.Submissions["asg-1/user-1"])
+		}
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		sub, err := uc.EvaluateProject(ctx, teacher, usecase.EvaluateProjectInput{SubmissionID: "v1", Scores: []domain.CriterionScore{{CriterionID: "arch", Score: 35}}, Status: "accepted"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sub.Grade != 35 || len(sub.RubricScores) != 1 || sub.RubricScores[0].Title != "Architecture" {
+			t.Errorf("unexpected project evaluation: %+v", sub)
+		}
+	})
+
+	t.Run("QueueCarriesRubric", func(t *testing.T) {
+		uc := newUseCase(newRepo())
+		list, err := uc.GetPendingList(ctx, teacher, "")
+		if err != nil || len(list) != 2 {
+			t.Fatalf("expected homework and project in queue, got %+v (%v)", list, err)
+		}
+		for _, rec := range list {
+			if rec.Rubric == nil || len(rec.Rubric.Criteria) == 0 {
+				t.Errorf("%s %s: expected rubric in queue", rec.Kind, rec.ID)
+			}
+		}
+	})
+}
+
+func TestReviewUseCase_SimilarityReport(t *testing.T) {
+	ctx := context.Background()
+	repoMock := mocks.NewReviewRepositoryMock()
+	// teacher-1 видит user-1 и user-2, student-b — ученик другой группы.
+	scopeRepo := scopeMocks.TwoGroups()
+	scopeRepo.TeacherStudents["teacher-1"] = []string{"user-1", "user-2"}
+	uc := usecase.NewReviewUseCase(repoMock, scopeUseCase.NewScopeUseCase(scopeRepo), s3Mocks.NewS3StorageMock(), &completerStub{})
+	repoMock.Similarity["asg-1/user-1"] = &domain.SimilarityReport{
+		AssignmentID: "asg-1",
+		UserID:       "user-1",
+		VersionID:    "ver-1",
+		Version:      2,
+		Content:      "Мой ответ. The quick brown fox jumps over the lazy dog, again!",
+		Matches: []*domain.SimilarityMatch{
+			{VersionID: "ver-2", StudentID: "user-2", Score: 0.8, Content: "the QUICK brown fox jumps over the lazy dog"},
+			{VersionID: "ver-3", StudentID: "student-b", StudentName: "Чужой Ученик", Score: 0.6, Content: "quick brown fox jumps over the lazy dog"},
+		},
+	}
+
+	t.Run("PassagesSideBySide", func(t *testing.T) {
+		report, err := uc.GetSimilarityReport(ctx, teacher, "asg-1", "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Matches) != 2 || len(report.Matches[0].Passages) != 1 {
+			t.Fatalf("expected two matches, the first with one passage, got %+v", report.Matches)
+		}
+		p := report.Matches[0].Passages[0]
+		if p.Text != "The quick brown fox jumps over the lazy dog" || p.MatchedText != "the QUICK brown fox jumps over the lazy dog" {
+			t.Errorf("unexpected passage %+v", p)
+		}
+		if got := string([]rune(report.Content)[p.Start:p.End]); got != p.Text {
+			t.Errorf("passage offsets point to %q", got)
+		}
+	})
+
+	t.Run("ForeignStudentAnonymized", func(t *testing.T) {
+		report, err := uc.GetSimilarityReport(ctx, teacher, "asg-1", "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		m := report.Matches[1]
+		if !m.Anonymized || m.Score != 0.6 || m.StudentName != "Ученик 1" {
+			t.Fatalf("expected anonymized match with score, got %+v", m)
+		}
+		if m.StudentID != "" || m.VersionID != "" || m.Content != "" || len(m.Passages) != 0 {
+			t.Errorf("foreign work leaked: %+v", m)
+		}
+	})
+
+	t.Run("StudentRequired", func(t *testing.T) {
+		if _, err := uc.GetSimilarityReport(ctx, teacher, "asg-1", ""); !errors.Is(err, usecase.ErrStudentRequired) {
+			t.Fatalf("expected ErrStudentRequired, got %v", err)
+		}
+	})
+
+	t.Run("OutOfScope", func(t *testing.T) {
+		other := domain.Actor{UserID: "teacher-b", Role: domain.RoleTeacher}
+		if _, err := uc.GetSimilarityReport(ctx, other, "asg-1", "user-1"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("NotSubmitted", func(t *testing.T) {
+		if _, err := uc.GetSimilarityReport(ctx, teacher, "asg-2", "user-1"); !errors.Is(err, usecase.ErrVersionNotFound) {
+			t.Fatalf("expected ErrVersionNotFound, got %v", err)
+		}
+	})
+}
+
+func TestReviewUseCase_CourseCompletion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AcceptedHomeworkChecksCompletion", func(t *testing.T) {
+		completer := &completerStub{}
+		uc := newUseCaseWithCompleter(mocks.NewReviewRepositoryMock(), completer)
+		if err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "sub-1", StudentID: "user-1", Grade: 5, Status: "on_revision"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(completer.calls) != 0 {
+			t.Fatalf("revision must not complete the course, got %v", completer.calls)
+		}
+		if err := uc.Evaluate(ctx, teacher, usecase.EvaluateInput{SubmissionID: "sub-1", StudentID: "user-1", Grade: 80, Status: "accepted"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(completer.calls) != 1 || completer.calls[0] != "user-1/course-1" {
+			t.Fatalf("expected completion check for user-1/course-1, got %v", completer.calls)
+		}
+	})
+
+	t.Run("AcceptedProjectChecksCompletion", func(t *testing.T) {
+		completer := &completerStub{}
+		repoMock := mocks.NewReviewRepositoryMock()
+		repoMock.ProjectSubmissions["v1"] = &domain.ProjectSubmission{ID: "v1", ProjectID: "p1", UserID: "user-1", Version: 1, Status: "pending_check", MaxScore: 50}
+		repoMock.ItemCourses["p1"] = "course-7"
+		uc := newUseCaseWithCompleter(repoMock, completer)
+		if _, err := uc.EvaluateProject(ctx, teacher, usecase.EvaluateProjectInput{SubmissionID: "v1", Grade: 40, Status: "accepted"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(completer.calls) != 1 || completer.calls[0] != "user-1/course-7" {
+			t.Fatalf("expected completion check for user-1/course-7, got %v", completer.calls)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package mocks
+
+import (
+	"context"
+
+	"lms_backend/internal/scope/repository"
+)
+
+// ScopeRepositoryMock хранит связи явно: кто чей преподаватель, куратор или родитель,
+// какие уроки ведёт преподаватель и на какие уроки записан ученик.
+type ScopeRepositoryMock struct {
+	TeacherStudents map[string][]string
+	CuratorStudents map[string][]string
+	ParentChildren  map[string][]string
+	TeacherLessons  map[string][]string
+	CuratorLessons  map[string][]string
+	StudentLessons  map[string][]string
+	CuratorGroups   map[string][]string
+}
+
+var _ repository.ScopeRepository = (*ScopeRepositoryMock)(nil)
+
+func NewScopeRepositoryMock() *ScopeRepositoryMock {
+	return &ScopeRepositoryMock{
+		TeacherStudents: make(map[string][]string),
+		CuratorStudents: make(map[string][]string),
+		ParentChildren:  make(map[string][]string),
+		TeacherLessons:  make(map[string][]string),
+		CuratorLessons:  make(map[string][]string),
+		StudentLessons:  make(map[string][]string),
+		CuratorGroups:   make(map[string][]string),
+	}
+}
+
+func has(list []string, id string) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ScopeRepositoryMock) IsTeacherOfStudent(ctx context.Context, teacherID, studentID string) (bool, error) {
+	return has(m.TeacherStudents[teacherID], studentID), nil
+}
+
+func (m *ScopeRepositoryMock) IsCuratorOfStudent(ctx context.Context, curatorID, studentID string) (bool, error) {
+	return has(m.CuratorStudents[curatorID], studentID), nil
+}
+
+func (m *ScopeRepositoryMock) IsParentOf(ctx context.Context, parentID, childID string) (bool, error) {
+	return has(m.ParentChildren[parentID], childID), nil
+}
+
+func (m *ScopeRepositoryMock) IsTeacherOfLesson(ctx context.Context, teacherID, lessonID string) (bool, error) {
+	return has(m.TeacherLessons[teacherID], lessonID), nil
+}
+
+func (m *ScopeRepositoryMock) IsCuratorOfLesson(ctx context.Context, curatorID, lessonID string) (bool, error) {
+	return has(m.CuratorLessons[curatorID], lessonID), nil
+}
+
+func (m *ScopeRepositoryMock) IsEnrolledInLesson(ctx context.Context, studentID, lessonID string) (bool, error) {
+	return has(m.StudentLessons[studentID], lessonID), nil
+}
+
+func (m *ScopeRepositoryMock) IsCuratorOfGroup(ctx context.Context, curatorID, groupID string) (bool, error) {
+	return has(m.CuratorGroups[curatorID], groupID), nil
+}
+
+func (m *ScopeRepositoryMock) StudentsOfTeacher(ctx context.Context, teacherID string) ([]string, error) {
+	return m.TeacherStudents[teacherID], nil
+}
+
+func (m *ScopeRepositoryMock) StudentsOfCurator(ctx context.Context, curatorID string) ([]string, error) {
+	return m.CuratorStudents[curatorID], nil
+}
+
+func (m *ScopeRepositoryMock) ChildrenOfParent(ctx context.Context, parentID string) ([]string, error) {
+	return m.ParentChildren[parentID], nil
+}
+
+// TwoGroups — типовая раскладка для тестов на межгрупповой доступ:
+// группа A (teacher-a, curator-a, student-a, урок lesson-a) и группа B (teacher-b, curator-b, student-b, lesson-b).
+// parent-a — родитель student-a.
+func TwoGroups() *ScopeRepositoryMock {
+	m := NewScopeRepositoryMock()
+	m.TeacherStudents["teacher-a"] = []string{"student-a"}
+	m.TeacherStudents["teacher-b"] = []string{"student-b"}
+	m.CuratorStudents["curator-a"] = []string{"student-a"}
+	m.CuratorStudents["curator-b"] = []string{"student-b"}
+	m.ParentChildren["parent-a"] = []string{"student-a"}
+	m.TeacherLessons["teacher-a"] = []string{"lesson-a"}
+	m.TeacherLessons["teacher-b"] = []string{"lesson-b"}
+	m.CuratorLessons["curator-a"] = []string{"lesson-a"}
+	m.CuratorLessons["curator-b"] = []string{"lesson-b"}
+	m.StudentLessons["student-a"] = []string{"lesson-a"}
+	m.StudentLessons["student-b"] = []string{"lesson-b"}
+	m.CuratorGroups["curator-a"] = []string{"group-a"}
+	m.CuratorGroups["curator-b"] = []string{"group-b"}
+	return m
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ScopeRepository отвечает на вопросы «связан ли пользователь с учеником/уроком/группой».
+// Связи: groups.teacher_id и groups.curator_id (ученик в группе через user_courses.group_id),
+// course_teachers, преподаватель или замена на уроке курса, child_parent_link.
+type ScopeRepository interface {
+	IsTeacherOfStudent(ctx context.Context, teacherID, studentID string) (bool, error)
+	IsCuratorOfStudent(ctx context.Context, curatorID, studentID string) (bool, error)
+	IsParentOf(ctx context.Context, parentID, childID string) (bool, error)
+	IsTeacherOfLesson(ctx context.Context, teacherID, lessonID string) (bool, error)
+	IsCuratorOfLesson(ctx context.Context, curatorID, lessonID string) (bool, error)
+	IsEnrolledInLesson(ctx context.Context, studentID, lessonID string) (bool, error)
+	IsCuratorOfGroup(ctx context.Context, curatorID, groupID string) (bool, error)
+	StudentsOfTeacher(ctx context.Context, teacherID string) ([]string, error)
+	StudentsOfCurator(ctx context.Context, curatorID string) ([]string, error)
+	ChildrenOfParent(ctx context.Context, parentID string) ([]string, error)
+}
+
+type scopeRepository struct {
+	db *sql.DB
+}
+
+func NewScopeRepository(db *sql.DB) ScopeRepository {
+	return &scopeRepository{db: db}
+}
+
+// teacherStudentsQuery — ученики преподавателя: его группы, курсы из course_teachers
+// и курсы, где он ведёт или заменяет уроки.
+const teacherStudentsQuery = `
+	SELECT uc.user_id FROM user_courses uc
+	JOIN groups g ON g.id = uc.group_id
+	WHERE g.teacher_id = $1
+	UNION
+	SELECT uc.user_id FROM user_courses uc
+	JOIN course_teachers ct ON ct.course_id = uc.course_id
+	WHERE ct.teacher_id = $1
+	UNION
+	SELECT uc.user_id FROM user_courses uc
+	JOIN lessons l ON l.course_id = uc.course_id
+	WHERE l.teacher_id = $1 OR l.substituted_teacher_id = $1
+`
+
+const curatorStudentsQuery = `
+	SELECT uc.user_id FROM user_courses uc
+	JOIN groups g ON g.id = uc.group_id
+	WHERE g.curator_id = $1
+`
+
+func (r *scopeRepository) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var ok bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS ("+query+")", args...).Scan(&ok)
+	return ok, err
+}
+
+func (r *scopeRepository) ids(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	return result, rows.Err()
+}
+
+func (r *scopeRepository) IsTeacherOfStudent(ctx context.Context, teacherID, studentID string) (bool, error) {
+	return r.exists(ctx, `SELECT 1 FROM (`+teacherStudentsQuery+`) s WHERE s.user_id = $2`, teacherID, studentID)
+}
+
+func (r *scopeRepository) IsCuratorOfStudent(ctx context.Context, curatorID, studentID string) (bool, error) {
+	return r.exists(ctx, curatorStudentsQuery+` AND uc.user_id = $2`, curatorID, studentID)
+}
+
+func (r *scopeRepository) IsParentOf(ctx context.Context, parentID, childID string) (bool, error) {
+	return r.exists(ctx, `
+		SELECT 1 FROM child_parent_link
+		WHERE parent_id = $1 AND child_id = $2 AND is_active = TRUE
+	`, parentID, childID)
+}
+
+func (r *scopeRepository) IsTeacherOfLesson(ctx context.Context, teacherID, lessonID string) (bool, error) {
+	return r.exists(ctx, `
+		SELECT 1 FROM lessons l
+		WHERE l.id = $2 AND (
+			l.teacher_id = $1 OR l.substituted_teacher_id = $1
+			OR EXISTS (SELECT 1 FROM course_teachers ct WHERE ct.course_id = l.course_id AND ct.teacher_id = $1)
+			OR EXISTS (
+				SELECT 1 FROM groups g JOIN streams s ON s.id = g.stream_id
+				WHERE s.course_id = l.course_id AND g.teacher_id = $1
+			)
+		)
+	`, teacherID, lessonID)
+}
+
+func (r *scopeRepository) IsCuratorOfLesson(ctx context.Context, curatorID, lessonID string) (bool, error) {
+	return r.exists(ctx, `
+		SELECT 1 FROM lessons l
+		JOIN streams s ON s.course_id = l.course_id
+		JOIN groups g ON g.stream_id = s.id
+		WHERE l.id = $2 AND g.curator_id = $1
+	`, curatorID, lessonID)
+}
+
+func (r *scopeRepository) IsEnrolledInLesson(ctx context.Context, studentID, lessonID string) (bool, error) {
+	return r.exists(ctx, `
+		SELECT 1 FROM lessons l
+		JOIN user_courses uc ON uc.course_id = l.course_id
+		WHERE l.id = $2 AND uc.user_id = $1
+	`, studentID, lessonID)
+}
+
+func (r *scopeRepository) IsCuratorOfGroup(ctx context.Context, curatorID, groupID string) (bool, error) {
+	return r.exists(ctx, `SELECT 1 FROM groups WHERE id = $2 AND curator_id = $1`, curatorID, groupID)
+}
+
+func (r *scopeRepository) StudentsOfTeacher(ctx context.Context, teacherID string) ([]string, error) {
+	return r.ids(ctx, teacherStudentsQuery, teacherID)
+}
+
+func (r *scopeRepository) StudentsOfCurator(ctx context.Context, curatorID string) ([]string, error) {
+	return r.ids(ctx, curatorStudentsQuery, curatorID)
+}
+
+func (r *scopeRepository) ChildrenOfParent(ctx context.Context, parentID string) ([]string, error) {
+	return r.ids(ctx, `SELECT child_id FROM child_parent_link WHERE parent_id = $1 AND is_active = TRUE`, parentID)
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/scope/repository"
+)
+
+// ScopeUseCase решает, может ли пользователь работать с конкретным учеником, уроком или группой.
+// Права из role_permissions отвечают на вопрос «что можно делать», scope — «с кем».
+// Все методы проверки возвращают domain.ErrOutOfScope при отказе.
+type ScopeUseCase interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
+	CanAccessLesson(ctx context.Context, actor domain.Actor, lessonID string) error
+	CanAccessLessonStudent(ctx context.Context, actor domain.Actor, lessonID, studentID string) error
+	CanManageGroup(ctx context.Context, actor domain.Actor, groupID string) error
+	VisibleStudents(ctx context.Context, actor domain.Actor) (*domain.StudentScope, error)
+}
+
+type scopeUseCase struct {
+	repo repository.ScopeRepository
+}
+
+func NewScopeUseCase(repo repository.ScopeRepository) ScopeUseCase {
+	return &scopeUseCase{repo: repo}
+}
+
+func allow(ok bool, err error) error {
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrOutOfScope
+	}
+	return nil
+}
+
+func (uc *scopeUseCase) CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error {
+	if actor.HasGlobalScope() {
+		return nil
+	}
+	switch actor.Role {
+	case domain.RoleStudent:
+		return allow(actor.UserID == studentID, nil)
+	case domain.RoleParent:
+		return allow(uc.repo.IsParentOf(ctx, actor.UserID, studentID))
+	case domain.RoleTeacher:
+		return allow(uc.repo.IsTeacherOfStudent(ctx, actor.UserID, studentID))
+	case domain.RoleCurator:
+		return allow(uc.repo.IsCuratorOfStudent(ctx, actor.UserID, studentID))
+	}
+	return domain.ErrOutOfScope
+}
+
+func (uc *scopeUseCase) CanAccessLesson(ctx context.Context, actor domain.Actor, lessonID string) error {
+	if actor.HasGlobalScope() {
+		return nil
+	}
+	switch actor.Role {
+	case domain.RoleTeacher:
+		return allow(uc.repo.IsTeacherOfLesson(ctx, actor.UserID, lessonID))
+	case domain.RoleCurator:
+		return allow(uc.repo.IsCuratorOfLesson(ctx, actor.UserID, lessonID))
+	case domain.RoleStudent:
+		return allow(uc.repo.IsEnrolledInLesson(ctx, actor.UserID, lessonID))
+	}
+	return domain.ErrOutOfScope
+}
+
+// CanAccessLessonStudent — доступ к уроку и к ученику, причём ученик должен быть записан на курс урока.
+// Так преподаватель не отметит на своём уроке ученика из чужого курса.
+func (uc *scopeUseCase) CanAccessLessonStudent(ctx context.Context, actor domain.Actor, lessonID, studentID string) error {
+	if err := uc.CanAccessLesson(ctx, actor, lessonID); err != nil {
+		return err
+	}
+	if err := allow(uc.repo.IsEnrolledInLesson(ctx, studentID, lessonID)); err != nil {
+		return err
+	}
+	return uc.CanAccessStudent(ctx, actor, studentID)
+}
+
+// CanManageGroup — куратор меняет только свои группы, преподаватели группами не управляют.
+func (uc *scopeUseCase) CanManageGroup(ctx context.Context, actor domain.Actor, groupID string) error {
+	if actor.HasGlobalScope() {
+		return nil
+	}
+	if actor.Role == domain.RoleCurator {
+		return allow(uc.repo.IsCuratorOfGroup(ctx, actor.UserID, groupID))
+	}
+	return domain.ErrOutOfScope
+}
+
+// VisibleStudents — для фильтрации списков (очередь проверки, заявки на заморозку).
+func (uc *scopeUseCase) VisibleStudents(ctx context.Context, actor domain.Actor) (*domain.StudentScope, error) {
+	if actor.HasGlobalScope() {
+		return &domain.StudentScope{All: true}, nil
+	}
+
+	var ids []string
+	var err error
+	switch actor.Role {
+	case domain.RoleStudent:
+		ids = []string{actor.UserID}
+	case domain.RoleParent:
+		ids, err = uc.repo.ChildrenOfParent(ctx, actor.UserID)
+	case domain.RoleTeacher:
+		ids, err = uc.repo.StudentsOfTeacher(ctx, actor.UserID)
+	case domain.RoleCurator:
+		ids, err = uc.repo.StudentsOfCurator(ctx, actor.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scope := &domain.StudentScope{IDs: make(map[string]bool, len(ids))}
+	for _, id := range ids {
+		scope.IDs[id] = true
+	}
+	return scope, nil
+}
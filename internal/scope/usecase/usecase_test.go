@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lms_backend/internal/domain"
+	"lms_backend/internal/scope/mocks"
+	"lms_backend/internal/scope/usecase"
+)
+
+func actor(id string, role domain.Role) domain.Actor {
+	return domain.Actor{UserID: id, Role: role}
+}
+
+func TestCanAccessStudent(t *testing.T) {
+	uc := usecase.NewScopeUseCase(mocks.TwoGroups())
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		actor   domain.Actor
+		student string
+		allowed bool
+	}{
+		{"Admin Any Student", actor("admin-1", domain.RoleAdmin), "student-b", true},
+		{"Moderator Any Student", actor("mod-1", domain.RoleModerator), "student-b", true},
+		{"Teacher Own Group", actor("teacher-a", domain.RoleTeacher), "student-a", true},
+		{"Teacher Other Group", actor("teacher-a", domain.RoleTeacher), "student-b", false},
+		{"Curator Own Group", actor("curator-b", domain.RoleCurator), "student-b", true},
+		{"Curator Other Group", actor("curator-b", domain.RoleCurator), "student-a", false},
+		{"Parent Own Child", actor("parent-a", domain.RoleParent), "student-a", true},
+		{"Parent Other Child", actor("parent-a", domain.RoleParent), "student-b", false},
+		{"Student Self", actor("student-a", domain.RoleStudent), "student-a", true},
+		{"Student Other", actor("student-a", domain.RoleStudent), "student-b", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := uc.CanAccessStudent(ctx, c.actor, c.student)
+			if c.allowed && err != nil {
+				t.Fatalf("expected access, got %v", err)
+			}
+			if !c.allowed && !errors.Is(err, domain.ErrOutOfScope) {
+				t.Fatalf("expected ErrOutOfScope, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCanAccessLessonStudent(t *testing.T) {
+	repo := mocks.TwoGroups()
+	uc := usecase.NewScopeUseCase(repo)
+	ctx := context.Background()
+
+	t.Run("Own Lesson Own Student", func(t *testing.T) {
+		if err := uc.CanAccessLessonStudent(ctx, actor("teacher-a", domain.RoleTeacher), "lesson-a", "student-a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Other Group Lesson", func(t *testing.T) {
+		err := uc.CanAccessLessonStudent(ctx, actor("teacher-a", domain.RoleTeacher), "lesson-b", "student-b")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("Student Not Enrolled In Lesson", func(t *testing.T) {
+		// Даже админ не может отметить на уроке ученика, который не записан на курс.
+		err := uc.CanAccessLessonStudent(ctx, actor("admin-1", domain.RoleAdmin), "lesson-a", "student-b")
+		if !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+}
+
+func TestCanManageGroup(t *testing.T) {
+	uc := usecase.NewScopeUseCase(mocks.TwoGroups())
+	ctx := context.Background()
+
+	if err := uc.CanManageGroup(ctx, actor("curator-a", domain.RoleCurator), "group-a"); err != nil {
+		t.Errorf("curator should manage own group: %v", err)
+	}
+	if err := uc.CanManageGroup(ctx, actor("curator-a", domain.RoleCurator), "group-b"); !errors.Is(err, domain.ErrOutOfScope) {
+		t.Errorf("expected ErrOutOfScope for foreign group, got %v", err)
+	}
+	if err := uc.CanManageGroup(ctx, actor("teacher-a", domain.RoleTeacher), "group-a"); !errors.Is(err, domain.ErrOutOfScope) {
+		t.Errorf("expected ErrOutOfScope for teacher, got %v", err)
+	}
+}
+
+func TestVisibleStudents(t *testing.T) {
+	uc := usecase.NewScopeUseCase(mocks.TwoGroups())
+	ctx := context.Background()
+
+	scope, err := uc.VisibleStudents(ctx, actor("teacher-b", domain.RoleTeacher))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !scope.Contains("student-b") || scope.Contains("student-a") {
+		t.Errorf("unexpected teacher scope: %+v", scope)
+	}
+
+	scope, _ = uc.VisibleStudents(ctx, actor("admin-1", domain.RoleAdmin))
+	if !scope.Contains("student-a") || !scope.Contains("student-b") {
+		t.Error("admin must see everyone")
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	authMiddleware "lms_backend/internal/auth/delivery/middleware"
 	"lms_backend/internal/httperror"
 	"lms_backend/internal/statistics/usecase"
 	"net/http"
@@ -24,11 +25,16 @@ func NewStatisticsHandler(uc usecase.StatisticsUseCase) *StatisticsHandler {
 // @Success 200 {object} domain.StudentStatistics
 // @Router /api/statistics/students/{studentId} [get]
 func (h *StatisticsHandler) GetStudentStatistics(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := chi.URLParam(r, "studentId")
 
-	stats, err := h.uc.GetStudentStatistics(r.Context(), studentID)
+	stats, err := h.uc.GetStudentStatistics(r.Context(), actor, studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
@@ -43,11 +49,16 @@ func (h *StatisticsHandler) GetStudentStatistics(w http.ResponseWriter, r *http.
 // @Success 200 {object} domain.StudentStatistics
 // @Router /api/statistics/students/{studentId}/refresh [post]
 func (h *StatisticsHandler) RefreshStudentStatistics(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authMiddleware.ActorFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	studentID := chi.URLParam(r, "studentId")
 
-	stats, err := h.uc.RefreshStudentStatistics(r.Context(), studentID)
+	stats, err := h.uc.RefreshStudentStatistics(r.Context(), actor, studentID)
 	if err != nil {
-		httperror.Internal(w, err)
+		httperror.Respond(w, err)
 		return
 	}
 
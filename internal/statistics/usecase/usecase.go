@@ -7,22 +7,34 @@ import (
 )
 
 type StatisticsUseCase interface {
-	GetStudentStatistics(ctx context.Context, studentID string) (*domain.StudentStatistics, error)
-	RefreshStudentStatistics(ctx context.Context, studentID string) (*domain.StudentStatistics, error)
+	GetStudentStatistics(ctx context.Context, actor domain.Actor, studentID string) (*domain.StudentStatistics, error)
+	RefreshStudentStatistics(ctx context.Context, actor domain.Actor, studentID string) (*domain.StudentStatistics, error)
+}
+
+// ScopeChecker — проверка доступа к ученику (реализует scope.ScopeUseCase).
+type ScopeChecker interface {
+	CanAccessStudent(ctx context.Context, actor domain.Actor, studentID string) error
 }
 
 type statisticsUseCase struct {
-	repo repository.StatisticsRepository
+	repo  repository.StatisticsRepository
+	scope ScopeChecker
 }
 
-func NewStatisticsUseCase(repo repository.StatisticsRepository) StatisticsUseCase {
-	return &statisticsUseCase{repo: repo}
+func NewStatisticsUseCase(repo repository.StatisticsRepository, scope ScopeChecker) StatisticsUseCase {
+	return &statisticsUseCase{repo: repo, scope: scope}
 }
 
-func (uc *statisticsUseCase) GetStudentStatistics(ctx context.Context, studentID string) (*domain.StudentStatistics, error) {
+func (uc *statisticsUseCase) GetStudentStatistics(ctx context.Context, actor domain.Actor, studentID string) (*domain.StudentStatistics, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.GetByStudent(ctx, studentID)
 }
 
-func (uc *statisticsUseCase) RefreshStudentStatistics(ctx context.Context, studentID string) (*domain.StudentStatistics, error) {
+func (uc *statisticsUseCase) RefreshStudentStatistics(ctx context.Context, actor domain.Actor, studentID string) (*domain.StudentStatistics, error) {
+	if err := uc.scope.CanAccessStudent(ctx, actor, studentID); err != nil {
+		return nil, err
+	}
 	return uc.repo.RecalculateStatistics(ctx, studentID)
 }
@@ -6,13 +6,21 @@ import (
 	"testing"
 
 	"lms_backend/internal/domain"
+	scopeMocks "lms_backend/internal/scope/mocks"
+	scopeUseCase "lms_backend/internal/scope/usecase"
 	"lms_backend/internal/statistics/mocks"
 	"lms_backend/internal/statistics/usecase"
 )
 
+var admin = domain.Actor{UserID: "admin-1", Role: domain.RoleAdmin}
+
+func newUseCase(repo *mocks.StatisticsRepoMock) usecase.StatisticsUseCase {
+	return usecase.NewStatisticsUseCase(repo, scopeUseCase.NewScopeUseCase(scopeMocks.TwoGroups()))
+}
+
 func TestGetStudentStatistics(t *testing.T) {
 	repo := mocks.NewStatisticsRepoMock()
-	uc := usecase.NewStatisticsUseCase(repo)
+	uc := newUseCase(repo)
 
 	repo.GetByStudentFunc = func(ctx context.Context, studentID string) (*domain.StudentStatistics, error) {
 		if studentID == "" {
@@ -26,7 +34,7 @@ func TestGetStudentStatistics(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		stats, err := uc.GetStudentStatistics(context.Background(), "s1")
+		stats, err := uc.GetStudentStatistics(context.Background(), admin, "s1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -36,7 +44,7 @@ func TestGetStudentStatistics(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		_, err := uc.GetStudentStatistics(context.Background(), "")
+		_, err := uc.GetStudentStatistics(context.Background(), admin, "")
 		if err == nil {
 			t.Error("expected error")
 		}
@@ -45,7 +53,7 @@ func TestGetStudentStatistics(t *testing.T) {
 
 func TestRefreshStudentStatistics(t *testing.T) {
 	repo := mocks.NewStatisticsRepoMock()
-	uc := usecase.NewStatisticsUseCase(repo)
+	uc := newUseCase(repo)
 
 	repo.RecalculateStatisticsFunc = func(ctx context.Context, studentID string) (*domain.StudentStatistics, error) {
 		if studentID == "fail" {
@@ -59,7 +67,7 @@ func TestRefreshStudentStatistics(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		stats, err := uc.RefreshStudentStatistics(context.Background(), "s1")
+		stats, err := uc.RefreshStudentStatistics(context.Background(), admin, "s1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -69,9 +77,44 @@ func TestRefreshStudentStatistics(t *testing.T) {
 	})
 
 	t.Run("recalc error", func(t *testing.T) {
-		_, err := uc.RefreshStudentStatistics(context.Background(), "fail")
+		_, err := uc.RefreshStudentStatistics(context.Background(), admin, "fail")
 		if err == nil {
 			t.Error("expected error")
 		}
 	})
 }
+
+func TestStudentStatisticsScope(t *testing.T) {
+	repo := mocks.NewStatisticsRepoMock()
+	uc := newUseCase(repo)
+	called := false
+	repo.GetByStudentFunc = func(ctx context.Context, studentID string) (*domain.StudentStatistics, error) {
+		called = true
+		return &domain.StudentStatistics{StudentID: studentID}, nil
+	}
+	ctx := context.Background()
+
+	t.Run("teacher of other group", func(t *testing.T) {
+		teacher := domain.Actor{UserID: "teacher-a", Role: domain.RoleTeacher}
+		if _, err := uc.GetStudentStatistics(ctx, teacher, "student-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+		if called {
+			t.Error("repository must not be queried for out-of-scope student")
+		}
+	})
+
+	t.Run("student reads another student", func(t *testing.T) {
+		student := domain.Actor{UserID: "student-a", Role: domain.RoleStudent}
+		if _, err := uc.RefreshStudentStatistics(ctx, student, "student-b"); !errors.Is(err, domain.ErrOutOfScope) {
+			t.Fatalf("expected ErrOutOfScope, got %v", err)
+		}
+	})
+
+	t.Run("parent reads own child", func(t *testing.T) {
+		parent := domain.Actor{UserID: "parent-a", Role: domain.RoleParent}
+		if _, err := uc.GetStudentStatistics(ctx, parent, "student-a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
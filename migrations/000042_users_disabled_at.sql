@@ -0,0 +1,6 @@
+-- +goose Up
+-- Отключённый пользователь не может войти; выставляется через cmd/tools/admin disable-user.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,105 @@
+-- +goose Up
+-- Справочник прав. Коды совпадают с константами domain.Perm*.
+CREATE TABLE IF NOT EXISTS permissions (
+    code VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- Какие права есть у роли. Редактируется через /api/admin/permissions.
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL,
+    permission_code VARCHAR(64) NOT NULL REFERENCES permissions(code) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    granted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (role, permission_code)
+);
+
+INSERT INTO permissions (code, description) VALUES
+    ('dashboard.view', 'Админские дашборды и дашборд куратора'),
+    ('courses.view', 'Просмотр курсов, уроков, тестов и проектов в админке'),
+    ('courses.edit', 'Создание и изменение курсов, модулей, уроков, тестов, проектов, загрузка медиа'),
+    ('schedule.manage', 'Отмена уроков и замена преподавателя'),
+    ('users.view', 'Просмотр пользователей и их карточек'),
+    ('users.create', 'Создание пользователей'),
+    ('users.edit', 'Изменение пользователей'),
+    ('users.delete', 'Удаление пользователей'),
+    ('users.sessions', 'Принудительное завершение сессий пользователя'),
+    ('finance.balance', 'Изменение баланса пользователя'),
+    ('enrollment.manage', 'Запись на курс и отчисление'),
+    ('groups.view', 'Просмотр потоков и групп'),
+    ('groups.manage', 'Создание и изменение потоков и групп, перевод студентов и преподавателей'),
+    ('attendance.view', 'Просмотр посещаемости'),
+    ('attendance.mark', 'Отметка посещаемости'),
+    ('freeze.request', 'Создание заявок на заморозку'),
+    ('freeze.approve', 'Одобрение и отклонение заморозок'),
+    ('access.approve', 'Одобрение и отклонение заявок на доступ'),
+    ('comments.manage', 'Комментарии о студентах'),
+    ('notifications.send', 'Отправка уведомлений'),
+    ('statistics.view', 'Статистика студентов'),
+    ('reports.export', 'Выгрузка отчётов'),
+    ('banners.edit', 'Управление баннерами'),
+    ('submissions.review', 'Проверка домашних заданий'),
+    ('security.manage', 'Политика обязательной 2FA'),
+    ('permissions.manage', 'Просмотр и изменение прав ролей')
+ON CONFLICT (code) DO NOTHING;
+
+-- Администратор получает все права.
+INSERT INTO role_permissions (role, permission_code)
+SELECT 'admin', code FROM permissions
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission_code) VALUES
+    ('moderator', 'dashboard.view'),
+    ('moderator', 'courses.view'),
+    ('moderator', 'courses.edit'),
+    ('moderator', 'schedule.manage'),
+    ('moderator', 'users.view'),
+    ('moderator', 'users.create'),
+    ('moderator', 'users.edit'),
+    ('moderator', 'enrollment.manage'),
+    ('moderator', 'groups.view'),
+    ('moderator', 'groups.manage'),
+    ('moderator', 'attendance.view'),
+    ('moderator', 'attendance.mark'),
+    ('moderator', 'freeze.request'),
+    ('moderator', 'freeze.approve'),
+    ('moderator', 'access.approve'),
+    ('moderator', 'comments.manage'),
+    ('moderator', 'notifications.send'),
+    ('moderator', 'statistics.view'),
+    ('moderator', 'reports.export'),
+    ('moderator', 'banners.edit'),
+    ('moderator', 'submissions.review'),
+
+    ('curator', 'dashboard.view'),
+    ('curator', 'courses.view'),
+    ('curator', 'schedule.manage'),
+    ('curator', 'users.view'),
+    ('curator', 'users.edit'),
+    ('curator', 'finance.balance'),
+    ('curator', 'enrollment.manage'),
+    ('curator', 'groups.view'),
+    ('curator', 'groups.manage'),
+    ('curator', 'attendance.view'),
+    ('curator', 'attendance.mark'),
+    ('curator', 'freeze.request'),
+    ('curator', 'freeze.approve'),
+    ('curator', 'access.approve'),
+    ('curator', 'comments.manage'),
+    ('curator', 'notifications.send'),
+    ('curator', 'statistics.view'),
+    ('curator', 'reports.export'),
+
+    ('teacher', 'courses.view'),
+    ('teacher', 'users.view'),
+    ('teacher', 'groups.view'),
+    ('teacher', 'attendance.view'),
+    ('teacher', 'attendance.mark'),
+    ('teacher', 'comments.manage'),
+    ('teacher', 'statistics.view'),
+    ('teacher', 'submissions.review')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
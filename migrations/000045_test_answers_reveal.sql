@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE tests
+ADD COLUMN IF NOT EXISTS answers_reveal VARCHAR(20) NOT NULL DEFAULT 'never'
+    CHECK (answers_reveal IN ('never', 'after_submit', 'after_pass'));
+
+-- +goose Down
+ALTER TABLE tests DROP COLUMN IF EXISTS answers_reveal;
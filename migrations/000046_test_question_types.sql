@@ -0,0 +1,43 @@
+-- +goose Up
+ALTER TABLE test_questions
+ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'single'
+    CHECK (type IN ('single', 'multiple', 'numeric', 'ordering', 'matching', 'text')),
+ADD COLUMN IF NOT EXISTS match_options JSONB NOT NULL DEFAULT '[]'::jsonb,
+ADD COLUMN IF NOT EXISTS correct_answers JSONB NOT NULL DEFAULT '[]'::jsonb,
+ADD COLUMN IF NOT EXISTS tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS partial_credit BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE test_questions ALTER COLUMN correct_answer TYPE TEXT;
+
+ALTER TABLE test_attempts ALTER COLUMN score TYPE NUMERIC(10, 2);
+
+ALTER TABLE test_attempts
+ADD COLUMN IF NOT EXISTS results JSONB NOT NULL DEFAULT '[]'::jsonb,
+ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'graded'
+    CHECK (status IN ('graded', 'pending_review')),
+ADD COLUMN IF NOT EXISTS graded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS graded_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_test_attempts_pending ON test_attempts(submitted_at) WHERE status = 'pending_review';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_test_attempts_pending;
+
+ALTER TABLE test_attempts
+DROP COLUMN IF EXISTS graded_at,
+DROP COLUMN IF EXISTS graded_by,
+DROP COLUMN IF EXISTS status,
+DROP COLUMN IF EXISTS results;
+
+ALTER TABLE test_attempts ALTER COLUMN score TYPE INTEGER;
+
+ALTER TABLE test_questions ALTER COLUMN correct_answer TYPE VARCHAR(255);
+
+ALTER TABLE test_questions
+DROP COLUMN IF EXISTS position,
+DROP COLUMN IF EXISTS partial_credit,
+DROP COLUMN IF EXISTS tolerance,
+DROP COLUMN IF EXISTS correct_answers,
+DROP COLUMN IF EXISTS match_options,
+DROP COLUMN IF EXISTS type;
@@ -0,0 +1,58 @@
+-- +goose Up
+ALTER TABLE tests
+ADD COLUMN IF NOT EXISTS time_limit_min INTEGER NOT NULL DEFAULT 0 CHECK (time_limit_min >= 0),
+ADD COLUMN IF NOT EXISTS max_attempts INTEGER NOT NULL DEFAULT 0 CHECK (max_attempts >= 0),
+ADD COLUMN IF NOT EXISTS scoring VARCHAR(10) NOT NULL DEFAULT 'best' CHECK (scoring IN ('best', 'last')),
+ADD COLUMN IF NOT EXISTS shuffle_questions BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS shuffle_options BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS pool_size INTEGER NOT NULL DEFAULT 0 CHECK (pool_size >= 0);
+
+ALTER TABLE test_attempts DROP CONSTRAINT IF EXISTS test_attempts_status_check;
+ALTER TABLE test_attempts
+ADD CONSTRAINT test_attempts_status_check
+    CHECK (status IN ('in_progress', 'graded', 'pending_review', 'expired'));
+
+ALTER TABLE test_attempts
+ADD COLUMN IF NOT EXISTS question_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+ADD COLUMN IF NOT EXISTS seed BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS deadline_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE test_attempts SET started_at = submitted_at WHERE started_at IS NULL;
+ALTER TABLE test_attempts ALTER COLUMN started_at SET NOT NULL;
+ALTER TABLE test_attempts ALTER COLUMN started_at SET DEFAULT NOW();
+
+-- у начатой попытки submitted_at ещё нет
+ALTER TABLE test_attempts ALTER COLUMN submitted_at DROP NOT NULL;
+ALTER TABLE test_attempts ALTER COLUMN submitted_at DROP DEFAULT;
+
+-- не больше одной незавершённой попытки на ученика и тест
+CREATE UNIQUE INDEX IF NOT EXISTS idx_test_attempts_in_progress
+    ON test_attempts(user_id, test_id) WHERE status = 'in_progress';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_test_attempts_in_progress;
+
+DELETE FROM test_attempts WHERE status = 'in_progress';
+UPDATE test_attempts SET status = 'graded' WHERE status = 'expired';
+UPDATE test_attempts SET submitted_at = started_at WHERE submitted_at IS NULL;
+ALTER TABLE test_attempts ALTER COLUMN submitted_at SET DEFAULT NOW();
+ALTER TABLE test_attempts ALTER COLUMN submitted_at SET NOT NULL;
+
+ALTER TABLE test_attempts
+DROP COLUMN IF EXISTS deadline_at,
+DROP COLUMN IF EXISTS started_at,
+DROP COLUMN IF EXISTS seed,
+DROP COLUMN IF EXISTS question_ids;
+
+ALTER TABLE test_attempts DROP CONSTRAINT IF EXISTS test_attempts_status_check;
+ALTER TABLE test_attempts
+ADD CONSTRAINT test_attempts_status_check CHECK (status IN ('graded', 'pending_review'));
+
+ALTER TABLE tests
+DROP COLUMN IF EXISTS pool_size,
+DROP COLUMN IF EXISTS shuffle_options,
+DROP COLUMN IF EXISTS shuffle_questions,
+DROP COLUMN IF EXISTS scoring,
+DROP COLUMN IF EXISTS max_attempts,
+DROP COLUMN IF EXISTS time_limit_min;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Каждая отправка проекта — отдельная версия; текущее состояние — последняя версия.
+CREATE TABLE IF NOT EXISTS project_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    submission_text TEXT NOT NULL DEFAULT '',
+    submission_files JSONB NOT NULL DEFAULT '[]'::jsonb,
+    status assignment_status NOT NULL DEFAULT 'pending_check',
+    grade INTEGER,
+    teacher_comment TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, user_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_submissions_user ON project_submissions(user_id, project_id, version DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS project_submissions;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- История сдачи ДЗ: каждая отправка — неизменяемая версия со своими файлами, оценкой и комментарием.
+-- user_assignments_submission остаётся текущим состоянием и всегда совпадает с последней версией.
+CREATE TABLE IF NOT EXISTS assignment_submission_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assignment_id UUID NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    submission_text TEXT NOT NULL DEFAULT '',
+    submission_files JSONB NOT NULL DEFAULT '[]'::jsonb,
+    status assignment_status NOT NULL DEFAULT 'pending_check',
+    grade INTEGER,
+    teacher_comment TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (assignment_id, user_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_submission_versions_user ON assignment_submission_versions(user_id, assignment_id, version DESC);
+
+INSERT INTO assignment_submission_versions
+    (assignment_id, user_id, version, submission_text, submission_files, status, grade, teacher_comment, submitted_at)
+SELECT assignment_id, user_id, 1, COALESCE(submission_text, ''), COALESCE(submission_files, '[]'::jsonb),
+       status, grade, teacher_comment, COALESCE(submitted_at, NOW())
+FROM user_assignments_submission
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS assignment_submission_versions;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Обсуждение сдачи ДЗ: сообщения преподавателей и ученика, вложения, привязка к файлу/строкам версии.
+CREATE TABLE IF NOT EXISTS submission_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assignment_id UUID NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version INTEGER,
+    body TEXT NOT NULL DEFAULT '',
+    attachments JSONB NOT NULL DEFAULT '[]'::jsonb,
+    anchor_file TEXT,
+    line_start INTEGER,
+    line_end INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (line_end IS NULL OR line_start IS NOT NULL AND line_end >= line_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_comments_thread ON submission_comments(assignment_id, student_id, created_at);
+
+-- Отметки о прочтении: по одной на пользователя и сообщение.
+CREATE TABLE IF NOT EXISTS submission_comment_reads (
+    comment_id UUID NOT NULL REFERENCES submission_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS submission_comment_reads;
+DROP TABLE IF EXISTS submission_comments;
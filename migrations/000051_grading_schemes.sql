@@ -0,0 +1,7 @@
+-- +goose Up
+-- Схема оценивания ДЗ курса (см. domain.GradeScheme). По умолчанию — прежняя сетка 20/40/60/80/100.
+ALTER TABLE courses
+    ADD COLUMN IF NOT EXISTS grading_scheme JSONB NOT NULL DEFAULT '{"type": "numeric", "min": 20, "max": 100, "step": 20}';
+
+-- +goose Down
+ALTER TABLE courses DROP COLUMN IF EXISTS grading_scheme;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Рубрики: критерии оценки ДЗ или проекта с диапазоном баллов.
+CREATE TABLE IF NOT EXISTS rubric_criteria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assignment_id UUID REFERENCES assignments(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    max_points INTEGER NOT NULL CHECK (max_points > 0),
+    position INTEGER NOT NULL DEFAULT 0,
+    CHECK (num_nonnulls(assignment_id, project_id) = 1)
+);
+CREATE INDEX IF NOT EXISTS idx_rubric_criteria_assignment ON rubric_criteria (assignment_id, position);
+CREATE INDEX IF NOT EXISTS idx_rubric_criteria_project ON rubric_criteria (project_id, position);
+
+-- Разбивка оценки по критериям (domain.CriterionScore) хранится рядом с оценкой.
+ALTER TABLE user_assignments_submission ADD COLUMN IF NOT EXISTS rubric_scores JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE assignment_submission_versions ADD COLUMN IF NOT EXISTS rubric_scores JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE project_submissions ADD COLUMN IF NOT EXISTS rubric_scores JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE project_submissions DROP COLUMN IF EXISTS rubric_scores;
+ALTER TABLE assignment_submission_versions DROP COLUMN IF EXISTS rubric_scores;
+ALTER TABLE user_assignments_submission DROP COLUMN IF EXISTS rubric_scores;
+DROP TABLE IF EXISTS rubric_criteria;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- Срок сдачи ДЗ: абсолютный (due_at) или в часах от начала урока (due_offset_hours).
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS due_offset_hours INTEGER CHECK (due_offset_hours >= 0);
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS late_policy VARCHAR(20) NOT NULL DEFAULT 'accept';
+-- Политики: accept, penalize, reject
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS late_penalty_percent INTEGER NOT NULL DEFAULT 0 CHECK (late_penalty_percent BETWEEN 0 AND 100);
+ALTER TABLE assignments ADD CONSTRAINT assignments_single_deadline CHECK (due_at IS NULL OR due_offset_hours IS NULL);
+
+-- Отметка об опоздании фиксируется при отправке работы.
+ALTER TABLE user_assignments_submission ADD COLUMN IF NOT EXISTS is_late BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE assignment_submission_versions ADD COLUMN IF NOT EXISTS is_late BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Запросы учеников на продление срока ДЗ
+CREATE TABLE IF NOT EXISTS assignment_extension_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    assignment_id UUID NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_due_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'PENDING',
+    -- Статусы: PENDING, APPROVED, REJECTED
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    review_comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_extension_requests_student ON assignment_extension_requests(student_id, assignment_id);
+CREATE INDEX IF NOT EXISTS idx_extension_requests_status ON assignment_extension_requests(status);
+
+-- +goose Down
+DROP TABLE IF EXISTS assignment_extension_requests;
+ALTER TABLE assignment_submission_versions DROP COLUMN IF EXISTS is_late;
+ALTER TABLE user_assignments_submission DROP COLUMN IF EXISTS is_late;
+ALTER TABLE assignments DROP CONSTRAINT IF EXISTS assignments_single_deadline;
+ALTER TABLE assignments DROP COLUMN IF EXISTS late_penalty_percent;
+ALTER TABLE assignments DROP COLUMN IF EXISTS late_policy;
+ALTER TABLE assignments DROP COLUMN IF EXISTS due_offset_hours;
+ALTER TABLE assignments DROP COLUMN IF EXISTS due_at;
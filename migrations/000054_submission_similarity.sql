@@ -0,0 +1,23 @@
+-- +goose Up
+-- Отпечаток версии ДЗ (текст ответа и текстовых вложений + MinHash-подпись) для курсов с защитой от копирования.
+CREATE TABLE IF NOT EXISTS submission_fingerprints (
+    version_id UUID PRIMARY KEY REFERENCES assignment_submission_versions(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    signature BIGINT[] NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Пары похожих версий одного задания: version_id — более поздняя версия, matched_version_id — работа другого ученика.
+CREATE TABLE IF NOT EXISTS submission_similarities (
+    version_id UUID NOT NULL REFERENCES assignment_submission_versions(id) ON DELETE CASCADE,
+    matched_version_id UUID NOT NULL REFERENCES assignment_submission_versions(id) ON DELETE CASCADE,
+    score NUMERIC(4, 3) NOT NULL CHECK (score BETWEEN 0 AND 1),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (version_id, matched_version_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_similarities_matched ON submission_similarities(matched_version_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS submission_similarities;
+DROP TABLE IF EXISTS submission_fingerprints;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Уроки, открытые администратором конкретному ученику в обход правил последовательного прохождения.
+CREATE TABLE IF NOT EXISTS lesson_unlocks (
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    unlocked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (lesson_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lesson_unlocks_student ON lesson_unlocks(student_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_unlocks;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Просмотр видео урока: позиция для продолжения и объединённые просмотренные отрезки [[начало, конец], ...] в секундах.
+CREATE TABLE IF NOT EXISTS lesson_video_progress (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    position_sec INTEGER NOT NULL DEFAULT 0,
+    duration_sec INTEGER NOT NULL DEFAULT 0,
+    watched_ranges JSONB NOT NULL DEFAULT '[]'::jsonb,
+    watched_sec INTEGER NOT NULL DEFAULT 0,
+    -- Момент, когда просмотрено не меньше 80% видео; после этого урок считается пройденным.
+    completed_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, lesson_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lesson_video_progress_lesson ON lesson_video_progress(lesson_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_video_progress;
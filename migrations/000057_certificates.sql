@@ -0,0 +1,18 @@
+-- +goose Up
+-- Сертификаты об окончании курса: один на ученика и курс, проверяются публично по коду.
+CREATE TABLE IF NOT EXISTS certificates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(20) NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    student_name TEXT NOT NULL,
+    course_title TEXT NOT NULL,
+    pdf_url TEXT NOT NULL DEFAULT '',
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, course_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificates_course ON certificates(course_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS certificates;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Отзыв сертификата администратором: отозванный сертификат остаётся в базе, но при проверке недействителен.
+ALTER TABLE certificates
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS revoke_reason TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE certificates
+    DROP COLUMN IF EXISTS revoke_reason,
+    DROP COLUMN IF EXISTS revoked_by,
+    DROP COLUMN IF EXISTS revoked_at;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Отзыв сертификата — отдельное право: courses.edit есть и у модератора.
+INSERT INTO permissions (code, description) VALUES
+    ('certificates.revoke', 'Отзыв выданных сертификатов')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission_code) VALUES
+    ('admin', 'certificates.revoke')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE code = 'certificates.revoke';
@@ -0,0 +1,252 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrUnsupportedFont = errors.New("unsupported font")
+
+// helveticaASCII — ширины символов 32–126 Helvetica в тысячных долях кегля (из AFM).
+var helveticaASCII = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+func helveticaWidth(r rune) int {
+	if r >= 32 && r <= 126 {
+		return helveticaASCII[r-32]
+	}
+	return 556
+}
+
+// TrueTypeFont — шрифт TrueType (glyf), встраиваемый в PDF как CIDFontType2 с кодировкой Identity-H.
+// Шрифты с CFF-контурами (OpenType .otf) не поддерживаются.
+type TrueTypeFont struct {
+	data       []byte
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	cmap       map[rune]uint16
+	advances   []int
+}
+
+// ParseTrueType читает таблицы head, hhea, hmtx и cmap, нужные для вывода текста.
+func ParseTrueType(data []byte) (*TrueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%w: file is too short", ErrUnsupportedFont)
+	}
+	if v := binary.BigEndian.Uint32(data); v != 0x00010000 && v != 0x74727565 {
+		return nil, fmt.Errorf("%w: not a TrueType font", ErrUnsupportedFont)
+	}
+	tables := map[string][]byte{}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, fmt.Errorf("%w: truncated table directory", ErrUnsupportedFont)
+		}
+		off := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if off < 0 || length < 0 || off+length > len(data) {
+			return nil, fmt.Errorf("%w: table out of range", ErrUnsupportedFont)
+		}
+		tables[string(data[rec:rec+4])] = data[off : off+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap", "glyf"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("%w: missing %s table", ErrUnsupportedFont, tag)
+		}
+	}
+
+	head, hhea := tables["head"], tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 {
+		return nil, fmt.Errorf("%w: truncated head or hhea", ErrUnsupportedFont)
+	}
+	f := &TrueTypeFont{data: data, unitsPerEm: int(binary.BigEndian.Uint16(head[18:]))}
+	if f.unitsPerEm == 0 {
+		return nil, fmt.Errorf("%w: zero unitsPerEm", ErrUnsupportedFont)
+	}
+	for i := range f.bbox {
+		f.bbox[i] = f.scale(int(int16(binary.BigEndian.Uint16(head[36+2*i:]))))
+	}
+	f.ascent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[4:]))))
+	f.descent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[6:]))))
+
+	hmtx := tables["hmtx"]
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if numMetrics == 0 || len(hmtx) < 4*numMetrics {
+		return nil, fmt.Errorf("%w: truncated hmtx", ErrUnsupportedFont)
+	}
+	f.advances = make([]int, numMetrics)
+	for i := range f.advances {
+		f.advances[i] = f.scale(int(binary.BigEndian.Uint16(hmtx[4*i:])))
+	}
+
+	cmap, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.cmap = cmap
+	return f, nil
+}
+
+// scale переводит единицы шрифта в тысячные доли кегля.
+func (f *TrueTypeFont) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// glyph — номер глифа символа; отсутствующие в шрифте символы выводятся глифом 0 (.notdef).
+func (f *TrueTypeFont) glyph(r rune) uint16 {
+	return f.cmap[r]
+}
+
+func (f *TrueTypeFont) advance(gid uint16) int {
+	if int(gid) < len(f.advances) {
+		return f.advances[gid]
+	}
+	return f.advances[len(f.advances)-1]
+}
+
+// parseCmap выбирает юникодную подтаблицу: формат 12 (вся плоскость Unicode) или формат 4 (BMP).
+func parseCmap(t []byte) (map[rune]uint16, error) {
+	if len(t) < 4 {
+		return nil, fmt.Errorf("%w: truncated cmap", ErrUnsupportedFont)
+	}
+	var fmt4, fmt12 []byte
+	n := int(binary.BigEndian.Uint16(t[2:]))
+	for i := 0; i < n && 4+8*i+8 <= len(t); i++ {
+		rec := t[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		off := int(binary.BigEndian.Uint32(rec[4:]))
+		if off+2 > len(t) || !(platform == 0 || platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(t[off:]) {
+		case 4:
+			fmt4 = t[off:]
+		case 12:
+			fmt12 = t[off:]
+		}
+	}
+	switch {
+	case fmt12 != nil:
+		return parseCmap12(fmt12)
+	case fmt4 != nil:
+		return parseCmap4(fmt4)
+	}
+	return nil, fmt.Errorf("%w: no unicode cmap", ErrUnsupportedFont)
+}
+
+func parseCmap4(t []byte) (map[rune]uint16, error) {
+	if len(t) < 14 {
+		return nil, fmt.Errorf("%w: truncated cmap format 4", ErrUnsupportedFont)
+	}
+	segs := int(binary.BigEndian.Uint16(t[6:])) / 2
+	ends, starts, deltas, ranges := 14, 16+2*segs, 16+4*segs, 16+6*segs
+	if ranges+2*segs > len(t) {
+		return nil, fmt.Errorf("%w: truncated cmap format 4", ErrUnsupportedFont)
+	}
+	m := map[rune]uint16{}
+	for i := 0; i < segs; i++ {
+		end := int(binary.BigEndian.Uint16(t[ends+2*i:]))
+		start := int(binary.BigEndian.Uint16(t[starts+2*i:]))
+		delta := binary.BigEndian.Uint16(t[deltas+2*i:])
+		rangeOff := int(binary.BigEndian.Uint16(t[ranges+2*i:]))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			gid := uint16(c) + delta
+			if rangeOff != 0 {
+				addr := ranges + 2*i + rangeOff + 2*(c-start)
+				if addr+2 > len(t) {
+					break
+				}
+				if gid = binary.BigEndian.Uint16(t[addr:]); gid != 0 {
+					gid += delta
+				}
+			}
+			if gid != 0 {
+				m[rune(c)] = gid
+			}
+		}
+	}
+	return m, nil
+}
+
+func parseCmap12(t []byte) (map[rune]uint16, error) {
+	if len(t) < 16 {
+		return nil, fmt.Errorf("%w: truncated cmap format 12", ErrUnsupportedFont)
+	}
+	groups := int(binary.BigEndian.Uint32(t[12:]))
+	if groups < 0 || 16+12*groups > len(t) {
+		return nil, fmt.Errorf("%w: truncated cmap format 12", ErrUnsupportedFont)
+	}
+	m := map[rune]uint16{}
+	for i := 0; i < groups; i++ {
+		g := t[16+12*i:]
+		start, end := binary.BigEndian.Uint32(g), binary.BigEndian.Uint32(g[4:])
+		gid := binary.BigEndian.Uint32(g[8:])
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			m[rune(c)] = uint16(gid + c - start)
+		}
+	}
+	return m, nil
+}
+
+// writeObjects пишет Type0-шрифт с идентификатором id и зависимые объекты (id+1…id+4).
+// used — использованные глифы и символы, из которых они получены (для ширин и ToUnicode).
+func (f *TrueTypeFont) writeObjects(w *writer, id int, used map[uint16]rune) error {
+	gids := make([]int, 0, len(used))
+	for gid := range used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var widths, toUnicode strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, f.advance(uint16(gid)))
+	}
+	toUnicode.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for i := 0; i < len(gids); i += 100 {
+		chunk := gids[i:min(i+100, len(gids))]
+		fmt.Fprintf(&toUnicode, "%d beginbfchar\n", len(chunk))
+		for _, gid := range chunk {
+			fmt.Fprintf(&toUnicode, "<%04X> <%s>\n", gid, utf16Hex(used[uint16(gid)]))
+		}
+		toUnicode.WriteString("endbfchar\n")
+	}
+	toUnicode.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	const name = "/EmbeddedFont"
+	w.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont %s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, id+1, id+4))
+	w.object(id+1, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont %s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW %d /W [%s] >>",
+		name, id+2, f.advance(0), widths.String()))
+	w.object(id+2, fmt.Sprintf("<< /Type /FontDescriptor /FontName %s /Flags 32 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.ascent, id+3))
+	if err := w.stream(id+3, fmt.Sprintf(" /Length1 %d", len(f.data)), f.data); err != nil {
+		return err
+	}
+	return w.stream(id+4, "", []byte(toUnicode.String()))
+}
+
+func utf16Hex(r rune) string {
+	if r < 0x10000 {
+		return fmt.Sprintf("%04X", r)
+	}
+	r -= 0x10000
+	return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+}
@@ -0,0 +1,212 @@
+// Package pdf — минимальный генератор одностраничных PDF-документов с текстом и рамками.
+// Без шрифта используется стандартная Helvetica (только латиница и Latin-1), для кириллицы
+// нужно передать TrueType-шрифт — он встраивается в документ целиком.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Align string
+
+const (
+	AlignLeft   Align = "left"
+	AlignCenter Align = "center"
+	AlignRight  Align = "right"
+)
+
+// Text — строка текста; X, Y — точка привязки в пунктах от левого нижнего угла страницы.
+type Text struct {
+	X, Y  float64
+	Size  float64
+	Align Align
+	Value string
+}
+
+// Rect — контур прямоугольника толщиной LineWidth.
+type Rect struct {
+	X, Y, Width, Height float64
+	LineWidth           float64
+}
+
+type Document struct {
+	Width, Height float64
+	font          *TrueTypeFont
+	texts         []Text
+	rects         []Rect
+}
+
+// New создаёт страницу размером width×height пунктов; font == nil — Helvetica.
+func New(width, height float64, font *TrueTypeFont) *Document {
+	return &Document{Width: width, Height: height, font: font}
+}
+
+func (d *Document) AddText(t Text) {
+	d.texts = append(d.texts, t)
+}
+
+func (d *Document) AddRect(r Rect) {
+	d.rects = append(d.rects, r)
+}
+
+// TextWidth — ширина строки в пунктах при размере шрифта size.
+func (d *Document) TextWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		if d.font != nil {
+			units += d.font.advance(d.font.glyph(r))
+		} else {
+			units += helveticaWidth(r)
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Bytes собирает документ.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	used := map[uint16]rune{}
+	content := d.content(used)
+
+	pw := &writer{}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	pw.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object(2, "<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	pw.object(3, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		num(d.Width), num(d.Height)))
+	if err := pw.stream(4, "", content); err != nil {
+		return 0, err
+	}
+	if d.font == nil {
+		pw.object(5, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	} else if err := d.font.writeObjects(pw, 5, used); err != nil {
+		return 0, err
+	}
+	pw.finish()
+	n, err := w.Write(pw.buf.Bytes())
+	return int64(n), err
+}
+
+func (d *Document) content(used map[uint16]rune) []byte {
+	var b strings.Builder
+	for _, r := range d.rects {
+		fmt.Fprintf(&b, "%s w %s %s %s %s re S\n", num(r.LineWidth), num(r.X), num(r.Y), num(r.Width), num(r.Height))
+	}
+	for _, t := range d.texts {
+		x := t.X
+		switch t.Align {
+		case AlignCenter:
+			x -= d.TextWidth(t.Value, t.Size) / 2
+		case AlignRight:
+			x -= d.TextWidth(t.Value, t.Size)
+		}
+		fmt.Fprintf(&b, "BT /F1 %s Tf %s %s Td %s Tj ET\n", num(t.Size), num(x), num(t.Y), d.encode(t.Value, used))
+	}
+	return []byte(b.String())
+}
+
+// encode — строка в кодировке шрифта: WinAnsi для Helvetica (символы вне Latin-1 заменяются на «?»),
+// номера глифов для встроенного шрифта.
+func (d *Document) encode(s string, used map[uint16]rune) string {
+	var b strings.Builder
+	if d.font != nil {
+		b.WriteByte('<')
+		for _, r := range s {
+			gid := d.font.glyph(r)
+			if _, ok := used[gid]; !ok {
+				used[gid] = r
+			}
+			fmt.Fprintf(&b, "%04X", gid)
+		}
+		b.WriteByte('>')
+		return b.String()
+	}
+	b.WriteByte('(')
+	for _, r := range s {
+		if r < 32 || r > 255 || (r > 126 && r < 160) {
+			r = '?'
+		}
+		if r == '(' || r == ')' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(byte(r))
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// writer собирает объекты и таблицу перекрёстных ссылок.
+type writer struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (w *writer) printf(format string, args ...any) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+func (w *writer) object(id int, body string) {
+	w.begin(id)
+	w.printf("%s\nendobj\n", body)
+}
+
+func (w *writer) begin(id int) {
+	if w.offsets == nil {
+		w.offsets = map[int]int{}
+	}
+	w.offsets[id] = w.buf.Len()
+	w.printf("%d 0 obj\n", id)
+}
+
+// stream пишет сжатый поток; extra — дополнительные ключи словаря.
+func (w *writer) stream(id int, extra string, data []byte) error {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	w.begin(id)
+	w.printf("<< /Length %d /Filter /FlateDecode%s >>\nstream\n", z.Len(), extra)
+	w.buf.Write(z.Bytes())
+	w.printf("\nendstream\nendobj\n")
+	return nil
+}
+
+func (w *writer) finish() {
+	ids := make([]int, 0, len(w.offsets))
+	for id := range w.offsets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	size := ids[len(ids)-1] + 1
+	xref := w.buf.Len()
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", size)
+	for id := 1; id < size; id++ {
+		w.printf("%010d 00000 n \n", w.offsets[id])
+	}
+	w.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, xref)
+}
+
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-0" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	t.Run("xref points at objects", func(t *testing.T) {
+		d := New(842, 595, nil)
+		d.AddRect(Rect{X: 20, Y: 20, Width: 802, Height: 555, LineWidth: 2})
+		d.AddText(Text{X: 421, Y: 300, Size: 24, Align: AlignCenter, Value: "Certificate (1)"})
+		out, err := d.Bytes()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+			t.Fatalf("not a pdf: %q", out[:20])
+		}
+		m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+		if m == nil {
+			t.Fatalf("no startxref")
+		}
+		xref, _ := strconv.Atoi(string(m[1]))
+		entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+		if len(entries) != 5 {
+			t.Fatalf("expected 5 objects, got %d", len(entries))
+		}
+		for i, e := range entries {
+			off, _ := strconv.Atoi(string(e[1]))
+			if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(out[off:], []byte(want)) {
+				t.Fatalf("xref entry %d points at %q", i+1, out[off:off+10])
+			}
+		}
+	})
+
+	t.Run("helvetica text is escaped and centered", func(t *testing.T) {
+		d := New(200, 100, nil)
+		d.AddText(Text{X: 100, Y: 50, Size: 10, Align: AlignCenter, Value: "A(b)Ж"})
+		content := pageContent(t, d)
+		// A(667) + ( + b + ) + ? = 667+333+556+333+556 = 2445 → 24.45pt, половина — 12.225.
+		if want := `BT /F1 10 Tf 87.78 50 Td (A\(b\)?) Tj ET`; !bytes.Contains(content, []byte(want)) {
+			t.Fatalf("content %q does not contain %q", content, want)
+		}
+	})
+}
+
+func TestParseTrueType(t *testing.T) {
+	t.Run("rejects non-truetype data", func(t *testing.T) {
+		_, err := ParseTrueType([]byte("OTTO\x00\x00\x00\x00\x00\x00\x00\x00"))
+		if !errors.Is(err, ErrUnsupportedFont) {
+			t.Fatalf("expected ErrUnsupportedFont, got %v", err)
+		}
+	})
+}
+
+// pageContent распаковывает поток содержимого страницы (объект 4).
+func pageContent(t *testing.T, d *Document) []byte {
+	t.Helper()
+	out, err := d.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := bytes.Index(out, []byte("4 0 obj"))
+	data := out[start:]
+	data = data[bytes.Index(data, []byte("stream\n"))+len("stream\n"):]
+	data = data[:bytes.Index(data, []byte("\nendstream"))]
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("content is not zlib: %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("content is not zlib: %v", err)
+	}
+	return content
+}
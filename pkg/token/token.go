@@ -0,0 +1,206 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformed  = errors.New("token is malformed")
+	ErrUnknownKey = errors.New("token signed with unknown key")
+	ErrSignature  = errors.New("token signature is invalid")
+	ErrExpired    = errors.New("token has expired")
+	ErrPurpose    = errors.New("token issued for another purpose")
+)
+
+// Claims — полезная нагрузка токена. Формат совместим с JWT (HS256).
+// Purpose пустой у access-токенов; служебные токены (например, между шагами входа с 2FA)
+// помечаются назначением и не принимаются Parse.
+type Claims struct {
+	UserID     string `json:"sub"`
+	Role       string `json:"role"`
+	SessionID  string `json:"sid,omitempty"`
+	MFA        bool   `json:"mfa,omitempty"`
+	MFAPending bool   `json:"mfa_pending,omitempty"`
+	Purpose    string `json:"pur,omitempty"`
+	IssuedAt   int64  `json:"iat"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// Manager подписывает и проверяет токены. Подпись всегда делается активным ключом,
+// а проверка принимает любой известный ключ — так старые токены продолжают работать
+// во время ротации, пока их ключ не убран из конфигурации.
+type Manager struct {
+	keys      map[string][]byte
+	activeKID string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+func NewManager(keys map[string][]byte, activeKID string, ttl time.Duration) (*Manager, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("token: no signing keys configured")
+	}
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("token: active key %q is not configured", activeKID)
+	}
+	for kid, secret := range keys {
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("token: key %q must be at least 32 bytes", kid)
+		}
+	}
+	return &Manager{keys: keys, activeKID: activeKID, ttl: ttl, now: time.Now}, nil
+}
+
+// ParseKeys разбирает строку вида "kid1:secret1,kid2:secret2".
+// Первый ключ в списке считается активным, если activeKID не задан явно.
+func ParseKeys(raw string) (map[string][]byte, string, error) {
+	keys := make(map[string][]byte)
+	first := ""
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, ":")
+		if !ok || kid == "" || secret == "" {
+			return nil, "", fmt.Errorf("token: invalid key entry %q", pair)
+		}
+		if first == "" {
+			first = kid
+		}
+		keys[kid] = []byte(secret)
+	}
+	return keys, first, nil
+}
+
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+func (m *Manager) Issue(userID, role, sessionID string) (string, *Claims, error) {
+	return m.IssueWith(Claims{UserID: userID, Role: role, SessionID: sessionID}, m.ttl)
+}
+
+// IssueWith подписывает произвольные claims; iat и exp выставляются по ttl.
+func (m *Manager) IssueWith(claims Claims, ttl time.Duration) (string, *Claims, error) {
+	now := m.now()
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(ttl).Unix()
+	tok, err := m.sign(&claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return tok, &claims, nil
+}
+
+func (m *Manager) sign(claims *Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: m.activeKID})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encode(h) + "." + encode(p)
+	return signingInput + "." + encode(mac(m.keys[m.activeKID], signingInput)), nil
+}
+
+// Parse проверяет access-токен.
+func (m *Manager) Parse(tok string) (*Claims, error) {
+	claims, err := m.verify(tok)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, ErrPurpose
+	}
+	if claims.SessionID == "" {
+		return nil, ErrMalformed
+	}
+	return claims, nil
+}
+
+// ParsePurpose проверяет служебный токен с заданным назначением.
+func (m *Manager) ParsePurpose(tok, purpose string) (*Claims, error) {
+	claims, err := m.verify(tok)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purpose {
+		return nil, ErrPurpose
+	}
+	return claims, nil
+}
+
+func (m *Manager) verify(tok string) (*Claims, error) {
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	rawHeader, err := decode(parts[0])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	var h header
+	if err := json.Unmarshal(rawHeader, &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrMalformed
+	}
+
+	secret, ok := m.keys[h.Kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	sig, err := decode(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if !hmac.Equal(sig, mac(secret, parts[0]+"."+parts[1])) {
+		return nil, ErrSignature
+	}
+
+	rawClaims, err := decode(parts[1])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	var claims Claims
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return nil, ErrMalformed
+	}
+	if claims.UserID == "" || claims.Role == "" {
+		return nil, ErrMalformed
+	}
+	if m.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+	return &claims, nil
+}
+
+func mac(secret []byte, input string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(input))
+	return h.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
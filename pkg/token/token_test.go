@@ -0,0 +1,112 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestManager(t *testing.T, active string) *Manager {
+	t.Helper()
+	keys := map[string][]byte{
+		"k1": []byte(strings.Repeat("a", 32)),
+		"k2": []byte(strings.Repeat("b", 32)),
+	}
+	m, err := NewManager(keys, active, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestManager_IssueAndParse(t *testing.T) {
+	m := newTestManager(t, "k1")
+
+	tok, _, err := m.Issue("user-1", "admin", "sess-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims, err := m.Parse(tok)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Role != "admin" || claims.SessionID != "sess-1" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestManager_Parse(t *testing.T) {
+	m := newTestManager(t, "k1")
+	tok, _, _ := m.Issue("user-1", "student", "sess-1")
+
+	t.Run("LegacyPlainToken", func(t *testing.T) {
+		if _, err := m.Parse("user-1:admin"); err != ErrMalformed {
+			t.Errorf("expected ErrMalformed, got %v", err)
+		}
+	})
+
+	t.Run("TamperedPayload", func(t *testing.T) {
+		parts := strings.Split(tok, ".")
+		forged, _, _ := m.Issue("user-1", "admin", "sess-1")
+		parts[1] = strings.Split(forged, ".")[1]
+		parts[2] = strings.Split(tok, ".")[2]
+		if _, err := m.Parse(strings.Join(parts, ".")); err != ErrSignature {
+			t.Errorf("expected ErrSignature, got %v", err)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		m.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		defer func() { m.now = time.Now }()
+		if _, err := m.Parse(tok); err != ErrExpired {
+			t.Errorf("expected ErrExpired, got %v", err)
+		}
+	})
+
+	t.Run("KeyRotation", func(t *testing.T) {
+		rotated := newTestManager(t, "k2")
+		if _, err := rotated.Parse(tok); err != nil {
+			t.Errorf("token signed by previous key should stay valid: %v", err)
+		}
+
+		onlyNew, _ := NewManager(map[string][]byte{"k2": []byte(strings.Repeat("b", 32))}, "k2", time.Hour)
+		if _, err := onlyNew.Parse(tok); err != ErrUnknownKey {
+			t.Errorf("expected ErrUnknownKey, got %v", err)
+		}
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, active, err := ParseKeys("k1:secret-one, k2:secret-two")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active != "k1" || len(keys) != 2 || string(keys["k2"]) != "secret-two" {
+		t.Errorf("unexpected result: %v %q", keys, active)
+	}
+
+	if _, _, err := ParseKeys("broken"); err == nil {
+		t.Error("expected error for entry without kid")
+	}
+}
+
+func TestManager_Purpose(t *testing.T) {
+	m := newTestManager(t, "k1")
+	tok, _, err := m.IssueWith(Claims{UserID: "user-1", Role: "admin", Purpose: "mfa"}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := m.Parse(tok); err != ErrPurpose {
+		t.Errorf("purpose token must not be accepted as access token, got %v", err)
+	}
+	if _, err := m.ParsePurpose(tok, "mfa"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	access, _, _ := m.Issue("user-1", "admin", "sess-1")
+	if _, err := m.ParsePurpose(access, "mfa"); err != ErrPurpose {
+		t.Errorf("access token must not be accepted as purpose token, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Векторы из RFC 6238, приложение B (SHA1, ключ "12345678901234567890"), последние 6 цифр.
+func TestCode_RFC6238(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range cases {
+		got, err := Code(secret, Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("time %d: expected %s, got %s", unix, want, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	code, _ := Code(secret, Step(now))
+
+	t.Run("CurrentStep", func(t *testing.T) {
+		step, ok := Validate(secret, code, now, 1)
+		if !ok || step != Step(now) {
+			t.Errorf("expected valid code for step %d, got %d %v", Step(now), step, ok)
+		}
+	})
+
+	t.Run("ClockSkew", func(t *testing.T) {
+		if _, ok := Validate(secret, code, now.Add(Period*time.Second), 1); !ok {
+			t.Error("expected code from previous step to be accepted")
+		}
+		if _, ok := Validate(secret, code, now.Add(3*Period*time.Second), 1); ok {
+			t.Error("expected old code to be rejected")
+		}
+	})
+
+	t.Run("WrongCode", func(t *testing.T) {
+		if _, ok := Validate(secret, "12345", now, 1); ok {
+			t.Error("expected short code to be rejected")
+		}
+	})
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Cap Education", "admin@capedu.kz", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/Cap%20Education:admin@capedu.kz?") || !strings.Contains(uri, "secret=ABC") {
+		t.Errorf("unexpected uri: %s", uri)
+	}
+}
@@ -178,16 +178,28 @@ S3_REGION=us-east-1
 S3_BUCKET_NAME=capeducation
 S3_ACCESS_KEY_ID=JLC3NTLH51VE0LB7D1EZ
 S3_SECRET_ACCESS_KEY=qu3BqIk4D90Dhz9piWIDSwBoRrCukhA84HHATwB1
-SYSTEM_SECRET=my_ultra_secret_backdoor_key_2026
 ENVEOF
             log "Created .env with default values"
         fi
     else
         log ".env already exists, keeping it"
     fi
+    ensure_token_keys
     cd ..
 }
 
+# Без AUTH_TOKEN_KEYS приложение не стартует, поэтому ключ подписи генерируется при установке.
+ensure_token_keys() {
+    if grep -q '^AUTH_TOKEN_KEYS=.\+' .env; then
+        return
+    fi
+    local secret
+    secret=$(head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n')
+    sed -i '/^AUTH_TOKEN_KEYS=/d' .env
+    echo "AUTH_TOKEN_KEYS=k1:${secret}" >> .env
+    ok "Generated AUTH_TOKEN_KEYS in .env"
+}
+
 run_docker() {
     cd "$PROJECT_DIR"
     log "Starting services with Docker Compose..."